@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// --- PRIORIDAD DE ALARMA ---
+
+// AlarmsConfig deriva la prioridad de alarma de cada punto real de las
+// convenciones de nombre (p.ej. _H_H → critical, _H → high), para que el
+// documento de filosofía de alarmas del SCADA salga de la misma fuente que
+// las listas. Las reglas se evalúan en orden: las más específicas primero.
+type AlarmsConfig struct {
+	Rules []AlarmRule `yaml:"rules"`
+	// Levels son las prioridades admitidas, de mayor a menor; ordenan la
+	// exportación "alarms" y el resumen. Por defecto critical, high, medium,
+	// low.
+	Levels  []string `yaml:"levels"`
+	Default string   `yaml:"default"` // prioridad de los puntos sin regla; vacío = sin alarma
+}
+
+// AlarmRule asigna Priority a las variables que cumplen alguno de los
+// patrones, en las listas indicadas (vacío = todas).
+type AlarmRule struct {
+	Priority string   `yaml:"priority"`
+	Lists    []string `yaml:"lists"`
+	Patterns []string `yaml:"patterns"`
+}
+
+var defaultAlarmLevels = []string{"critical", "high", "medium", "low"}
+
+func (c AlarmsConfig) enabled() bool {
+	return len(c.Rules) > 0 || c.Default != ""
+}
+
+func (c AlarmsConfig) levels() []string {
+	if len(c.Levels) > 0 {
+		return c.Levels
+	}
+	return defaultAlarmLevels
+}
+
+// rank es la posición de una prioridad en levels; las desconocidas van al
+// final.
+func (c AlarmsConfig) rank(priority string) int {
+	if i := slices.Index(c.levels(), priority); i >= 0 {
+		return i
+	}
+	return len(c.levels())
+}
+
+// alarmTagger resuelve la prioridad de un punto.
+type alarmTagger struct {
+	cfg      AlarmsConfig
+	matchers []*ruleMatcher
+}
+
+func compileAlarms(cfg AlarmsConfig) (*alarmTagger, error) {
+	t := &alarmTagger{cfg: cfg}
+	check := func(priority string) error {
+		if !slices.Contains(cfg.levels(), priority) {
+			return fmt.Errorf("alarms: prioridad %q fuera de levels (%s)", priority, strings.Join(cfg.levels(), ", "))
+		}
+		return nil
+	}
+	for _, r := range cfg.Rules {
+		if r.Priority == "" {
+			return nil, fmt.Errorf("alarms: regla sin prioridad")
+		}
+		if err := check(r.Priority); err != nil {
+			return nil, err
+		}
+		m, err := compileRules(r.Patterns, false)
+		if err != nil {
+			return nil, fmt.Errorf("alarms %s: %v", r.Priority, err)
+		}
+		t.matchers = append(t.matchers, m)
+	}
+	if cfg.Default != "" {
+		if err := check(cfg.Default); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *alarmTagger) priority(list, varName string) string {
+	for i, m := range t.matchers {
+		r := t.cfg.Rules[i]
+		if len(r.Lists) > 0 && !slices.ContainsFunc(r.Lists, func(l string) bool { return strings.EqualFold(l, list) }) {
+			continue
+		}
+		if m.Any(varName) {
+			return r.Priority
+		}
+	}
+	return t.cfg.Default
+}
+
+// tagAlarms asigna la prioridad de alarma a los puntos reales de todas las
+// listas.
+func tagAlarms(l *Lists, cfg AlarmsConfig) error {
+	if !cfg.enabled() {
+		return nil
+	}
+	t, err := compileAlarms(cfg)
+	if err != nil {
+		return err
+	}
+	for _, s := range listSections(l) {
+		for i := range s.Items {
+			if p := &s.Items[i]; !p.Spare {
+				p.Alarm = t.priority(s.Name, p.Var)
+			}
+		}
+	}
+	return nil
+}
+
+// alarmPoint es un punto con alarma y su posición.
+type alarmPoint struct {
+	list  string
+	index int
+	Point
+}
+
+// alarmPoints devuelve los puntos con prioridad, de mayor a menor y en el
+// orden de las listas dentro de cada prioridad.
+func alarmPoints(l *Lists) []alarmPoint {
+	cfg := GlobalConfig.App.Alarms
+	var out []alarmPoint
+	for _, s := range listSections(l) {
+		for idx, p := range s.Items {
+			if !p.Spare && p.Alarm != "" {
+				out = append(out, alarmPoint{s.Name, idx, p})
+			}
+		}
+	}
+	slices.SortStableFunc(out, func(a, b alarmPoint) int {
+		return cfg.rank(a.Alarm) - cfg.rank(b.Alarm)
+	})
+	return out
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"alarms", ".alarms.csv", writeAlarmsCSV})
+}
+
+// writeAlarmsCSV escribe la tabla de alarmas por prioridad: la base del
+// documento de filosofía de alarmas.
+func writeAlarmsCSV(out io.Writer, ctx ExportContext) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"priority", "node", "list", "index", "name", "var", "type", "description", "area"})
+	for _, p := range alarmPoints(ctx.Lists) {
+		w.Write([]string{p.Alarm, ctx.Node, p.list, strconv.Itoa(p.index), p.Name, p.Var, p.Type, p.Desc, p.Area})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// alarmSheet es la hoja de Excel de alarmas por prioridad.
+func alarmSheet(l *Lists) xlsxSheet {
+	sh := xlsxSheet{Name: tr("Alarmas"), Rows: [][]string{{"Prioridad", "Lista", "Índice", "Punto", "Variable", "Tipo", "Descripción", "Área"}}}
+	for _, p := range alarmPoints(l) {
+		sh.Rows = append(sh.Rows, []string{p.Alarm, p.list, strconv.Itoa(p.index), p.Name, p.Var, p.Type, p.Desc, p.Area})
+	}
+	return sh
+}
+
+// printAlarmSummary muestra el número de alarmas por prioridad.
+func printAlarmSummary(l *Lists) {
+	counts := map[string]int{}
+	for _, p := range alarmPoints(l) {
+		counts[p.Alarm]++
+	}
+	var parts []string
+	for _, level := range GlobalConfig.App.Alarms.levels() {
+		if counts[level] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", level, counts[level]))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Println(trf("Alarmas: %s", strings.Join(parts, " | ")))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// --- ANÁLISIS DE SOLO LECTURA ---
+//
+// "dnpgen analyze" ejecuta el parseo, la clasificación y todas las
+// validaciones de la generación en memoria, sin SIGEXT y sin tocar el disco:
+// no escribe listas, exportaciones, estado de .dnpgen, estadísticas de uso
+// ni registro de ejecución, y no cambia de directorio. Sirve para revisar
+// proyectos en las carpetas compartidas de producción, donde no se permite
+// escribir. Lee el estado anterior (instantánea, recuentos, mapa de
+// índices) para informar de deriva y cambios, pero no lo actualiza.
+
+// nodeAnalysis es el resultado de analizar un nodo.
+type nodeAnalysis struct {
+	Node      string          `json:"node"`
+	Result    *GenerateResult `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	ErrorCode string          `json:"error_code,omitempty"`
+}
+
+// analyzeNode genera en memoria. Llama a generate y no a runGenerate: las
+// estadísticas de uso también son una escritura.
+func analyzeNode(projectPath, node string, incremental bool) nodeAnalysis {
+	a := nodeAnalysis{Node: node}
+	res, err := generate(GenerateRequest{ProjectPath: projectPath, NodeName: node, SkipExt: true, CheckOnly: true, Incremental: incremental})
+	if err != nil {
+		a.Error, a.ErrorCode = err.Error(), errorCode(err)
+		return a
+	}
+	a.Result = res
+	return a
+}
+
+// runAnalyze implementa "dnpgen analyze -path RUTA (-node A,B | -all)".
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	fs.Usage = manUsage("analyze", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos a analizar, separados por comas"))
+	all := fs.Bool("all", false, tr("Analizar todos los nodos con .SIG"))
+	incremental := fs.Bool("incremental", false, tr("Informar de los cambios respecto a la última generación"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(tr("Uso: dnpgen.exe analyze -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-incremental] [-json]"))
+	}
+	if isZipProject(*projectPath) {
+		// Abrir la copia .zip la extrae a un directorio temporal.
+		log.Fatal(tr("[FATAL] analyze no admite copias .zip: extraerlas escribe en disco"))
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var nodes []string
+	if *all {
+		infos, err := listProjectNodes(abs)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		for _, n := range infos {
+			if n.SigTime != nil {
+				nodes = append(nodes, n.Node)
+			}
+		}
+		if len(nodes) == 0 {
+			log.Fatalf(tr("[FATAL] No hay nodos con .SIG en %s"), abs)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	var results []nodeAnalysis
+	failed := 0
+	for _, node := range nodes {
+		a := analyzeNode(abs, node, *incremental)
+		if a.Error != "" {
+			failed++
+		}
+		results = append(results, a)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		for _, a := range results {
+			fmt.Println(boldText("\n=== " + a.Node + " ==="))
+			if a.Error != "" {
+				fmt.Println(errText(a.Error))
+				continue
+			}
+			printReport(a.Result)
+			printAnalysisDrift(a.Result)
+		}
+	}
+	if failed > 0 {
+		log.Printf(tr("[ERROR] %d de %d nodo(s) con errores"), failed, len(results))
+		os.Exit(1)
+	}
+}
+
+// printAnalysisDrift indica si las listas del disco coinciden con lo que
+// generaría el .SIG actual.
+func printAnalysisDrift(res *GenerateResult) {
+	switch {
+	case res.ListFile != "" && !fileExists(res.ListFile):
+		fmt.Println(warnText(trf("%s no existe: el nodo no se ha generado", filepath.Base(res.ListFile))))
+	case res.Drift:
+		fmt.Println(warnText(tr("Las listas del disco no coinciden con el .SIG actual (deriva)")))
+	default:
+		fmt.Println(okText(tr("Las listas del disco coinciden con el .SIG actual")))
+	}
+	if len(res.CountAlarms) > 0 {
+		fmt.Println(warnText(trf("Caída brusca de puntos respecto a la generación anterior: %s", formatCountAlarms(res.CountAlarms))))
+	}
+}
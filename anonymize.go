@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// --- ANONIMIZACIÓN ---
+//
+// Para compartir un caso con soporte sin enseñar los tags del cliente,
+// export-model y render aceptan -anonymize: antes de exportar, cada variable
+// se sustituye por un hash determinista (T + 10 hex), y lo mismo las
+// descripciones (D...), las áreas (A...) y el nombre del nodo (N...). Las
+// listas, los índices, los tipos, los spares y sus espejos, el escalado, los
+// objetos DNP3 y las reglas no cambian, así que el caso se reproduce igual.
+// El hash es un HMAC con app.anonymize.salt: la misma variable da el mismo
+// nombre en todas las exportaciones con la misma sal, y sin conocerla no se
+// pueden comprobar nombres candidatos. Sin sal, cualquiera que sospeche un
+// nombre puede confirmarlo.
+
+// AnonymizeConfig configura la anonimización.
+type AnonymizeConfig struct {
+	Salt string `yaml:"salt"` // admite ${VAR} de entorno
+}
+
+type anonymizer struct {
+	key   []byte
+	seen  map[string]string // hash → original, para detectar colisiones
+	names map[string]string // original → hash
+}
+
+func newAnonymizer(salt string) *anonymizer {
+	return &anonymizer{key: []byte(os.ExpandEnv(salt)), seen: map[string]string{}, names: map[string]string{}}
+}
+
+// hash devuelve el nombre anónimo de s con el prefijo de su clase; las
+// clases no comparten espacio de nombres.
+func (a *anonymizer) hash(prefix, s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	k := prefix + "\x00" + s
+	if h, ok := a.names[k]; ok {
+		return h, nil
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(k))
+	h := prefix + strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:5]))
+	if prev, ok := a.seen[h]; ok && prev != k {
+		return "", fmt.Errorf(tr("anonimización: %q y %q dan el mismo hash %s"), s, strings.SplitN(prev, "\x00", 2)[1], h)
+	}
+	a.seen[h], a.names[k] = k, h
+	return h, nil
+}
+
+// point anonimiza un punto. El nombre conserva su forma (@GV.<var>,
+// @GV.<spare>(<var>)): solo se sustituye la variable.
+func (a *anonymizer) point(p Point) (Point, error) {
+	v, err := a.hash("T", p.Var)
+	if err != nil {
+		return p, err
+	}
+	switch {
+	case p.Var == "":
+	case strings.HasSuffix(p.Name, "("+p.Var+")"):
+		p.Name = strings.TrimSuffix(p.Name, p.Var+")") + v + ")"
+	case p.Name == "@GV."+p.Var:
+		p.Name = "@GV." + v
+	default:
+		p.Name = strings.ReplaceAll(p.Name, p.Var, v)
+	}
+	p.Var = v
+	if p.Desc, err = a.hash("D", p.Desc); err != nil {
+		return p, err
+	}
+	if p.Area, err = a.hash("A", p.Area); err != nil {
+		return p, err
+	}
+	if p.Pair != nil {
+		pair := *p.Pair
+		if pair.Trip, err = a.hash("T", pair.Trip); err != nil {
+			return p, err
+		}
+		if pair.Close, err = a.hash("T", pair.Close); err != nil {
+			return p, err
+		}
+		p.Pair = &pair
+	}
+	return p, nil
+}
+
+func (a *anonymizer) points(in []Point) ([]Point, error) {
+	if in == nil {
+		return nil, nil
+	}
+	out := make([]Point, len(in))
+	for i, p := range in {
+		var err error
+		if out[i], err = a.point(p); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// anonymizeContext devuelve una copia anonimizada del contexto de
+// exportación; el original no cambia. Se quitan la ruta del proyecto y el
+// .SIG.
+func anonymizeContext(ctx ExportContext, salt string) (ExportContext, error) {
+	a := newAnonymizer(salt)
+	node, err := a.hash("N", ctx.Node)
+	if err != nil {
+		return ctx, err
+	}
+	l := &Lists{disabled: ctx.Lists.disabled}
+	for _, s := range []struct {
+		dst *[]Point
+		src []Point
+	}{{&l.AI, ctx.Lists.AI}, {&l.AO, ctx.Lists.AO}, {&l.DI, ctx.Lists.DI}, {&l.DO, ctx.Lists.DO}, {&l.OS, ctx.Lists.OS}} {
+		if *s.dst, err = a.points(s.src); err != nil {
+			return ctx, err
+		}
+	}
+	for _, e := range ctx.Lists.Extra {
+		if e.Items, err = a.points(e.Items); err != nil {
+			return ctx, err
+		}
+		l.Extra = append(l.Extra, e)
+	}
+	return ExportContext{Node: node, Lists: l, Anonymized: true}, nil
+}
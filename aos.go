@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- ESTADO DE SALIDAS ANALÓGICAS (AOS) ---
+
+// AOSConfig genera, para cada salida analógica, su punto de estado (Analog
+// Output Status) que esperan los masters DNP3. Con list vacío el AOS ocupa
+// en AI la posición del spare de espejo, manteniendo los índices alineados;
+// con una lista de extra_lists se escribe allí y AI sigue la estrategia de
+// espejo normal.
+type AOSConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Mode    string `yaml:"mode"`   // mirror (mismo nombre) o suffix
+	Suffix  string `yaml:"suffix"` // mode suffix; por defecto _AOS
+	List    string `yaml:"list"`
+}
+
+// aosBuilder construye los puntos AOS de una ejecución.
+type aosBuilder struct {
+	cfg    AOSConfig
+	target int // índice en Lists.Extra; -1 = AI
+}
+
+func newAOSBuilder(cfg AOSConfig, extra []CustomList) (*aosBuilder, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	switch cfg.Mode {
+	case "", "mirror", "suffix":
+	default:
+		return nil, fmt.Errorf("ao_status: modo desconocido %q", cfg.Mode)
+	}
+	b := &aosBuilder{cfg: cfg, target: -1}
+	if cfg.List != "" {
+		for i, e := range extra {
+			if e.Name == strings.ToUpper(cfg.List) {
+				b.target = i
+			}
+		}
+		if b.target < 0 {
+			return nil, fmt.Errorf("ao_status: la lista %q no está en extra_lists", cfg.List)
+		}
+	}
+	return b, nil
+}
+
+func (b *aosBuilder) point(ao Point) Point {
+	v := ao.Var
+	if b.cfg.Mode == "suffix" {
+		suffix := b.cfg.Suffix
+		if suffix == "" {
+			suffix = "_AOS"
+		}
+		v += suffix
+	}
+	return Point{Name: "@GV." + v, Var: v, Type: ao.Type, Desc: ao.Desc, AOS: true}
+}
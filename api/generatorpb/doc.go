@@ -0,0 +1,9 @@
+// Package generatorpb contiene el contrato gRPC del generador DNP3.
+//
+// Los ficheros *.pb.go se generan desde generator.proto:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative generator.proto
+package generatorpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative generator.proto
@@ -0,0 +1,280 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: generator.proto
+
+package generatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Generator_Submit_FullMethodName      = "/dnpgen.v1.Generator/Submit"
+	Generator_Generate_FullMethodName    = "/dnpgen.v1.Generator/Generate"
+	Generator_Watch_FullMethodName       = "/dnpgen.v1.Generator/Watch"
+	Generator_GetStatus_FullMethodName   = "/dnpgen.v1.Generator/GetStatus"
+	Generator_GetArtifact_FullMethodName = "/dnpgen.v1.Generator/GetArtifact"
+)
+
+// GeneratorClient is the client API for Generator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GeneratorClient interface {
+	Submit(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error)
+	Watch(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error)
+	GetStatus(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (*JobStatus, error)
+	GetArtifact(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (*Artifact, error)
+}
+
+type generatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeneratorClient(cc grpc.ClientConnInterface) GeneratorClient {
+	return &generatorClient{cc}
+}
+
+func (c *generatorClient) Submit(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, Generator_Submit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Generator_ServiceDesc.Streams[0], Generator_Generate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateRequest, JobEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Generator_GenerateClient = grpc.ServerStreamingClient[JobEvent]
+
+func (c *generatorClient) Watch(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Generator_ServiceDesc.Streams[1], Generator_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobRef, JobEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Generator_WatchClient = grpc.ServerStreamingClient[JobEvent]
+
+func (c *generatorClient) GetStatus(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, Generator_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) GetArtifact(ctx context.Context, in *JobRef, opts ...grpc.CallOption) (*Artifact, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Artifact)
+	err := c.cc.Invoke(ctx, Generator_GetArtifact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeneratorServer is the server API for Generator service.
+// All implementations must embed UnimplementedGeneratorServer
+// for forward compatibility.
+type GeneratorServer interface {
+	Submit(context.Context, *GenerateRequest) (*JobStatus, error)
+	Generate(*GenerateRequest, grpc.ServerStreamingServer[JobEvent]) error
+	Watch(*JobRef, grpc.ServerStreamingServer[JobEvent]) error
+	GetStatus(context.Context, *JobRef) (*JobStatus, error)
+	GetArtifact(context.Context, *JobRef) (*Artifact, error)
+	mustEmbedUnimplementedGeneratorServer()
+}
+
+// UnimplementedGeneratorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGeneratorServer struct{}
+
+func (UnimplementedGeneratorServer) Submit(context.Context, *GenerateRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (UnimplementedGeneratorServer) Generate(*GenerateRequest, grpc.ServerStreamingServer[JobEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedGeneratorServer) Watch(*JobRef, grpc.ServerStreamingServer[JobEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedGeneratorServer) GetStatus(context.Context, *JobRef) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedGeneratorServer) GetArtifact(context.Context, *JobRef) (*Artifact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifact not implemented")
+}
+func (UnimplementedGeneratorServer) mustEmbedUnimplementedGeneratorServer() {}
+func (UnimplementedGeneratorServer) testEmbeddedByValue()                   {}
+
+// UnsafeGeneratorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeneratorServer will
+// result in compilation errors.
+type UnsafeGeneratorServer interface {
+	mustEmbedUnimplementedGeneratorServer()
+}
+
+func RegisterGeneratorServer(s grpc.ServiceRegistrar, srv GeneratorServer) {
+	// If the following call pancis, it indicates UnimplementedGeneratorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Generator_ServiceDesc, srv)
+}
+
+func _Generator_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_Submit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).Submit(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_Generate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GeneratorServer).Generate(m, &grpc.GenericServerStream[GenerateRequest, JobEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Generator_GenerateServer = grpc.ServerStreamingServer[JobEvent]
+
+func _Generator_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobRef)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GeneratorServer).Watch(m, &grpc.GenericServerStream[JobRef, JobEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Generator_WatchServer = grpc.ServerStreamingServer[JobEvent]
+
+func _Generator_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).GetStatus(ctx, req.(*JobRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_GetArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).GetArtifact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_GetArtifact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).GetArtifact(ctx, req.(*JobRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Generator_ServiceDesc is the grpc.ServiceDesc for Generator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Generator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dnpgen.v1.Generator",
+	HandlerType: (*GeneratorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Submit",
+			Handler:    _Generator_Submit_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Generator_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetArtifact",
+			Handler:    _Generator_GetArtifact_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Generate",
+			Handler:       _Generator_Generate_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Generator_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "generator.proto",
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// --- PROYECTOS COMPRIMIDOS (ZIP) ---
+//
+// -path admite una copia de seguridad del proyecto en .zip. Se extraen solo
+// los ficheros necesarios (.mwt, .SIG y los .ini del recurso) a un directorio
+// temporal, se genera desde allí y las salidas van a -out o, por defecto, a
+// una carpeta junto al zip con su mismo nombre.
+
+// zipNeeded indica si una entrada del zip hace falta para generar.
+func zipNeeded(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".sig", ".mwt", ".ini":
+		return true
+	}
+	return false
+}
+
+func isZipProject(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// openZipProject prepara req si su proyecto es un zip: lo extrae, apunta
+// ProjectPath a la raíz del proyecto dentro de la extracción y fija OutDir.
+// node puede estar vacío (todos los nodos). La función devuelta borra la
+// extracción; es no-op si el proyecto no es un zip.
+func openZipProject(req *GenerateRequest) (func(), error) {
+	if !isZipProject(req.ProjectPath) {
+		return func() {}, nil
+	}
+	zipPath, err := filepath.Abs(req.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "dnpgen-zip-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+	n, err := extractZip(zipPath, tmp)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("%s: %v", filepath.Base(zipPath), err)
+	}
+	root, err := findProjectRoot(tmp, req.NodeName)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("%s: %v", filepath.Base(zipPath), err)
+	}
+	log.Printf(tr("Proyecto comprimido %s: %d fichero(s) extraídos en %s"), filepath.Base(zipPath), n, root)
+
+	req.ProjectPath = root
+	if req.OutDir == "" {
+		req.OutDir = strings.TrimSuffix(zipPath, filepath.Ext(zipPath))
+	} else if abs, err := filepath.Abs(req.OutDir); err == nil {
+		req.OutDir = abs
+	}
+	return cleanup, nil
+}
+
+// extractZip extrae las entradas necesarias en dir y devuelve cuántas.
+func extractZip(zipPath, dir string) (int, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	n := 0
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !zipNeeded(f.Name) {
+			continue
+		}
+		name := filepath.FromSlash(f.Name)
+		if !filepath.IsLocal(name) {
+			return n, fmt.Errorf(tr("entrada fuera del directorio: %s"), f.Name)
+		}
+		dest := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return n, err
+		}
+		if err := extractZipFile(f, dest); err != nil {
+			return n, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func extractZipFile(f *zip.File, dest string) error {
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// Conserva la fecha: la comprobación de .SIG desactualizado la usa.
+	return os.Chtimes(dest, f.Modified, f.Modified)
+}
+
+// findProjectRoot busca, empezando por los directorios menos profundos, el
+// primero que es la raíz de un proyecto con el nodo (o con algún nodo si
+// node está vacío). Las copias suelen traer una carpeta con el nombre de la
+// planta por encima del proyecto.
+func findProjectRoot(dir, node string) (string, error) {
+	var dirs []string
+	filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	depth := func(p string) int { return strings.Count(p, string(filepath.Separator)) }
+	sort.SliceStable(dirs, func(a, b int) bool { return depth(dirs[a]) < depth(dirs[b]) })
+	if node != "" {
+		// Primero por el .SIG: un .mwt suelto en la raíz del zip no basta si
+		// el recurso está dentro de otra carpeta.
+		for _, pick := range []func(nodePaths) string{
+			func(p nodePaths) string { return p.Sig },
+			func(p nodePaths) string { return p.Mwt },
+		} {
+			for _, d := range dirs {
+				if fileExists(pick(nodePathsFor(d, node))) {
+					return d, nil
+				}
+			}
+		}
+		return "", fmt.Errorf(tr("no contiene el nodo %s"), node)
+	}
+	for _, d := range dirs {
+		if nodes, err := discoverNodes(d); err == nil && len(nodes) > 0 {
+			return d, nil
+		}
+	}
+	return "", errors.New(tr("no contiene ningún proyecto con ficheros .SIG"))
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- ÁREAS / SUBSISTEMAS ---
+
+// AreasConfig etiqueta cada punto con el área o subsistema al que pertenece,
+// para poder comisionar por áreas. Las reglas se evalúan en orden; sin
+// regla aplicable y con from_prefix, el área es el prefijo del nombre hasta
+// el primer separador.
+type AreasConfig struct {
+	Rules      []AreaRule `yaml:"rules"`
+	FromPrefix bool       `yaml:"from_prefix"`
+	Separator  string     `yaml:"separator"` // por defecto "_"
+	Default    string     `yaml:"default"`   // área de los puntos sin regla
+}
+
+// AreaRule asigna Name a las variables que cumplen alguno de los patrones.
+type AreaRule struct {
+	Name     string   `yaml:"name"`
+	Patterns []string `yaml:"patterns"`
+}
+
+func (c AreasConfig) enabled() bool {
+	return len(c.Rules) > 0 || c.FromPrefix || c.Default != ""
+}
+
+// areaTagger resuelve el área de una variable.
+type areaTagger struct {
+	cfg      AreasConfig
+	matchers []*ruleMatcher
+}
+
+func compileAreas(cfg AreasConfig) (*areaTagger, error) {
+	t := &areaTagger{cfg: cfg}
+	for _, r := range cfg.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("areas: regla sin nombre")
+		}
+		m, err := compileRules(r.Patterns, false)
+		if err != nil {
+			return nil, fmt.Errorf("areas %s: %v", r.Name, err)
+		}
+		t.matchers = append(t.matchers, m)
+	}
+	return t, nil
+}
+
+func (t *areaTagger) area(varName string) string {
+	for i, m := range t.matchers {
+		if m.Any(varName) {
+			return t.cfg.Rules[i].Name
+		}
+	}
+	if t.cfg.FromPrefix {
+		sep := t.cfg.Separator
+		if sep == "" {
+			sep = "_"
+		}
+		if prefix, _, ok := strings.Cut(varName, sep); ok && prefix != "" {
+			return prefix
+		}
+	}
+	return t.cfg.Default
+}
+
+// tagAreas etiqueta los puntos reales de todas las listas.
+func tagAreas(l *Lists, cfg AreasConfig) error {
+	if !cfg.enabled() {
+		return nil
+	}
+	t, err := compileAreas(cfg)
+	if err != nil {
+		return err
+	}
+	for _, s := range listSections(l) {
+		for i := range s.Items {
+			if p := &s.Items[i]; !p.Spare {
+				p.Area = t.area(p.Var)
+			}
+		}
+	}
+	return nil
+}
+
+// areaCount es el número de puntos reales de un área en cada lista.
+type areaCount struct {
+	Area   string
+	ByList map[string]int
+	Total  int
+}
+
+// countAreas agrupa los puntos reales por área, ordenadas por nombre (los
+// puntos sin área van al final).
+func countAreas(l *Lists) []areaCount {
+	index := map[string]*areaCount{}
+	var out []*areaCount
+	for _, s := range listSections(l) {
+		for _, p := range s.Items {
+			if p.Spare {
+				continue
+			}
+			c := index[p.Area]
+			if c == nil {
+				c = &areaCount{Area: p.Area, ByList: map[string]int{}}
+				index[p.Area] = c
+				out = append(out, c)
+			}
+			c.ByList[s.Name]++
+			c.Total++
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Area == "") != (out[j].Area == "") {
+			return out[j].Area == ""
+		}
+		return out[i].Area < out[j].Area
+	})
+	res := make([]areaCount, len(out))
+	for i, c := range out {
+		res[i] = *c
+	}
+	return res
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"areas", ".areas.csv", writeAreasCSV})
+}
+
+// writeAreasCSV escribe los puntos reales agrupados por área, en el orden de
+// las listas dentro de cada una.
+func writeAreasCSV(out io.Writer, ctx ExportContext) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"area", "node", "list", "index", "name", "var", "type", "description"})
+	for _, c := range countAreas(ctx.Lists) {
+		for _, s := range listSections(ctx.Lists) {
+			for idx, p := range s.Items {
+				if !p.Spare && p.Area == c.Area {
+					w.Write([]string{c.Area, ctx.Node, s.Name, strconv.Itoa(idx), p.Name, p.Var, p.Type, p.Desc})
+				}
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// areaSheets devuelve una hoja por área con sus puntos reales de todas las
+// listas, para repartir el comisionado.
+func areaSheets(l *Lists) []xlsxSheet {
+	var sheets []xlsxSheet
+	for _, c := range countAreas(l) {
+		name := c.Area
+		if name == "" {
+			name = tr("(sin área)")
+		}
+		sh := xlsxSheet{Name: tr("Área ") + name, Rows: [][]string{{"Lista", "Índice", "Punto", "Variable", "Tipo", "Descripción"}}}
+		for _, s := range listSections(l) {
+			for idx, p := range s.Items {
+				if !p.Spare && p.Area == c.Area {
+					sh.Rows = append(sh.Rows, []string{s.Name, strconv.Itoa(idx), p.Name, p.Var, p.Type, p.Desc})
+				}
+			}
+		}
+		sheets = append(sheets, sh)
+	}
+	return sheets
+}
+
+// printAreaSummary muestra los puntos reales por área y lista.
+func printAreaSummary(l *Lists) {
+	var names []string
+	for _, s := range listSections(l) {
+		names = append(names, s.Name)
+	}
+	var rows [][]string
+	for _, c := range countAreas(l) {
+		name := c.Area
+		if name == "" {
+			name = tr("(sin área)")
+		}
+		row := []string{name}
+		for _, n := range names {
+			row = append(row, strconv.Itoa(c.ByList[n]))
+		}
+		rows = append(rows, append(row, strconv.Itoa(c.Total)))
+	}
+	fmt.Println()
+	printTable(tr("ÁREA")+"\t"+strings.Join(names, "\t")+"\tTOTAL", rows, nil)
+}
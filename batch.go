@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- GENERACIÓN DE TODOS LOS NODOS ---
+
+// runBatch genera los nodos de un proyecto (todos si nodes está vacío) y
+// comprueba al final que ninguna variable real esté mapeada en más de un
+// nodo. Devuelve false si algún nodo falló o hay duplicados no permitidos
+// que la política de hallazgos considera bloqueantes.
+// base lleva las opciones comunes a todos los nodos; cp, si no es nil,
+// apunta los nodos terminados y salta los que ya lo estaban.
+func runBatch(base GenerateRequest, nodes []string, cp *batchCheckpoint) bool {
+	bookName := base.ProjectPath
+	cpProject, err := filepath.Abs(normalizeLongPath(bookName))
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+	cleanup, err := openZipProject(&base)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+	defer cleanup()
+	projectPath := base.ProjectPath
+	if len(nodes) == 0 {
+		if nodes, err = discoverNodes(projectPath); err != nil {
+			log.Printf("[ERROR] %v", err)
+			return false
+		}
+		if len(nodes) == 0 {
+			log.Printf(tr("[ERROR] No hay ficheros .SIG en %s"), projectPath)
+			return false
+		}
+	}
+
+	ok := true
+	report := &emailReport{Project: projectPath, Date: formatStamp(time.Now())}
+	generated := map[string]*Lists{}
+	var rows [][]string
+	var flagged []bool
+	for _, node := range nodes {
+		if cp.done(cpProject, node) {
+			if l, err := resumedLists(projectPath, node, base.OutDir); err != nil {
+				log.Printf(tr("[WARN] %s: ya generado, pero no se pudo leer su lista (%v); se genera de nuevo"), node, err)
+			} else {
+				log.Printf(tr("=== Nodo %s: ya generado en la ejecución interrumpida, se salta ==="), node)
+				generated[node] = l
+				rows = append(rows, []string{node, strconv.Itoa(len(l.DI)), strconv.Itoa(len(l.DO)), strconv.Itoa(len(l.AI)), strconv.Itoa(len(l.AO)), "-"})
+				flagged = append(flagged, false)
+				continue
+			}
+		}
+		log.Printf(tr("=== Nodo %s ==="), node)
+		req := base
+		req.NodeName = node
+		res, err := runGenerate(req)
+		notifyResult(req, "", res, err)
+		report.add(node, res, err)
+		if err != nil {
+			log.Printf("[ERROR] %s: %v", node, err)
+			ok = false
+			continue
+		}
+		generated[node] = res.lists
+		if err := cp.mark(cpProject, node); err != nil {
+			log.Printf(tr("[WARN] No se pudo guardar el punto de control: %v"), err)
+		}
+		if d := res.Delta; d != nil && !d.First {
+			log.Printf(tr("%s: +%d -%d ~%d (índices movidos: %d)"), node, d.count("added"), d.count("removed"), d.count("changed"), d.count("moved"))
+		}
+		rows = append(rows, []string{node, strconv.Itoa(res.DI), strconv.Itoa(res.DO), strconv.Itoa(res.AI), strconv.Itoa(res.AO), strconv.Itoa(len(res.NameIssues))})
+		flagged = append(flagged, len(res.NameIssues) > 0 || res.StaleSig || len(res.CountAlarms) > 0)
+	}
+
+	dups, err := findSharedPoints(generated, GlobalConfig.App.SharedPoints)
+	if err != nil {
+		log.Printf("[ERROR] shared_points: %v", err)
+		return false
+	}
+	// Los duplicados son hallazgos "duplicate": error por defecto.
+	findings := newFindingSet("")
+	for _, d := range dups {
+		findings.add(FindingDuplicate, SeverityError, tr("variable %s mapeada en varios nodos: %s"), d.Var, strings.Join(d.Nodes, ", "))
+	}
+	dupErr := findings.verdict()
+	if dupErr != nil {
+		log.Printf("[ERROR] %v", dupErr)
+	}
+
+	fmt.Println(boldText(tr("\n--- RESUMEN ---")))
+	printTable(tr("NODO\tDI\tDO\tAI\tAO\tNOMBRES"), rows, func(i int) func(string) string {
+		if flagged[i] {
+			return warnText
+		}
+		return nil
+	})
+	if len(findings.list) > 0 {
+		color := warnText
+		if dupErr != nil {
+			color = errText
+			ok = false
+		}
+		fmt.Print(color(trf("\n%d variable(s) duplicadas entre nodos (ver shared_points para permitirlas)\n", len(findings.list))))
+	}
+	if GlobalConfig.App.PointBook.Enabled && !base.CheckOnly && len(generated) > 0 {
+		bookDir := base.OutDir
+		if bookDir == "" {
+			bookDir = projectPath
+		}
+		paths, err := writePointBook(bookName, bookDir, generated)
+		if err != nil {
+			log.Printf("[ERROR] point_book: %v", withCode(ErrExportFailed, err))
+			ok = false
+		}
+		for _, p := range paths {
+			fmt.Println(trf("Libro de puntos: %s", p))
+		}
+	}
+	report.OK, report.Duplicates = ok, dups
+	sendBatchEmail(report)
+	return ok
+}
+
+// resumedLists lee la lista ya generada de un nodo saltado por -resume.
+func resumedLists(projectPath, node, outDir string) (*Lists, error) {
+	abs, err := filepath.Abs(normalizeLongPath(projectPath))
+	if err != nil {
+		return nil, err
+	}
+	return readListsFile(filepath.Join(outputDirFor(abs, node, outDir), GlobalConfig.App.Output.fileName()))
+}
+
+// sharedPoint es una variable real presente en las listas de varios nodos.
+type sharedPoint struct {
+	Var   string
+	Nodes []string // "NODO/LISTA", ordenados
+}
+
+// findSharedPoints busca variables reales (no spares) que aparecen en más de
+// un nodo. Las que cumplen alguno de los patrones de allow se ignoran: son
+// puntos compartidos a propósito.
+func findSharedPoints(byNode map[string]*Lists, allow []string) ([]sharedPoint, error) {
+	allowed, err := compileRules(allow, false)
+	if err != nil {
+		return nil, err
+	}
+
+	where := map[string][]string{}
+	nodesOf := map[string]map[string]bool{}
+	for node, l := range byNode {
+		for _, list := range listSections(l) {
+			for _, p := range list.Items {
+				if p.Spare {
+					continue
+				}
+				where[p.Var] = append(where[p.Var], node+"/"+list.Name)
+				if nodesOf[p.Var] == nil {
+					nodesOf[p.Var] = map[string]bool{}
+				}
+				nodesOf[p.Var][node] = true
+			}
+		}
+	}
+
+	var out []sharedPoint
+	for v, nodes := range nodesOf {
+		if len(nodes) < 2 || allowed.Any(v) {
+			continue
+		}
+		locs := where[v]
+		sort.Strings(locs)
+		out = append(out, sharedPoint{Var: v, Nodes: locs})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Var < out[j].Var })
+	return out, nil
+}
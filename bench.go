@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"text/tabwriter"
+	"time"
+)
+
+// --- BANCO DE PRUEBAS DE RENDIMIENTO ---
+
+// runBench mide el pipeline (lectura, clasificación, renderizado y escritura)
+// sobre un .SIG sintético, para dimensionar proyectos de 100k+ señales sin
+// depender de un proyecto real. Las mismas etapas están en bench_test.go
+// para "go test -bench".
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.Usage = manUsage("bench", fs)
+	signals := fs.Int("signals", 100000, tr("Número de señales del .SIG sintético"))
+	runs := fs.Int("runs", 3, tr("Repeticiones por etapa (se informa la mejor)"))
+	sigPath := fs.String("sig", "", tr("Usar este .SIG en lugar de uno sintético"))
+	fs.Parse(args)
+
+	dir, err := os.MkdirTemp("", "dnpgen-bench")
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := *sigPath
+	if file == "" {
+		file = filepath.Join(dir, "BENCH.SIG")
+		if err := writeSyntheticSig(file, *signals); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	st, err := os.Stat(file)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	var lists *Lists
+	var content []byte
+	count := 0
+	stages := []struct {
+		name string
+		fn   func() error
+	}{
+		{"parse", func() error {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			count = 0
+			_, _, err = ParseSIG(f, func(Signal) { count++ })
+			return err
+		}},
+		{"classify", func() (err error) {
+			lists, _, err = processSigFile(file, nil)
+			return err
+		}},
+		{"render", func() error {
+			content = renderLists(lists)
+			return nil
+		}},
+		{"write", func() error {
+			return os.WriteFile(filepath.Join(dir, ListFile), content, 0o644)
+		}},
+	}
+
+	fmt.Printf(tr("SIG: %s (%d señales, %.1f MB)\n\n"), filepath.Base(file), *signals, float64(st.Size())/1e6)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, tr("ETAPA\tMEJOR\tns/señal\tMB asignados\tasignaciones\t"))
+	for _, s := range stages {
+		best := time.Duration(0)
+		var allocBytes, allocs uint64
+		for i := 0; i < max(*runs, 1); i++ {
+			runtime.GC()
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			start := time.Now()
+			if err := s.fn(); err != nil {
+				log.Fatalf("[FATAL] %s: %v", s.name, err)
+			}
+			d := time.Since(start)
+			runtime.ReadMemStats(&after)
+			if best == 0 || d < best {
+				best = d
+				allocBytes, allocs = after.TotalAlloc-before.TotalAlloc, after.Mallocs-before.Mallocs
+			}
+		}
+		perSignal := float64(best.Nanoseconds()) / float64(max(count, 1))
+		fmt.Fprintf(tw, "%s\t%s\t%.0f\t%.1f\t%d\t\n", s.name, best.Round(time.Microsecond), perSignal, float64(allocBytes)/1e6, allocs)
+	}
+	tw.Flush()
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	fmt.Printf(tr("\nSeñales leídas: %d | DI: %d | DO: %d | AI: %d | AO: %d | heap en uso: %.1f MB\n"),
+		count, len(lists.DI), len(lists.DO), len(lists.AI), len(lists.AO), float64(ms.HeapInuse)/1e6)
+}
+
+// writeSyntheticSig genera un .SIG con una mezcla de tipos parecida a la de
+// un proyecto real, incluidas variables que cumplen los patrones de salida.
+func writeSyntheticSig(path string, n int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	kinds := []struct{ prefix, suffix, typ string }{
+		{"FT", "_PV", "AA"},
+		{"LIT", "_H_H", "AA"},
+		{"TK", "_SP", "REAL"},
+		{"P", "_RUN", "LA"},
+		{"P", "_CMD", "LA"},
+		{"V", "_OPEN", "BOOL"},
+		{"ALM", "_TXT", "STRING"},
+	}
+	for i := 0; i < n; i++ {
+		k := kinds[i%len(kinds)]
+		fmt.Fprintf(w, "SIG=@GV.%s%06d%s TYPE=%s\r\n", k.prefix, i, k.suffix, k.typ)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
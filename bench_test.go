@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// benchSignals es el tamaño del .SIG sintético de los benchmarks.
+const benchSignals = 100000
+
+// benchSig escribe el .SIG sintético y carga el config.yaml del repositorio
+// para clasificar con reglas reales.
+func benchSig(b *testing.B) string {
+	b.Helper()
+	if err := loadConfigFile(ConfigFile); err != nil {
+		b.Fatal(err)
+	}
+	file := filepath.Join(b.TempDir(), "BENCH.SIG")
+	if err := writeSyntheticSig(file, benchSignals); err != nil {
+		b.Fatal(err)
+	}
+	return file
+}
+
+func BenchmarkParse(b *testing.B) {
+	file := benchSig(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := os.Open(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n := 0
+		if _, _, err := ParseSIG(f, func(Signal) { n++ }); err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+		if n != benchSignals {
+			b.Fatalf("%d señales, se esperaban %d", n, benchSignals)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*benchSignals), "ns/señal")
+}
+
+// BenchmarkClassify informa además de la memoria que ocupan las listas
+// clasificadas (heap-B/señal): crece con el .SIG, ver processSigFile.
+func BenchmarkClassify(b *testing.B) {
+	file := benchSig(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	var lists *Lists
+	for i := 0; i < b.N; i++ {
+		var err error
+		if lists, _, err = processSigFile(file, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*benchSignals), "ns/señal")
+
+	var before, after runtime.MemStats
+	lists = nil
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	lists, _, _ = processSigFile(file, nil)
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/benchSignals, "heap-B/señal")
+	runtime.KeepAlive(lists)
+}
+
+func BenchmarkWrite(b *testing.B) {
+	file := benchSig(b)
+	lists, _, err := processSigFile(file, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	out := filepath.Join(b.TempDir(), ListFile)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := writeFileAtomic(out, renderLists(lists)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*benchSignals), "ns/señal")
+}
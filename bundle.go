@@ -0,0 +1,532 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- PAQUETES PARA REDES AISLADAS ---
+//
+// Pasar ficheros sueltos entre redes acaba en ejecuciones con un .SIG o un
+// config.yaml distintos. "dnpgen bundle export" empaqueta en un único zip lo
+// que determina el resultado: el .SIG y el .mwt de los nodos, los .ini del
+// recurso, el config.yaml cargado, el estado de <salida>/.dnpgen (mapa de
+// índices, recuentos, instantánea) y la versión del generador. Además anota
+// el SHA-256 de las listas que produce esa combinación. En el portátil de
+// puesta en marcha "dnpgen bundle run" genera desde el paquete (sin SIGEXT
+// ni red) y comprueba que las listas salen idénticas; "bundle import" solo
+// lo extrae.
+
+// bundleFormat es la versión del formato del paquete.
+const bundleFormat = 1
+
+// bundleManifest es bundle.json dentro del paquete.
+type bundleManifest struct {
+	Format    int            `json:"format"`
+	Requires  BuildInfo      `json:"requires"` // generador que creó el paquete
+	Project   string         `json:"project"`  // nombre de la carpeta del proyecto
+	Nodes     []string       `json:"nodes"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []bundleFile   `json:"files"`
+	Expected  []bundleOutput `json:"expected"`
+}
+
+// bundleFile es un fichero del paquete; Path es relativo al zip.
+type bundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// bundleOutput es una lista que bundle run tiene que reproducir. El hash no
+// incluye la línea de cabecera "; dnpgen ...".
+type bundleOutput struct {
+	Node   string `json:"node"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// Rutas dentro del paquete.
+const (
+	bundleManifestName = "bundle.json"
+	bundleConfigName   = "config.yaml"
+	bundleProjectDir   = "project"
+	bundleStateDir     = "state"
+)
+
+// bundleInputs devuelve los ficheros de entrada de un nodo: .SIG, .mwt y
+// los .ini de su recurso.
+func bundleInputs(absProjectPath, node string) ([]string, error) {
+	paths := nodePathsFor(absProjectPath, node)
+	if !fileExists(paths.Sig) {
+		return nil, codedErrorf(ErrSigNotFound, tr("no existe .SIG: %s"), paths.Sig)
+	}
+	files := []string{paths.Sig}
+	if fileExists(paths.Mwt) {
+		files = append(files, paths.Mwt)
+	}
+	entries, err := os.ReadDir(paths.Resource)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".ini") {
+			files = append(files, filepath.Join(paths.Resource, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+// bundleWriter añade ficheros al zip y los anota en el manifiesto.
+type bundleWriter struct {
+	zw       *zip.Writer
+	manifest *bundleManifest
+	seen     map[string]bool
+}
+
+func (b *bundleWriter) add(name string, data []byte, modified time.Time) error {
+	if b.seen[name] {
+		return nil
+	}
+	b.seen[name] = true
+	w, err := b.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if name != bundleManifestName {
+		b.manifest.Files = append(b.manifest.Files, bundleFile{Path: name, SHA256: sha256Hex(data), Size: int64(len(data))})
+	}
+	return nil
+}
+
+// addFile añade un fichero del disco bajo name conservando su fecha (la
+// comprobación de .SIG desactualizado la necesita).
+func (b *bundleWriter) addFile(name, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	return b.add(name, data, info.ModTime())
+}
+
+// exportBundle escribe el paquete de los nodos en out.
+func exportBundle(absProjectPath string, nodes []string, out string) (*bundleManifest, error) {
+	if configSource == "" {
+		return nil, errors.New(tr("no hay config.yaml cargado"))
+	}
+	m := &bundleManifest{
+		Format:    bundleFormat,
+		Requires:  buildInfo(),
+		Project:   filepath.Base(absProjectPath),
+		Nodes:     nodes,
+		CreatedAt: stampNow(),
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundleWriter{zw: zip.NewWriter(f), manifest: m, seen: map[string]bool{}}
+	fail := func(err error) (*bundleManifest, error) {
+		f.Close()
+		os.Remove(out)
+		return nil, err
+	}
+
+	if err := b.addFile(bundleConfigName, configSource); err != nil {
+		return fail(err)
+	}
+	for _, node := range nodes {
+		inputs, err := bundleInputs(absProjectPath, node)
+		if err != nil {
+			return fail(fmt.Errorf("%s: %v", node, err))
+		}
+		for _, file := range inputs {
+			rel, err := filepath.Rel(absProjectPath, file)
+			if err != nil || !filepath.IsLocal(rel) {
+				return fail(fmt.Errorf(tr("%s está fuera del proyecto (app.layout)"), file))
+			}
+			if err := b.addFile(path.Join(bundleProjectDir, filepath.ToSlash(rel)), file); err != nil {
+				return fail(err)
+			}
+		}
+		// Estado de generaciones anteriores: sin él, el mapa de índices y
+		// la alarma de recuento darían otro resultado.
+		state := snapshotDir(outputDirFor(absProjectPath, node, ""))
+		if entries, err := os.ReadDir(state); err == nil {
+			for _, e := range entries {
+				if !e.IsDir() && strings.HasPrefix(e.Name(), node+".") {
+					if err := b.addFile(path.Join(bundleStateDir, e.Name()), filepath.Join(state, e.Name())); err != nil {
+						return fail(err)
+					}
+				}
+			}
+		}
+
+		// Listas que produce esta combinación, para comprobarlas al ejecutar.
+		res, err := runGenerate(GenerateRequest{ProjectPath: absProjectPath, NodeName: node, SkipExt: true, CheckOnly: true})
+		if err != nil {
+			return fail(fmt.Errorf("%s: %v", node, err))
+		}
+		if res.Drift {
+			log.Printf(tr("[WARN] %s: las listas del proyecto no coinciden con el .SIG actual; el paquete reproduce las del .SIG"), node)
+		}
+		for _, o := range listOutputs("", res.lists, res.content) {
+			m.Expected = append(m.Expected, bundleOutput{Node: node, File: filepath.Base(o.path), SHA256: sha256Hex(stripListHeader(o.content))})
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := b.add(bundleManifestName, append(data, '\n'), m.CreatedAt); err != nil {
+		return fail(err)
+	}
+	if err := b.zw.Close(); err != nil {
+		return fail(err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(out)
+		return nil, err
+	}
+	return m, nil
+}
+
+// importBundle extrae el paquete en dir comprobando el hash de cada fichero.
+func importBundle(bundle, dir string) (*bundleManifest, error) {
+	r, err := zip.OpenReader(bundle)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var m *bundleManifest
+	for _, f := range r.File {
+		if f.Name == bundleManifestName {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			m = &bundleManifest{}
+			if err := json.Unmarshal(data, m); err != nil {
+				return nil, fmt.Errorf("%s: %v", bundleManifestName, err)
+			}
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf(tr("%s no es un paquete de dnpgen (falta %s)"), filepath.Base(bundle), bundleManifestName)
+	}
+	if m.Format > bundleFormat {
+		return nil, fmt.Errorf(tr("formato de paquete %d no soportado (esta versión admite hasta %d)"), m.Format, bundleFormat)
+	}
+
+	want := map[string]string{bundleManifestName: ""}
+	for _, bf := range m.Files {
+		want[bf.Path] = bf.SHA256
+	}
+	for _, f := range r.File {
+		sum, ok := want[f.Name]
+		if !ok || f.FileInfo().IsDir() {
+			if !f.FileInfo().IsDir() {
+				return nil, fmt.Errorf(tr("%s: fichero no declarado en %s"), f.Name, bundleManifestName)
+			}
+			continue
+		}
+		name := filepath.FromSlash(f.Name)
+		if !filepath.IsLocal(name) {
+			return nil, fmt.Errorf(tr("entrada fuera del directorio: %s"), f.Name)
+		}
+		dest := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, err
+		}
+		if err := extractZipFile(f, dest); err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		if sum == "" {
+			continue
+		}
+		data, err := os.ReadFile(dest)
+		if err != nil {
+			return nil, err
+		}
+		if sha256Hex(data) != sum {
+			return nil, fmt.Errorf(tr("%s: el hash no coincide con %s (paquete dañado o modificado)"), f.Name, bundleManifestName)
+		}
+		delete(want, f.Name)
+	}
+	delete(want, bundleManifestName)
+	for name := range want {
+		return nil, fmt.Errorf(tr("falta %s en el paquete"), name)
+	}
+	return m, nil
+}
+
+// checkBundleFiles comprueba que un paquete ya extraído no se ha modificado.
+func checkBundleFiles(dir string, m *bundleManifest) error {
+	for _, bf := range m.Files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(bf.Path)))
+		if err != nil {
+			return err
+		}
+		if sha256Hex(data) != bf.SHA256 {
+			return fmt.Errorf(tr("%s: el hash no coincide con %s (paquete dañado o modificado)"), bf.Path, bundleManifestName)
+		}
+	}
+	return nil
+}
+
+// checkBundleVersion exige la misma versión del generador que creó el
+// paquete: otra versión puede generar listas distintas.
+func checkBundleVersion(m *bundleManifest, force bool) error {
+	b := buildInfo()
+	switch {
+	case m.Requires.Version != b.Version:
+		if !force {
+			return fmt.Errorf(tr("el paquete requiere dnpgen %s y esta es la %s (use -force para ejecutarlo igualmente)"), m.Requires.Version, b.Version)
+		}
+		log.Printf(tr("[WARN] El paquete requiere dnpgen %s; se ejecuta con la %s"), m.Requires.Version, b.Version)
+	case m.Requires.Commit != "" && b.Commit != "" && m.Requires.Commit != b.Commit:
+		log.Printf(tr("[WARN] El paquete se creó con el commit %s y este ejecutable es del %s"), m.Requires.Commit, b.Commit)
+	}
+	return nil
+}
+
+// BundleCheck compara una lista generada con la esperada por el paquete.
+type BundleCheck struct {
+	Node      string `json:"node"`
+	File      string `json:"file"`
+	Identical bool   `json:"identical"`
+}
+
+// runBundleDir genera los nodos de un paquete ya extraído en dir hacia out
+// y compara las listas con las esperadas.
+func runBundleDir(dir, out string, m *bundleManifest) ([]BundleCheck, error) {
+	GlobalConfig = Config{}
+	if err := loadConfigFile(filepath.Join(dir, bundleConfigName)); err != nil {
+		return nil, err
+	}
+	// Sin red en el portátil aislado: ni publicación ni bases de datos
+	// remotas. El estado va siempre junto a la salida.
+	GlobalConfig.App.Publish.Targets = nil
+	GlobalConfig.App.Database.Enabled = false
+	GlobalConfig.App.Incremental.Dir = ""
+
+	project := filepath.Join(dir, bundleProjectDir)
+	var checks []BundleCheck
+	for _, node := range m.Nodes {
+		outDir := filepath.Join(out, node)
+		if len(m.Nodes) == 1 {
+			outDir = out
+		}
+		if err := copyBundleState(filepath.Join(dir, bundleStateDir), snapshotDir(outDir), node); err != nil {
+			return nil, fmt.Errorf("%s: %v", node, err)
+		}
+		res, err := runGenerate(GenerateRequest{ProjectPath: project, NodeName: node, SkipExt: true, OutDir: outDir})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", node, err)
+		}
+		printSummary(res)
+		for _, e := range m.Expected {
+			if e.Node != node {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(outDir, e.File))
+			checks = append(checks, BundleCheck{Node: node, File: e.File, Identical: err == nil && sha256Hex(stripListHeader(data)) == e.SHA256})
+		}
+	}
+	return checks, nil
+}
+
+// copyBundleState copia el estado del nodo al directorio de estado de la
+// salida, salvo que ya exista uno (una ejecución anterior del paquete).
+func copyBundleState(from, to, node string) error {
+	entries, err := os.ReadDir(from)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), node+".") || fileExists(filepath.Join(to, e.Name())) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(from, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(to, 0o755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(to, e.Name()), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBundle implementa "dnpgen bundle export|import|run".
+func runBundle(args []string) {
+	usage := tr("Uso: dnpgen.exe bundle export|import|run [opciones] (dnpgen help bundle)")
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+	switch args[0] {
+	case "export":
+		loadConfiguration()
+		runBundleExport(args[1:])
+	case "import":
+		runBundleImport(args[1:])
+	case "run":
+		runBundleRun(args[1:])
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func runBundleExport(args []string) {
+	fs := flag.NewFlagSet("bundle export", flag.ExitOnError)
+	fs.Usage = manUsage("bundle export", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos del paquete, separados por comas"))
+	out := fs.String("o", "", tr("Fichero del paquete (vacío = <proyecto>-<nodo>.bundle.zip)"))
+	fs.Parse(args)
+
+	if *projectPath == "" || *nodeNames == "" {
+		log.Fatal(tr("Uso: dnpgen.exe bundle export -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" [-o paquete.zip]"))
+	}
+	abs, err := filepath.Abs(*projectPath)
+	if err != nil {
+		log.Fatalf(tr("Error ruta absoluta: %v"), err)
+	}
+	var nodes []string
+	for _, n := range strings.Split(*nodeNames, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			nodes = append(nodes, n)
+		}
+	}
+	file := *out
+	if file == "" {
+		file = sanitizeFileName(filepath.Base(abs)+"-"+strings.Join(nodes, "_")) + ".bundle.zip"
+	}
+	m, err := exportBundle(abs, nodes, file)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	fmt.Println(okText(trf("Paquete %s: %d fichero(s), %d lista(s) esperadas, dnpgen %s", file, len(m.Files), len(m.Expected), m.Requires.Version)))
+}
+
+func runBundleImport(args []string) {
+	fs := flag.NewFlagSet("bundle import", flag.ExitOnError)
+	fs.Usage = manUsage("bundle import", fs)
+	dest := fs.String("dest", "", tr("Directorio donde extraer el paquete (vacío = carpeta con el nombre del paquete)"))
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal(tr("Uso: dnpgen.exe bundle import [-dest DIR] paquete.bundle.zip"))
+	}
+	bundle := fs.Arg(0)
+	dir := *dest
+	if dir == "" {
+		dir = strings.TrimSuffix(strings.TrimSuffix(bundle, ".zip"), ".bundle")
+	}
+	m, err := importBundle(bundle, dir)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	// Solo se avisa: la versión se exige al ejecutar.
+	checkBundleVersion(m, true)
+	fmt.Println(okText(trf("Paquete extraído en %s (%s: %s)", dir, m.Project, strings.Join(m.Nodes, ", "))))
+	fmt.Println(trf("Ejecute: dnpgen bundle run %s", dir))
+}
+
+func runBundleRun(args []string) {
+	fs := flag.NewFlagSet("bundle run", flag.ExitOnError)
+	fs.Usage = manUsage("bundle run", fs)
+	out := fs.String("out", "", tr("Directorio de salida (vacío = <paquete>.out)"))
+	force := fs.Bool("force", false, tr("Ejecutar aunque la versión del generador no coincida"))
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal(tr("Uso: dnpgen.exe bundle run [-out DIR] [-force] paquete.bundle.zip|DIR"))
+	}
+	src := fs.Arg(0)
+
+	dir := src
+	if info, err := os.Stat(src); err == nil && !info.IsDir() {
+		tmp, err := os.MkdirTemp("", "dnpgen-bundle-")
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+		if _, err := importBundle(src, dir); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestName))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var m bundleManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		log.Fatalf("[FATAL] %s: %v", bundleManifestName, err)
+	}
+	if err := checkBundleFiles(dir, &m); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if err := checkBundleVersion(&m, *force); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	outDir := *out
+	if outDir == "" {
+		outDir = strings.TrimSuffix(strings.TrimSuffix(filepath.Clean(src), ".zip"), ".bundle") + ".out"
+	}
+	if outDir, err = filepath.Abs(outDir); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	checks, err := runBundleDir(dir, outDir, &m)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Node < checks[j].Node })
+	var rows [][]string
+	differ := 0
+	for _, c := range checks {
+		status := okText(tr("idéntica"))
+		if !c.Identical {
+			status = errText(tr("DIFERENTE"))
+			differ++
+		}
+		rows = append(rows, []string{c.Node, c.File, status})
+	}
+	fmt.Println()
+	printTable(tr("NODO\tLISTA\tRESULTADO"), rows, nil)
+	if differ > 0 {
+		fmt.Println(errText(trf("\n%d lista(s) no coinciden con las del paquete", differ)))
+		os.Exit(1)
+	}
+	fmt.Println(okText(trf("\nSalidas idénticas a las del paquete en %s", outDir)))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- PUNTOS DE CONTROL DE LOTES ---
+//
+// Un -all o -workspace sobre 40 nodos que falla en el 30 (p.ej. porque se
+// cae la unidad de red) no tiene por qué empezar de cero: cada nodo generado
+// se apunta en un fichero de control local, fuera del proyecto, y -resume
+// salta los ya apuntados y sigue por el primero sin terminar (los que
+// fallaron se reintentan). Los nodos saltados se leen de su __lists.ini para
+// que la comprobación de duplicados y el libro de puntos vean el lote
+// completo. El control solo vale para la misma ejecución: mismas opciones y
+// mismas reglas (rulesHash); si no, -resume se niega. Un lote que termina
+// bien borra su fichero.
+
+// CheckpointConfig configura los puntos de control de lotes.
+type CheckpointConfig struct {
+	// Dir es el directorio de los ficheros de control. Vacío =
+	// dnpgen/checkpoints en el directorio de configuración del usuario
+	// (%AppData% en Windows).
+	Dir string `yaml:"dir"`
+}
+
+func (c CheckpointConfig) dir() (string, error) {
+	if c.Dir != "" {
+		return os.ExpandEnv(c.Dir), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dnpgen", "checkpoints"), nil
+}
+
+// batchCheckpoint es el fichero de control de un lote.
+type batchCheckpoint struct {
+	Run     string           `json:"run"`     // proyecto (-all) o workspace
+	Options string           `json:"options"` // huella de opciones y reglas
+	Started time.Time        `json:"started"`
+	Done    []checkpointNode `json:"done"`
+
+	path string
+}
+
+// checkpointNode es un nodo terminado.
+type checkpointNode struct {
+	Project string    `json:"project"`
+	Node    string    `json:"node"`
+	At      time.Time `json:"at"`
+}
+
+// checkpointOptions es la huella de lo que decide el resultado de un lote.
+func checkpointOptions(base GenerateRequest) string {
+	data, _ := json.Marshal(struct {
+		SkipExt     bool     `json:"skip_ext"`
+		CheckOnly   bool     `json:"check_only"`
+		Incremental bool     `json:"incremental"`
+		OutDir      string   `json:"out_dir"`
+		Only        []string `json:"only"`
+		Rules       string   `json:"rules"`
+	}{base.SkipExt, base.CheckOnly, base.Incremental, base.OutDir, base.Only, rulesHash()})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// openCheckpoint prepara el control del lote run. Con resume continúa el
+// anterior, que debe ser de las mismas opciones (si no hay ninguno, empieza
+// de cero); sin resume empieza uno nuevo y descarta el que hubiera.
+func openCheckpoint(run string, base GenerateRequest, resume bool) (*batchCheckpoint, error) {
+	abs, err := filepath.Abs(normalizeLongPath(run))
+	if err != nil {
+		return nil, err
+	}
+	dir, err := GlobalConfig.App.Checkpoints.dir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(abs)))
+	cp := &batchCheckpoint{
+		Run:     abs,
+		Options: checkpointOptions(base),
+		Started: time.Now().UTC(),
+		Done:    []checkpointNode{},
+		path:    filepath.Join(dir, filepath.Base(abs)+"-"+hex.EncodeToString(sum[:6])+".json"),
+	}
+	if !resume {
+		if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return cp, nil
+	}
+	data, err := os.ReadFile(cp.path)
+	if os.IsNotExist(err) {
+		log.Printf(tr("No hay ninguna ejecución interrumpida de %s: se generan todos los nodos"), abs)
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var prev batchCheckpoint
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return nil, fmt.Errorf("%s: %v", cp.path, err)
+	}
+	if prev.Options != cp.Options {
+		return nil, fmt.Errorf(tr("la ejecución interrumpida de %s usaba otras opciones o reglas; ejecute sin -resume"), abs)
+	}
+	prev.path = cp.path
+	return &prev, nil
+}
+
+func checkpointKey(project, node string) string {
+	project = filepath.Clean(project)
+	return strings.ToLower(project) + "\x00" + strings.ToLower(node)
+}
+
+// done indica si el nodo ya se generó en este lote.
+func (cp *batchCheckpoint) done(project, node string) bool {
+	if cp == nil {
+		return false
+	}
+	k := checkpointKey(project, node)
+	for _, d := range cp.Done {
+		if checkpointKey(d.Project, d.Node) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// mark apunta un nodo terminado y guarda el fichero.
+func (cp *batchCheckpoint) mark(project, node string) error {
+	if cp == nil || cp.done(project, node) {
+		return nil
+	}
+	cp.Done = append(cp.Done, checkpointNode{Project: filepath.Clean(project), Node: node, At: time.Now().UTC()})
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cp.path, append(data, '\n'))
+}
+
+// close cierra el lote: si terminó sin errores borra el fichero; si no, lo
+// conserva y recuerda cómo continuar.
+func (cp *batchCheckpoint) close(ok bool) {
+	if cp == nil {
+		return
+	}
+	if !ok {
+		log.Printf(tr("[WARN] Lote incompleto: %d nodo(s) terminados; repita la orden con -resume para seguir por el primero sin terminar"), len(cp.Done))
+		return
+	}
+	if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+		log.Printf(tr("[WARN] No se pudo borrar el fichero de control %s: %v"), cp.path, err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- ESTADO DE COMUNICACIONES ---
+//
+// El master necesita un punto de estado de comunicación por cada equipo que
+// cuelga de la RTU (PLC, medidores, variadores). Los equipos se reconocen
+// por el nombre de sus variables en el .SIG: comm_health.devices son regex
+// cuyo grupo "device" (o el primero) es el nombre del equipo, y por cada
+// equipo distinto se añade un punto <equipo>_COMM_OK a la lista de
+// diagnóstico comm_health.list, una de extra_lists. Como en los derivados,
+// la variable de estado tiene que existir en el programa de la RTU; dnpgen
+// solo la mapea.
+
+// CommHealthConfig configura los puntos de estado de comunicación.
+type CommHealthConfig struct {
+	Enabled bool             `yaml:"enabled"`
+	List    string           `yaml:"list"` // lista de extra_lists de destino
+	Devices []CommDeviceRule `yaml:"devices"`
+}
+
+// CommDeviceRule reconoce los equipos por sus variables.
+type CommDeviceRule struct {
+	Pattern string `yaml:"pattern"` // regex sobre la variable, sin "@GV."
+	// Name y Desc admiten {device} y {node}; vacíos, "@GV.{device}_COMM_OK"
+	// y "Comunicación con {device}".
+	Name string `yaml:"name"`
+	Desc string `yaml:"desc"`
+	Type string `yaml:"type"` // BOOL por defecto
+}
+
+// commHealthPoints añade a la lista de diagnóstico un punto por equipo, en
+// el orden en que aparece su primera variable. Los equipos cuya variable de
+// estado ya está en el .SIG se omiten: el punto ya sale por clasificación.
+// Devuelve cuántos puntos añadió.
+func commHealthPoints(l *Lists, node string, cfg CommHealthConfig) (int, error) {
+	if !cfg.Enabled {
+		return 0, nil
+	}
+	var dest *CustomList
+	for i := range l.Extra {
+		if l.Extra[i].Name == strings.ToUpper(cfg.List) {
+			dest = &l.Extra[i]
+		}
+	}
+	if dest == nil {
+		return 0, fmt.Errorf(tr("comm_health: la lista %q no está en extra_lists"), cfg.List)
+	}
+	type compiled struct {
+		re    *regexp.Regexp
+		group int
+	}
+	rules := make([]compiled, len(cfg.Devices))
+	for i, d := range cfg.Devices {
+		re, err := regexp.Compile(d.Pattern)
+		if err != nil {
+			return 0, fmt.Errorf("comm_health.devices[%d]: %v", i, err)
+		}
+		group := re.SubexpIndex("device")
+		if group < 0 {
+			group = 1
+		}
+		if re.NumSubexp() < group {
+			return 0, fmt.Errorf(tr("comm_health.devices[%d]: el patrón necesita un grupo con el nombre del equipo"), i)
+		}
+		rules[i] = compiled{re, group}
+	}
+
+	existing := map[string]bool{}
+	var sources []Point
+	for _, s := range listSections(l) {
+		for _, p := range s.Items {
+			if p.Spare || p.System || p.Var == "" {
+				continue
+			}
+			existing[p.Var] = true
+			if s.Name != dest.Name {
+				sources = append(sources, p)
+			}
+		}
+	}
+
+	// Orden del .SIG, no de las listas: el índice no depende de en qué lista
+	// cayó la primera variable del equipo.
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].line < sources[j].line })
+	added := 0
+	for _, p := range sources {
+		for i, r := range rules {
+			m := r.re.FindStringSubmatch(p.Var)
+			if m == nil || m[r.group] == "" {
+				continue
+			}
+			d := cfg.Devices[i]
+			name, desc, typ := d.Name, d.Desc, d.Type
+			if name == "" {
+				name = "@GV.{device}_COMM_OK"
+			}
+			if desc == "" {
+				desc = tr("Comunicación con {device}")
+			}
+			if typ == "" {
+				typ = "BOOL"
+			}
+			vars := map[string]string{"device": m[r.group], "node": node}
+			name = expandTemplate(name, vars)
+			v := strings.TrimPrefix(name, "@GV.")
+			if !existing[v] {
+				existing[v] = true
+				dest.Items = append(dest.Items, Point{Name: name, Var: v, Type: typ, Desc: expandTemplate(desc, vars),
+					Rule: fmt.Sprintf("comm_health.devices[%d]", i)})
+				added++
+			}
+			break
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// --- COMPACTACIÓN DE SPARES ---
+
+// Políticas de compactación.
+const (
+	CompactTrailing = "trailing" // solo los spares al final de cada lista (no cambia índices)
+	CompactRuns     = "runs"     // recorta las rachas internas a -keep spares
+	CompactAll      = "all"      // elimina todos los spares
+)
+
+// indexRemap es una fila de la tabla de reasignación para el master. NewIndex
+// es -1 si el punto se eliminó.
+type indexRemap struct {
+	List               string
+	OldIndex, NewIndex int
+	Name               string
+}
+
+func runCompact(args []string) {
+	fs := flag.NewFlagSet("compact", flag.ExitOnError)
+	fs.Usage = manUsage("compact", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (usa su __lists.ini)"))
+	listsPath := fs.String("lists", "", tr("Ruta directa a un __lists.ini (alternativa a -path/-node)"))
+	policy := fs.String("policy", CompactTrailing, tr("Política: trailing, runs o all"))
+	keep := fs.Int("keep", 0, tr("Spares que se conservan de cada racha interna (policy runs)"))
+	remapPath := fs.String("remap", "", tr("Fichero CSV para la tabla de reasignación (vacío = salida estándar)"))
+	dryRun := fs.Bool("dry-run", false, tr("No reescribir el fichero de listas"))
+	fs.Parse(args)
+
+	file := *listsPath
+	if file == "" {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe compact -path \"C:\\Ruta\" -node \"NombreNodo\" [-policy trailing|runs|all]"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		file = listsPathFor(abs, *nodeName)
+	}
+	switch *policy {
+	case CompactTrailing, CompactRuns, CompactAll:
+	default:
+		log.Fatalf(tr("[FATAL] Política desconocida %q"), *policy)
+	}
+
+	lists, err := readListsFile(file)
+	if err != nil {
+		log.Fatalf(tr("[FATAL] Error leyendo %s: %v"), file, err)
+	}
+	markConfiguredSpares(lists)
+
+	var remap []indexRemap
+	for _, t := range []struct {
+		name  string
+		items *[]Point
+	}{{"AI", &lists.AI}, {"AO", &lists.AO}, {"DI", &lists.DI}, {"DO", &lists.DO}} {
+		before := len(*t.items)
+		var rows []indexRemap
+		*t.items, rows = compactList(t.name, *t.items, *policy, *keep)
+		remap = append(remap, rows...)
+		log.Printf(tr("%s: %d -> %d entradas"), t.name, before, len(*t.items))
+	}
+
+	out := io.Writer(os.Stdout)
+	if *remapPath != "" {
+		f, err := os.Create(*remapPath)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeRemapCSV(out, remap); err != nil {
+		log.Fatalf(tr("[FATAL] Tabla de reasignación: %v"), err)
+	}
+
+	if *dryRun {
+		log.Printf(tr("Simulación: %s no se modifica"), file)
+		return
+	}
+	if err := writeFileAtomic(file, withListHeader(renderLists(lists))); err != nil {
+		log.Fatalf(tr("[FATAL] Error escribiendo %s: %v"), file, err)
+	}
+	log.Printf(tr("%s compactado (política %s)"), filepath.Base(file), *policy)
+}
+
+// markConfiguredSpares marca como spare las líneas que corresponden a la
+// estrategia de spares de su lista (nombre fijo sin "(VAR)", numerados o
+// etiquetas reservadas); readListsFile solo reconoce la forma con paréntesis.
+func markConfiguredSpares(l *Lists) {
+	for _, t := range []struct {
+		list  string
+		items []Point
+	}{{"AI", l.AI}, {"AO", l.AO}, {"DI", l.DI}, {"DO", l.DO}} {
+		isSpare := spareMatcher(t.list)
+		for i := range t.items {
+			if isSpare(t.items[i].Name) {
+				t.items[i].Spare = true
+			}
+		}
+	}
+}
+
+// compactList aplica la política a una lista y devuelve la lista resultante
+// junto con las filas de reasignación de los índices que cambian.
+func compactList(list string, items []Point, policy string, keep int) ([]Point, []indexRemap) {
+	drop := make([]bool, len(items))
+	last := len(items) - 1
+	for last >= 0 && items[last].Spare {
+		drop[last] = true
+		last--
+	}
+	switch policy {
+	case CompactAll:
+		for i := 0; i <= last; i++ {
+			drop[i] = items[i].Spare
+		}
+	case CompactRuns:
+		run := 0
+		for i := 0; i <= last; i++ {
+			if !items[i].Spare {
+				run = 0
+				continue
+			}
+			run++
+			drop[i] = run > keep
+		}
+	}
+
+	var out []Point
+	var rows []indexRemap
+	for i, p := range items {
+		if drop[i] {
+			rows = append(rows, indexRemap{list, i, -1, p.Name})
+			continue
+		}
+		if len(out) != i {
+			rows = append(rows, indexRemap{list, i, len(out), p.Name})
+		}
+		out = append(out, p)
+	}
+	if out == nil {
+		out = []Point{}
+	}
+	return out, rows
+}
+
+func writeRemapCSV(out io.Writer, rows []indexRemap) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"list", "old_index", "new_index", "name"})
+	for _, r := range rows {
+		newIndex := strconv.Itoa(r.NewIndex)
+		if r.NewIndex < 0 {
+			newIndex = ""
+		}
+		w.Write([]string{r.List, strconv.Itoa(r.OldIndex), newIndex, r.Name})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("csv: %v", err)
+	}
+	return nil
+}
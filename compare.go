@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// --- COMPARACIÓN DE NODOS (COMPARE-NODES) ---
+//
+// Las parejas de RTU redundantes tienen que exponer el mismo mapa DNP3.
+// "dnpgen compare-nodes A B" clasifica los dos nodos (o lee dos modelos de
+// export-model) y compara cada variable: en qué lista e índice está y con
+// qué tipo, SOE, escalado y objeto DNP3. Sale con código 1 si difieren.
+
+// NodeComparison es el resultado de comparar dos nodos.
+type NodeComparison struct {
+	A         string            `json:"a"`
+	B         string            `json:"b"`
+	Common    int               `json:"common"` // variables idénticas en ambos
+	OnlyA     []PointPlacement  `json:"only_a,omitempty"`
+	OnlyB     []PointPlacement  `json:"only_b,omitempty"`
+	Different []PointDifference `json:"different,omitempty"`
+	Lengths   []ListLengths     `json:"lengths,omitempty"` // listas de distinta longitud
+}
+
+// PointPlacement es una variable y sus posiciones, p.ej. "DI[3]".
+type PointPlacement struct {
+	Var    string   `json:"var"`
+	Places []string `json:"places"`
+}
+
+// PointDifference es una variable presente en los dos nodos con otra
+// posición o atributos.
+type PointDifference struct {
+	Var     string   `json:"var"`
+	A       []string `json:"a"`
+	B       []string `json:"b"`
+	Details []string `json:"details,omitempty"`
+}
+
+// ListLengths es el número de entradas de una lista en cada nodo.
+type ListLengths struct {
+	List string `json:"list"`
+	A    int    `json:"a"`
+	B    int    `json:"b"`
+}
+
+// Identical indica si los dos mapas son iguales.
+func (c *NodeComparison) Identical() bool {
+	return len(c.OnlyA) == 0 && len(c.OnlyB) == 0 && len(c.Different) == 0 && len(c.Lengths) == 0
+}
+
+// placedPoint es un punto del modelo con el nombre de su lista.
+type placedPoint struct {
+	list string
+	ModelPoint
+}
+
+// modelVariables agrupa los puntos reales (sin spares) de un modelo por
+// variable, en orden de lista e índice.
+func modelVariables(m *PointModel) map[string][]placedPoint {
+	vars := map[string][]placedPoint{}
+	for _, l := range m.Lists {
+		for _, p := range l.Points {
+			if !p.Spare {
+				vars[p.Var] = append(vars[p.Var], placedPoint{l.Name, p})
+			}
+		}
+	}
+	return vars
+}
+
+func placeOf(p placedPoint) string {
+	return fmt.Sprintf("%s[%d]", p.list, p.Index)
+}
+
+func placesOf(points []placedPoint) []string {
+	places := make([]string, len(points))
+	for i, p := range points {
+		places[i] = placeOf(p)
+	}
+	return places
+}
+
+// pointDetails describe en qué difieren dos puntos en la misma posición.
+func pointDetails(a, b placedPoint) []string {
+	var d []string
+	at := placeOf(a)
+	if a.Type != b.Type {
+		d = append(d, trf("%s: tipo %s ≠ %s", at, a.Type, b.Type))
+	}
+	if a.SOE != b.SOE {
+		d = append(d, trf("%s: SOE %t ≠ %t", at, a.SOE, b.SOE))
+	}
+	if !reflect.DeepEqual(a.Scaling, b.Scaling) {
+		d = append(d, trf("%s: escalado distinto", at))
+	}
+	if !reflect.DeepEqual(a.Object, b.Object) {
+		d = append(d, trf("%s: objeto DNP3 distinto", at))
+	}
+	return d
+}
+
+// compareModels compara los mapas de dos nodos.
+func compareModels(a, b *PointModel) *NodeComparison {
+	c := &NodeComparison{A: a.Node, B: b.Node}
+	va, vb := modelVariables(a), modelVariables(b)
+	names := make([]string, 0, len(va)+len(vb))
+	for v := range va {
+		names = append(names, v)
+	}
+	for v := range vb {
+		if _, ok := va[v]; !ok {
+			names = append(names, v)
+		}
+	}
+	sort.Strings(names)
+
+	for _, v := range names {
+		pa, inA := va[v]
+		pb, inB := vb[v]
+		switch {
+		case !inB:
+			c.OnlyA = append(c.OnlyA, PointPlacement{Var: v, Places: placesOf(pa)})
+		case !inA:
+			c.OnlyB = append(c.OnlyB, PointPlacement{Var: v, Places: placesOf(pb)})
+		default:
+			diff := PointDifference{Var: v, A: placesOf(pa), B: placesOf(pb)}
+			if reflect.DeepEqual(diff.A, diff.B) {
+				for i := range pa {
+					diff.Details = append(diff.Details, pointDetails(pa[i], pb[i])...)
+				}
+				if len(diff.Details) == 0 {
+					c.Common++
+					continue
+				}
+			}
+			c.Different = append(c.Different, diff)
+		}
+	}
+
+	lengths := map[string][2]int{}
+	var order []string
+	for i, m := range []*PointModel{a, b} {
+		for _, l := range m.Lists {
+			n, ok := lengths[l.Name]
+			if !ok {
+				order = append(order, l.Name)
+			}
+			n[i] = len(l.Points)
+			lengths[l.Name] = n
+		}
+	}
+	for _, name := range order {
+		if n := lengths[name]; n[0] != n[1] {
+			c.Lengths = append(c.Lengths, ListLengths{List: name, A: n[0], B: n[1]})
+		}
+	}
+	return c
+}
+
+// printComparison escribe el informe legible de compare-nodes.
+func printComparison(c *NodeComparison) {
+	fmt.Println(boldText(trf("\n--- %s frente a %s ---", c.A, c.B)))
+	fmt.Println(trf("Variables idénticas: %d", c.Common))
+	if len(c.Lengths) > 0 {
+		var rows [][]string
+		for _, l := range c.Lengths {
+			rows = append(rows, []string{l.List, fmt.Sprint(l.A), fmt.Sprint(l.B)})
+		}
+		fmt.Println(warnText(tr("Listas de distinta longitud:")))
+		printTable(tr("LISTA")+"\t"+c.A+"\t"+c.B, rows, nil)
+	}
+	for _, only := range []struct {
+		node   string
+		points []PointPlacement
+	}{{c.A, c.OnlyA}, {c.B, c.OnlyB}} {
+		if len(only.points) == 0 {
+			continue
+		}
+		fmt.Println(warnText(trf("Solo en %s (%d):", only.node, len(only.points))))
+		for _, p := range only.points {
+			fmt.Printf("  %s  %s\n", p.Var, strings.Join(p.Places, ", "))
+		}
+	}
+	if len(c.Different) > 0 {
+		fmt.Println(warnText(trf("Clasificadas distinto (%d):", len(c.Different))))
+		for _, d := range c.Different {
+			if len(d.Details) == 0 {
+				fmt.Printf("  %s  %s: %s  %s: %s\n", d.Var, c.A, strings.Join(d.A, ", "), c.B, strings.Join(d.B, ", "))
+				continue
+			}
+			for _, detail := range d.Details {
+				fmt.Printf("  %s  %s\n", d.Var, detail)
+			}
+		}
+	}
+	if c.Identical() {
+		fmt.Println(okText(trf("%s y %s exponen el mismo mapa", c.A, c.B)))
+	} else {
+		fmt.Println(errText(trf("%s y %s exponen mapas distintos", c.A, c.B)))
+	}
+}
+
+// loadNodeModel devuelve el modelo de arg: un modelo de export-model (.json o
+// .yaml) si es un fichero, o el nodo arg del proyecto clasificado en memoria.
+func loadNodeModel(arg, projectPath string, skipExt bool) (*PointModel, error) {
+	switch strings.ToLower(filepath.Ext(arg)) {
+	case ".json", ".yaml", ".yml":
+		if fileExists(arg) {
+			return readModel(arg)
+		}
+	}
+	if projectPath == "" {
+		return nil, fmt.Errorf(tr("%s: falta -path para clasificar el nodo"), arg)
+	}
+	res, err := runGenerate(GenerateRequest{ProjectPath: projectPath, NodeName: arg, SkipExt: skipExt, CheckOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", arg, err)
+	}
+	abs, _ := filepath.Abs(projectPath)
+	return buildModel(ExportContext{Project: abs, Node: arg, Lists: res.lists, Sig: res.SigFile}), nil
+}
+
+// runCompareNodes implementa "dnpgen compare-nodes".
+func runCompareNodes(args []string) {
+	fs := flag.NewFlagSet("compare-nodes", flag.ExitOnError)
+	fs.Usage = manUsage("compare-nodes", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto o copia .zip"))
+	skipExt := fs.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	format := fs.String("format", "text", tr("Formato del informe: text o json"))
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatal(tr("Uso: dnpgen.exe compare-nodes -path \"C:\\Ruta\" NodoA NodoB | modeloA.json modeloB.json"))
+	}
+	var models [2]*PointModel
+	for i, arg := range fs.Args() {
+		m, err := loadNodeModel(arg, *projectPath, *skipExt)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		models[i] = m
+	}
+
+	c := compareModels(models[0], models[1])
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(c); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	case "text":
+		printComparison(c)
+	default:
+		log.Fatalf(tr("formato %q no soportado (text, json)"), *format)
+	}
+	if !c.Identical() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// --- AUTOCOMPLETADO DE LA CONSOLA ---
+
+// completionCommands describe los subcomandos y sus flags para los scripts
+// de autocompletado. "" es la generación normal. Al añadir un flag a un
+// subcomando hay que añadirlo también aquí.
+var completionCommands = []struct {
+	Name  string
+	Flags []string
+	Args  []string // argumentos posicionales fijos
+}{
+	{"", []string{"path", "node", "skip-ext", "all", "workspace", "out", "incremental", "only", "resume", "version"}, nil},
+	{"generate", []string{"path", "node", "skip-ext", "all", "workspace", "out", "incremental", "only", "resume"}, nil},
+	{"server", []string{"addr", "grpc-addr", "queue-dir", "log", "workers", "queue-limit"}, nil},
+	{"service", nil, []string{"install", "uninstall", "run"}},
+	{"simulate", []string{"path", "node", "lists", "addr", "address"}, nil},
+	{"verify", []string{"path", "node", "lists", "host", "port", "address", "master"}, nil},
+	{"query", []string{"path", "db", "name", "list", "node", "from", "to", "spares"}, nil},
+	{"nodes", []string{"path", "json"}, nil},
+	{"bench", []string{"signals", "runs", "sig"}, nil},
+	{"gen-fixture", []string{"out", "project", "node", "digits", "areas", "desc", "shuffle", "seed", "lf",
+		"ai", "ao", "di", "do", "strings", "unknown",
+		"name-ai", "name-ao", "name-di", "name-do", "name-strings", "name-unknown"}, nil},
+	{"compact", []string{"path", "node", "lists", "policy", "keep", "remap", "dry-run"}, nil},
+	{"update", []string{"url", "check", "force"}, nil},
+	{"export-model", []string{"path", "node", "skip-ext", "format", "o", "anonymize"}, nil},
+	{"render", []string{"model", "out", "exports", "write-lists", "anonymize"}, nil},
+	{"compare-nodes", []string{"path", "skip-ext", "format"}, nil},
+	{"scaffold", []string{"type", "o", "force", "types"}, nil},
+	{"rename", []string{"path", "node", "map", "skip-ext", "report", "dry-run", "force"}, nil},
+	{"check-roundtrip", []string{"path", "node", "lists", "sigext"}, nil},
+	{"analyze", []string{"path", "node", "all", "incremental", "json"}, nil},
+	{"spares", []string{"path", "node", "all", "out", "json"}, nil},
+	{"golden", []string{"path", "node", "all", "dir", "exports", "update", "json"}, nil},
+	{"selftest", []string{"keep", "json"}, nil},
+	{"gui", []string{"path", "addr", "no-browser"}, nil},
+	{"preflight", []string{"path", "node", "all", "out", "report", "json"}, nil},
+	{"migrate", []string{"path", "node", "all", "out", "apply", "json"}, nil},
+	{"pair", []string{"path", "pair", "skip-ext", "check", "json"}, nil},
+	{"rules", []string{"path", "node", "sig", "strict", "format", "all", "out", "old", "new", "json"}, []string{"lint", "names", "impact"}},
+	{"completion", nil, []string{"bash", "zsh", "powershell"}},
+	{"errors", nil, []string{"list", "explain"}},
+	{"config", []string{"o", "from"}, []string{"schema"}},
+	{"stats", []string{"since", "project", "json"}, nil},
+	{"perf", []string{"path", "node", "last", "threshold", "json"}, []string{"report"}},
+	{"post-build", []string{"path", "node", "skip-ext", "out", "result", "json"}, nil},
+	{"bundle", []string{"path", "node", "o", "dest", "out", "force"}, []string{"export", "import", "run"}},
+	{"verify-artifacts", []string{"path", "node", "manifest", "public-key", "allow-unsigned", "json", "keygen"}, nil},
+	{"help", nil, []string{"generate", "server", "service", "simulate", "verify", "query", "nodes", "gen-fixture", "bench",
+		"rules", "export-model", "render", "scaffold", "rename", "compare-nodes", "pair", "check-roundtrip", "analyze", "spares", "golden", "selftest", "gui", "preflight", "migrate", "compact", "update",
+		"completion", "errors", "config", "stats", "perf", "post-build", "verify-artifacts", "bundle", "examples"}},
+	{"examples", nil, []string{"node", "batch", "ci", "server"}},
+}
+
+// completionGlobalFlags valen en cualquier subcomando (ver extractGlobalFlags).
+var completionGlobalFlags = []string{"lang", "no-color", "firmware"}
+
+// completionValues indica cómo completar el valor de cada flag: "dir" y
+// "file" delegan en el shell, "nodes" consulta los nodos del proyecto y el
+// resto son listas fijas.
+var completionValues = map[string]string{
+	"path":      "dir",
+	"out":       "dir",
+	"project":   "dir",
+	"queue-dir": "dir",
+	"lists":     "file",
+	"workspace": "file",
+	"sig":       "file",
+	"db":        "file",
+	"remap":     "file",
+	"map":       "file",
+	"report":    "file",
+	"log":       "file",
+	"model":     "file",
+	"manifest":  "file",
+	"dest":      "dir",
+	"o":         "file",
+	"node":      "nodes",
+	"lang":      "es en",
+	"firmware":  "cwm-05 cwm-06 cwm-07",
+	"type":      "metering-station pumping-station tank-farm valve-station",
+	"list":      "DI DO AI AO",
+	"format":    "json yaml",
+	"policy":    strings.Join([]string{CompactTrailing, CompactRuns, CompactAll}, " "),
+}
+
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		log.Fatal("Uso: dnpgen.exe completion bash|zsh|powershell")
+	}
+	var tpl string
+	switch args[0] {
+	case "bash":
+		tpl = bashCompletion
+	case "zsh":
+		tpl = zshCompletion + bashCompletion
+	case "powershell":
+		tpl = powershellCompletion
+	default:
+		log.Fatalf("[FATAL] Shell no soportado %q (bash, zsh, powershell)", args[0])
+	}
+	if err := template.Must(template.New(args[0]).Parse(tpl)).Execute(os.Stdout, completionData()); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+}
+
+type completionCommand struct {
+	Name, Flags, Args string
+}
+
+type completionValue struct {
+	Flag, Kind string
+}
+
+func completionData() any {
+	var cmds []completionCommand
+	var subs []string
+	for _, c := range completionCommands {
+		var flags []string
+		for _, f := range append(c.Flags, completionGlobalFlags...) {
+			flags = append(flags, "-"+f)
+		}
+		cmds = append(cmds, completionCommand{c.Name, strings.Join(flags, " "), strings.Join(c.Args, " ")})
+		if c.Name != "" {
+			subs = append(subs, c.Name)
+		}
+	}
+	var values []completionValue
+	for f, kind := range completionValues {
+		values = append(values, completionValue{f, kind})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Flag < values[j].Flag })
+	return map[string]any{
+		"Commands":    cmds,
+		"Subcommands": strings.Join(subs, " "),
+		"Values":      values,
+	}
+}
+
+// runCompleteHelper es el subcomando oculto que usan los scripts para los
+// valores dinámicos: "__complete nodes <ruta>" lista los nodos del proyecto.
+func runCompleteHelper(args []string) {
+	if len(args) < 1 || args[0] != "nodes" {
+		return
+	}
+	project := "."
+	if len(args) > 1 && args[1] != "" {
+		project = args[1]
+	}
+	abs, err := filepath.Abs(project)
+	if err != nil {
+		return
+	}
+	nodes, _ := listProjectNodes(abs)
+	for _, n := range nodes {
+		fmt.Println(n.Node)
+	}
+}
+
+const bashCompletion = `# Autocompletado bash de dnpgen: source <(dnpgen completion bash)
+_dnpgen() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local sub="" path="" i w
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        w="${COMP_WORDS[i]}"
+        case "$w" in
+            -path|--path) path="${COMP_WORDS[i+1]}" ;;
+            -path=*|--path=*) path="${w#*=}" ;;
+            -lang|--lang|-firmware|--firmware) ((i++)) ;;
+            -*) ;;
+            *) [[ -z "$sub" && " {{.Subcommands}} " == *" $w "* ]] && sub="$w" ;;
+        esac
+    done
+
+    case "${prev#-}" in
+{{- range .Values}}
+        {{.Flag}}|-{{.Flag}})
+{{- if eq .Kind "dir"}}
+            COMPREPLY=($(compgen -d -- "$cur")); return ;;
+{{- else if eq .Kind "file"}}
+            COMPREPLY=($(compgen -f -- "$cur")); return ;;
+{{- else if eq .Kind "nodes"}}
+            COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" __complete nodes "$path" 2>/dev/null)" -- "$cur")); return ;;
+{{- else}}
+            COMPREPLY=($(compgen -W "{{.Kind}}" -- "$cur")); return ;;
+{{- end}}
+{{- end}}
+    esac
+
+    local flags="" words=""
+    case "$sub" in
+{{- range .Commands}}
+        "{{.Name}}") flags="{{.Flags}}"; words="{{.Args}}" ;;
+{{- end}}
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ -z "$sub" ]]; then
+        COMPREPLY=($(compgen -W "{{.Subcommands}}" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "$words" -- "$cur"))
+    fi
+}
+complete -o default -F _dnpgen dnpgen dnpgen.exe
+`
+
+const zshCompletion = `#compdef dnpgen dnpgen.exe
+# Autocompletado zsh de dnpgen: source <(dnpgen completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+`
+
+const powershellCompletion = `# Autocompletado PowerShell de dnpgen: dnpgen completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName dnpgen, dnpgen.exe -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '') { $words = $words[0..($words.Count - 2)] }
+    $prev = if ($words.Count -gt 1) { $words[-1].TrimStart('-') } else { '' }
+
+    $subcommands = '{{.Subcommands}}' -split ' '
+    $sub = ''
+    $path = ''
+    for ($i = 1; $i -lt $words.Count; $i++) {
+        $w = $words[$i]
+        if ($w -match '^--?path$' -and $i + 1 -lt $words.Count) { $path = $words[$i + 1] }
+        elseif ($w -match '^--?(lang|firmware)$') { $i++ }
+        elseif ($sub -eq '' -and $subcommands -contains $w) { $sub = $w }
+    }
+
+    $candidates = $null
+    switch ($prev) {
+{{- range .Values}}
+{{- if eq .Kind "dir" "file"}}
+        '{{.Flag}}' { return }
+{{- else if eq .Kind "nodes"}}
+        '{{.Flag}}' { $candidates = & $words[0] __complete nodes $path 2>$null }
+{{- else}}
+        '{{.Flag}}' { $candidates = '{{.Kind}}' -split ' ' }
+{{- end}}
+{{- end}}
+    }
+    if ($null -eq $candidates) {
+        $flags = ''
+        $positional = ''
+        switch ($sub) {
+{{- range .Commands}}
+            '{{.Name}}' { $flags = '{{.Flags}}'; $positional = '{{.Args}}' }
+{{- end}}
+        }
+        if ($wordToComplete.StartsWith('-')) { $candidates = $flags -split ' ' }
+        elseif ($sub -eq '') { $candidates = $subcommands }
+        elseif ($positional -ne '') { $candidates = $positional -split ' ' }
+        else { return }
+    }
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// --- SALIDA DE CONSOLA (FLAGS GLOBALES Y COLORES) ---
+
+// globalFlags son los flags que valen para cualquier subcomando. Se extraen
+// de os.Args antes de despachar, así "dnpgen -no-color nodes ..." funciona
+// igual que "dnpgen nodes -no-color ...", y la ayuda de -h ya sale en el
+// idioma elegido.
+var globalFlags struct {
+	Lang     string
+	NoColor  bool
+	Firmware string
+}
+
+// extractGlobalFlags quita -lang y -no-color (con uno o dos guiones) de args.
+func extractGlobalFlags(args []string) []string {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		name, value, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if !strings.HasPrefix(a, "-") {
+			rest = append(rest, a)
+			continue
+		}
+		switch name {
+		case "lang":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			globalFlags.Lang = value
+		case "firmware":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			globalFlags.Firmware = value
+		case "no-color":
+			globalFlags.NoColor = !hasValue || value != "false"
+		default:
+			rest = append(rest, a)
+		}
+	}
+	return rest
+}
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// colorStdout y colorStderr indican si cada salida admite colores: solo en
+// consola, sin NO_COLOR (https://no-color.org) ni -no-color.
+var colorStdout, colorStderr bool
+
+func initColor() {
+	if os.Getenv("NO_COLOR") != "" || globalFlags.NoColor {
+		return
+	}
+	colorStdout = isTerminal(os.Stdout) && enableVirtualTerminal(os.Stdout)
+	colorStderr = isTerminal(os.Stderr) && enableVirtualTerminal(os.Stderr)
+	if colorStderr {
+		setLogOutput(levelWriter{os.Stderr})
+	}
+}
+
+func paint(enabled bool, code, s string) string {
+	if !enabled || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Atajos para la salida estándar (resúmenes e informes).
+func okText(s string) string   { return paint(colorStdout, ansiGreen, s) }
+func warnText(s string) string { return paint(colorStdout, ansiYellow, s) }
+func errText(s string) string  { return paint(colorStdout, ansiRed, s) }
+func boldText(s string) string { return paint(colorStdout, ansiBold, s) }
+
+// levelWriter colorea las líneas del log según su etiqueta.
+type levelWriter struct{ w io.Writer }
+
+func (lw levelWriter) Write(p []byte) (int, error) {
+	code := ""
+	switch {
+	case bytes.Contains(p, []byte("[FATAL]")), bytes.Contains(p, []byte("[ERROR]")):
+		code = ansiRed
+	case bytes.Contains(p, []byte("[WARN]")):
+		code = ansiYellow
+	}
+	if code == "" {
+		return lw.w.Write(p)
+	}
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	if _, err := io.WriteString(lw.w, code+string(line)+ansiReset+"\n"); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// printTable escribe una tabla alineada con la cabecera en negrita. Los
+// colores se aplican después de alinear para que los códigos ANSI no
+// descuadren las columnas.
+func printTable(header string, rows [][]string, rowColor func(i int) func(string) string) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	io.WriteString(tw, header+"\n")
+	for _, r := range rows {
+		io.WriteString(tw, strings.Join(r, "\t")+"\n")
+	}
+	tw.Flush()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			line = boldText(line)
+		case rowColor != nil:
+			if c := rowColor(i - 1); c != nil {
+				line = c(line)
+			}
+		}
+		os.Stdout.WriteString(line + "\n")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+// enableVirtualTerminal no hace nada fuera de Windows: los terminales
+// interpretan los códigos ANSI directamente.
+func enableVirtualTerminal(*os.File) bool { return true }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- PAREJAS TRIP/CLOSE DE SALIDAS DIGITALES ---
+//
+// Los mandos de interruptor usan dos variables, V_TRIP y V_CLOSE, pero el
+// master los envía a un único punto DNP3 (CROB) con el código trip o close.
+// classification.control_pairs define las parejas por patrón: de cada
+// variable de DO que casa trip o close se quita la parte que casa y las dos
+// con la misma base se funden en un solo punto de DO, en el índice de la
+// primera, que lleva la variable de keep y las dos en Pair. La otra sale de
+// DO junto con su espejo en DI. Las que quedan sin pareja dan un hallazgo
+// control_pair (warning).
+
+// ControlPairRule es una regla de parejas trip/close.
+type ControlPairRule struct {
+	Trip  string `yaml:"trip"`  // regex sobre la variable, p.ej. "_TRIP$"
+	Close string `yaml:"close"` // regex sobre la variable, p.ej. "_CLOSE$"
+	// Keep es la variable que queda como punto: trip (por defecto) o close.
+	Keep string `yaml:"keep"`
+}
+
+// ControlPair es la pareja fundida en un punto de DO.
+type ControlPair struct {
+	Trip  string `json:"trip"`
+	Close string `json:"close"`
+}
+
+type compiledPair struct {
+	rule            string
+	tripRe, closeRe *regexp.Regexp
+	keepTrip        bool
+	trips, closes   map[string]int // base → índice en DO
+}
+
+func compileControlPairs(rules []ControlPairRule) ([]*compiledPair, error) {
+	var out []*compiledPair
+	for i, r := range rules {
+		label := "control_pairs[" + strconv.Itoa(i) + "]"
+		c := &compiledPair{rule: label, trips: map[string]int{}, closes: map[string]int{}}
+		if r.Trip == "" || r.Close == "" {
+			return nil, fmt.Errorf(tr("%s: faltan trip o close"), label)
+		}
+		var err error
+		if c.tripRe, err = regexp.Compile(r.Trip); err != nil {
+			return nil, fmt.Errorf("%s.trip: %v", label, err)
+		}
+		if c.closeRe, err = regexp.Compile(r.Close); err != nil {
+			return nil, fmt.Errorf("%s.close: %v", label, err)
+		}
+		switch strings.ToLower(r.Keep) {
+		case "", "trip":
+			c.keepTrip = true
+		case "close":
+		default:
+			return nil, fmt.Errorf(tr("%s: keep desconocido %q (trip o close)"), label, r.Keep)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// pairBase es la variable sin la parte que casa; "" si no casa.
+func pairBase(re *regexp.Regexp, v string) string {
+	loc := re.FindStringIndex(v)
+	if loc == nil {
+		return ""
+	}
+	return v[:loc[0]] + v[loc[1]:]
+}
+
+// pairControls funde las parejas trip/close de DO. Devuelve las parejas
+// fundidas y los mensajes de las variables sin pareja.
+func pairControls(l *Lists, rules []ControlPairRule) (int, []string, error) {
+	pairs, err := compileControlPairs(rules)
+	if err != nil || len(pairs) == 0 {
+		return 0, nil, err
+	}
+	// Cada variable va a la primera regla que la reconoce.
+	for i, p := range l.DO {
+		if p.Spare || p.System || p.Var == "" {
+			continue
+		}
+		for _, c := range pairs {
+			if b := pairBase(c.tripRe, p.Var); b != "" {
+				c.trips[b] = i
+				break
+			}
+			if b := pairBase(c.closeRe, p.Var); b != "" {
+				c.closes[b] = i
+				break
+			}
+		}
+	}
+
+	drop := map[int]bool{} // índices de DO que salen
+	var unpaired []string
+	merged := 0
+	for _, c := range pairs {
+		for base, ti := range c.trips {
+			ci, ok := c.closes[base]
+			if !ok {
+				unpaired = append(unpaired, trf("%s: %s sin su close", c.rule, l.DO[ti].Var))
+				continue
+			}
+			first, other := min(ti, ci), max(ti, ci)
+			kept := l.DO[ti]
+			if !c.keepTrip {
+				kept = l.DO[ci]
+			}
+			kept.Pair = &ControlPair{Trip: l.DO[ti].Var, Close: l.DO[ci].Var}
+			kept.Rule = c.rule
+			l.DO[first] = kept
+			drop[other] = true
+			merged++
+		}
+		for base, ci := range c.closes {
+			if _, ok := c.trips[base]; !ok {
+				unpaired = append(unpaired, trf("%s: %s sin su trip", c.rule, l.DO[ci].Var))
+			}
+		}
+	}
+	sort.Strings(unpaired)
+	if len(drop) == 0 {
+		return 0, unpaired, nil
+	}
+
+	// El espejo de la variable que sale es la entrada de DI que procede de la
+	// misma línea del .SIG.
+	lines := map[int]bool{}
+	do := make([]Point, 0, len(l.DO)-len(drop))
+	for i, p := range l.DO {
+		if drop[i] {
+			lines[p.line] = true
+			continue
+		}
+		do = append(do, p)
+	}
+	l.DO = do
+	di := make([]Point, 0, len(l.DI))
+	for _, p := range l.DI {
+		if p.line > 0 && lines[p.line] && strings.HasPrefix(p.Rule, "mirror:") {
+			continue
+		}
+		di = append(di, p)
+	}
+	l.DI = di
+	return merged, unpaired, nil
+}
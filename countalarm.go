@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- ALARMA POR CAÍDA DE PUNTOS ---
+
+// CountAlarmConfig compara el número de puntos reales de cada lista con la
+// generación anterior del nodo. Una caída brusca casi siempre es un .SIG mal
+// exportado (SIGEXT cortado, recurso equivocado), no un cambio real, y en las
+// regeneraciones programadas nadie mira el resumen.
+type CountAlarmConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	DropPercent float64 `yaml:"drop_percent"` // caída relativa mínima; 20 por defecto
+	DropMin     int     `yaml:"drop_min"`     // caída absoluta mínima; 10 por defecto
+	// Fail hace fallar la generación sin sobrescribir las listas.
+	Fail bool `yaml:"fail"`
+}
+
+func (c CountAlarmConfig) dropPercent() float64 {
+	if c.DropPercent > 0 {
+		return c.DropPercent
+	}
+	return 20
+}
+
+func (c CountAlarmConfig) dropMin() int {
+	if c.DropMin > 0 {
+		return c.DropMin
+	}
+	return 10
+}
+
+// CountAlarm es una lista cuyo número de puntos reales cayó por encima de
+// los umbrales.
+type CountAlarm struct {
+	List   string `json:"list"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
+
+func (a CountAlarm) String() string {
+	return fmt.Sprintf("%s %d -> %d", a.List, a.Before, a.After)
+}
+
+// pointCounts es el recuento guardado tras cada generación.
+type pointCounts struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Counts      map[string]int `json:"counts"`
+}
+
+func countsPath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".counts.json")
+}
+
+// countRealPoints cuenta los puntos que no son spare de cada lista.
+func countRealPoints(l *Lists) *pointCounts {
+	c := &pointCounts{GeneratedAt: stampNow(), Counts: map[string]int{}}
+	for _, s := range listSections(l) {
+		n := 0
+		for _, p := range s.Items {
+			if !p.Spare {
+				n++
+			}
+		}
+		c.Counts[s.Name] = n
+	}
+	return c
+}
+
+// checkCountDrop compara cur con el recuento guardado del nodo. Sin recuento
+// previo no hay alarma.
+func checkCountDrop(outDir, node string, cur *pointCounts) ([]CountAlarm, error) {
+	data, err := os.ReadFile(countsPath(outDir, node))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var prev pointCounts
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return nil, fmt.Errorf("%s: %v", countsPath(outDir, node), err)
+	}
+	cfg := GlobalConfig.App.CountAlarm
+	var alarms []CountAlarm
+	names := make([]string, 0, len(prev.Counts))
+	for name := range prev.Counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		before, after := prev.Counts[name], cur.Counts[name]
+		drop := before - after
+		if drop >= cfg.dropMin() && float64(drop)*100 >= cfg.dropPercent()*float64(before) {
+			alarms = append(alarms, CountAlarm{List: name, Before: before, After: after})
+		}
+	}
+	return alarms, nil
+}
+
+func writeCounts(outDir, node string, c *pointCounts) error {
+	path := countsPath(outDir, node)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+func formatCountAlarms(alarms []CountAlarm) string {
+	parts := make([]string, len(alarms))
+	for i, a := range alarms {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ", ")
+}
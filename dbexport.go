@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// --- EXPORTACIÓN A BASE DE DATOS RELACIONAL (POSTGRESQL / MYSQL) ---
+
+// DatabaseConfig configura el volcado del modelo de puntos a una base externa
+// tras cada ejecución (p.ej. para el sistema de gestión de activos).
+type DatabaseConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Driver  string `yaml:"driver"` // postgres, mysql
+	DSN     string `yaml:"dsn"`
+	Table   string `yaml:"table"` // vacío = rtu_points
+}
+
+var sqlIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlDialect aísla las diferencias de SQL entre motores.
+type sqlDialect struct {
+	driver      string
+	createTable string
+	upsert      string
+	placeholder func(n int) string
+}
+
+func dialectFor(driver, table string) (*sqlDialect, error) {
+	cols := "project, node, list, idx, name, var, type, spare, run_id, generated_at"
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgx":
+		return &sqlDialect{
+			driver: "pgx",
+			createTable: `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	project      TEXT        NOT NULL,
+	node         TEXT        NOT NULL,
+	list         TEXT        NOT NULL,
+	idx          INTEGER     NOT NULL,
+	name         TEXT        NOT NULL,
+	var          TEXT        NOT NULL,
+	type         TEXT        NOT NULL,
+	spare        BOOLEAN     NOT NULL,
+	run_id       TEXT        NOT NULL,
+	generated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (project, node, list, idx)
+)`,
+			upsert: `INSERT INTO ` + table + ` (` + cols + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (project, node, list, idx) DO UPDATE SET
+	name = EXCLUDED.name, var = EXCLUDED.var, type = EXCLUDED.type, spare = EXCLUDED.spare,
+	run_id = EXCLUDED.run_id, generated_at = EXCLUDED.generated_at`,
+			placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		}, nil
+	case "mysql", "mariadb":
+		return &sqlDialect{
+			driver: "mysql",
+			createTable: `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	project      VARCHAR(255) NOT NULL,
+	node         VARCHAR(128) NOT NULL,
+	list         VARCHAR(16)  NOT NULL,
+	idx          INT          NOT NULL,
+	name         VARCHAR(255) NOT NULL,
+	var          VARCHAR(255) NOT NULL,
+	type         VARCHAR(32)  NOT NULL,
+	spare        BOOLEAN      NOT NULL,
+	run_id       VARCHAR(64)  NOT NULL,
+	generated_at DATETIME     NOT NULL,
+	PRIMARY KEY (project, node, list, idx)
+)`,
+			upsert: `INSERT INTO ` + table + ` (` + cols + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+	name = VALUES(name), var = VALUES(var), type = VALUES(type), spare = VALUES(spare),
+	run_id = VALUES(run_id), generated_at = VALUES(generated_at)`,
+			placeholder: func(int) string { return "?" },
+		}, nil
+	}
+	return nil, fmt.Errorf("driver de base de datos desconocido %q", driver)
+}
+
+// exportToDatabase hace upsert de los puntos del nodo y elimina los que ya no
+// existen (índices de una ejecución anterior más larga).
+func exportToDatabase(project, node string, l *Lists) error {
+	cfg := GlobalConfig.App.Database
+	table := cfg.Table
+	if table == "" {
+		table = "rtu_points"
+	}
+	if !sqlIdentRe.MatchString(table) {
+		return fmt.Errorf("nombre de tabla inválido %q", table)
+	}
+	d, err := dialectFor(cfg.Driver, table)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(d.driver, cfg.DSN)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if _, err := db.Exec(d.createTable); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(d.upsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	runID := newJobID()
+	for _, s := range listSections(l) {
+		for idx, p := range s.Items {
+			if _, err := stmt.Exec(project, node, s.Name, idx, p.Name, p.Var, p.Type, p.Spare, runID, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	cleanup := fmt.Sprintf(`DELETE FROM %s WHERE project = %s AND node = %s AND run_id <> %s`,
+		table, d.placeholder(1), d.placeholder(2), d.placeholder(3))
+	if _, err := tx.Exec(cleanup, project, node, runID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
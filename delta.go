@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- GENERACIÓN INCREMENTAL (DELTA DEL SIG) ---
+
+// IncrementalConfig guarda, tras cada generación, una instantánea del modelo
+// de puntos y compara la siguiente con ella. La salida sigue siendo completa:
+// lo incremental es el informe, para que la revisión escale con el cambio.
+type IncrementalConfig struct {
+	Enabled bool   `yaml:"enabled"` // equivale a -incremental en cada ejecución
+	Dir     string `yaml:"dir"`     // vacío = <salida>/.dnpgen; relativo a la salida
+}
+
+// sigSnapshot es la instantánea de una generación.
+type sigSnapshot struct {
+	Generator   string          `json:"generator"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	SigSHA256   string          `json:"sig_sha256"`
+	Points      []snapshotPoint `json:"points"`
+}
+
+// snapshotPoint es una variable con todas sus posiciones (lista:índice).
+type snapshotPoint struct {
+	Var       string   `json:"var"`
+	Type      string   `json:"type,omitempty"`
+	Desc      string   `json:"desc,omitempty"`
+	Positions []string `json:"positions"`
+}
+
+// PointChange es un punto añadido, eliminado o modificado.
+type PointChange struct {
+	Var    string `json:"var"`
+	Kind   string `json:"kind"` // added, removed, changed, moved
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// SigDelta resume los cambios respecto a la generación anterior.
+type SigDelta struct {
+	First      bool          `json:"first,omitempty"`       // no había instantánea previa
+	SigChanged bool          `json:"sig_changed"`           // el hash del .SIG cambió
+	Previous   time.Time     `json:"previous,omitempty"`    // fecha de la instantánea previa
+	Changes    []PointChange `json:"changes,omitempty"`     // ordenados por tipo y variable
+	ReportFile string        `json:"report_file,omitempty"` // <nodo>.delta.csv
+}
+
+func (d *SigDelta) count(kind string) int {
+	n := 0
+	for _, c := range d.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func snapshotDir(outDir string) string {
+	dir := GlobalConfig.App.Incremental.Dir
+	switch {
+	case dir == "":
+		return filepath.Join(outDir, ".dnpgen")
+	case filepath.IsAbs(dir):
+		return dir
+	}
+	return filepath.Join(outDir, dir)
+}
+
+func snapshotPath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".snapshot.json")
+}
+
+// takeSnapshot construye la instantánea de los puntos reales.
+func takeSnapshot(sigFile string, l *Lists) *sigSnapshot {
+	s := &sigSnapshot{Generator: generatorLine(), GeneratedAt: stampNow(), SigSHA256: describeFile(sigFile).SHA256}
+	index := map[string]int{}
+	for _, sec := range listSections(l) {
+		for idx, p := range sec.Items {
+			if p.Spare {
+				continue
+			}
+			pos := sec.Name + ":" + strconv.Itoa(idx)
+			if i, ok := index[p.Var]; ok {
+				s.Points[i].Positions = append(s.Points[i].Positions, pos)
+				continue
+			}
+			index[p.Var] = len(s.Points)
+			s.Points = append(s.Points, snapshotPoint{Var: p.Var, Type: p.Type, Desc: p.Desc, Positions: []string{pos}})
+		}
+	}
+	return s
+}
+
+func readSnapshot(path string) (*sigSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s sigSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &s, nil
+}
+
+func writeSnapshot(path string, s *sigSnapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// diffSnapshots compara dos instantáneas. Un punto "changed" cambió de tipo,
+// descripción o lista; "moved" solo cambió de índice dentro de sus listas.
+func diffSnapshots(prev, cur *sigSnapshot) *SigDelta {
+	d := &SigDelta{SigChanged: prev.SigSHA256 != cur.SigSHA256, Previous: prev.GeneratedAt}
+	before := map[string]snapshotPoint{}
+	for _, p := range prev.Points {
+		before[p.Var] = p
+	}
+	for _, p := range cur.Points {
+		old, ok := before[p.Var]
+		delete(before, p.Var)
+		switch {
+		case !ok:
+			d.Changes = append(d.Changes, PointChange{Var: p.Var, Kind: "added", After: describeSnapshotPoint(p)})
+		case old.Type != p.Type || old.Desc != p.Desc || listsOf(old) != listsOf(p):
+			d.Changes = append(d.Changes, PointChange{Var: p.Var, Kind: "changed", Before: describeSnapshotPoint(old), After: describeSnapshotPoint(p)})
+		case strings.Join(old.Positions, " ") != strings.Join(p.Positions, " "):
+			d.Changes = append(d.Changes, PointChange{Var: p.Var, Kind: "moved", Before: strings.Join(old.Positions, " "), After: strings.Join(p.Positions, " ")})
+		}
+	}
+	for _, p := range before {
+		d.Changes = append(d.Changes, PointChange{Var: p.Var, Kind: "removed", Before: describeSnapshotPoint(p)})
+	}
+	order := map[string]int{"added": 0, "removed": 1, "changed": 2, "moved": 3}
+	sort.Slice(d.Changes, func(i, j int) bool {
+		a, b := d.Changes[i], d.Changes[j]
+		if a.Kind != b.Kind {
+			return order[a.Kind] < order[b.Kind]
+		}
+		return a.Var < b.Var
+	})
+	return d
+}
+
+func listsOf(p snapshotPoint) string {
+	var names []string
+	for _, pos := range p.Positions {
+		name, _, _ := strings.Cut(pos, ":")
+		names = append(names, name)
+	}
+	return strings.Join(names, " ")
+}
+
+func describeSnapshotPoint(p snapshotPoint) string {
+	s := strings.Join(p.Positions, " ") + " " + p.Type
+	if p.Desc != "" {
+		s += " \"" + p.Desc + "\""
+	}
+	return s
+}
+
+// computeDelta compara el modelo actual con la instantánea del nodo.
+func computeDelta(outDir, node string, cur *sigSnapshot) (*SigDelta, error) {
+	prev, err := readSnapshot(snapshotPath(outDir, node))
+	if os.IsNotExist(err) {
+		return &SigDelta{First: true, SigChanged: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return diffSnapshots(prev, cur), nil
+}
+
+// writeDeltaReport escribe <nodo>.delta.csv con los cambios.
+func writeDeltaReport(outDir, node string, d *SigDelta) (string, error) {
+	path := filepath.Join(outDir, node+".delta.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"kind", "var", "before", "after"})
+	for _, c := range d.Changes {
+		w.Write([]string{c.Kind, c.Var, c.Before, c.After})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// maxDeltaLines limita los cambios que se listan en consola; el resto está
+// en el informe CSV.
+const maxDeltaLines = 50
+
+func printDelta(d *SigDelta) {
+	switch {
+	case d.First:
+		fmt.Println(tr("\nIncremental: sin generación previa, se guarda la instantánea inicial"))
+		return
+	case len(d.Changes) == 0:
+		fmt.Println(okText(trf("\nIncremental: sin cambios desde %s", formatStamp(d.Previous))))
+		return
+	}
+	fmt.Println(boldText(trf("\nCambios desde %s: +%d -%d ~%d (índices movidos: %d)",
+		formatStamp(d.Previous), d.count("added"), d.count("removed"), d.count("changed"), d.count("moved"))))
+	marks := map[string]string{"added": "+", "removed": "-", "changed": "~", "moved": ">"}
+	for i, c := range d.Changes {
+		if i == maxDeltaLines {
+			fmt.Println(trf("  ... y %d más (ver %s)", len(d.Changes)-i, filepath.Base(d.ReportFile)))
+			break
+		}
+		detail := c.After
+		if c.Kind == "removed" {
+			detail = c.Before
+		} else if c.Before != "" {
+			detail = c.Before + " -> " + c.After
+		}
+		fmt.Printf("  %s %s  %s\n", marks[c.Kind], c.Var, detail)
+	}
+}
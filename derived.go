@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// --- PUNTOS DERIVADOS ---
+
+// DerivedPoint genera un punto calculado por cada variable que cumple
+// Pattern, p.ej. un contador de horas <VAR>_HOURS en AI por cada <VAR>_RUN.
+// La variable derivada tiene que existir en el programa de la RTU; dnpgen
+// solo la mapea.
+type DerivedPoint struct {
+	Pattern string `yaml:"pattern"` // regex sobre la variable de origen
+	From    string `yaml:"from"`    // lista de origen (AI, AO, DI, DO); vacío = todas
+	List    string `yaml:"list"`    // lista de destino
+	// Name es la línea del punto derivado: admite {var} (variable de
+	// origen, sin "@GV."), {list} (lista de origen) y {node}.
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	Desc string `yaml:"desc"` // admite las mismas variables que Name
+	// Range, si se indica, aparta los índices From-To de la lista de destino
+	// para los derivados (como app.reserved); los que sobran quedan como
+	// spares reservados. Sin rango se añaden al final de la lista.
+	Range *struct {
+		From int `yaml:"from"`
+		To   int `yaml:"to"`
+	} `yaml:"range"`
+}
+
+// derivedPoints añade a l los puntos derivados sin rango y devuelve como
+// reservas los que tienen rango, para aplicarlas junto con app.reserved.
+// Los derivados cuya variable ya está en la lista de destino se omiten: el
+// punto ya se mapeó a mano.
+func derivedPoints(l *Lists, node string, rules []DerivedPoint) ([]IndexReservation, error) {
+	targets := map[string]*[]Point{"AI": &l.AI, "AO": &l.AO, "DI": &l.DI, "DO": &l.DO}
+	var reservations []IndexReservation
+	for i, d := range rules {
+		label := fmt.Sprintf("derived_points[%d]", i)
+		re, err := regexp.Compile(d.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", label, err)
+		}
+		dest, ok := targets[strings.ToUpper(d.List)]
+		if !ok {
+			return nil, fmt.Errorf(tr("%s: lista de destino desconocida %q"), label, d.List)
+		}
+		if d.Name == "" {
+			return nil, fmt.Errorf(tr("%s: falta name"), label)
+		}
+		sources := []string{"AI", "AO", "DI", "DO"}
+		if d.From != "" {
+			if _, ok := targets[strings.ToUpper(d.From)]; !ok {
+				return nil, fmt.Errorf(tr("%s: lista de origen desconocida %q"), label, d.From)
+			}
+			sources = []string{strings.ToUpper(d.From)}
+		}
+
+		existing := map[string]bool{}
+		for _, p := range *dest {
+			existing[p.Var] = true
+		}
+		points := []Point{} // no nil: marca la reserva como de derivados
+		for _, src := range sources {
+			for _, p := range *targets[src] {
+				if p.Spare || p.System || !re.MatchString(p.Var) {
+					continue
+				}
+				vars := map[string]string{"var": p.Var, "list": src, "node": node}
+				name := expandTemplate(d.Name, vars)
+				v := strings.TrimPrefix(name, "@GV.")
+				if existing[v] {
+					continue
+				}
+				existing[v] = true
+				points = append(points, Point{Name: name, Var: v, Type: d.Type, Desc: expandTemplate(d.Desc, vars), Rule: label})
+			}
+		}
+
+		if d.Range == nil {
+			*dest = append(*dest, points...)
+			continue
+		}
+		if size := d.Range.To - d.Range.From + 1; len(points) > size {
+			return nil, fmt.Errorf(tr("%s: %d puntos derivados no caben en %s %d-%d"), label, len(points), strings.ToUpper(d.List), d.Range.From, d.Range.To)
+		}
+		reservations = append(reservations, IndexReservation{List: d.List, From: d.Range.From, To: d.Range.To, derived: points})
+	}
+	return reservations, nil
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- PAQUETE DE DIAGNÓSTICO ---
+//
+// Si la generación falla se ofrece un zip con lo que soporte suele pedir:
+// log de la ejecución, config.yaml sin secretos, salida de SIGEXT, el tramo
+// del .SIG con problemas y datos del entorno.
+
+// DiagnosticsConfig controla el paquete de diagnóstico.
+type DiagnosticsConfig struct {
+	OnFailure string `yaml:"on_failure"` // ask (por defecto), always, never
+	Dir       string `yaml:"dir"`        // vacío = directorio actual
+}
+
+const (
+	maxDiagLog     = 1 << 20 // el log se recorta por el principio
+	maxSigExcerpt  = 200     // líneas del .SIG sin diagnósticos
+	sigContextSize = 3       // líneas de contexto alrededor de cada diagnóstico
+)
+
+// runLog guarda una copia del log de la ejecución para el paquete.
+var runLog logCapture
+
+type logCapture struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (c *logCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf = append(c.buf, p...)
+	if over := len(c.buf) - maxDiagLog; over > 0 {
+		c.buf = c.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (c *logCapture) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.buf...)
+}
+
+// captureRunLog duplica el log hacia runLog, manteniendo la salida actual.
+func captureRunLog() {
+	setLogOutput(io.MultiWriter(logOutput, &runLog))
+}
+
+// lastSigExt es la última ejecución de SIGEXT (local o remota).
+var lastSigExt struct {
+	mu      sync.Mutex
+	command string
+	output  []byte
+	err     error
+}
+
+// recordSigExt guarda una ejecución de SIGEXT para el paquete de diagnóstico
+// y la añade a la transcripción de la generación (ver runSigExtRetry).
+func recordSigExt(transcript io.Writer, command string, output []byte, err error) {
+	lastSigExt.mu.Lock()
+	lastSigExt.command, lastSigExt.output, lastSigExt.err = command, output, err
+	lastSigExt.mu.Unlock()
+	fmt.Fprintf(transcript, "> %s\n", command)
+	transcript.Write(output)
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		fmt.Fprintln(transcript)
+	}
+}
+
+// offerDiagnostics pregunta (o decide según app.diagnostics.on_failure) si
+// se escribe el paquete tras un fallo de generación.
+func offerDiagnostics(req GenerateRequest, res *GenerateResult, runErr error) {
+	switch strings.ToLower(GlobalConfig.App.Diagnostics.OnFailure) {
+	case "never":
+		return
+	case "always":
+	default:
+		if !isTerminal(os.Stdin) {
+			return
+		}
+		answer := strings.ToLower(promptLine(tr("¿Generar paquete de diagnóstico para soporte? [s/N]: ")))
+		if answer != "s" && answer != "si" && answer != "sí" && answer != "y" && answer != "yes" {
+			return
+		}
+	}
+	path, err := writeDiagnostics(req, res, runErr)
+	if err != nil {
+		log.Printf(tr("[ERROR] Paquete de diagnóstico: %v"), err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, warnText(trf("Paquete de diagnóstico: %s", path)))
+}
+
+func writeDiagnostics(req GenerateRequest, res *GenerateResult, runErr error) (string, error) {
+	dir := GlobalConfig.App.Diagnostics.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("dnpgen-diag-%s-%s.zip", sanitizeFileName(req.NodeName), time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	zw := zip.NewWriter(f)
+	now := time.Now()
+	add := func(name string, data []byte) {
+		if err == nil {
+			var w io.Writer
+			if w, err = zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now}); err == nil {
+				_, err = w.Write(data)
+			}
+		}
+	}
+
+	add("error.txt", []byte(fmt.Sprintf("%v\n", runErr)))
+	add("run.log", runLog.Bytes())
+	if cfg, cerr := redactedConfig(); cerr == nil {
+		add("config.yaml", cfg)
+	} else {
+		add("config.yaml", []byte(fmt.Sprintf("# no se pudo serializar: %v\n", cerr)))
+	}
+	add("sigext.txt", sigExtReport())
+	add("environment.txt", environmentReport(req))
+	if abs, aerr := filepath.Abs(req.ProjectPath); aerr == nil {
+		sig := nodePathsFor(abs, req.NodeName).Sig
+		var diags []SigDiagnostic
+		if res != nil {
+			diags = res.SigDiagnostics
+		}
+		add("sig_excerpt.txt", sigExcerpt(sig, diags))
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?* `, r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func sigExtReport() []byte {
+	lastSigExt.mu.Lock()
+	defer lastSigExt.mu.Unlock()
+	if lastSigExt.command == "" {
+		return []byte("SIGEXT no se ejecutó en esta sesión (-skip-ext o fallo previo)\n")
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Comando: %s\nResultado: ", lastSigExt.command)
+	if lastSigExt.err != nil {
+		fmt.Fprintf(&b, "%v\n", lastSigExt.err)
+	} else {
+		b.WriteString("correcto\n")
+	}
+	b.WriteString("\n--- salida ---\n")
+	b.Write(lastSigExt.output)
+	return b.Bytes()
+}
+
+func environmentReport(req GenerateRequest) []byte {
+	var b bytes.Buffer
+	host, _ := os.Hostname()
+	wd, _ := os.Getwd()
+	exe, _ := os.Executable()
+	fmt.Fprintf(&b, "dnpgen: %s\n", buildInfo())
+	fmt.Fprintf(&b, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Fecha: %s\n", formatStamp(time.Now()))
+	fmt.Fprintf(&b, "Equipo: %s\nEjecutable: %s\nDirectorio: %s\n", host, exe, wd)
+	fmt.Fprintf(&b, "Argumentos: %q\n", os.Args[1:])
+	fmt.Fprintf(&b, "Proyecto: %s\nNodo: %s\nSkipExt: %v\n", req.ProjectPath, req.NodeName, req.SkipExt)
+	if abs, err := filepath.Abs(req.ProjectPath); err == nil {
+		paths := nodePathsFor(abs, req.NodeName)
+		for _, p := range []string{paths.Resource, paths.Mwt, paths.Sig} {
+			if st, err := os.Stat(p); err == nil {
+				fmt.Fprintf(&b, "  %s (%d bytes, %s)\n", p, st.Size(), formatStamp(st.ModTime()))
+			} else {
+				fmt.Fprintf(&b, "  %s: %v\n", p, err)
+			}
+		}
+	}
+	for _, k := range []string{"DNPGEN_LANG", "NO_COLOR", "WINEPREFIX", "PROCESSOR_ARCHITECTURE", "OS"} {
+		if v, ok := os.LookupEnv(k); ok {
+			fmt.Fprintf(&b, "%s=%s\n", k, v)
+		}
+	}
+	return b.Bytes()
+}
+
+// sigExcerpt devuelve las líneas del .SIG alrededor de cada diagnóstico, o
+// el principio del fichero si no los hay.
+func sigExcerpt(path string, diags []SigDiagnostic) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return []byte(fmt.Sprintf("%s: %v\n", path, err))
+	}
+	defer f.Close()
+
+	want := map[int]bool{}
+	for _, d := range diags {
+		for i := d.Line - sigContextSize; i <= d.Line+sigContextSize; i++ {
+			want[i] = true
+		}
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n", path)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), maxSigLine)
+	prev := 0
+	for n := 1; sc.Scan(); n++ {
+		if len(want) == 0 && n > maxSigExcerpt {
+			b.WriteString("...\n")
+			break
+		}
+		if len(want) > 0 && !want[n] {
+			continue
+		}
+		if prev != 0 && n != prev+1 {
+			b.WriteString("...\n")
+		}
+		fmt.Fprintf(&b, "%6d: %q\n", n, sc.Text())
+		prev = n
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintf(&b, "(lectura interrumpida: %v)\n", err)
+	}
+	return b.Bytes()
+}
+
+// redactedConfig serializa la configuración activa sin contraseñas, claves,
+// DSN ni URLs de webhooks (que suelen llevar el token en la ruta).
+func redactedConfig() ([]byte, error) {
+	return redactedYAML(GlobalConfig)
+}
+
+func redactedYAML(cfg Config) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(cfg); err != nil {
+		return nil, err
+	}
+	redactNode(&root, "")
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, err
+	}
+	// Última defensa por si un campo nuevo lleva una clave con otro nombre.
+	if bytes.Contains(out, []byte("PRIVATE KEY")) {
+		return nil, errors.New(tr("la configuración redactada aún contiene una clave privada"))
+	}
+	return out, nil
+}
+
+// secretKeys son las claves YAML cuyo valor nunca sale del equipo.
+var secretKeys = map[string]bool{
+	"password": true, "passphrase": true, "dsn": true, "token": true,
+	"secret_key": true, "access_key": true, "private_key": true, "api_key": true,
+	"salt": true, // con la sal de app.anonymize se comprueban nombres candidatos
+}
+
+// isSecretKey indica si el valor de la clave se sustituye por ***.
+func isSecretKey(key string) bool {
+	if secretKeys[key] {
+		return true
+	}
+	for _, suffix := range []string{"_password", "_passphrase", "_secret", "_token"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactNode(n *yaml.Node, parent string) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i].Value, n.Content[i+1]
+			if val.Kind == yaml.ScalarNode && val.Value != "" {
+				switch {
+				case isSecretKey(key):
+					val.Value = "***"
+				case key == "url" && parent == "targets":
+					val.Value = redactURL(val.Value)
+				case parent == "headers":
+					val.Value = "***" // tokens de sig_source
+				}
+			}
+			redactNode(val, key)
+		}
+		return
+	}
+	for _, c := range n.Content {
+		redactNode(c, parent) // elementos de una secuencia: conservan la clave padre
+	}
+}
+
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
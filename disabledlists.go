@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- LISTAS DESACTIVADAS ---
+//
+// Un nodo de telemetría sin mandos no necesita DO ni AO, pero la lógica de
+// espejo les añade un spare por cada entrada. app.disabled_lists desactiva
+// listas para todos los nodos (default) o para uno (nodes): la lista no se
+// llena, sus espejos (spares y estados de AO) desaparecen de la lista
+// opuesta, las reservas que apuntan a ella se ignoran y su sección *LIST no
+// se escribe ni se exporta. Una señal que las reglas llevan a una lista
+// desactivada no se genera: el hallazgo disabled_list (error por defecto)
+// avisa de que el .SIG no es el que se esperaba para el nodo.
+
+// DisabledListsConfig son las listas desactivadas por nodo.
+type DisabledListsConfig struct {
+	Default []string            `yaml:"default"` // AI, AO, DI, DO u OS
+	Nodes   map[string][]string `yaml:"nodes"`   // sustituye a default para un nodo
+}
+
+// forNode devuelve las listas desactivadas del nodo, validadas.
+func (c DisabledListsConfig) forNode(node string) (map[string]bool, error) {
+	lists := c.Default
+	for k, v := range c.Nodes {
+		if strings.EqualFold(k, node) {
+			lists = v
+		}
+	}
+	out := map[string]bool{}
+	for _, name := range lists {
+		list := strings.ToUpper(strings.TrimSpace(name))
+		switch list {
+		case "AI", "AO", "DI", "DO", "OS":
+			out[list] = true
+		default:
+			return nil, fmt.Errorf(tr("disabled_lists: lista desconocida %q (disponibles: AI, AO, DI, DO, OS)"), name)
+		}
+	}
+	if out["AI"] && out["AO"] && out["DI"] && out["DO"] {
+		return nil, fmt.Errorf(tr("disabled_lists: el nodo %s se queda sin listas DNP3"), node)
+	}
+	return out, nil
+}
+
+// disabledPoint es una señal que las reglas llevan a una lista desactivada.
+type disabledPoint struct {
+	List string
+	P    Point
+}
+
+func (d disabledPoint) String() string {
+	return trf("%s (línea %d del .SIG, %s) va a %s, desactivada en este nodo: no se genera", d.P.Var, d.P.line, pointRule(d.P), d.List)
+}
+
+// disableLists vacía las listas desactivadas recién clasificadas y quita de
+// la lista opuesta los espejos de sus puntos. Devuelve las señales que se
+// quedan fuera.
+func disableLists(l *Lists, rep *sigReport, disabled map[string]bool) []disabledPoint {
+	if len(disabled) == 0 {
+		return nil
+	}
+	l.disabled = disabled
+	var dropped []disabledPoint
+	for _, pair := range []struct {
+		a, b   string
+		as, bs *[]Point
+	}{{"AI", "AO", &l.AI, &l.AO}, {"DI", "DO", &l.DI, &l.DO}, {"AO", "AI", &l.AO, &l.AI}, {"DO", "DI", &l.DO, &l.DI}} {
+		if !disabled[pair.a] {
+			continue
+		}
+		// Con mirror el punto sigue en la lista opuesta: no se pierde.
+		both := map[int]bool{}
+		for _, p := range *pair.bs {
+			if p.Rule == "mirror:both" {
+				both[p.line] = true
+			}
+		}
+		lines := map[int]bool{}
+		for _, p := range *pair.as {
+			if !p.Spare && !p.AOS && p.Rule != "mirror:both" && !both[p.line] {
+				dropped = append(dropped, disabledPoint{pair.a, p})
+				lines[p.line] = true
+			}
+		}
+		*pair.as = []Point{}
+		kept := (*pair.bs)[:0]
+		for _, p := range *pair.bs {
+			if (p.Spare || p.AOS) && lines[p.line] {
+				continue
+			}
+			kept = append(kept, p)
+		}
+		*pair.bs = kept
+	}
+	if disabled["OS"] {
+		for _, p := range l.OS {
+			dropped = append(dropped, disabledPoint{"OS", p})
+		}
+		l.OS = []Point{}
+	}
+	// Sin una de las dos listas no hay simetría que comprobar.
+	asym := rep.Asymmetries[:0]
+	for _, a := range rep.Asymmetries {
+		if !disabled[a.In] && !disabled[a.Out] {
+			asym = append(asym, a)
+		}
+	}
+	rep.Asymmetries = asym
+	return dropped
+}
+
+// enabledReservations quita las reservas de las listas desactivadas.
+func enabledReservations(rs []IndexReservation, disabled map[string]bool) []IndexReservation {
+	if len(disabled) == 0 {
+		return rs
+	}
+	var out []IndexReservation
+	for _, r := range rs {
+		if !disabled[strings.ToUpper(r.List)] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
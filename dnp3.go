@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// --- PROTOCOLO DNP3 (CAPA DE ENLACE, TRANSPORTE Y APLICACIÓN) ---
+//
+// Implementación mínima, suficiente para el simulador de outstation y el modo
+// de verificación contra una RTU real: tramas FT3 con CRC, segmentación de
+// transporte y cabeceras de objeto estáticas.
+
+// Funciones de la capa de enlace.
+const (
+	linkDir = 0x80
+	linkPrm = 0x40
+
+	linkResetLinkStates = 0x00
+	linkTestLink        = 0x02
+	linkConfirmedData   = 0x03
+	linkUnconfirmedData = 0x04
+	linkRequestStatus   = 0x09
+
+	linkAck        = 0x00
+	linkLinkStatus = 0x0B
+)
+
+// Códigos de función de la capa de aplicación.
+const (
+	fcConfirm         = 0x00
+	fcRead            = 0x01
+	fcWrite           = 0x02
+	fcSelect          = 0x03
+	fcOperate         = 0x04
+	fcDirectOperate   = 0x05
+	fcDirectOperateNR = 0x06
+	fcEnableUnsol     = 0x14
+	fcDisableUnsol    = 0x15
+	fcDelayMeasure    = 0x17
+	fcResponse        = 0x81
+)
+
+// Bits de control de la capa de aplicación.
+const (
+	appFir = 0x80
+	appFin = 0x40
+	appCon = 0x20
+)
+
+// Bits de indicaciones internas (IIN).
+const (
+	iin1DeviceRestart = 0x80
+	iin2NoFuncSupport = 0x01
+	iin2ObjectUnknown = 0x02
+)
+
+const (
+	maxLinkData      = 250
+	maxTransportData = maxLinkData - 1
+	maxAppFragment   = 2048
+)
+
+// dnpCRC calcula el CRC-16 DNP (polinomio 0x3D65 reflejado).
+func dnpCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = (crc >> 1) ^ 0xA6BC
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return ^crc
+}
+
+func appendCRC(out, block []byte) []byte {
+	out = append(out, block...)
+	return binary.LittleEndian.AppendUint16(out, dnpCRC(block))
+}
+
+// linkFrame es una trama de enlace ya validada.
+type linkFrame struct {
+	Ctrl      byte
+	Dest, Src uint16
+	Data      []byte
+}
+
+func encodeLinkFrame(ctrl byte, dest, src uint16, data []byte) []byte {
+	hdr := []byte{0x05, 0x64, byte(5 + len(data)), ctrl, byte(dest), byte(dest >> 8), byte(src), byte(src >> 8)}
+	out := appendCRC(nil, hdr)
+	for i := 0; i < len(data); i += 16 {
+		out = appendCRC(out, data[i:min(i+16, len(data))])
+	}
+	return out
+}
+
+func readLinkFrame(r *bufio.Reader) (*linkFrame, error) {
+	// Sincroniza con el inicio de trama 0x05 0x64.
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != 0x05 {
+			continue
+		}
+		if next, err := r.Peek(1); err != nil {
+			return nil, err
+		} else if next[0] == 0x64 {
+			break
+		}
+	}
+	hdr := make([]byte, 10)
+	hdr[0] = 0x05
+	if _, err := io.ReadFull(r, hdr[1:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint16(hdr[8:]) != dnpCRC(hdr[:8]) {
+		return nil, errors.New("CRC de cabecera inválido")
+	}
+	if hdr[2] < 5 {
+		return nil, fmt.Errorf("longitud de trama inválida: %d", hdr[2])
+	}
+	f := &linkFrame{
+		Ctrl: hdr[3],
+		Dest: binary.LittleEndian.Uint16(hdr[4:]),
+		Src:  binary.LittleEndian.Uint16(hdr[6:]),
+	}
+	remaining := int(hdr[2]) - 5
+	for remaining > 0 {
+		n := min(remaining, 16)
+		block := make([]byte, n+2)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint16(block[n:]) != dnpCRC(block[:n]) {
+			return nil, errors.New("CRC de bloque inválido")
+		}
+		f.Data = append(f.Data, block[:n]...)
+		remaining -= n
+	}
+	return f, nil
+}
+
+// dnpConn es una asociación DNP3 sobre TCP, desde el punto de vista de una
+// estación local (master u outstation).
+type dnpConn struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	local    uint16
+	remote   uint16
+	master   bool
+	txSeq    byte
+	rxBuf    []byte
+	appSeq   byte
+	deadline time.Duration
+}
+
+func newDNPConn(conn net.Conn, local, remote uint16, master bool) *dnpConn {
+	return &dnpConn{conn: conn, r: bufio.NewReader(conn), local: local, remote: remote, master: master}
+}
+
+func (c *dnpConn) ctrl(fc byte, primary bool) byte {
+	ctrl := fc
+	if c.master {
+		ctrl |= linkDir
+	}
+	if primary {
+		ctrl |= linkPrm
+	}
+	return ctrl
+}
+
+func (c *dnpConn) writeLink(fc byte, primary bool, data []byte) error {
+	_, err := c.conn.Write(encodeLinkFrame(c.ctrl(fc, primary), c.remote, c.local, data))
+	return err
+}
+
+// sendFragment segmenta un fragmento de aplicación en la capa de transporte.
+func (c *dnpConn) sendFragment(frag []byte) error {
+	for i := 0; i == 0 || i < len(frag); i += maxTransportData {
+		end := min(i+maxTransportData, len(frag))
+		th := c.txSeq & 0x3F
+		if i == 0 {
+			th |= 0x40
+		}
+		if end == len(frag) {
+			th |= 0x80
+		}
+		c.txSeq++
+		if err := c.writeLink(linkUnconfirmedData, true, append([]byte{th}, frag[i:end]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFragment devuelve el siguiente fragmento de aplicación completo,
+// atendiendo por el camino los servicios de la capa de enlace.
+func (c *dnpConn) readFragment() ([]byte, error) {
+	for {
+		if c.deadline > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.deadline))
+		}
+		f, err := readLinkFrame(c.r)
+		if err != nil {
+			return nil, err
+		}
+		if f.Dest != c.local && f.Dest < 0xFFF0 {
+			continue
+		}
+		if !c.master {
+			c.remote = f.Src
+		}
+		if f.Ctrl&linkPrm == 0 {
+			continue // respuestas de enlace (ACK/LINK_STATUS) a nuestras peticiones
+		}
+		switch f.Ctrl & 0x0F {
+		case linkResetLinkStates, linkTestLink:
+			c.writeLink(linkAck, false, nil)
+			continue
+		case linkRequestStatus:
+			c.writeLink(linkLinkStatus, false, nil)
+			continue
+		case linkConfirmedData:
+			c.writeLink(linkAck, false, nil)
+		case linkUnconfirmedData:
+		default:
+			continue
+		}
+		if len(f.Data) == 0 {
+			continue
+		}
+		th := f.Data[0]
+		if th&0x40 != 0 {
+			c.rxBuf = c.rxBuf[:0]
+		}
+		c.rxBuf = append(c.rxBuf, f.Data[1:]...)
+		if th&0x80 != 0 {
+			frag := append([]byte(nil), c.rxBuf...)
+			c.rxBuf = c.rxBuf[:0]
+			if len(frag) >= 2 {
+				return frag, nil
+			}
+		}
+	}
+}
+
+// objectHeader es una cabecera de objeto de la capa de aplicación.
+type objectHeader struct {
+	Group, Variation, Qualifier byte
+	Start, Stop                 int
+	Count                       int
+	Data                        []byte // datos crudos de los objetos (si los hay)
+}
+
+// staticObjectSize devuelve el tamaño en bytes de cada objeto estático o de
+// comando soportado; 0 indica un objeto desconocido.
+func staticObjectSize(group, variation byte) int {
+	switch {
+	case group == 1 && variation == 2, group == 10 && variation == 2:
+		return 1
+	case group == 20 && variation == 1, group == 30 && variation == 1, group == 30 && variation == 5,
+		group == 40 && variation == 1, group == 40 && variation == 3, group == 41 && variation == 1,
+		group == 41 && variation == 3:
+		return 5
+	case group == 20 && variation == 2, group == 30 && variation == 2, group == 40 && variation == 2,
+		group == 41 && variation == 2:
+		return 3
+	case group == 20 && variation == 5, group == 30 && variation == 3:
+		return 4
+	case group == 20 && variation == 6, group == 30 && variation == 4:
+		return 2
+	case group == 30 && variation == 6, group == 40 && variation == 4, group == 41 && variation == 4:
+		return 9
+	case group == 12 && variation == 1:
+		return 11
+	case group == 52 && variation == 2:
+		return 2
+	case group == 110 || group == 111:
+		return int(variation) // cadenas de octetos: la variación es la longitud
+	}
+	return 0
+}
+
+// parseObjects recorre las cabeceras de objeto de un fragmento, incluidos los
+// objetos empaquetados g1v1/g10v1/g80v1.
+func parseObjects(data []byte) ([]objectHeader, error) {
+	var out []objectHeader
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return out, errors.New("cabecera de objeto truncada")
+		}
+		h := objectHeader{Group: data[0], Variation: data[1], Qualifier: data[2]}
+		data = data[3:]
+		prefix := 0
+		switch h.Qualifier {
+		case 0x06:
+		case 0x00, 0x01:
+			size := 1 + int(h.Qualifier)
+			if len(data) < 2*size {
+				return out, errors.New("rango truncado")
+			}
+			h.Start, h.Stop = readUint(data[:size]), readUint(data[size:2*size])
+			if h.Stop < h.Start {
+				return out, errors.New("rango inválido")
+			}
+			h.Count = h.Stop - h.Start + 1
+			data = data[2*size:]
+		case 0x07, 0x08, 0x17, 0x28:
+			size := 1
+			if h.Qualifier == 0x08 || h.Qualifier == 0x28 {
+				size = 2
+			}
+			if h.Qualifier == 0x17 {
+				prefix = 1
+			} else if h.Qualifier == 0x28 {
+				prefix = 2
+			}
+			if len(data) < size {
+				return out, errors.New("contador truncado")
+			}
+			h.Count = readUint(data[:size])
+			data = data[size:]
+		default:
+			return out, fmt.Errorf("calificador 0x%02X no soportado", h.Qualifier)
+		}
+
+		if h.Qualifier != 0x06 && !isClassOrAllVariation(h) {
+			n := objectDataLen(h, prefix)
+			if n < 0 {
+				return out, fmt.Errorf("objeto g%dv%d no soportado", h.Group, h.Variation)
+			}
+			if len(data) < n {
+				return out, fmt.Errorf("datos de g%dv%d truncados", h.Group, h.Variation)
+			}
+			h.Data, data = data[:n], data[n:]
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// isClassOrAllVariation indica cabeceras sin datos (variación 0 o clases g60).
+func isClassOrAllVariation(h objectHeader) bool {
+	return h.Variation == 0 || h.Group == 60
+}
+
+func objectDataLen(h objectHeader, prefix int) int {
+	if (h.Group == 1 || h.Group == 10 || h.Group == 80) && h.Variation == 1 {
+		if prefix != 0 {
+			return -1
+		}
+		return (h.Count + 7) / 8
+	}
+	size := staticObjectSize(h.Group, h.Variation)
+	if size == 0 {
+		return -1
+	}
+	return h.Count * (size + prefix)
+}
+
+func readUint(b []byte) int {
+	switch len(b) {
+	case 1:
+		return int(b[0])
+	case 2:
+		return int(binary.LittleEndian.Uint16(b))
+	}
+	return 0
+}
+
+// appendRangeHeader añade una cabecera con calificador 0x01 (inicio/fin de 16 bits).
+func appendRangeHeader(out []byte, group, variation byte, start, stop int) []byte {
+	out = append(out, group, variation, 0x01)
+	out = binary.LittleEndian.AppendUint16(out, uint16(start))
+	return binary.LittleEndian.AppendUint16(out, uint16(stop))
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- GRUPO Y VARIACIÓN DNP3 ---
+
+// DNP3Object es el grupo y la variación por defecto con que el master lee un
+// punto (p.ej. AI como grupo 30 variación 5, float) y, opcionalmente, la
+// variación de sus eventos.
+type DNP3Object struct {
+	Group          int `yaml:"group" json:"group"`
+	Variation      int `yaml:"variation" json:"variation"`
+	EventVariation int `yaml:"event_variation,omitempty" json:"event_variation,omitempty"`
+}
+
+func (o DNP3Object) String() string {
+	return fmt.Sprintf("g%dv%d", o.Group, o.Variation)
+}
+
+// DNP3ObjectRule asigna un objeto a los puntos de List que cumplen Type
+// (regex sobre el tipo SIG) y Pattern (regex sobre la variable); los vacíos
+// no filtran.
+type DNP3ObjectRule struct {
+	List       string `yaml:"list"`
+	Type       string `yaml:"type"`
+	Pattern    string `yaml:"pattern"`
+	DNP3Object `yaml:",inline"`
+}
+
+// DNP3ObjectsConfig asigna grupo y variación: la primera regla que cumple el
+// punto y, si ninguna, el valor por defecto de su lista. Los spares solo
+// toman el de la lista.
+type DNP3ObjectsConfig struct {
+	Defaults map[string]DNP3Object `yaml:"defaults"`
+	Rules    []DNP3ObjectRule      `yaml:"rules"`
+}
+
+// dnp3StaticGroups es el grupo de objetos estáticos de cada lista; un grupo
+// omitido en la configuración toma este valor.
+var dnp3StaticGroups = map[string]int{"DI": 1, "DO": 10, "AI": 30, "AO": 40, "OS": 110}
+
+func (c DNP3ObjectsConfig) enabled() bool {
+	return len(c.Defaults) > 0 || len(c.Rules) > 0
+}
+
+// resolve completa el grupo y comprueba que corresponde a la lista.
+func (o DNP3Object) resolve(list string) (DNP3Object, error) {
+	group := dnp3StaticGroups[list]
+	if o.Group == 0 {
+		o.Group = group
+	}
+	if o.Group != group {
+		return o, fmt.Errorf(tr("el grupo %d no corresponde a la lista %s (grupo %d)"), o.Group, list, group)
+	}
+	if o.Variation <= 0 {
+		return o, fmt.Errorf(tr("falta la variación del grupo %d"), o.Group)
+	}
+	return o, nil
+}
+
+// assignObjects fija Object en los puntos de AI, AO, DI, DO y OS.
+func assignObjects(l *Lists, cfg DNP3ObjectsConfig) error {
+	if !cfg.enabled() {
+		return nil
+	}
+	defaults := map[string]*DNP3Object{}
+	for list, o := range cfg.Defaults {
+		list = strings.ToUpper(list)
+		if _, ok := dnp3StaticGroups[list]; !ok {
+			return fmt.Errorf(tr("dnp3_objects.defaults: lista desconocida %q"), list)
+		}
+		r, err := o.resolve(list)
+		if err != nil {
+			return fmt.Errorf("dnp3_objects.defaults.%s: %v", list, err)
+		}
+		defaults[list] = &r
+	}
+	type compiled struct {
+		list         string
+		types, names *regexp.Regexp
+		object       DNP3Object
+	}
+	var rules []compiled
+	for i, r := range cfg.Rules {
+		label := "dnp3_objects.rules[" + strconv.Itoa(i) + "]"
+		c := compiled{list: strings.ToUpper(r.List)}
+		if _, ok := dnp3StaticGroups[c.list]; !ok {
+			return fmt.Errorf(tr("%s: lista desconocida %q"), label, r.List)
+		}
+		var err error
+		if c.object, err = r.DNP3Object.resolve(c.list); err != nil {
+			return fmt.Errorf("%s: %v", label, err)
+		}
+		if r.Type != "" {
+			if c.types, err = regexp.Compile(r.Type); err != nil {
+				return fmt.Errorf("%s: %v", label, err)
+			}
+		}
+		if r.Pattern != "" {
+			if c.names, err = regexp.Compile(r.Pattern); err != nil {
+				return fmt.Errorf("%s: %v", label, err)
+			}
+		}
+		rules = append(rules, c)
+	}
+
+	for _, s := range []listSection{{Name: "AI", Items: l.AI}, {Name: "AO", Items: l.AO}, {Name: "DI", Items: l.DI}, {Name: "DO", Items: l.DO}, {Name: "OS", Items: l.OS}} {
+		for i := range s.Items {
+			p := &s.Items[i]
+			p.Object = defaults[s.Name]
+			if p.Spare {
+				continue
+			}
+			for _, r := range rules {
+				if r.list == s.Name && (r.types == nil || r.types.MatchString(p.Type)) && (r.names == nil || r.names.MatchString(p.Var)) {
+					o := r.object
+					p.Object = &o
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// objectColumns devuelve grupo, variación y variación de eventos como texto
+// para las exportaciones tabulares; vacíos si el punto no tiene objeto.
+func objectColumns(p Point) (string, string, string) {
+	if p.Object == nil {
+		return "", "", ""
+	}
+	event := ""
+	if p.Object.EventVariation > 0 {
+		event = strconv.Itoa(p.Object.EventVariation)
+	}
+	return strconv.Itoa(p.Object.Group), strconv.Itoa(p.Object.Variation), event
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- CONFIGURACIÓN EFECTIVA POR EJECUCIÓN ---
+
+// configSource es el config.yaml que se cargó.
+var configSource string
+
+// writeEffectiveConfig escribe <nodo>.effective-config.yaml con la
+// configuración tal como se aplicó en esta ejecución (idioma resuelto
+// incluido), para poder repetir la generación aunque el config.yaml global
+// cambie después. Las opciones de línea de comandos van en la cabecera.
+// Contraseñas, claves y DSN se sustituyen por *** igual que en el zip de
+// diagnóstico.
+func writeEffectiveConfig(outDir string, req GenerateRequest) (string, error) {
+	cfg := GlobalConfig
+	cfg.App.Locale = locale
+	body, err := redactedYAML(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var opts []string
+	if req.SkipExt {
+		opts = append(opts, "-skip-ext")
+	}
+	if req.Incremental {
+		opts = append(opts, "-incremental")
+	}
+	if req.OutDir != "" {
+		opts = append(opts, fmt.Sprintf("-out %q", req.OutDir))
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Configuración efectiva de %s\n", req.NodeName)
+	fmt.Fprintf(&b, "# %s\n", generatorLine())
+	fmt.Fprintf(&b, "# Generado: %s\n", formatStamp(time.Now()))
+	fmt.Fprintf(&b, "# Origen: %s\n", configSource)
+	if len(opts) > 0 {
+		fmt.Fprintf(&b, "# Opciones: %s\n", strings.Join(opts, " "))
+	}
+	b.WriteString("# Contraseñas y DSN sustituidos por ***: restaurarlos antes de reutilizarlo.\n")
+	b.Write(body)
+
+	path := filepath.Join(outDir, req.NodeName+".effective-config.yaml")
+	return path, writeFileAtomic(path, b.Bytes())
+}
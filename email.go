@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- INFORME POR CORREO (SMTP) ---
+
+// EmailConfig configura el envío del informe al terminar una generación de
+// varios nodos (-all o -workspace): un resumen por nodo en el cuerpo y los
+// informes HTML/Excel adjuntos.
+type EmailConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"` // 587 por defecto (465 con tls: tls)
+	TLS      string   `yaml:"tls"`  // starttls (por defecto), tls o none
+	User     string   `yaml:"user"`
+	Password string   `yaml:"password"` // admite ${VAR} de entorno
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Cc       []string `yaml:"cc"`
+	// Subject admite {project}, {date}, {ok}, {total} y {status}.
+	Subject string `yaml:"subject"`
+	// Template es una plantilla html/template para el cuerpo; vacío = la
+	// tabla por defecto.
+	Template string   `yaml:"template"`
+	Attach   []string `yaml:"attach"` // exportadores adjuntados, p.ej. [html, xlsx]
+	On       []string `yaml:"on"`     // success, failure (vacío = ambos)
+}
+
+// emailReport son los datos de la plantilla del cuerpo.
+type emailReport struct {
+	Project    string
+	Date       string
+	OK         bool
+	Nodes      []emailNode
+	Duplicates []sharedPoint
+}
+
+type emailNode struct {
+	Node               string
+	Error              string
+	DI, DO, AI, AO, OS int
+	NameIssues         int
+	Drift, StaleSig    bool
+	CountAlarms        string
+	attachments        []string
+}
+
+func (r *emailReport) add(node string, res *GenerateResult, err error) {
+	n := emailNode{Node: node}
+	if err != nil {
+		n.Error = err.Error()
+		r.Nodes = append(r.Nodes, n)
+		return
+	}
+	n.DI, n.DO, n.AI, n.AO, n.OS = res.DI, res.DO, res.AI, res.AO, res.OS
+	n.NameIssues, n.Drift, n.StaleSig = len(res.NameIssues), res.Drift, res.StaleSig
+	n.CountAlarms = formatCountAlarms(res.CountAlarms)
+	for _, name := range GlobalConfig.App.Email.Attach {
+		e, ok := exporterRegistry[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		for _, a := range res.Artifacts {
+			if filepath.Base(a) == e.FileName(node) {
+				n.attachments = append(n.attachments, a)
+			}
+		}
+	}
+	r.Nodes = append(r.Nodes, n)
+}
+
+func (r *emailReport) okCount() int {
+	ok := 0
+	for _, n := range r.Nodes {
+		if n.Error == "" {
+			ok++
+		}
+	}
+	return ok
+}
+
+var emailDefaultTemplate = `<!DOCTYPE html>
+<html><body style="font-family:Segoe UI,Arial,sans-serif">
+<h2>Mapa de puntos DNP3 — {{.Project}}</h2>
+<p>{{.Date}}</p>
+<table border="1" cellspacing="0" cellpadding="4">
+<tr><th>Nodo</th><th>DI</th><th>DO</th><th>AI</th><th>AO</th><th>Nombres</th><th>Estado</th></tr>
+{{range .Nodes}}<tr>
+<td>{{.Node}}</td>{{if .Error}}<td colspan="5"></td><td style="color:#b00">{{.Error}}</td>{{else}}<td>{{.DI}}</td><td>{{.DO}}</td><td>{{.AI}}</td><td>{{.AO}}</td><td>{{.NameIssues}}</td>
+<td>{{if .CountAlarms}}<span style="color:#b00">Caída de puntos: {{.CountAlarms}}</span>{{else if .StaleSig}}.SIG desactualizado{{else if .Drift}}Deriva{{else}}OK{{end}}</td>{{end}}
+</tr>
+{{end}}</table>
+{{if .Duplicates}}<p style="color:#b00">Variables duplicadas entre nodos:</p>
+<ul>{{range .Duplicates}}<li>{{.Var}}: {{range $i, $n := .Nodes}}{{if $i}}, {{end}}{{$n}}{{end}}</li>{{end}}</ul>{{end}}
+<p style="color:#888">{{generator}}</p>
+</body></html>
+`
+
+// sendBatchEmail envía el informe de una generación por lotes si está
+// configurado. Los fallos solo se registran en el log.
+func sendBatchEmail(r *emailReport) {
+	cfg := GlobalConfig.App.Email
+	if !cfg.Enabled {
+		return
+	}
+	outcome := "failure"
+	if r.OK {
+		outcome = "success"
+	}
+	if len(cfg.On) > 0 && !containsFold(cfg.On, outcome) {
+		return
+	}
+	if err := sendEmail(cfg, r); err != nil {
+		log.Printf(tr("[WARN] Informe por correo: %v"), err)
+		return
+	}
+	log.Printf(tr("Informe enviado por correo a %s"), strings.Join(cfg.To, ", "))
+}
+
+func sendEmail(cfg EmailConfig, r *emailReport) error {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return errors.New(tr("se requieren host, from y to"))
+	}
+	msg, err := buildEmail(cfg, r)
+	if err != nil {
+		return err
+	}
+
+	mode := strings.ToLower(cfg.TLS)
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if mode == "tls" {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	conn, err := net.DialTimeout("tcp", addr, 30*time.Second)
+	if err != nil {
+		return err
+	}
+	tlsCfg := &tls.Config{ServerName: cfg.Host}
+	if mode == "tls" {
+		conn = tls.Client(conn, tlsCfg)
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if mode == "" || mode == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf(tr("%s no admite STARTTLS (use tls: none para enviar sin cifrar)"), addr)
+		}
+		if err := c.StartTLS(tlsCfg); err != nil {
+			return err
+		}
+	}
+	if cfg.User != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.User, os.ExpandEnv(cfg.Password), cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range append(append([]string{}, cfg.To...), cfg.Cc...) {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("%s: %v", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmail compone el mensaje MIME: cuerpo HTML y adjuntos en base64.
+func buildEmail(cfg EmailConfig, r *emailReport) ([]byte, error) {
+	src := emailDefaultTemplate
+	if cfg.Template != "" {
+		data, err := os.ReadFile(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("plantilla: %v", err)
+		}
+		src = string(data)
+	}
+	tpl, err := template.New("email").Funcs(template.FuncMap{"generator": generatorLine}).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("plantilla: %v", err)
+	}
+	var body bytes.Buffer
+	if err := tpl.Execute(&body, r); err != nil {
+		return nil, fmt.Errorf("plantilla: %v", err)
+	}
+
+	status := "OK"
+	if !r.OK {
+		status = "ERROR"
+	}
+	subject := cfg.Subject
+	if subject == "" {
+		subject = "Mapa de puntos {project}: {ok}/{total} nodos ({status})"
+	}
+	subject = expandTemplate(subject, map[string]string{
+		"project": filepath.Base(r.Project),
+		"date":    r.Date,
+		"ok":      strconv.Itoa(r.okCount()),
+		"total":   strconv.Itoa(len(r.Nodes)),
+		"status":  status,
+	})
+
+	var msg bytes.Buffer
+	mw := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	if len(cfg.Cc) > 0 {
+		fmt.Fprintf(&msg, "Cc: %s\r\n", strings.Join(cfg.Cc, ", "))
+	}
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(part, body.Bytes())
+
+	for _, n := range r.Nodes {
+		for _, a := range n.attachments {
+			data, err := os.ReadFile(a)
+			if err != nil {
+				return nil, err
+			}
+			ctype := mime.TypeByExtension(filepath.Ext(a))
+			if ctype == "" {
+				ctype = "application/octet-stream"
+			}
+			name := mime.QEncoding.Encode("utf-8", filepath.Base(a))
+			part, err := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {ctype + `; name="` + name + `"`},
+				"Content-Transfer-Encoding": {"base64"},
+				"Content-Disposition":       {`attachment; filename="` + name + `"`},
+			})
+			if err != nil {
+				return nil, err
+			}
+			writeBase64(part, data)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// writeBase64 escribe data en base64 con líneas de 76 caracteres (RFC 2045).
+func writeBase64(w io.Writer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		w.Write([]byte(enc[:76] + "\r\n"))
+		enc = enc[76:]
+	}
+	w.Write([]byte(enc + "\r\n"))
+}
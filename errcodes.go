@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// --- CATÁLOGO DE ERRORES ---
+//
+// Cada modo de fallo de la generación lleva un código estable (CW1001
+// SIG_NOT_FOUND...) que aparece al principio del mensaje, en los logs y en
+// el JSON del servidor y las notificaciones (error_code), para que los
+// scripts de soporte no dependan del texto ni del idioma.
+// "dnpgen errors explain CW1001" muestra la causa y cómo resolverlo.
+
+// Códigos de error. No se reutilizan ni se renumeran.
+const (
+	ErrSigNotFound       = "CW1001"
+	ErrResourceNotFound  = "CW1002"
+	ErrPreflightFailed   = "CW1003"
+	ErrSigParseFailed    = "CW1004"
+	ErrStaleSig          = "CW1005"
+	ErrProjectInvalid    = "CW1006"
+	ErrPartialInvalid    = "CW1007"
+	ErrMergeConflict     = "CW1008"
+	ErrConfigInvalid     = "CW1010"
+	ErrConfigNotFound    = "CW1011"
+	ErrConfigMalformed   = "CW1012"
+	ErrFindingsFailed    = "CW1020"
+	ErrCountDrop         = "CW1021"
+	ErrRoundTripMismatch = "CW1022"
+	ErrPairMismatch      = "CW1023"
+	ErrFrozenViolated    = "CW1024"
+	ErrWriteFailed       = "CW1030"
+	ErrExportFailed      = "CW1031"
+	ErrDatabaseFailed    = "CW1032"
+	ErrPublishFailed     = "CW1033"
+	ErrStateInvalid      = "CW1034"
+	ErrSigningFailed     = "CW1035"
+	ErrSigExtFailed      = "CW1040"
+	ErrSigSourceFailed   = "CW1041"
+	ErrQueueFull         = "CW1050"
+	ErrUnclassified      = "CW1999"
+)
+
+// errorInfo describe un código del catálogo.
+type errorInfo struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Remedy  string `json:"remedy"`
+}
+
+var errorCatalog = map[string]errorInfo{
+	ErrSigNotFound: {ErrSigNotFound, "SIG_NOT_FOUND", "No existe el .SIG del nodo",
+		"Ejecute SIGEXT (sin -skip-ext) o compruebe el nombre del nodo y app.sigext_paths; \"dnpgen nodes -path\" muestra qué nodos tienen .SIG."},
+	ErrResourceNotFound: {ErrResourceNotFound, "RESOURCE_NOT_FOUND", "No existe el recurso RTU del proyecto",
+		"Compruebe que -path es la raíz del proyecto de ControlWave (con C\\CWave_Micro\\R\\RTU_RESOURCE) o configure app.layout si la estructura es otra."},
+	ErrPreflightFailed: {ErrPreflightFailed, "PREFLIGHT_FAILED", "Fallaron las comprobaciones previas",
+		"Revise los problemas del mensaje: permisos de escritura, ficheros de solo lectura o bloqueados por ControlWave Designer y rutas inexistentes."},
+	ErrSigParseFailed: {ErrSigParseFailed, "SIG_PARSE_FAILED", "No se pudo leer o interpretar el .SIG",
+		"Regenere el .SIG con SIGEXT; si persiste, adjunte el .SIG y el paquete de diagnóstico al informe."},
+	ErrStaleSig: {ErrStaleSig, "STALE_SIG", "El .SIG es anterior al .mwt",
+		"Ejecute SIGEXT para regenerar el .SIG, o app.stale_sig: warn si usar el .SIG antiguo es intencionado."},
+	ErrProjectInvalid: {ErrProjectInvalid, "PROJECT_INVALID", "La ruta del proyecto o la copia .zip no es válida",
+		"Compruebe -path: tiene que existir y, si es un .zip, contener un proyecto con ficheros .SIG."},
+	ErrPartialInvalid: {ErrPartialInvalid, "PARTIAL_INVALID", "La regeneración parcial (-only) no es posible",
+		"Compruebe los nombres de -only (AI, AO, DI, DO, OS o las de extra_lists) y que existe el fichero de listas del que conservar las demás; si no, genere una vez completo."},
+	ErrMergeConflict: {ErrMergeConflict, "MERGE_CONFLICT", "Las ediciones a mano chocan con la generación",
+		"Ejecute en consola para resolver los conflictos uno a uno, o fije app.merge.conflicts (ours conserva la edición, theirs lo generado); la base es <salida>/.dnpgen/<nodo>.base.ini."},
+	ErrConfigInvalid: {ErrConfigInvalid, "CONFIG_INVALID", "Una sección de config.yaml no es válida",
+		"Corrija la sección citada en el mensaje (clasificación, reservas, áreas, objetos DNP3, nombres o findings); \"dnpgen rules lint\" revisa los patrones."},
+	ErrConfigNotFound: {ErrConfigNotFound, "CONFIG_NOT_FOUND", "No se encuentra config.yaml",
+		"Coloque config.yaml junto al ejecutable o en el directorio actual; \"dnpgen scaffold\" escribe uno inicial."},
+	ErrConfigMalformed: {ErrConfigMalformed, "CONFIG_MALFORMED", "config.yaml no es YAML válido",
+		"Corrija la línea indicada: sangría con espacios (no tabuladores) y comillas simples en las rutas con \\."},
+	ErrFindingsFailed: {ErrFindingsFailed, "FINDINGS_FAILED", "Hay hallazgos con severidad de fallo",
+		"Revise los hallazgos del mensaje y corríjalos, o ajuste app.findings.fail_on y app.findings.policy."},
+	ErrCountDrop: {ErrCountDrop, "COUNT_DROP", "Caída brusca de puntos respecto a la generación anterior",
+		"Compruebe que el .SIG está completo; si la caída es real, genere una vez sin app.count_alarm.fail o borre <salida>/.dnpgen/<nodo>.counts.json."},
+	ErrRoundTripMismatch: {ErrRoundTripMismatch, "ROUNDTRIP_MISMATCH", "__lists.ini no se relee igual que se generó",
+		"Busque nombres con caracteres no admitidos o códigos *LIST repetidos; \"dnpgen check-roundtrip\" detalla las diferencias."},
+	ErrPairMismatch: {ErrPairMismatch, "PAIR_MISMATCH", "Los dos nodos de una pareja redundante no exponen el mismo mapa DNP3",
+		"Revise las diferencias por índice del mensaje y app.redundancy (primary_only, standby_only, rename); no se escribe ninguno de los dos nodos hasta que coincidan."},
+	ErrFrozenViolated: {ErrFrozenViolated, "FROZEN_VIOLATED", "Un punto congelado ya no se puede conservar en su índice",
+		"El .SIG ya no trae el punto o las reglas lo llevan a otra lista: restaure la variable o la regla, o, si el cambio está aprobado, quite el punto del fichero de congelación (<salida>/<nodo>.freeze.yaml)."},
+	ErrWriteFailed: {ErrWriteFailed, "WRITE_FAILED", "No se pudo escribir un fichero de salida",
+		"Compruebe permisos y espacio libre en el directorio de salida y que ControlWave Designer no tiene abiertas las listas."},
+	ErrExportFailed: {ErrExportFailed, "EXPORT_FAILED", "Falló una exportación",
+		"Revise el exportador citado en el mensaje y su sección de config.yaml (exports, protocols)."},
+	ErrDatabaseFailed: {ErrDatabaseFailed, "DATABASE_FAILED", "Falló la base de puntos o la exportación a base de datos",
+		"Compruebe app.pointdb y app.database (ruta, DSN, credenciales) y que ningún otro proceso bloquea la base."},
+	ErrPublishFailed: {ErrPublishFailed, "PUBLISH_FAILED", "No se pudieron publicar los artefactos",
+		"Compruebe los destinos de app.publish.targets y su acceso; sin app.publish.required la generación no falla."},
+	ErrStateInvalid: {ErrStateInvalid, "STATE_INVALID", "Un fichero de estado de <salida>/.dnpgen está dañado",
+		"Borre el fichero citado (instantánea, recuento o mapa de índices) y la siguiente generación lo recrea; el mapa de índices pierde las retiradas pendientes."},
+	ErrSigningFailed: {ErrSigningFailed, "SIGNING_FAILED", "No se pudo firmar el manifiesto",
+		"Compruebe app.signing.private_key o private_key_file (Ed25519 en base64, hex o PEM); \"dnpgen verify-artifacts -keygen\" genera un par de claves."},
+	ErrSigExtFailed: {ErrSigExtFailed, "SIGEXT_FAILED", "SIGEXT terminó con error",
+		"Revise <nodo>.sigext.log, app.sigext_path y la licencia de ControlWave; con -skip-ext se usa el .SIG existente."},
+	ErrSigSourceFailed: {ErrSigSourceFailed, "SIG_SOURCE_FAILED", "No se pudo traer el .SIG del origen configurado",
+		"Revise <nodo>.sigext.log y el backend de app.sig_source (URL, token, paquete o comando); con -skip-ext se usa el .SIG existente."},
+	ErrQueueFull: {ErrQueueFull, "QUEUE_FULL", "La cola de trabajos del servidor está llena",
+		"Espere a que terminen los trabajos en curso o aumente -queue-limit."},
+	ErrUnclassified: {ErrUnclassified, "UNCLASSIFIED", "Error sin clasificar",
+		"Adjunte el log y el paquete de diagnóstico al informar del problema."},
+}
+
+// codedError es un error con su código del catálogo.
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.code + " " + errorCatalog[e.code].Name + ": " + e.err.Error()
+}
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode asigna un código a err. Un error que ya lo tiene lo conserva.
+func withCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var c *codedError
+	if errors.As(err, &c) {
+		return err
+	}
+	return &codedError{code, err}
+}
+
+// codedErrorf es fmt.Errorf con código.
+func codedErrorf(code, format string, args ...any) error {
+	return &codedError{code, fmt.Errorf(format, args...)}
+}
+
+// errorCode devuelve el código de err: ErrUnclassified si no tiene, "" si
+// err es nil.
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var c *codedError
+	if errors.As(err, &c) {
+		return c.code
+	}
+	return ErrUnclassified
+}
+
+// lookupError busca un código por número o por nombre (CW1001,
+// SIG_NOT_FOUND, 1001).
+func lookupError(key string) (errorInfo, bool) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if !strings.HasPrefix(key, "CW") {
+		if _, ok := errorCatalog["CW"+key]; ok {
+			key = "CW" + key
+		}
+	}
+	if info, ok := errorCatalog[key]; ok {
+		return info, true
+	}
+	for _, info := range errorCatalog {
+		if info.Name == key {
+			return info, true
+		}
+	}
+	return errorInfo{}, false
+}
+
+func errorCodes() []string {
+	codes := make([]string, 0, len(errorCatalog))
+	for code := range errorCatalog {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// runErrors implementa "dnpgen errors list [-json]" y "dnpgen errors
+// explain <código>".
+func runErrors(args []string) {
+	usage := tr("Uso: dnpgen.exe errors list [-json] | errors explain CW1001")
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+	switch args[0] {
+	case "list":
+		if len(args) > 1 && strings.TrimLeft(args[1], "-") == "json" {
+			var all []errorInfo
+			for _, code := range errorCodes() {
+				info := errorCatalog[code]
+				info.Summary, info.Remedy = tr(info.Summary), tr(info.Remedy)
+				all = append(all, info)
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(all); err != nil {
+				log.Fatalf("[FATAL] %v", err)
+			}
+			return
+		}
+		var rows [][]string
+		for _, code := range errorCodes() {
+			info := errorCatalog[code]
+			rows = append(rows, []string{info.Code, info.Name, tr(info.Summary)})
+		}
+		printTable(tr("CÓDIGO\tNOMBRE\tDESCRIPCIÓN"), rows, nil)
+	case "explain":
+		if len(args) != 2 {
+			log.Fatal(usage)
+		}
+		info, ok := lookupError(args[1])
+		if !ok {
+			log.Fatalf(tr("código de error desconocido %q (dnpgen errors list muestra el catálogo)"), args[1])
+		}
+		fmt.Println(boldText(info.Code + " " + info.Name))
+		fmt.Printf("    %s\n\n", tr(info.Summary))
+		fmt.Println(boldText(tr("CÓMO RESOLVERLO")))
+		fmt.Printf("    %s\n", tr(info.Remedy))
+	default:
+		log.Fatal(usage)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// --- EXPORTADORES ---
+//
+// Cada formato de salida es un Exporter registrado por nombre. La lista
+// app.exports del YAML decide cuáles se ejecutan en cada generación. Para
+// añadir un formato basta con un fichero nuevo que llame a RegisterExporter
+// desde init().
+
+// ExportContext es lo que recibe un exportador: el modelo de puntos ya
+// clasificado de un nodo.
+type ExportContext struct {
+	Project string
+	Node    string
+	Lists   *Lists
+	Sig     string // .SIG de origen; vacío si el modelo no viene de un .SIG
+
+	// Anonymized indica que los nombres son hashes (ver anonymizeContext).
+	Anonymized bool
+}
+
+// Exporter genera un artefacto a partir del modelo de puntos de un nodo.
+type Exporter interface {
+	// Name es el identificador usado en app.exports.
+	Name() string
+	// FileName devuelve el nombre del fichero que se escribe para el nodo.
+	FileName(node string) string
+	Export(w io.Writer, ctx ExportContext) error
+}
+
+var exporterRegistry = map[string]Exporter{}
+
+// RegisterExporter añade un exportador al registro. Registrar dos veces el
+// mismo nombre es un error de programación.
+func RegisterExporter(e Exporter) {
+	name := strings.ToLower(e.Name())
+	if _, dup := exporterRegistry[name]; dup {
+		panic("exportador duplicado: " + name)
+	}
+	exporterRegistry[name] = e
+}
+
+// exporterNames devuelve los exportadores disponibles, ordenados.
+func exporterNames() []string {
+	names := make([]string, 0, len(exporterRegistry))
+	for n := range exporterRegistry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exporterFunc adapta una función a Exporter; el fichero es <nodo><suffix>.
+type exporterFunc struct {
+	name, suffix string
+	fn           func(io.Writer, ExportContext) error
+}
+
+func (e exporterFunc) Name() string                                { return e.name }
+func (e exporterFunc) FileName(node string) string                 { return node + e.suffix }
+func (e exporterFunc) Export(w io.Writer, ctx ExportContext) error { return e.fn(w, ctx) }
+
+// writeExports ejecuta los exportadores de app.exports y los de los
+// protocolos del nodo, y devuelve las rutas escritas en dir.
+func writeExports(dir string, ctx ExportContext) ([]string, error) {
+	protocols, err := protocolExportNames(ctx.Node)
+	if err != nil {
+		return nil, err
+	}
+	return writeNamedExports(dir, ctx, append(append([]string{}, GlobalConfig.App.Exports...), protocols...))
+}
+
+// writeNamedExports escribe en dir las exportaciones indicadas por nombre.
+// Los exportadores solo leen el modelo, así que se ejecutan a la vez con
+// hasta app.export_workers workers; si fallan varios, el error los reúne
+// todos y se devuelven igualmente las rutas de los que sí se escribieron.
+func writeNamedExports(dir string, ctx ExportContext, names []string) ([]string, error) {
+	var exporters []Exporter
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		e, ok := exporterRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf("exportador desconocido %q (disponibles: %s)", name, strings.Join(exporterNames(), ", "))
+		}
+		// Un exportador pedido dos veces (app.exports y protocolos) escribiría
+		// el mismo fichero desde dos workers.
+		if !seen[name] {
+			seen[name] = true
+			exporters = append(exporters, e)
+		}
+	}
+
+	paths := make([]string, len(exporters))
+	errs := make([]error, len(exporters))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < exportWorkers(len(exporters)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				e := exporters[i]
+				path := filepath.Join(dir, e.FileName(ctx.Node))
+				log.Printf(tr("Exportando %s..."), filepath.Base(path))
+				if err := writeExportFile(path, e, ctx); err != nil {
+					errs[i] = fmt.Errorf("%s: %v", e.Name(), err)
+					continue
+				}
+				paths[i] = path
+			}
+		}()
+	}
+	for i := range exporters {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var written, failed []string
+	for i := range exporters {
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			continue
+		}
+		written = append(written, paths[i])
+	}
+	switch len(failed) {
+	case 0:
+		return written, nil
+	case 1:
+		return written, errors.New(failed[0])
+	}
+	return written, fmt.Errorf(tr("%d exportadores fallaron: %s"), len(failed), strings.Join(failed, "; "))
+}
+
+// exportWorkers es el número de exportadores simultáneos: app.export_workers
+// o, si es 0, uno por CPU; nunca más que exportadores.
+func exportWorkers(n int) int {
+	w := GlobalConfig.App.ExportWorkers
+	if w <= 0 {
+		w = runtime.NumCPU()
+	}
+	return max(min(w, n), 1)
+}
+
+// writeExportFile escribe un exportador. Un pánico del exportador se
+// convierte en su error para no tumbar los demás workers.
+func writeExportFile(path string, e Exporter, ctx ExportContext) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf(tr("pánico en el exportador: %v"), r)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return e.Export(f, ctx)
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"csv", ".points.csv", writePointsCSV})
+	RegisterExporter(exporterFunc{"json", ".points.json", writePointsJSON})
+}
+
+// writePointsCSV escribe una fila por entrada de cada lista.
+func writePointsCSV(out io.Writer, ctx ExportContext) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"node", "list", "index", "name", "var", "type", "spare", "soe", "description", "scale", "offset", "units", "area", "group", "variation", "event_variation", "alarm_priority", "trip_var", "close_var"})
+	for _, list := range listSections(ctx.Lists) {
+		for idx, p := range list.Items {
+			scale, offset, units := scalingColumns(p)
+			group, variation, event := objectColumns(p)
+			var trip, close string
+			if p.Pair != nil {
+				trip, close = p.Pair.Trip, p.Pair.Close
+			}
+			w.Write([]string{ctx.Node, list.Name, strconv.Itoa(idx), p.Name, p.Var, p.Type, strconv.FormatBool(p.Spare), strconv.FormatBool(p.SOE), p.Desc, scale, offset, units, p.Area, group, variation, event, p.Alarm, trip, close})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writePointsJSON escribe el modelo de puntos del nodo.
+func writePointsJSON(w io.Writer, ctx ExportContext) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	doc := map[string]any{
+		"node": ctx.Node,
+		"ai":   ctx.Lists.AI,
+		"ao":   ctx.Lists.AO,
+		"di":   ctx.Lists.DI,
+		"do":   ctx.Lists.DO,
+	}
+	if len(ctx.Lists.OS) > 0 {
+		doc["os"] = ctx.Lists.OS
+	}
+	return enc.Encode(doc)
+}
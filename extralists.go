@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// --- LISTAS ADICIONALES ---
+
+// ExtraList es una sección *LIST más allá de las cuatro estándar (p.ej.
+// alarmas, contadores o SOE en firmwares que lo admiten). Según Source se
+// llena con las variables que cumplen alguno de los patrones (y, si se
+// indica, uno de los tipos), que van a esta lista en lugar de a DI/DO/AI/AO
+// o además de ellas con copy; con copias de los puntos ya clasificados cuya
+// etiqueta de regla cumple Rule; o solo con Entries.
+type ExtraList struct {
+	Name     string   `yaml:"name"`
+	Code     string   `yaml:"code"`
+	Title    string   `yaml:"title"`
+	Source   string   `yaml:"source"` // patterns (por defecto), rule o static
+	Patterns []string `yaml:"patterns"`
+	Types    []string `yaml:"types"` // vacío = cualquier tipo; si no, subcadenas del tipo SIG
+	Copy     bool     `yaml:"copy"`  // también en su lista estándar
+	// Rule es la regex sobre las etiquetas de los puntos de las listas
+	// estándar (source: rule): la regla que los clasificó, p.ej. "type:REAL"
+	// o "digital_output_regex[0]", "soe", "area:<área>" y "alarm:<prioridad>".
+	Rule string `yaml:"rule"`
+	// Entries son líneas fijas que abren la lista, tal como se escriben.
+	Entries []string `yaml:"entries"`
+}
+
+// Orígenes de una lista adicional.
+const (
+	ExtraSourcePatterns = "patterns"
+	ExtraSourceRule     = "rule"
+	ExtraSourceStatic   = "static"
+)
+
+func (e ExtraList) source() string {
+	if e.Source == "" {
+		return ExtraSourcePatterns
+	}
+	return e.Source
+}
+
+// CustomList son los puntos de una lista adicional.
+type CustomList struct {
+	Name, Code, Title string
+	Items             []Point
+}
+
+type extraRouter struct {
+	lists    []ExtraList
+	matchers []*ruleMatcher
+}
+
+// compileExtraLists valida las listas adicionales: nombres y códigos únicos
+// y distintos de los estándar.
+func compileExtraLists(lists []ExtraList) (*extraRouter, error) {
+	names := map[string]bool{"AI": true, "AO": true, "DI": true, "DO": true}
+	codes := map[string]bool{ListCodeAI: true, ListCodeAO: true, ListCodeDI: true, ListCodeDO: true}
+	if s := GlobalConfig.App.Classification.Strings; s.enabled() {
+		names["OS"], codes[s.code()] = true, true
+	}
+	r := &extraRouter{lists: lists}
+	for _, e := range lists {
+		name := strings.ToUpper(e.Name)
+		switch {
+		case name == "" || e.Code == "":
+			return nil, fmt.Errorf("extra_lists: name y code son obligatorios")
+		case names[name]:
+			return nil, fmt.Errorf("extra_lists: nombre repetido %q", e.Name)
+		case codes[e.Code]:
+			return nil, fmt.Errorf("extra_lists %s: código %s ya en uso", e.Name, e.Code)
+		}
+		names[name], codes[e.Code] = true, true
+		switch e.source() {
+		case ExtraSourcePatterns:
+			if e.Rule != "" {
+				return nil, fmt.Errorf(tr("extra_lists %s: rule requiere source: rule"), e.Name)
+			}
+		case ExtraSourceRule:
+			if e.Rule == "" || len(e.Patterns) > 0 || len(e.Types) > 0 {
+				return nil, fmt.Errorf(tr("extra_lists %s: source rule usa rule, no patterns ni types"), e.Name)
+			}
+			if _, err := regexp.Compile(e.Rule); err != nil {
+				return nil, fmt.Errorf("extra_lists %s: %v", e.Name, err)
+			}
+		case ExtraSourceStatic:
+			if len(e.Entries) == 0 || len(e.Patterns) > 0 || e.Rule != "" {
+				return nil, fmt.Errorf(tr("extra_lists %s: source static solo admite entries"), e.Name)
+			}
+		default:
+			return nil, fmt.Errorf(tr("extra_lists %s: source %q desconocido (patterns, rule, static)"), e.Name, e.Source)
+		}
+		m, err := compileRules(e.Patterns, false)
+		if err != nil {
+			return nil, fmt.Errorf("extra_lists %s: %v", e.Name, err)
+		}
+		r.matchers = append(r.matchers, m)
+	}
+	return r, nil
+}
+
+// newCustomLists crea las listas adicionales configuradas, vacías.
+func newCustomLists(lists []ExtraList) []CustomList {
+	out := make([]CustomList, 0, len(lists))
+	for _, e := range lists {
+		title := e.Title
+		if title == "" {
+			title = strings.ToUpper(e.Name) + " DNP"
+		}
+		out = append(out, CustomList{Name: strings.ToUpper(e.Name), Code: e.Code, Title: title, Items: []Point{}})
+	}
+	return out
+}
+
+// addStaticEntries abre cada lista adicional con sus entries.
+func addStaticEntries(l *Lists, lists []ExtraList) {
+	for i, e := range lists {
+		for _, name := range e.Entries {
+			l.Extra[i].Items = append(l.Extra[i].Items, Point{
+				Name: name, Var: strings.TrimPrefix(name, "@GV."), Rule: "extra_lists:" + strings.ToUpper(e.Name),
+			})
+		}
+	}
+}
+
+// pointTags son las etiquetas de un punto con las que se compara Rule.
+func pointTags(p Point) []string {
+	tags := []string{pointRule(p)}
+	if p.SOE {
+		tags = append(tags, "soe")
+	}
+	if p.Area != "" {
+		tags = append(tags, "area:"+p.Area)
+	}
+	if p.Alarm != "" {
+		tags = append(tags, "alarm:"+p.Alarm)
+	}
+	return tags
+}
+
+// fillRuleLists copia a las listas con source rule los puntos de las listas
+// estándar cuyas etiquetas cumplen su regla, en orden de lista e índice. Se
+// aplica con los puntos ya clasificados, marcados y con sus reservas.
+func fillRuleLists(l *Lists, lists []ExtraList) error {
+	for i, e := range lists {
+		if e.source() != ExtraSourceRule {
+			continue
+		}
+		re, err := regexp.Compile(e.Rule)
+		if err != nil {
+			return fmt.Errorf("extra_lists %s: %v", e.Name, err)
+		}
+		for _, sec := range listSections(l) {
+			if isExtraList(l, sec.Name) {
+				continue
+			}
+			for _, p := range sec.Items {
+				for _, tag := range pointTags(p) {
+					if re.MatchString(tag) {
+						l.Extra[i].Items = append(l.Extra[i].Items, p)
+						break
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isExtraList(l *Lists, name string) bool {
+	for _, e := range l.Extra {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// route devuelve el índice de la primera lista adicional que recibe la
+// variable, o -1.
+func (r *extraRouter) route(varName, varType string) int {
+	for i, e := range r.lists {
+		if e.source() != ExtraSourcePatterns || !r.matchers[i].Any(varName) {
+			continue
+		}
+		if len(e.Types) == 0 {
+			return i
+		}
+		for _, t := range e.Types {
+			if strings.Contains(varType, t) {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// --- HALLAZGOS DE VALIDACIÓN ---
+//
+// Todas las validaciones (nombres y convenciones de nombres, líneas del .SIG,
+// .SIG incompleto, tipos sin lista, simetría de las listas espejo (también
+// con -only), capacidad
+// de las listas, __vardef.ini, duplicados entre nodos, spares repetidos,
+// límites del firmware, señales de listas desactivadas, validadores propios y
+// etapas más lentas que en ejecuciones anteriores) producen hallazgos con una
+// severidad. app.findings permite cambiar la severidad de cada tipo y fija a
+// partir de qué severidad falla la generación; las regresiones de
+// rendimiento nunca la hacen fallar.
+
+// Severidades, de menor a mayor. SeverityOff descarta el hallazgo.
+const (
+	SeverityOff     = "off"
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Tipos de hallazgo, usados como claves de app.findings.policy.
+const (
+	FindingNameLength     = "name_length"
+	FindingNameChars      = "name_chars"
+	FindingNaming         = "naming"
+	FindingSigLine        = "sig_line"
+	FindingSigIncomplete  = "sig_incomplete"
+	FindingSigFormat      = "sig_format"
+	FindingUnknownType    = "unknown_type"
+	FindingCapacity       = "capacity"
+	FindingVarDefMismatch = "vardef_mismatch"
+	FindingDuplicate      = "duplicate"
+	FindingValidator      = "validator"
+	FindingFirmware       = "firmware"
+	FindingMirror         = "mirror_asymmetry"
+	FindingMirrorSplit    = "mirror_split"
+	FindingRulesVersion   = "rules_version"
+	FindingControlPair    = "control_pair"
+	FindingDuplicateSpare = "duplicate_spare"
+	FindingPerfRegression = "perf_regression"
+	FindingDisabledList   = "disabled_list"
+)
+
+// findingDefaults es la severidad de cada tipo si la política no la cambia.
+// Las de nombres dependen de app.names.action (ver nameSeverity).
+var findingDefaults = map[string]string{
+	FindingSigLine:        SeverityWarning,
+	FindingSigIncomplete:  SeverityWarning,
+	FindingSigFormat:      SeverityInfo,
+	FindingUnknownType:    SeverityInfo,
+	FindingCapacity:       SeverityError,
+	FindingVarDefMismatch: SeverityWarning,
+	FindingDuplicate:      SeverityError,
+	FindingValidator:      SeverityError,
+	FindingFirmware:       SeverityError,
+	FindingMirror:         SeverityError,
+	FindingMirrorSplit:    SeverityWarning,
+	FindingNaming:         SeverityWarning,
+	FindingControlPair:    SeverityWarning,
+	FindingPerfRegression: SeverityWarning,
+	FindingDisabledList:   SeverityError,
+}
+
+// FindingsConfig es la política de hallazgos.
+type FindingsConfig struct {
+	// Policy cambia la severidad por tipo: info, warning, error u off.
+	Policy map[string]string `yaml:"policy"`
+	// FailOn es la severidad mínima que hace fallar: error (por defecto),
+	// warning o never.
+	FailOn string `yaml:"fail_on"`
+	// Capacity es el número máximo de puntos por lista (AI, DI...); sin
+	// entrada o 0 = sin límite.
+	Capacity map[string]int `yaml:"capacity"`
+}
+
+// Finding es el resultado de una validación.
+type Finding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Node     string `json:"node,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Code, f.Message)
+}
+
+func severityRank(s string) int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityError:
+		return 3
+	}
+	return 0
+}
+
+// validate comprueba la política antes de generar.
+func (c FindingsConfig) validate() error {
+	for code, s := range c.Policy {
+		if _, ok := findingDefaults[code]; !ok && code != FindingNameLength && code != FindingNameChars && code != FindingRulesVersion && code != FindingDuplicateSpare {
+			return fmt.Errorf(tr("findings.policy: tipo de hallazgo desconocido %q"), code)
+		}
+		if s != SeverityOff && severityRank(s) == 0 {
+			return fmt.Errorf(tr("findings.policy.%s: severidad desconocida %q"), code, s)
+		}
+	}
+	switch c.FailOn {
+	case "", SeverityError, SeverityWarning, "never":
+		return nil
+	}
+	return fmt.Errorf(tr("findings.fail_on desconocido %q"), c.FailOn)
+}
+
+// severity devuelve la severidad de un tipo de hallazgo según la política.
+func (c FindingsConfig) severity(code, fallback string) string {
+	if s, ok := c.Policy[code]; ok {
+		return s
+	}
+	if s, ok := findingDefaults[code]; ok {
+		return s
+	}
+	return fallback
+}
+
+// fails indica si un hallazgo de severidad s hace fallar la generación.
+func (c FindingsConfig) fails(s string) bool {
+	switch c.FailOn {
+	case "never":
+		return false
+	case SeverityWarning:
+		return severityRank(s) >= severityRank(SeverityWarning)
+	}
+	return s == SeverityError
+}
+
+// findingSet acumula hallazgos aplicando la política.
+type findingSet struct {
+	cfg  FindingsConfig
+	node string
+	list []Finding
+}
+
+func newFindingSet(node string) *findingSet {
+	return &findingSet{cfg: GlobalConfig.App.Findings, node: node}
+}
+
+// add registra un hallazgo; fallback es la severidad si el tipo no tiene
+// valor por defecto ni política. Los de severidad off se descartan.
+func (s *findingSet) add(code, fallback, format string, args ...any) {
+	s.addAs(code, s.cfg.severity(code, fallback), fmt.Sprintf(format, args...))
+}
+
+// addAs registra un hallazgo con una severidad fija, sin consultar la
+// política.
+func (s *findingSet) addAs(code, severity, message string) {
+	if severity == SeverityOff {
+		return
+	}
+	s.list = append(s.list, Finding{Code: code, Severity: severity, Node: s.node, Message: message})
+}
+
+// verdict registra los avisos y devuelve un error con los hallazgos que
+// hacen fallar la generación según fail_on.
+func (s *findingSet) verdict() error {
+	var failing []string
+	for _, f := range s.list {
+		switch {
+		case s.cfg.fails(f.Severity):
+			failing = append(failing, f.String())
+		case f.Severity == SeverityError:
+			log.Printf("[ERROR] %s: %s", f.Code, f.Message)
+		case f.Severity == SeverityWarning:
+			log.Printf("[WARN] %s: %s", f.Code, f.Message)
+		}
+	}
+	if len(failing) == 0 {
+		return nil
+	}
+	return fmt.Errorf(tr("%d hallazgo(s) bloqueantes:\n  - %s"), len(failing), strings.Join(failing, "\n  - "))
+}
+
+// nameSeverity traduce app.names.action a la severidad por defecto de los
+// hallazgos de nombres.
+func nameSeverity(action string) string {
+	if strings.EqualFold(action, "error") {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// collectFindings convierte en hallazgos las comprobaciones de un nodo ya
+// clasificado.
+func collectFindings(s *findingSet, l *Lists, rep *sigReport, nameIssues []NameIssue, naming []NameViolation) {
+	nameSev := nameSeverity(GlobalConfig.App.NameRules.Action)
+	for _, issue := range nameIssues {
+		if issue.badChars {
+			s.add(FindingNameChars, nameSev, "%s", issue)
+		}
+		if issue.tooLong {
+			s.add(FindingNameLength, nameSev, "%s", issue)
+		}
+	}
+	for _, v := range naming {
+		s.add(FindingNaming, SeverityWarning, "%s", v)
+	}
+	if rep.Format.normalized() {
+		s.add(FindingSigFormat, SeverityInfo, "%s", rep.Format)
+	}
+	for _, d := range rep.Diagnostics {
+		s.add(FindingSigLine, SeverityWarning, "%s", d)
+	}
+	for _, a := range rep.Asymmetries {
+		s.add(FindingMirror, SeverityError, "%s", a)
+	}
+	types := make([]string, 0, len(rep.Unknown))
+	for t := range rep.Unknown {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		s.add(FindingUnknownType, SeverityInfo, tr("%d señal(es) de tipo %s sin lista"), rep.Unknown[t], t)
+	}
+	for _, sec := range listSections(l) {
+		if limit := s.cfg.Capacity[sec.Name]; limit > 0 && len(sec.Items) > limit {
+			s.add(FindingCapacity, SeverityError, tr("lista %s: %d puntos (máx. %d)"), sec.Name, len(sec.Items), limit)
+		}
+	}
+	checkFirmware(s, l)
+	checkDuplicateSpares(s, l)
+	if GlobalConfig.App.Scaling.FromVarDef {
+		for _, sec := range []listSection{{Name: "AI", Items: l.AI}, {Name: "AO", Items: l.AO}} {
+			for i, p := range sec.Items {
+				if !p.Spare && !p.System && !p.AOS && p.Scaling == nil {
+					s.add(FindingVarDefMismatch, SeverityWarning, tr("%s[%d] %s: sin rango en %s"), sec.Name, i, p.Var, VarDefFile)
+				}
+			}
+		}
+	}
+}
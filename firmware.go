@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// --- PRESETS DE FIRMWARE DE LA RTU ---
+//
+// Cada versión de firmware de la CWave Micro admite un número distinto de
+// puntos por lista, unos códigos de *LIST y unos tipos de variable, y limita
+// los nombres. Un preset (app.firmware o -firmware) agrupa esos límites para
+// que la validación corresponda al equipo de destino sin repetirlos en cada
+// config.yaml.
+
+// FirmwarePreset son los límites de una versión de firmware. Los valores
+// vacíos no limitan.
+type FirmwarePreset struct {
+	Description string         `yaml:"description"`
+	Capacity    map[string]int `yaml:"capacity"`   // puntos por lista (AI, DI...)
+	ListCodes   []string       `yaml:"list_codes"` // códigos *LIST admitidos
+	// MaxNameLength y AllowedChars se aplican como app.names.max_length y
+	// app.names.allowed_chars si estos no están configurados.
+	MaxNameLength int      `yaml:"max_name_length"`
+	AllowedChars  string   `yaml:"allowed_chars"`
+	Types         []string `yaml:"types"` // tipos SIG admitidos en las listas
+}
+
+// firmwarePresets son los presets incluidos. app.firmware_presets añade
+// otros o sustituye estos.
+var firmwarePresets = map[string]FirmwarePreset{
+	"cwm-05": {
+		Description:   "ControlWave Micro, firmware 05.x",
+		Capacity:      map[string]int{"AI": 512, "AO": 256, "DI": 1024, "DO": 512},
+		ListCodes:     []string{ListCodeAI, ListCodeAO, ListCodeDI, ListCodeDO},
+		MaxNameLength: 32,
+		AllowedChars:  "A-Za-z0-9_.@()",
+		Types:         []string{"AA", "LA", "AAR", "REAL", "BOOL", "AO", "DO"},
+	},
+	"cwm-06": {
+		Description:   "ControlWave Micro, firmware 06.x",
+		Capacity:      map[string]int{"AI": 1024, "AO": 512, "DI": 2048, "DO": 1024},
+		ListCodes:     []string{ListCodeAI, ListCodeAO, ListCodeDI, ListCodeDO, ListCodeOS},
+		MaxNameLength: 64,
+		AllowedChars:  "A-Za-z0-9_.@()",
+		Types:         []string{"AA", "LA", "AAR", "REAL", "LREAL", "BOOL", "DINT", "UDINT", "AO", "DO", "STRING"},
+	},
+	"cwm-07": {
+		Description:   "ControlWave Micro, firmware 07.x",
+		Capacity:      map[string]int{"AI": 4096, "AO": 2048, "DI": 8192, "DO": 4096},
+		ListCodes:     []string{ListCodeAI, ListCodeAO, ListCodeDI, ListCodeDO, ListCodeOS, "32766", "32767"},
+		MaxNameLength: 64,
+		AllowedChars:  "A-Za-z0-9_.@()",
+	},
+}
+
+// firmwarePreset devuelve el preset de app.firmware, o nil si no hay.
+func firmwarePreset() (*FirmwarePreset, error) {
+	name := GlobalConfig.App.Firmware
+	if name == "" {
+		return nil, nil
+	}
+	if p, ok := GlobalConfig.App.FirmwarePresets[name]; ok {
+		return &p, nil
+	}
+	if p, ok := firmwarePresets[name]; ok {
+		return &p, nil
+	}
+	return nil, fmt.Errorf(tr("firmware desconocido %q (disponibles: %s)"), name, strings.Join(firmwareNames(), ", "))
+}
+
+// firmwareNames lista los presets incluidos y los de app.firmware_presets.
+func firmwareNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, presets := range []map[string]FirmwarePreset{firmwarePresets, GlobalConfig.App.FirmwarePresets} {
+		for name := range presets {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyFirmware vuelca en la configuración los límites del preset que no
+// estén ya configurados (capacidad por lista y reglas de nombres), para que
+// las comprobaciones de siempre los apliquen. -firmware sustituye a
+// app.firmware.
+func applyFirmware() {
+	if globalFlags.Firmware != "" {
+		GlobalConfig.App.Firmware = globalFlags.Firmware
+	}
+	p, err := firmwarePreset()
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if p == nil {
+		return
+	}
+	cfg := &GlobalConfig.App
+	if len(p.Capacity) > 0 && cfg.Findings.Capacity == nil {
+		cfg.Findings.Capacity = map[string]int{}
+	}
+	for list, n := range p.Capacity {
+		if _, ok := cfg.Findings.Capacity[list]; !ok {
+			cfg.Findings.Capacity[list] = n
+		}
+	}
+	if cfg.NameRules.MaxLength == 0 {
+		cfg.NameRules.MaxLength = p.MaxNameLength
+	}
+	if cfg.NameRules.AllowedChars == "" {
+		cfg.NameRules.AllowedChars = p.AllowedChars
+	}
+}
+
+// checkFirmware añade hallazgos "firmware" por las listas y los tipos que el
+// preset no admite. La capacidad y los nombres ya los comprueban los
+// hallazgos capacity y name_*.
+func checkFirmware(s *findingSet, l *Lists) {
+	p, err := firmwarePreset()
+	if err != nil || p == nil {
+		return
+	}
+	name := GlobalConfig.App.Firmware
+	codes := map[string]bool{}
+	for _, c := range p.ListCodes {
+		codes[c] = true
+	}
+	types := map[string]bool{}
+	for _, t := range p.Types {
+		types[strings.ToUpper(t)] = true
+	}
+	for _, sec := range listSections(l) {
+		if len(codes) > 0 && !codes[sec.Code] && len(sec.Items) > 0 {
+			s.add(FindingFirmware, SeverityError, tr("lista %s: el firmware %s no admite el código *LIST %s"), sec.Name, name, sec.Code)
+		}
+		if len(types) == 0 {
+			continue
+		}
+		unsupported := map[string]int{}
+		for _, pt := range sec.Items {
+			if !pt.Spare && !pt.System && pt.Type != "" && !types[strings.ToUpper(pt.Type)] {
+				unsupported[pt.Type]++
+			}
+		}
+		found := make([]string, 0, len(unsupported))
+		for t := range unsupported {
+			found = append(found, t)
+		}
+		sort.Strings(found)
+		for _, t := range found {
+			s.add(FindingFirmware, SeverityError, tr("lista %s: %d punto(s) de tipo %s, que el firmware %s no admite"), sec.Name, unsupported[t], t, name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// --- GENERADOR DE .SIG SINTÉTICOS ---
+
+// fixtureKind es una categoría de señal del .SIG sintético. Los nombres por
+// defecto siguen las convenciones habituales de salidas (_SP, _CMD), que son
+// las que buscan los patrones de classification.
+type fixtureKind struct {
+	flag, typ, name, desc string
+	count                 *int
+	template              *string
+}
+
+func runGenFixture(args []string) {
+	fs := flag.NewFlagSet("gen-fixture", flag.ExitOnError)
+	fs.Usage = manUsage("gen-fixture", fs)
+	out := fs.String("out", "", tr("Fichero .SIG a escribir (por defecto <nodo>.SIG)"))
+	project := fs.String("project", "", tr("Crear un proyecto completo en este directorio (recurso RTU, .SIG y .mwt)"))
+	node := fs.String("node", "FIXTURE", tr("Nombre del nodo"))
+	digits := fs.Int("digits", 5, tr("Dígitos de {n} en los nombres"))
+	areas := fs.String("areas", "", tr("Prefijos de área para {area}, separados por comas"))
+	desc := fs.Bool("desc", false, tr("Añadir DESC= a cada señal"))
+	shuffle := fs.Bool("shuffle", false, tr("Mezclar las categorías en lugar de escribirlas en bloques"))
+	seed := fs.Int64("seed", 1, tr("Semilla de -shuffle (misma semilla, mismo fichero)"))
+	lf := fs.Bool("lf", false, tr("Finales de línea LF en lugar de CRLF"))
+
+	kinds := []fixtureKind{
+		{flag: "ai", typ: "AA", name: "{area}FT{n}_PV", desc: "Caudal"},
+		{flag: "ao", typ: "REAL", name: "{area}TK{n}_SP", desc: "Consigna"},
+		{flag: "di", typ: "LA", name: "{area}P{n}_RUN", desc: "Bomba en marcha"},
+		{flag: "do", typ: "BOOL", name: "{area}P{n}_CMD", desc: "Orden de bomba"},
+		{flag: "strings", typ: "STRING", name: "{area}DEV{n}_ID", desc: "Identificación"},
+		{flag: "unknown", typ: "TMR", name: "{area}TMR{n}", desc: "Temporizador"},
+	}
+	defaults := map[string]int{"ai": 1000, "ao": 200, "di": 2000, "do": 500}
+	for i := range kinds {
+		k := &kinds[i]
+		k.count = fs.Int(k.flag, defaults[k.flag], trf("Número de señales %s", strings.ToUpper(k.flag)))
+		k.template = fs.String("name-"+k.flag, k.name, trf("Plantilla de nombre %s ({n}, {area})", strings.ToUpper(k.flag)))
+	}
+	fs.Parse(args)
+
+	path := *out
+	if *project != "" {
+		resource := filepath.Join(*project, RelativePathToResource)
+		if err := os.MkdirAll(resource, 0o755); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		// El .mwt se crea antes para que el .SIG no se considere obsoleto.
+		if err := os.WriteFile(filepath.Join(*project, *node+".mwt"), nil, 0o644); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if path == "" {
+			path = filepath.Join(resource, *node+".SIG")
+		}
+	}
+	if path == "" {
+		path = *node + ".SIG"
+	}
+
+	var prefixes []string
+	for _, a := range strings.Split(*areas, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			prefixes = append(prefixes, a+"_")
+		}
+	}
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	type line struct{ kind, n int }
+	var lines []line
+	for ki, k := range kinds {
+		for n := 0; n < *k.count; n++ {
+			lines = append(lines, line{ki, n})
+		}
+	}
+	if *shuffle {
+		rand.New(rand.NewSource(*seed)).Shuffle(len(lines), func(i, j int) { lines[i], lines[j] = lines[j], lines[i] })
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	w := bufio.NewWriter(f)
+	eol := "\r\n"
+	if *lf {
+		eol = "\n"
+	}
+	counts := make([]int, len(kinds))
+	for _, l := range lines {
+		k := kinds[l.kind]
+		name := strings.NewReplacer(
+			"{n}", fmt.Sprintf("%0*d", *digits, l.n),
+			"{area}", prefixes[l.n%len(prefixes)],
+		).Replace(*k.template)
+		fmt.Fprintf(w, "SIG=@GV.%s TYPE=%s", name, k.typ)
+		if *desc {
+			fmt.Fprintf(w, " DESC=\"%s %d\"", k.desc, l.n)
+		}
+		w.WriteString(eol)
+		counts[l.kind]++
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	var parts []string
+	for i, k := range kinds {
+		if counts[i] > 0 {
+			parts = append(parts, strings.ToUpper(k.flag)+": "+strconv.Itoa(counts[i]))
+		}
+	}
+	fmt.Println(okText(trf("%s: %d señales (%s)", path, len(lines), strings.Join(parts, " | "))))
+}
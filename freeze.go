@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- PUNTOS CONGELADOS ---
+//
+// Tras la SAT de un nodo solo se admiten altas: los puntos probados no pueden
+// cambiar de índice ni de nombre. El fichero de congelación (por defecto
+// <salida>/<nodo>.freeze.yaml) declara rangos de índices y puntos por nombre;
+// la referencia es el fichero de listas existente. Cada generación vuelve a
+// poner en su índice y con su nombre cada entrada congelada, los demás
+// puntos ocupan los índices libres, y falla (FROZEN_VIOLATED) si un punto
+// congelado ya no sale del .SIG o las reglas lo llevan a otra lista.
+
+// FreezeConfig localiza el fichero de congelación.
+type FreezeConfig struct {
+	// File admite {node}; relativo al directorio de salida del nodo. Vacío =
+	// "{node}.freeze.yaml".
+	File string `yaml:"file"`
+}
+
+func (c FreezeConfig) path(outDir, node string) string {
+	file := c.File
+	if file == "" {
+		file = "{node}.freeze.yaml"
+	}
+	file = expandTemplate(file, map[string]string{"node": node})
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(outDir, file)
+}
+
+// FreezeRange congela los índices From..To de List.
+type FreezeRange struct {
+	List string `yaml:"list"`
+	From int    `yaml:"from"`
+	To   int    `yaml:"to"`
+}
+
+// FreezeFile es el contenido del fichero de congelación.
+type FreezeFile struct {
+	Reason string        `yaml:"reason"` // p.ej. "SAT 2026-09-30"
+	Ranges []FreezeRange `yaml:"ranges"`
+	// Points son nombres de punto (con o sin @GV.), congelados en la lista e
+	// índice que tienen en el fichero de listas existente.
+	Points []string `yaml:"points"`
+}
+
+// readFreezeFile lee el fichero de congelación; nil si no existe.
+func readFreezeFile(path string) (*FreezeFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f FreezeFile
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &f, nil
+}
+
+// frozenEntries resuelve el fichero contra las listas de referencia: índices
+// congelados de cada lista.
+func (f *FreezeFile) frozenEntries(ref *Lists) (map[string]map[int]Point, error) {
+	// Los spares sin espejo (nombre fijo, pool o reserved) se reconocen por
+	// la estrategia de su lista.
+	sections := map[string][]Point{}
+	for _, s := range listSections(ref) {
+		isSpare := spareMatcher(s.Name)
+		for i := range s.Items {
+			if isSpare(s.Items[i].Name) {
+				s.Items[i].Spare = true
+			}
+		}
+		sections[s.Name] = s.Items
+	}
+	out := map[string]map[int]Point{}
+	add := func(list string, i int) {
+		if out[list] == nil {
+			out[list] = map[int]Point{}
+		}
+		out[list][i] = sections[list][i]
+	}
+	for _, r := range f.Ranges {
+		list := strings.ToUpper(r.List)
+		items, ok := sections[list]
+		if !ok {
+			return nil, fmt.Errorf(tr("rango %s %d-%d: lista desconocida"), r.List, r.From, r.To)
+		}
+		if r.From < 0 || r.To < r.From || r.To >= len(items) {
+			return nil, fmt.Errorf(tr("rango %s %d-%d: fuera de la lista (%d entradas)"), list, r.From, r.To, len(items))
+		}
+		for i := r.From; i <= r.To; i++ {
+			add(list, i)
+		}
+	}
+	for _, name := range f.Points {
+		v := strings.TrimPrefix(name, "@GV.")
+		found := false
+		for list, items := range sections {
+			for i, p := range items {
+				if !p.Spare && p.Var == v {
+					add(list, i)
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf(tr("el punto %s no está en el fichero de listas"), name)
+		}
+	}
+	return out, nil
+}
+
+// applyFreeze recoloca las entradas congeladas en su índice y con su nombre.
+// Devuelve cuántas entradas congeladas hay.
+func applyFreeze(l *Lists, outDir, node string) (int, error) {
+	path := GlobalConfig.App.Freeze.path(outDir, node)
+	f, err := readFreezeFile(path)
+	if err != nil || f == nil {
+		return 0, err
+	}
+	ref, err := readListsFile(filepath.Join(outDir, GlobalConfig.App.Output.fileName()))
+	if err != nil {
+		return 0, fmt.Errorf(tr("%s necesita el fichero de listas existente: %v"), filepath.Base(path), err)
+	}
+	frozen, err := f.frozenEntries(ref)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+
+	// Dónde está ahora cada variable, para explicar las que faltan.
+	where := map[string]string{}
+	for _, s := range listSections(l) {
+		for _, p := range s.Items {
+			if !p.Spare {
+				where[p.Var] = s.Name
+			}
+		}
+	}
+	var problems []string
+	total := 0
+	for _, s := range listSections(l) {
+		fixed := frozen[s.Name]
+		if len(fixed) == 0 {
+			continue
+		}
+		total += len(fixed)
+		items, err := freezeList(s.Name, s.Items, fixed)
+		if err != nil {
+			return 0, err
+		}
+		for _, i := range sortedIndexes(fixed) {
+			if p := fixed[i]; !p.Spare && !items.found[i] {
+				if other, ok := where[p.Var]; ok {
+					problems = append(problems, trf("%s[%d] %s: las reglas lo llevan ahora a %s", s.Name, i, p.Name, other))
+				} else {
+					problems = append(problems, trf("%s[%d] %s: ya no está en el .SIG", s.Name, i, p.Name))
+				}
+			}
+		}
+		l.setItems(s.Name, items.points)
+	}
+	if len(problems) > 0 {
+		reason := ""
+		if f.Reason != "" {
+			reason = " (" + f.Reason + ")"
+		}
+		return 0, codedErrorf(ErrFrozenViolated, tr("%d punto(s) congelado(s) en %s%s no se pueden conservar:\n  - %s"),
+			len(problems), filepath.Base(path), reason, strings.Join(problems, "\n  - "))
+	}
+	return total, nil
+}
+
+func sortedIndexes(m map[int]Point) []int {
+	out := make([]int, 0, len(m))
+	for i := range m {
+		out = append(out, i)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// frozenList es una lista con las entradas congeladas ya colocadas.
+type frozenList struct {
+	points []Point
+	found  map[int]bool // índices congelados que la generación aún produce
+}
+
+// freezeList coloca cada entrada congelada en su índice: el punto generado
+// con ese nombre (o con su variable, que recupera el nombre congelado) o, si
+// es un spare, el de la referencia. Los demás puntos siguen en su orden en
+// los índices libres; si no llegan al último congelado, el hueco se rellena
+// con spares.
+func freezeList(list string, items []Point, fixed map[int]Point) (frozenList, error) {
+	res := frozenList{found: map[int]bool{}}
+	used := make([]bool, len(items))
+	take := func(match func(Point) bool) (Point, bool) {
+		for i, p := range items {
+			if !used[i] && match(p) {
+				used[i] = true
+				return p, true
+			}
+		}
+		return Point{}, false
+	}
+	placed := map[int]Point{}
+	indexes := sortedIndexes(fixed)
+	// Primero por nombre exacto y después por variable, para que un punto
+	// renombrado no se quede el nombre de otro congelado.
+	for _, i := range indexes {
+		ref := fixed[i]
+		if p, ok := take(func(p Point) bool { return p.Name == ref.Name }); ok {
+			placed[i], res.found[i] = p, true
+		}
+	}
+	for _, i := range indexes {
+		ref := fixed[i]
+		if _, ok := placed[i]; ok || ref.Spare {
+			continue
+		}
+		if p, ok := take(func(p Point) bool { return !p.Spare && p.Var == ref.Var }); ok {
+			p.Name = ref.Name
+			placed[i], res.found[i] = p, true
+		}
+	}
+	for _, i := range indexes {
+		if _, ok := placed[i]; !ok {
+			placed[i] = fixed[i]
+		}
+	}
+
+	last := indexes[len(indexes)-1]
+	var spares *spareAllocator
+	next := 0
+	for idx := 0; idx <= last || next < len(items); idx++ {
+		if p, ok := placed[idx]; ok {
+			res.points = append(res.points, p)
+			continue
+		}
+		for next < len(items) && used[next] {
+			next++
+		}
+		if next < len(items) {
+			res.points = append(res.points, items[next])
+			used[next] = true
+			continue
+		}
+		if idx > last {
+			break
+		}
+		if spares == nil {
+			var err error
+			if spares, err = newSpareAllocator(list, items); err != nil {
+				return res, err
+			}
+		}
+		spare, err := spares.next("", "")
+		if err != nil {
+			return res, fmt.Errorf(tr("congelación %s[%d]: %v"), list, idx, err)
+		}
+		res.points = append(res.points, spare)
+	}
+	return res, nil
+}
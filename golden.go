@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dnp3converter/golden"
+)
+
+// --- FICHEROS GOLDEN ---
+//
+// Los equipos que mantienen sus propias reglas de clasificación necesitan
+// pruebas de regresión: "dnpgen golden" genera en memoria (sin SIGEXT ni
+// escrituras en la salida) y compara __lists.ini y las exportaciones con una
+// copia de referencia en <dir>/<nodo>/. Con -update se reescribe la
+// referencia. Sale con código 1 si algo difiere, de modo que basta con una
+// línea en el CI junto a un proyecto de prueba (gen-fixture -project) y el
+// config.yaml del equipo. La cabecera "; dnpgen ..." de las listas no se
+// compara: cambia con cada versión. La comparación y la reescritura están en
+// el paquete golden, para que las pruebas de cada equipo puedan usarlas.
+
+// GoldenNode agrupa los ficheros de un nodo.
+type GoldenNode struct {
+	Node  string        `json:"node"`
+	Files []golden.File `json:"files,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+func (n GoldenNode) failed() bool {
+	return n.Error != "" || golden.Failed(n.Files)
+}
+
+// goldenOutputs genera el nodo en memoria y devuelve el contenido de cada
+// fichero por nombre: las listas según app.output y las exportaciones
+// indicadas.
+func goldenOutputs(projectPath, node string, exports []string) (map[string][]byte, error) {
+	res, err := generate(GenerateRequest{ProjectPath: projectPath, NodeName: node, SkipExt: true, CheckOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]byte{}
+	for _, o := range listOutputs("", res.lists, res.content) {
+		out[filepath.Base(o.path)] = stripListHeader(o.content)
+	}
+	ctx := ExportContext{Project: projectPath, Node: node, Lists: res.lists, Sig: res.SigFile}
+	for _, name := range exports {
+		e, ok := exporterRegistry[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("exportador desconocido %q (disponibles: %s)", name, strings.Join(exporterNames(), ", "))
+		}
+		var buf bytes.Buffer
+		if err := e.Export(&buf, ctx); err != nil {
+			return nil, withCode(ErrExportFailed, fmt.Errorf("%s: %v", e.Name(), err))
+		}
+		out[e.FileName(node)] = buf.Bytes()
+	}
+	return out, nil
+}
+
+// goldenDiff es golden.Diff para mostrar: un fichero binario da una sola
+// línea.
+func goldenDiff(want, got []byte) []string {
+	if !golden.IsText(want) || !golden.IsText(got) {
+		return []string{tr("contenido binario distinto")}
+	}
+	return golden.Diff(want, got)
+}
+
+// runGolden implementa "dnpgen golden -path RUTA (-node A,B | -all) [-dir
+// DIR] [-exports csv,json] [-update]".
+func runGolden(args []string) {
+	fs := flag.NewFlagSet("golden", flag.ExitOnError)
+	fs.Usage = manUsage("golden", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto o copia .zip"))
+	nodeNames := fs.String("node", "", tr("Nodos a comprobar, separados por comas"))
+	all := fs.Bool("all", false, tr("Comprobar todos los nodos con .SIG"))
+	dir := fs.String("dir", "golden", tr("Directorio de referencia (un subdirectorio por nodo)"))
+	exports := fs.String("exports", "", tr("Exportaciones a comparar, separadas por comas (por defecto las de app.exports)"))
+	update := fs.Bool("update", false, tr("Reescribir la referencia con lo generado"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(tr("Uso: dnpgen.exe golden -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-dir golden] [-exports csv,json] [-update]"))
+	}
+	names := GlobalConfig.App.Exports
+	if *exports != "" {
+		names = parseOnly(*exports)
+	}
+	var nodes []string
+	if *all {
+		infos, err := listProjectNodes(*projectPath)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		for _, n := range infos {
+			if n.SigTime != nil {
+				nodes = append(nodes, n.Node)
+			}
+		}
+		if len(nodes) == 0 {
+			log.Fatalf(tr("[FATAL] No hay nodos con .SIG en %s"), *projectPath)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	var results []GoldenNode
+	failed := 0
+	for _, node := range nodes {
+		r := GoldenNode{Node: node}
+		got, err := goldenOutputs(*projectPath, node, names)
+		if err == nil {
+			r.Files, err = golden.Compare(filepath.Join(*dir, node), got, *update)
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		if r.failed() {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		for _, r := range results {
+			fmt.Println(boldText("\n=== " + r.Node + " ==="))
+			if r.Error != "" {
+				fmt.Println(errText(r.Error))
+			}
+			for _, f := range r.Files {
+				switch f.Status {
+				case golden.StatusOK:
+					fmt.Println(okText("  = " + f.File))
+				case golden.StatusUpdated:
+					fmt.Println(warnText(trf("  ~ %s (referencia actualizada)", f.File)))
+				case golden.StatusMissing:
+					fmt.Println(errText(trf("  + %s (sin referencia: ejecute con -update)", f.File)))
+				case golden.StatusExtra:
+					fmt.Println(errText(trf("  - %s (solo en la referencia)", f.File)))
+				default:
+					fmt.Println(errText("  ! " + f.File))
+					if f.Binary {
+						fmt.Println("      " + tr("contenido binario distinto"))
+					}
+					for _, d := range f.Diff {
+						fmt.Println("      " + d)
+					}
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		log.Printf(tr("[ERROR] %d de %d nodo(s) no coinciden con la referencia"), failed, len(results))
+		os.Exit(1)
+	}
+}
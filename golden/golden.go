@@ -0,0 +1,209 @@
+// Package golden compara ficheros generados con una copia de referencia
+// (ficheros golden) en un directorio y, a petición, la reescribe. Lo usan
+// "dnpgen golden", las comprobaciones previas y el autotest, y se puede
+// importar desde las pruebas de un equipo que mantenga sus propias reglas:
+//
+//	files, err := golden.Compare("testdata/golden/RTU01", got, *update)
+//	if err != nil || golden.Failed(files) { ... }
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MaxDiff es el número de líneas distintas que se muestran por fichero.
+const MaxDiff = 20
+
+// Estados de un fichero.
+const (
+	StatusOK      = "ok"
+	StatusChanged = "changed"
+	StatusMissing = "missing" // sin referencia
+	StatusExtra   = "extra"   // solo en la referencia
+	StatusUpdated = "updated"
+)
+
+// File es el resultado de comparar un fichero con su referencia.
+type File struct {
+	File   string `json:"file"`
+	Status string `json:"status"`
+	// Binary indica un cambio en un fichero que no es texto; Diff va vacío.
+	Binary bool     `json:"binary,omitempty"`
+	Diff   []string `json:"diff,omitempty"`
+}
+
+// Failed indica si algún fichero difiere de la referencia.
+func Failed(files []File) bool {
+	for _, f := range files {
+		if f.Status != StatusOK && f.Status != StatusUpdated {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare compara los ficheros de got (contenido por nombre) con los de dir.
+// Con update reescribe en dir los que cambian y borra los que ya no se
+// generan.
+func Compare(dir string, got map[string][]byte, update bool) ([]File, error) {
+	names := make([]string, 0, len(got))
+	for name := range got {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []File
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		want, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return files, err
+		}
+		if update {
+			if err == nil && bytes.Equal(want, got[name]) {
+				files = append(files, File{File: name, Status: StatusOK})
+				continue
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return files, err
+			}
+			if err := writeFileAtomic(path, got[name]); err != nil {
+				return files, err
+			}
+			files = append(files, File{File: name, Status: StatusUpdated})
+			continue
+		}
+		switch {
+		case err != nil:
+			files = append(files, File{File: name, Status: StatusMissing})
+		case bytes.Equal(want, got[name]):
+			files = append(files, File{File: name, Status: StatusOK})
+		case !IsText(want) || !IsText(got[name]):
+			files = append(files, File{File: name, Status: StatusChanged, Binary: true})
+		default:
+			files = append(files, File{File: name, Status: StatusChanged, Diff: Diff(want, got[name])})
+		}
+	}
+	// Lo que hay en la referencia y ya no se genera también es un cambio.
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return files, err
+	}
+	for _, e := range entries {
+		if _, ok := got[e.Name()]; !ok && !e.IsDir() {
+			status := StatusExtra
+			if update {
+				if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+					return files, err
+				}
+				status = StatusUpdated
+			}
+			files = append(files, File{File: e.Name(), Status: status})
+		}
+	}
+	return files, nil
+}
+
+// Diff devuelve las líneas de texto que cambian ("-N: " en la referencia,
+// "+N: " en lo generado, con su número de línea), hasta MaxDiff. Los finales
+// de línea CRLF y LF se consideran iguales.
+func Diff(want, got []byte) []string {
+	a := strings.Split(strings.ReplaceAll(string(want), "\r\n", "\n"), "\n")
+	b := strings.Split(strings.ReplaceAll(string(got), "\r\n", "\n"), "\n")
+	m := MatchLines(a, b)
+	var diff []string
+	add := func(s string) bool {
+		if len(diff) == MaxDiff {
+			diff = append(diff, "...")
+			return false
+		}
+		diff = append(diff, s)
+		return true
+	}
+	j := 0
+	for i := 0; i <= len(a); i++ {
+		// Las líneas de b anteriores a la pareja de a[i] son nuevas.
+		next := len(b)
+		if i < len(a) {
+			if m[i] < 0 {
+				if !add(fmt.Sprintf("-%d: %s", i+1, a[i])) {
+					return diff
+				}
+				continue
+			}
+			next = m[i]
+		}
+		for ; j < next; j++ {
+			if !add(fmt.Sprintf("+%d: %s", j+1, b[j])) {
+				return diff
+			}
+		}
+		j = next + 1
+	}
+	return diff
+}
+
+// IsText indica si data parece texto: sin bytes nulos.
+func IsText(data []byte) bool {
+	return bytes.IndexByte(data, 0) < 0
+}
+
+// MatchLines empareja las líneas de a con las de b según una subsecuencia
+// común más larga: m[i] es la línea de b de a[i], o -1.
+func MatchLines(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+	// Prefijo y sufijo comunes aparte: las ediciones suelen ser pocas y la
+	// tabla queda pequeña.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		m[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		m[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+	x, y := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n := len(y) + 1
+	dp := make([]int32, (len(x)+1)*n)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				dp[i*n+j] = dp[(i+1)*n+j+1] + 1
+			} else {
+				dp[i*n+j] = max(dp[(i+1)*n+j], dp[i*n+j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		switch {
+		case x[i] == y[j]:
+			m[pre+i] = pre + j
+			i, j = i+1, j+1
+		case dp[(i+1)*n+j] >= dp[i*n+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return m
+}
+
+// writeFileAtomic escribe en un temporal y renombra, para no dejar una
+// referencia a medias.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
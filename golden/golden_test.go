@@ -0,0 +1,84 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func statuses(files []File) map[string]string {
+	out := map[string]string{}
+	for _, f := range files {
+		out[f.File] = f.Status
+	}
+	return out
+}
+
+func TestCompareAndUpdate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "RTU01")
+	got := map[string][]byte{
+		"__lists.ini": []byte("[DI]\r\nA\r\nB\r\n"),
+		"RTU01.bin":   {0, 1, 2},
+	}
+
+	files, err := Compare(dir, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"__lists.ini": StatusMissing, "RTU01.bin": StatusMissing}; !reflect.DeepEqual(statuses(files), want) || !Failed(files) {
+		t.Fatalf("sin referencia: %v", files)
+	}
+
+	if files, err = Compare(dir, got, true); err != nil || Failed(files) {
+		t.Fatalf("update: %v, %v", files, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "viejo.csv"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got["__lists.ini"] = []byte("[DI]\r\nA\r\nC\r\n")
+	got["RTU01.bin"] = []byte{0, 1, 3}
+	files, err = Compare(dir, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"__lists.ini": StatusChanged, "RTU01.bin": StatusChanged, "viejo.csv": StatusExtra}
+	if !reflect.DeepEqual(statuses(files), want) {
+		t.Fatalf("con cambios: %v", files)
+	}
+	for _, f := range files {
+		switch f.File {
+		case "__lists.ini":
+			if !reflect.DeepEqual(f.Diff, []string{"-3: B", "+3: C"}) {
+				t.Errorf("diff = %q", f.Diff)
+			}
+		case "RTU01.bin":
+			if !f.Binary || f.Diff != nil {
+				t.Errorf("binario: %+v", f)
+			}
+		}
+	}
+
+	if files, err = Compare(dir, got, true); err != nil || Failed(files) {
+		t.Fatalf("update: %v, %v", files, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "viejo.csv")); !os.IsNotExist(err) {
+		t.Errorf("update no borró el fichero sobrante: %v", err)
+	}
+	if files, _ = Compare(dir, got, false); Failed(files) {
+		t.Errorf("tras update: %v", files)
+	}
+}
+
+func TestDiffLimit(t *testing.T) {
+	var want, got []byte
+	for i := 0; i < 2*MaxDiff; i++ {
+		want = append(want, "a\n"...)
+		got = append(got, "b\n"...)
+	}
+	d := Diff(want, got)
+	if len(d) != MaxDiff+1 || d[MaxDiff] != "..." {
+		t.Fatalf("Diff = %d líneas, última %q", len(d), d[len(d)-1])
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"dnp3converter/api/generatorpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// --- SERVICIO gRPC ---
+
+// grpcServer implementa generatorpb.GeneratorServer sobre la misma cola de
+// trabajos que el servidor HTTP.
+type grpcServer struct {
+	generatorpb.UnimplementedGeneratorServer
+	store *jobStore
+	cfg   ServerConfig
+}
+
+func serveGRPC(ctx context.Context, addr string, store *jobStore, cfg ServerConfig) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	auth := func(ctx context.Context) error {
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get("authorization"); len(v) > 0 {
+				header = v[0]
+			}
+		}
+		if !cfg.authorized(header) {
+			return status.Error(codes.Unauthenticated, "token no válido")
+		}
+		return nil
+	}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if err := auth(ctx); err != nil {
+				return nil, err
+			}
+			return h(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if err := auth(ss.Context()); err != nil {
+				return err
+			}
+			return h(srv, ss)
+		}),
+	)
+	generatorpb.RegisterGeneratorServer(srv, &grpcServer{store: store, cfg: cfg})
+	// Al cancelarse ctx se esperan las llamadas en curso; un Watch abierto
+	// no termina solo, así que el plazo es el mismo que el de HTTP.
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		t := time.AfterFunc(serverShutdownTimeout, srv.Stop)
+		defer t.Stop()
+		srv.GracefulStop()
+	}()
+	log.Printf("Servidor gRPC escuchando en %s", addr)
+	if err := srv.Serve(lis); err != nil {
+		return err
+	}
+	<-stopped
+	return nil
+}
+
+func (g *grpcServer) submit(req *generatorpb.GenerateRequest) (*Job, error) {
+	if req.GetProject() == "" || req.GetNode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "se requieren 'project' y 'node'")
+	}
+	r := GenerateRequest{
+		ProjectPath:   req.GetProject(),
+		NodeName:      req.GetNode(),
+		SkipExt:       req.GetSkipExt(),
+		CheckOnly:     req.GetCheckOnly(),
+		Incremental:   req.GetIncremental(),
+		OutDir:        req.GetOutDir(),
+		Only:          req.GetOnly(),
+		ProgressEvery: int(req.GetProgressEvery()),
+	}
+	if err := g.cfg.checkRequest(r); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	j, err := g.store.enqueue(r)
+	if err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return j, nil
+}
+
+func (g *grpcServer) Submit(ctx context.Context, req *generatorpb.GenerateRequest) (*generatorpb.JobStatus, error) {
+	j, err := g.submit(req)
+	if err != nil {
+		return nil, err
+	}
+	return g.GetStatus(ctx, &generatorpb.JobRef{Id: j.ID})
+}
+
+func (g *grpcServer) Generate(req *generatorpb.GenerateRequest, stream grpc.ServerStreamingServer[generatorpb.JobEvent]) error {
+	j, err := g.submit(req)
+	if err != nil {
+		return err
+	}
+	return g.Watch(&generatorpb.JobRef{Id: j.ID}, stream)
+}
+
+func (g *grpcServer) Watch(ref *generatorpb.JobRef, stream grpc.ServerStreamingServer[generatorpb.JobEvent]) error {
+	next := 0
+	for {
+		events, done, changed, ok := g.store.watch(ref.GetId(), next)
+		if !ok {
+			return status.Error(codes.NotFound, "trabajo no encontrado")
+		}
+		for _, ev := range events {
+			pe := &generatorpb.JobEvent{
+				Id:         ref.GetId(),
+				Stage:      ev.Stage,
+				Message:    ev.Message,
+				UnixMillis: ev.Time.UnixMilli(),
+				Kind:       ev.Kind,
+				Code:       ev.Code,
+			}
+			if ev.Signals != 0 || ev.Read != 0 || ev.Size != 0 || ev.Millis != 0 {
+				pe.Progress = &generatorpb.Progress{
+					Signals:   int32(ev.Signals),
+					ReadBytes: ev.Read,
+					SizeBytes: ev.Size,
+					ElapsedMs: ev.Millis,
+				}
+			}
+			if err := stream.Send(pe); err != nil {
+				return err
+			}
+		}
+		next += len(events)
+		if done {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (g *grpcServer) GetStatus(_ context.Context, ref *generatorpb.JobRef) (*generatorpb.JobStatus, error) {
+	j, ok := g.store.get(ref.GetId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "trabajo no encontrado")
+	}
+	st := &generatorpb.JobStatus{Id: j.ID, State: j.State, Error: j.Error, ErrorCode: j.ErrorCode}
+	if j.Result != nil {
+		st.Counts = &generatorpb.Counts{
+			Di: int32(j.Result.DI),
+			Do: int32(j.Result.DO),
+			Ai: int32(j.Result.AI),
+			Ao: int32(j.Result.AO),
+		}
+	}
+	return st, nil
+}
+
+func (g *grpcServer) GetArtifact(_ context.Context, ref *generatorpb.JobRef) (*generatorpb.Artifact, error) {
+	j, ok := g.store.get(ref.GetId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "trabajo no encontrado")
+	}
+	if j.State != JobDone {
+		return nil, status.Errorf(codes.FailedPrecondition, "trabajo no finalizado (%s)", j.State)
+	}
+	return &generatorpb.Artifact{Id: j.ID, Name: ListFile, Content: j.artifact}, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"dnp3converter/api/generatorpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCWatchForwardsGenerationEvents(t *testing.T) {
+	store, err := newJobStore("", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := store.enqueue(GenerateRequest{ProjectPath: "P", NodeName: "N"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	store.mu.Lock()
+	store.addEvent(j, "parse", "")
+	store.addJobEvent(j, JobEvent{Stage: "parse", Kind: EventProgress, Signals: 10, Read: 100, Size: 400, Time: now})
+	store.addJobEvent(j, JobEvent{Stage: "parse", Kind: EventWarning, Code: "CW2001", Message: "aviso", Time: now})
+	store.addJobEvent(j, JobEvent{Stage: "parse", Kind: EventStageFinished, Millis: 12, Time: now})
+	j.State = JobDone
+	store.addEvent(j, JobDone, "")
+	store.mu.Unlock()
+
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer()
+	generatorpb.RegisterGeneratorServer(srv, &grpcServer{store: store})
+	go srv.Serve(lis)
+	defer srv.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := generatorpb.NewGeneratorClient(conn).Watch(ctx, &generatorpb.JobRef{Id: j.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*generatorpb.JobEvent
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ev)
+	}
+
+	if len(got) != 6 {
+		t.Fatalf("%d eventos, se esperaban 6: %v", len(got), got)
+	}
+	if p := got[2]; p.GetKind() != EventProgress || p.GetProgress().GetSignals() != 10 || p.GetProgress().GetReadBytes() != 100 || p.GetProgress().GetSizeBytes() != 400 {
+		t.Errorf("progress = %v", p)
+	}
+	if w := got[3]; w.GetKind() != EventWarning || w.GetCode() != "CW2001" || w.GetMessage() != "aviso" {
+		t.Errorf("warning = %v", w)
+	}
+	if f := got[4]; f.GetKind() != EventStageFinished || f.GetProgress().GetElapsedMs() != 12 {
+		t.Errorf("stage_finished = %v", f)
+	}
+	if d := got[5]; d.GetKind() != "" || d.GetStage() != JobDone || d.GetProgress() != nil {
+		t.Errorf("done = %v", d)
+	}
+}
+
+func TestGRPCSubmitMatchesHTTPRequest(t *testing.T) {
+	store, err := newJobStore("", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &grpcServer{store: store}
+	st, err := g.Submit(context.Background(), &generatorpb.GenerateRequest{
+		Project: "P", Node: "N", SkipExt: true, CheckOnly: true, Incremental: true,
+		OutDir: "salida", Only: []string{"AI", "AO"}, ProgressEvery: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, _ := store.get(st.GetId())
+	want := GenerateRequest{ProjectPath: "P", NodeName: "N", SkipExt: true, CheckOnly: true, Incremental: true,
+		OutDir: "salida", Only: []string{"AI", "AO"}, ProgressEvery: 50}
+	if !reflect.DeepEqual(j.Request, want) {
+		t.Errorf("Request = %+v, se esperaba %+v", j.Request, want)
+	}
+}
+
+func TestGRPCGetStatusReportsErrorCode(t *testing.T) {
+	store, err := newJobStore("", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := store.enqueue(GenerateRequest{ProjectPath: "P", NodeName: "N"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.mu.Lock()
+	j.State, j.Error, j.ErrorCode = JobFailed, "sin .SIG", ErrConfigInvalid
+	store.mu.Unlock()
+
+	st, err := (&grpcServer{store: store}).GetStatus(context.Background(), &generatorpb.JobRef{Id: j.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetState() != JobFailed || st.GetError() != "sin .SIG" || st.GetErrorCode() != ErrConfigInvalid {
+		t.Errorf("GetStatus = %v", st)
+	}
+}
@@ -0,0 +1,655 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- INTERFAZ GRÁFICA ---
+//
+// "dnpgen gui" es la interfaz para quien no abre un terminal: sirve una
+// página en 127.0.0.1 y abre el navegador. Elige proyecto y nodo (o se
+// arrastra el .mwt o el .SIG del nodo), edita las reglas de clasificación
+// con vista previa de cómo quedan las listas y genera con un clic, viendo el
+// avance con los eventos de GenerateRequest. No usa un toolkit de escritorio
+// (Wails, Fyne) para no exigir cgo ni WebView2 y seguir siendo un único
+// ejecutable.
+
+// guiRules son las reglas de clasificación que se editan en la interfaz.
+type guiRules struct {
+	Analog  []string `json:"analog_output_regex"`
+	Digital []string `json:"digital_output_regex"`
+}
+
+// guiPoint es un punto de la vista previa.
+type guiPoint struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Var   string `json:"var"`
+	Type  string `json:"type"`
+	Rule  string `json:"rule,omitempty"`
+	Spare bool   `json:"spare,omitempty"`
+	// Moved es la lista en la que estaba con las reglas de config.yaml, si
+	// las reglas editadas lo cambian de lista.
+	Moved string `json:"moved,omitempty"`
+}
+
+type guiList struct {
+	Name   string     `json:"name"`
+	Title  string     `json:"title"`
+	Points []guiPoint `json:"points"`
+}
+
+type guiPreview struct {
+	Signals int            `json:"signals"`
+	Lists   []guiList      `json:"lists"`
+	Unknown map[string]int `json:"unknown,omitempty"`
+	Moved   int            `json:"moved"`
+}
+
+// guiServer atiende la interfaz. mu serializa las operaciones: la vista
+// previa cambia las reglas de GlobalConfig mientras clasifica.
+type guiServer struct {
+	mu      sync.Mutex
+	project string
+	config  string // config.yaml cargado, donde se guardan las reglas
+}
+
+func (g *guiServer) rules() guiRules {
+	c := GlobalConfig.App.Classification
+	return guiRules{Analog: c.AnalogRegex, Digital: c.DigitalRegex}
+}
+
+// validate compila las reglas y devuelve el primer error: la generación
+// ignora los patrones inválidos y aquí hay que avisar de ellos.
+func (r guiRules) validate() error {
+	if _, err := compileRules(r.Analog, false); err != nil {
+		return fmt.Errorf("analog_output_regex: %v", err)
+	}
+	if _, err := compileRules(r.Digital, false); err != nil {
+		return fmt.Errorf("digital_output_regex: %v", err)
+	}
+	return nil
+}
+
+// withRules ejecuta fn con las reglas r en GlobalConfig. Debe llamarse con
+// g.mu tomado.
+func (g *guiServer) withRules(r guiRules, fn func() error) error {
+	c := &GlobalConfig.App.Classification
+	analog, digital := c.AnalogRegex, c.DigitalRegex
+	defer func() { c.AnalogRegex, c.DigitalRegex = analog, digital }()
+	c.AnalogRegex, c.DigitalRegex = r.Analog, r.Digital
+	return fn()
+}
+
+// preview clasifica sig con las reglas editadas y marca los puntos que
+// cambian de lista respecto a las de config.yaml.
+func (g *guiServer) preview(sig string, r guiRules) (*guiPreview, error) {
+	before, _, err := processSigFile(sig, nil)
+	if err != nil {
+		return nil, err
+	}
+	listOf := map[string]string{}
+	for _, s := range listSections(before) {
+		for _, p := range s.Items {
+			if !p.Spare {
+				listOf[p.Var] = s.Name
+			}
+		}
+	}
+	var after *Lists
+	var rep *sigReport
+	if err := g.withRules(r, func() error {
+		after, rep, err = processSigFile(sig, nil)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	out := &guiPreview{Signals: rep.Signals, Unknown: rep.Unknown}
+	for _, s := range listSections(after) {
+		l := guiList{Name: s.Name, Title: s.Title, Points: []guiPoint{}}
+		for i, p := range s.Items {
+			gp := guiPoint{Index: i, Name: p.Name, Var: p.Var, Type: p.Type, Rule: p.Rule, Spare: p.Spare}
+			if was := listOf[p.Var]; !p.Spare && was != "" && was != s.Name {
+				gp.Moved = was
+				out.Moved++
+			}
+			l.Points = append(l.Points, gp)
+		}
+		out.Lists = append(out.Lists, l)
+	}
+	return out, nil
+}
+
+// saveRules escribe las reglas en config.yaml. Solo se sustituyen las
+// líneas de las dos claves (o se añaden bajo classification), de modo que el
+// resto del fichero y sus comentarios quedan igual; el original queda en
+// config.yaml.bak.
+func saveRules(path string, r guiRules) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		return fmt.Errorf(tr("%s está vacío"), path)
+	}
+	_, app := yamlKey(root.Content[0], "app")
+	clsKey, cls := yamlKey(app, "classification")
+	if cls == nil || cls.Kind != yaml.MappingNode || len(cls.Content) == 0 {
+		return fmt.Errorf(tr("%s no tiene app.classification"), path)
+	}
+	indent := cls.Content[0].Column - 1
+	lines := strings.SplitAfter(string(data), "\n")
+	block := func(key string, values []string) []string {
+		pad := strings.Repeat(" ", indent)
+		if len(values) == 0 {
+			return []string{pad + key + ": []\n"}
+		}
+		out := []string{pad + key + ":\n"}
+		for _, v := range values {
+			out = append(out, pad+"  - "+strconv.Quote(v)+"\n")
+		}
+		return out
+	}
+	// Las sustituciones van de abajo arriba para no mover las siguientes.
+	type edit struct {
+		from, to int // líneas [from, to), base 0
+		repl     []string
+	}
+	var edits []edit
+	for _, kv := range []struct {
+		key    string
+		values []string
+	}{{"analog_output_regex", r.Analog}, {"digital_output_regex", r.Digital}} {
+		k, v := yamlKey(cls, kv.key)
+		if k == nil {
+			edits = append(edits, edit{clsKey.Line, clsKey.Line, block(kv.key, kv.values)})
+			continue
+		}
+		last := max(k.Line, v.Line)
+		for _, item := range v.Content {
+			last = max(last, item.Line)
+		}
+		edits = append(edits, edit{k.Line - 1, last, block(kv.key, kv.values)})
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].from > edits[j].from })
+	for _, e := range edits {
+		lines = append(lines[:e.from], append(e.repl, lines[e.to:]...)...)
+	}
+	if err := writeFileAtomic(path+".bak", data); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, []byte(strings.Join(lines, "")))
+}
+
+// yamlKey devuelve la clave y el valor de key en el mapa n, o nil.
+func yamlKey(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if n == nil {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// guiDir es un directorio del selector de proyecto.
+type guiDir struct {
+	Path    string   `json:"path"`
+	Parent  string   `json:"parent,omitempty"`
+	Dirs    []string `json:"dirs"`
+	Project bool     `json:"project"` // tiene nodos
+}
+
+func listGuiDir(path string) (*guiDir, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		return nil, err
+	}
+	d := &guiDir{Path: abs, Dirs: []string{}}
+	if parent := filepath.Dir(abs); parent != abs {
+		d.Parent = parent
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			d.Dirs = append(d.Dirs, e.Name())
+		}
+	}
+	sort.Strings(d.Dirs)
+	if nodes, err := discoverNodes(abs); err == nil && len(nodes) > 0 {
+		d.Project = true
+	}
+	return d, nil
+}
+
+func (g *guiServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, guiPage)
+	})
+	mux.HandleFunc("GET /api/state", func(w http.ResponseWriter, r *http.Request) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"project": g.project, "config": g.config, "rules": g.rules(), "version": buildInfo().Version})
+	})
+	mux.HandleFunc("GET /api/dirs", func(w http.ResponseWriter, r *http.Request) {
+		d, err := listGuiDir(r.URL.Query().Get("path"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, d)
+	})
+	mux.HandleFunc("GET /api/nodes", func(w http.ResponseWriter, r *http.Request) {
+		abs, err := filepath.Abs(r.URL.Query().Get("project"))
+		if err == nil {
+			var nodes []nodeInfo
+			if nodes, err = listProjectNodes(abs); err == nil {
+				g.mu.Lock()
+				g.project = abs
+				g.mu.Unlock()
+				writeJSON(w, http.StatusOK, nodes)
+				return
+			}
+		}
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	})
+	// La vista previa clasifica el .SIG del nodo o, si se ha arrastrado un
+	// .SIG, su contenido.
+	mux.HandleFunc("POST /api/preview", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Project string   `json:"project"`
+			Node    string   `json:"node"`
+			Sig     string   `json:"sig"` // contenido de un .SIG arrastrado
+			Rules   guiRules `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := body.Rules.validate(); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		sig := ""
+		if body.Sig != "" {
+			f, err := os.CreateTemp("", "dnpgen-gui-*.SIG")
+			if err == nil {
+				_, err = f.WriteString(body.Sig)
+				f.Close()
+				defer os.Remove(f.Name())
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			sig = f.Name()
+		} else {
+			abs, err := filepath.Abs(body.Project)
+			if err != nil || body.Node == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": tr("se requieren proyecto y nodo, o un .SIG")})
+				return
+			}
+			sig = nodePathsFor(abs, body.Node).Sig
+		}
+		g.mu.Lock()
+		p, err := g.preview(sig, body.Rules)
+		g.mu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	})
+	mux.HandleFunc("POST /api/rules", func(w http.ResponseWriter, r *http.Request) {
+		var rules guiRules
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := rules.validate(); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if err := saveRules(g.config, rules); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		GlobalConfig.App.Classification.AnalogRegex = rules.Analog
+		GlobalConfig.App.Classification.DigitalRegex = rules.Digital
+		log.Printf(tr("Reglas de clasificación guardadas en %s"), g.config)
+		writeJSON(w, http.StatusOK, map[string]string{"config": g.config})
+	})
+	// La generación responde con una línea JSON por evento y una última con
+	// el resultado o el error.
+	mux.HandleFunc("POST /api/generate", func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ProjectPath == "" || req.NodeName == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": tr("se requieren proyecto y nodo")})
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		send := func(v any) {
+			enc.Encode(v)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		req.Events = func(e GenerateEvent) { send(map[string]any{"event": e}) }
+		g.mu.Lock()
+		res, err := runGenerate(req)
+		g.mu.Unlock()
+		if err != nil {
+			send(map[string]string{"error": err.Error(), "error_code": errorCode(err)})
+			return
+		}
+		send(map[string]any{"result": res})
+	})
+	return mux
+}
+
+// guiGuard solo atiende peticiones dirigidas a la dirección de escucha y
+// del mismo origen: la interfaz lee el disco y escribe config.yaml, y no debe
+// poder usarla otra página abierta en el navegador.
+func guiGuard(addr string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, port, _ := net.SplitHostPort(addr)
+		host, p, _ := net.SplitHostPort(r.Host)
+		local := p == port && (host == "localhost" || net.ParseIP(host).IsLoopback() || r.Host == addr)
+		if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+			local = false
+		}
+		if !local {
+			http.Error(w, tr("origen no permitido"), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// openBrowser abre url en el navegador del sistema.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// runGUI implementa "dnpgen gui [-path RUTA] [-addr 127.0.0.1:0]
+// [-no-browser]".
+func runGUI(args []string) {
+	fs := flag.NewFlagSet("gui", flag.ExitOnError)
+	fs.Usage = manUsage("gui", fs)
+	projectPath := fs.String("path", "", tr("Proyecto que se abre al arrancar"))
+	addr := fs.String("addr", "127.0.0.1:0", tr("Dirección de escucha (por defecto, un puerto libre local)"))
+	noBrowser := fs.Bool("no-browser", false, tr("No abrir el navegador"))
+	fs.Parse(args)
+
+	g := &guiServer{config: configSource}
+	if *projectPath != "" {
+		abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		g.project = abs
+	} else if wd, err := os.Getwd(); err == nil {
+		g.project = wd
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	url := "http://" + ln.Addr().String() + "/"
+	log.Printf(tr("Interfaz en %s (Ctrl+C para salir)"), url)
+	if !*noBrowser {
+		if err := openBrowser(url); err != nil {
+			log.Printf(tr("[WARN] No se pudo abrir el navegador: %v"), err)
+		}
+	}
+	log.Fatal(http.Serve(ln, guiGuard(ln.Addr().String(), g.routes())))
+}
+
+const guiPage = `<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>dnpgen</title>
+<style>
+body { font-family: sans-serif; margin: 0; display: flex; height: 100vh; }
+aside { width: 340px; padding: 1em; background: #f4f4f4; overflow-y: auto; box-sizing: border-box; }
+main { flex: 1; padding: 1em; overflow-y: auto; }
+h2 { font-size: 1em; margin: 1.2em 0 .4em; }
+input[type=text], textarea, select { width: 100%; box-sizing: border-box; }
+textarea { height: 7em; font-family: monospace; }
+button { margin: .3em .3em 0 0; }
+#dirs div { cursor: pointer; padding: 1px 4px; }
+#dirs div:hover { background: #ddd; }
+#drop { border: 2px dashed #aaa; padding: 1em; text-align: center; color: #666; margin-top: .5em; }
+#drop.over { border-color: #36c; color: #36c; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 1px 6px; text-align: left; font-size: 90%; }
+th { background: #eee; }
+tr.spare td { color: #999; }
+tr.moved td { background: #fff3c4; }
+.error { color: #b00; }
+.ok { color: #070; }
+#log { font-family: monospace; font-size: 85%; white-space: pre-wrap; }
+progress { width: 100%; }
+</style>
+</head>
+<body>
+<aside>
+<h2>Proyecto</h2>
+<input type="text" id="project">
+<div id="dirs"></div>
+<h2>Nodo</h2>
+<select id="node"></select>
+<div id="drop">Arrastre aquí el .mwt o el .SIG del nodo</div>
+<h2>Salidas analógicas (analog_output_regex)</h2>
+<textarea id="analog"></textarea>
+<h2>Salidas digitales (digital_output_regex)</h2>
+<textarea id="digital"></textarea>
+<div>
+<button id="save">Guardar reglas en config.yaml</button>
+<button id="reset">Deshacer cambios</button>
+</div>
+<h2>Generar</h2>
+<label><input type="checkbox" id="skipext"> Sin SIGEXT (usar el .SIG existente)</label><br>
+<button id="generate"><b>Generar listas</b></button>
+<progress id="progress" value="0" max="1"></progress>
+<div id="status"></div>
+<div id="log"></div>
+</aside>
+<main>
+<div id="summary"></div>
+<div id="preview"></div>
+</main>
+<script>
+const $ = id => document.getElementById(id);
+let saved = {analog_output_regex: [], digital_output_regex: []};
+let droppedSig = "";
+let timer = null;
+
+const lines = t => t.split("\n").map(s => s.trim()).filter(s => s);
+const rules = () => ({analog_output_regex: lines($("analog").value), digital_output_regex: lines($("digital").value)});
+const esc = s => String(s ?? "").replace(/[&<>"]/g, c => ({"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"}[c]));
+const status = (msg, cls) => { $("status").innerHTML = '<span class="' + (cls || "") + '">' + esc(msg) + "</span>"; };
+
+async function api(path, body) {
+  const r = await fetch(path, body === undefined ? {} : {method: "POST", body: JSON.stringify(body)});
+  const data = await r.json();
+  if (!r.ok) throw new Error(data.error);
+  return data;
+}
+
+function setRules(r) {
+  $("analog").value = (r.analog_output_regex || []).join("\n");
+  $("digital").value = (r.digital_output_regex || []).join("\n");
+}
+
+async function browse(path) {
+  try {
+    const d = await api("/api/dirs?path=" + encodeURIComponent(path));
+    $("project").value = d.path;
+    let html = d.parent ? '<div data-path="' + esc(d.parent) + '">..</div>' : "";
+    for (const name of d.dirs) html += '<div data-path="' + esc(d.path + "/" + name) + '">' + esc(name) + "/</div>";
+    $("dirs").innerHTML = html;
+    if (d.project) loadNodes(); else $("node").innerHTML = "";
+  } catch (e) { status(e.message, "error"); }
+}
+
+async function loadNodes() {
+  try {
+    const nodes = await api("/api/nodes?project=" + encodeURIComponent($("project").value));
+    $("node").innerHTML = nodes.map(n => '<option value="' + esc(n.node) + '">' + esc(n.node) + (n.sig_time ? "" : " (sin .SIG)") + "</option>").join("");
+    droppedSig = "";
+    schedulePreview();
+  } catch (e) { status(e.message, "error"); }
+}
+
+function schedulePreview() {
+  clearTimeout(timer);
+  timer = setTimeout(preview, 300);
+}
+
+async function preview() {
+  if (!droppedSig && !$("node").value) return;
+  try {
+    const p = await api("/api/preview", {project: $("project").value, node: $("node").value, sig: droppedSig, rules: rules()});
+    let sum = "<p>" + p.signals + " señales" + (droppedSig ? " (.SIG arrastrado)" : "") + " · ";
+    sum += p.lists.map(l => esc(l.name) + ": " + l.points.length).join(" · ");
+    if (p.moved) sum += ' · <b>' + p.moved + " punto(s) cambian de lista con las reglas editadas</b>";
+    if (p.unknown) sum += '<br><span class="error">Tipos sin clasificar: ' + esc(Object.entries(p.unknown).map(([t, n]) => t + " (" + n + ")").join(", ")) + "</span>";
+    $("summary").innerHTML = sum + "</p>";
+    let html = "";
+    for (const l of p.lists) {
+      html += "<h3>" + esc(l.name) + " - " + esc(l.title) + "</h3><table><tr><th>#</th><th>Punto</th><th>Tipo</th><th>Regla</th><th>Antes</th></tr>";
+      for (const pt of l.points) {
+        const cls = pt.moved ? "moved" : pt.spare ? "spare" : "";
+        html += '<tr class="' + cls + '"><td>' + pt.index + "</td><td>" + esc(pt.name) + "</td><td>" + esc(pt.type) + "</td><td>" + esc(pt.rule) + "</td><td>" + esc(pt.moved) + "</td></tr>";
+      }
+      html += "</table>";
+    }
+    $("preview").innerHTML = html;
+    status("");
+  } catch (e) { status(e.message, "error"); }
+}
+
+async function generate() {
+  const log = $("log");
+  log.textContent = JSON.stringify(rules()) === JSON.stringify(saved) ? "" : "Hay reglas sin guardar: se genera con las de config.yaml\n";
+  $("progress").removeAttribute("value");
+  $("generate").disabled = true;
+  try {
+    const r = await fetch("/api/generate", {method: "POST", body: JSON.stringify({project: $("project").value, node: $("node").value, skip_ext: $("skipext").checked})});
+    const reader = r.body.getReader();
+    const dec = new TextDecoder();
+    let buf = "";
+    for (;;) {
+      const {done, value} = await reader.read();
+      if (done) break;
+      buf += dec.decode(value, {stream: true});
+      let i;
+      while ((i = buf.indexOf("\n")) >= 0) {
+        const msg = JSON.parse(buf.slice(0, i));
+        buf = buf.slice(i + 1);
+        if (msg.event) {
+          const e = msg.event;
+          if (e.kind === "stage_started") log.textContent += e.message + "\n";
+          if (e.kind === "warning") log.textContent += "AVISO: " + e.message + "\n";
+          if (e.kind === "progress" && e.size) { $("progress").max = e.size; $("progress").value = e.read; }
+        } else if (msg.error) {
+          status(msg.error, "error");
+        } else if (msg.result) {
+          const res = msg.result;
+          status("Generado " + (res.list_file || "") + " · DI " + res.di + " · DO " + res.do + " · AI " + res.ai + " · AO " + res.ao, "ok");
+        }
+      }
+    }
+  } catch (e) { status(e.message, "error"); }
+  $("progress").max = 1; $("progress").value = 1;
+  $("generate").disabled = false;
+}
+
+$("project").addEventListener("change", () => browse($("project").value));
+$("dirs").addEventListener("click", e => { if (e.target.dataset.path) browse(e.target.dataset.path); });
+$("node").addEventListener("change", () => { droppedSig = ""; schedulePreview(); });
+$("analog").addEventListener("input", schedulePreview);
+$("digital").addEventListener("input", schedulePreview);
+$("reset").addEventListener("click", () => { setRules(saved); schedulePreview(); });
+$("generate").addEventListener("click", generate);
+$("save").addEventListener("click", async () => {
+  try {
+    const r = await api("/api/rules", rules());
+    saved = rules();
+    status("Reglas guardadas en " + r.config + " (copia en config.yaml.bak)", "ok");
+  } catch (e) { status(e.message, "error"); }
+});
+
+const drop = $("drop");
+drop.addEventListener("dragover", e => { e.preventDefault(); drop.classList.add("over"); });
+drop.addEventListener("dragleave", () => drop.classList.remove("over"));
+drop.addEventListener("drop", async e => {
+  e.preventDefault();
+  drop.classList.remove("over");
+  const f = e.dataTransfer.files[0];
+  if (!f) return;
+  const base = f.name.replace(/\.[^.]*$/, "");
+  const known = [...$("node").options].some(o => o.value === base);
+  if (known) $("node").value = base;
+  if (/\.sig$/i.test(f.name)) {
+    droppedSig = await f.text();
+    drop.textContent = f.name + (known ? "" : " (no es un nodo del proyecto: solo vista previa)");
+  } else if (/\.mwt$/i.test(f.name)) {
+    droppedSig = "";
+    drop.textContent = known ? f.name : f.name + ": el nodo " + base + " no está en el proyecto";
+  }
+  schedulePreview();
+});
+
+api("/api/state").then(s => {
+  saved = s.rules;
+  setRules(s.rules);
+  document.title = "dnpgen " + s.version;
+  browse(s.project);
+});
+</script>
+</body>
+</html>
+`
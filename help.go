@@ -0,0 +1,520 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// --- AYUDA INTEGRADA ---
+//
+// Los técnicos de campo trabajan sin conexión: la ayuda de cada subcomando
+// (-h o "dnpgen help <subcomando>") sale con formato de página de manual,
+// con los flags agrupados y ejemplos, y "dnpgen examples" recorre las
+// invocaciones habituales de principio a fin.
+
+// helpExample es un ejemplo de invocación.
+type helpExample struct {
+	Desc, Cmd string
+}
+
+// commandHelp es la página de ayuda de un subcomando. "" es la generación
+// normal.
+type commandHelp struct {
+	Name     string
+	Summary  string
+	Synopsis string
+	Examples []helpExample
+}
+
+var commandHelps = []commandHelp{
+	{"", "Genera __lists.ini y las exportaciones de uno o varios nodos",
+		`dnpgen [-path RUTA -node NODO | -path RUTA -all | -workspace plantas.yaml] [-skip-ext] [-out DIR] [-incremental] [-only ai,ao] [-resume]`,
+		[]helpExample{
+			{"Un nodo, con el .SIG existente", `dnpgen -path "D:\Proyectos\Planta" -node RTU01 -skip-ext`},
+			{"Todos los nodos del proyecto", `dnpgen -path "D:\Proyectos\Planta" -all`},
+			{"Varios proyectos descritos en un workspace", `dnpgen -workspace plantas.yaml`},
+			{"Continuar un lote interrumpido por el primer nodo sin terminar", `dnpgen -path "D:\Proyectos\Planta" -all -resume`},
+			{"Regenerar solo las analógicas y conservar las digitales", `dnpgen -path "D:\Proyectos\Planta" -node RTU01 -only ai,ao`},
+		}},
+	{"server", "Servidor HTTP/gRPC con cola de trabajos de generación",
+		`dnpgen server [-addr 127.0.0.1:8080] [-grpc-addr 127.0.0.1:9090] [-queue-dir DIR] [-workers N] [-queue-limit N] [-log FICHERO]`,
+		[]helpExample{
+			{"Servidor HTTP local con la cola en disco", `dnpgen server -queue-dir D:\dnpgen\cola`},
+			{"Escuchar en la red (exige app.server.token y app.server.roots)", `dnpgen server -addr 0.0.0.0:8080 -queue-dir D:\dnpgen\cola`},
+		}},
+	{"service", "Instala, desinstala o ejecuta el servidor como servicio de Windows",
+		`dnpgen service install|uninstall|run [flags de server]`,
+		[]helpExample{
+			{"Instalar el servicio con sus flags de server", `dnpgen service install -queue-dir D:\dnpgen\cola -log D:\dnpgen\server.log`},
+		}},
+	{"simulate", "Outstation DNP3 simulada con las listas de un nodo",
+		`dnpgen simulate -path RUTA -node NODO | -lists __lists.ini [-addr 0.0.0.0:20000]`,
+		[]helpExample{
+			{"Simular la RTU a partir de un __lists.ini", `dnpgen simulate -lists __lists.ini -addr 127.0.0.1:20000`},
+		}},
+	{"verify", "Compara las listas de un nodo con lo que expone la RTU real",
+		`dnpgen verify -path RUTA -node NODO | -lists __lists.ini -host IP [-port 20000]`,
+		[]helpExample{
+			{"Verificar una RTU en campo", `dnpgen verify -path "D:\Proyectos\Planta" -node RTU01 -host 10.1.2.21`},
+		}},
+	{"query", "Consulta la base de puntos SQLite del proyecto",
+		`dnpgen query -path RUTA | -db FICHERO [-name PATRÓN] [-list LISTA] [-node NODO] [-from N] [-to N] [-spares]`,
+		[]helpExample{
+			{"Dónde está cada señal de la bomba P101", `dnpgen query -path "D:\Proyectos\Planta" -name "P101_*"`},
+		}},
+	{"nodes", "Lista los nodos del proyecto y el estado de sus ficheros",
+		`dnpgen nodes -path RUTA [-json]`,
+		[]helpExample{
+			{"Nodos del proyecto en JSON", `dnpgen nodes -path "D:\Proyectos\Planta" -json`},
+		}},
+	{"gen-fixture", "Genera un .SIG o un proyecto sintético para pruebas",
+		`dnpgen gen-fixture [-out FICHERO.SIG | -project DIR] [-node NODO] [-ai N -ao N -di N -do N] [-seed N]`,
+		[]helpExample{
+			{"Proyecto de prueba con 500 entradas digitales", `dnpgen gen-fixture -project C:\tmp\prueba -di 500`},
+		}},
+	{"bench", "Mide el rendimiento del parseo y la clasificación",
+		`dnpgen bench [-signals N] [-runs N] | -sig FICHERO.SIG`,
+		[]helpExample{
+			{"Medir con un .SIG real", `dnpgen bench -sig RTU01.SIG -runs 5`},
+		}},
+	{"rules", "Revisión de la configuración: lint (reglas de clasificación), names (nombres) e impact (cambio de reglas)",
+		`dnpgen rules lint|names|impact [flags]`,
+		[]helpExample{
+			{"Página de cada revisión", `dnpgen help rules lint`},
+		}},
+	{"rules lint", "Detecta reglas de clasificación muertas y en conflicto",
+		`dnpgen rules lint -path RUTA -node NODO | -sig FICHERO.SIG [-strict]`,
+		[]helpExample{
+			{"Revisar las reglas contra un .SIG", `dnpgen rules lint -sig RTU01.SIG -strict`},
+		}},
+	{"rules impact", "Puntos que cambian de lista o de índice entre dos juegos de reglas",
+		`dnpgen rules impact -path RUTA (-node NODO[,NODO...] | -all) -new REGLAS.yaml [-old REGLAS.yaml] [-out DIR] [-json] [-strict]`,
+		[]helpExample{
+			{"Revisar unas reglas nuevas en todos los nodos", `dnpgen rules impact -path "D:\Proyectos\Planta" -all -new reglas_v2.yaml`},
+			{"Comparar dos propuestas entre sí", `dnpgen rules impact -path "D:\Proyectos\Planta" -node RTU01 -old reglas_a.yaml -new reglas_b.yaml -json`},
+		}},
+	{"rules names", "Informe de infracciones de las convenciones de nombres",
+		`dnpgen rules names -path RUTA [-node NODO] [-format text|csv] [-strict]`,
+		[]helpExample{
+			{"Infracciones de todos los nodos en CSV", `dnpgen rules names -path "D:\Proyectos\Planta" -format csv > nombres.csv`},
+		}},
+	{"export-model", "Exporta el modelo de puntos completo (índices, metadatos y regla)",
+		`dnpgen export-model -path RUTA -node NODO [-skip-ext] [-format json|yaml] [-o FICHERO] [-anonymize]`,
+		[]helpExample{
+			{"Guardar el modelo de un nodo", `dnpgen export-model -path "D:\Proyectos\Planta" -node RTU01 -o RTU01.model.json`},
+			{"Modelo sin nombres del cliente para soporte", `dnpgen export-model -path "D:\Proyectos\Planta" -node RTU01 -anonymize -o caso.model.json`},
+		}},
+	{"render", "Genera listas y exportaciones a partir de un modelo guardado",
+		`dnpgen render -model MODELO [-out DIR] [-exports csv,html] [-write-lists] [-anonymize]`,
+		[]helpExample{
+			{"Regenerar las exportaciones de un modelo", `dnpgen render -model RTU01.model.json -exports csv,html`},
+			{"CSV anonimizado a partir de un modelo guardado", `dnpgen render -model RTU01.model.json -exports csv -anonymize -out caso`},
+		}},
+	{"scaffold", "Escribe un config.yaml inicial para un tipo de estación",
+		`dnpgen scaffold -type TIPO [-o config.yaml|-] [-force] | -types`,
+		[]helpExample{
+			{"Configuración de una estación de bombeo", `dnpgen scaffold -type pumping-station`},
+		}},
+	{"rename", "Renombra variables conservando sus índices e informa al SCADA",
+		`dnpgen rename -path RUTA -node NODO -map renames.csv [-skip-ext] [-dry-run] [-force] [-report FICHERO]`,
+		[]helpExample{
+			{"Comprobar un renombrado sin escribir", `dnpgen rename -path "D:\Proyectos\Planta" -node RTU01 -map renames.csv -dry-run`},
+		}},
+	{"compare-nodes", "Compara los mapas DNP3 de dos nodos o dos modelos",
+		`dnpgen compare-nodes [-path RUTA] [-skip-ext] [-format text|json] NODO_A NODO_B`,
+		[]helpExample{
+			{"Comprobar una pareja redundante", `dnpgen compare-nodes -path "D:\Proyectos\Planta" RTU01A RTU01B`},
+		}},
+	{"pair", "Genera una pareja de RTU redundantes desde un .SIG y comprueba que su mapa DNP3 es idéntico",
+		`dnpgen pair -path RUTA [-pair NOMBRE] [-skip-ext] [-check] [-json]`,
+		[]helpExample{
+			{"Comprobar la pareja sin escribir", `dnpgen pair -path "D:\Proyectos\Planta" -pair RTU01 -check`},
+			{"Generar todas las parejas de app.redundancy", `dnpgen pair -path "D:\Proyectos\Planta"`},
+		}},
+	{"check-roundtrip", "Comprueba que __lists.ini se relee igual que se generó",
+		`dnpgen check-roundtrip -path RUTA -node NODO [-sigext] | -lists __lists.ini`,
+		[]helpExample{
+			{"Comprobar un __lists.ini existente", `dnpgen check-roundtrip -lists __lists.ini`},
+		}},
+	{"analyze", "Analiza un proyecto en memoria, sin SIGEXT y sin escribir nada",
+		`dnpgen analyze -path RUTA (-node NODO[,NODO...] | -all) [-incremental] [-json]`,
+		[]helpExample{
+			{"Revisar todos los nodos en la carpeta compartida de producción", `dnpgen analyze -path "\\servidor\proyectos\Planta" -all`},
+		}},
+	{"spares", "Consumo de spares y previsión de agotamiento de la capacidad de cada lista",
+		`dnpgen spares -path RUTA (-node NODO[,NODO...] | -all) [-out DIR] [-json]`,
+		[]helpExample{
+			{"Ver qué listas se agotarán primero", `dnpgen spares -path "D:\Proyectos\Planta" -all`},
+		}},
+	{"golden", "Compara las listas y exportaciones con una referencia (pruebas de regresión)",
+		`dnpgen golden -path RUTA (-node NODO[,NODO...] | -all) [-dir DIR] [-exports csv,json] [-update] [-json]`,
+		[]helpExample{
+			{"Crear la referencia del proyecto de prueba", `dnpgen golden -path pruebas\Planta -all -dir pruebas\golden -update`},
+			{"Comprobar en el CI que las reglas no cambian el resultado", `dnpgen golden -path pruebas\Planta -all -dir pruebas\golden -exports csv`},
+		}},
+	{"selftest", "Genera un proyecto de muestra incluido y comprueba el resultado (instalación)",
+		`dnpgen selftest [-keep] [-json]`,
+		[]helpExample{
+			{"Comprobar la instalación en un puesto nuevo", `dnpgen selftest`},
+			{"Dejar el proyecto de muestra para revisarlo", `dnpgen selftest -keep`},
+		}},
+	{"migrate", "Muestra y acepta los cambios de posición al cambiar las reglas (app.rules_version)",
+		`dnpgen migrate -path RUTA (-node NODO[,NODO...] | -all) [-out DIR] [-apply] [-json]`,
+		[]helpExample{
+			{"Ver qué puntos mueven las reglas nuevas", `dnpgen migrate -path "D:\Proyectos\Planta" -all`},
+			{"Regenerar y aceptar las reglas nuevas", `dnpgen migrate -path "D:\Proyectos\Planta" -all -apply`},
+		}},
+	{"preflight", "Comprobación antes de descargar al controlador, con lista imprimible",
+		`dnpgen preflight -path RUTA (-node NODO[,NODO...] | -all) [-out DIR] [-report FICHERO.html] [-json]`,
+		[]helpExample{
+			{"Revisar el nodo e imprimir la lista antes de la descarga", `dnpgen preflight -path "D:\Proyectos\Planta" -node RTU01 -report RTU01-descarga.html`},
+		}},
+	{"gui", "Interfaz gráfica en el navegador: nodos, reglas con vista previa y generación",
+		`dnpgen gui [-path RUTA] [-addr 127.0.0.1:0] [-no-browser]`,
+		[]helpExample{
+			{"Abrir la interfaz con un proyecto", `dnpgen gui -path "D:\Proyectos\Planta"`},
+		}},
+	{"compact", "Compacta los spares de las listas y escribe la tabla de reasignación",
+		`dnpgen compact -path RUTA -node NODO | -lists __lists.ini [-policy trailing|runs|all] [-keep N] [-remap FICHERO.csv] [-dry-run]`,
+		[]helpExample{
+			{"Ver la reasignación sin tocar las listas", `dnpgen compact -path "D:\Proyectos\Planta" -node RTU01 -policy runs -keep 2 -dry-run`},
+		}},
+	{"update", "Comprueba o instala una versión nueva de dnpgen",
+		`dnpgen update [-url URL] [-check] [-force]`,
+		[]helpExample{
+			{"Solo comprobar si hay versión nueva", `dnpgen update -check`},
+		}},
+	{"completion", "Script de autocompletado para bash, zsh o PowerShell",
+		`dnpgen completion bash|zsh|powershell`,
+		[]helpExample{
+			{"Activar el autocompletado en PowerShell", `dnpgen completion powershell | Out-String | Invoke-Expression`},
+		}},
+	{"errors", "Catálogo de códigos de error y cómo resolverlos",
+		`dnpgen errors list [-json] | errors explain CÓDIGO`,
+		[]helpExample{
+			{"Ver la causa y la solución de un código", `dnpgen errors explain CW1001`},
+			{"Catálogo en JSON para scripts de soporte", `dnpgen errors list -json`},
+		}},
+	{"config", "Escribe el JSON Schema de config.yaml para validarlo y autocompletarlo en el editor",
+		`dnpgen config schema [-o FICHERO] [-from config.yaml]`,
+		[]helpExample{
+			{"Esquema junto a config.yaml (VS Code: \"# yaml-language-server: $schema=config.schema.json\" en la primera línea)", `dnpgen config schema -o config.schema.json`},
+		}},
+	{"stats", "Resume las estadísticas de uso locales por proyecto",
+		`dnpgen stats [-since 30d] [-project PROYECTO] [-json] [FICHERO...]`,
+		[]helpExample{
+			{"Tiempo dedicado en el último mes", `dnpgen stats -since 30d`},
+			{"Juntar los ficheros de varios equipos", `dnpgen stats -json eq1\usage.jsonl eq2\usage.jsonl`},
+		}},
+	{"perf", "Historial de rendimiento de las generaciones (app.perf)",
+		`dnpgen perf report [flags]`,
+		[]helpExample{
+			{"Página del informe", `dnpgen help perf report`},
+		}},
+	{"perf report", "Compara las etapas de la última generación con las anteriores y marca regresiones",
+		`dnpgen perf report -path RUTA [-node NODO[,NODO...]] [-last N] [-threshold FACTOR] [-json]`,
+		[]helpExample{
+			{"¿Qué etapa se ha vuelto más lenta?", `dnpgen perf report -path "D:\Proyectos\Planta"`},
+			{"Un nodo contra sus 20 ejecuciones anteriores", `dnpgen perf report -path "D:\Proyectos\Planta" -node RTU01 -last 20`},
+		}},
+	{"post-build", "Genera el nodo desde la herramienta externa del IDE de CWave, sin consola, con fichero de resultado",
+		`dnpgen post-build [-path RUTA] [-node NODO] [-skip-ext] [-out DIR] [-result FICHERO] [-json] [RUTA_DEL_IDE...]`,
+		[]helpExample{
+			{"Con la ruta del .mwt que pasa el IDE", `dnpgen post-build "D:\Proyectos\Planta\RTU01.mwt"`},
+			{"Resultado en JSON en una ruta fija", `dnpgen post-build -path "D:\Proyectos\Planta" -node RTU01 -json -result D:\build\dnpgen.json`},
+		}},
+	{"verify-artifacts", "Comprueba la firma del manifiesto y los hashes de los ficheros generados",
+		`dnpgen verify-artifacts -path RUTA -node NODO | -manifest FICHERO [-public-key CLAVE] [-allow-unsigned] [-json] | -keygen`,
+		[]helpExample{
+			{"Comprobar las listas antes de descargarlas a la RTU", `dnpgen verify-artifacts -path "D:\Proyectos\Planta" -node RTU01`},
+			{"Comprobar una copia publicada", `dnpgen verify-artifacts -manifest \\servidor\rtu\RTU01.manifest.json`},
+			{"Generar el par de claves de firma", `dnpgen verify-artifacts -keygen`},
+		}},
+	{"bundle", "Paquetes para redes aisladas: export, import y run",
+		`dnpgen bundle export|import|run [flags]`,
+		[]helpExample{
+			{"Página de cada operación", `dnpgen help bundle export`},
+		}},
+	{"bundle export", "Empaqueta .SIG, config.yaml, estado y versión del generador en un zip",
+		`dnpgen bundle export -path RUTA -node NODO[,NODO...] [-o PAQUETE.bundle.zip]`,
+		[]helpExample{
+			{"Paquete de un nodo para la puesta en marcha", `dnpgen bundle export -path "D:\Proyectos\Planta" -node RTU01 -o E:\RTU01.bundle.zip`},
+		}},
+	{"bundle import", "Extrae un paquete comprobando sus hashes",
+		`dnpgen bundle import [-dest DIR] PAQUETE.bundle.zip`,
+		[]helpExample{
+			{"Extraer en el portátil", `dnpgen bundle import -dest C:\Puesta\RTU01 E:\RTU01.bundle.zip`},
+		}},
+	{"bundle run", "Genera desde un paquete y comprueba que las listas son idénticas",
+		`dnpgen bundle run [-out DIR] [-force] PAQUETE.bundle.zip|DIR`,
+		[]helpExample{
+			{"Generar en el portátil aislado", `dnpgen bundle run -out C:\Puesta\RTU01\salida E:\RTU01.bundle.zip`},
+		}},
+	{"help", "Página de ayuda de un subcomando", `dnpgen help [SUBCOMANDO]`,
+		[]helpExample{{"Ayuda de rules names", `dnpgen help rules names`}}},
+	{"examples", "Invocaciones habituales de principio a fin", `dnpgen examples [node|batch|ci|server]`,
+		[]helpExample{{"Comprobaciones para integración continua", `dnpgen examples ci`}}},
+}
+
+func findHelp(name string) *commandHelp {
+	if name == "generate" {
+		name = ""
+	}
+	for i := range commandHelps {
+		if commandHelps[i].Name == name {
+			return &commandHelps[i]
+		}
+	}
+	return nil
+}
+
+// flagGroups reparte los flags de todos los subcomandos en las secciones de
+// la página; los que no aparecen van a "EJECUCIÓN".
+var flagGroups = []struct {
+	Title string
+	Flags []string
+}{
+	{"ENTRADA", []string{"path", "node", "all", "workspace", "sig", "lists", "model", "db", "map", "project", "type"}},
+	{"SALIDA", []string{"out", "o", "format", "report", "remap", "json", "write-lists", "exports", "log", "queue-dir", "result"}},
+}
+
+// globalFlagHelp son los flags de extractGlobalFlags, válidos en cualquier
+// subcomando.
+var globalFlagHelp = []struct{ Flag, Usage string }{
+	{"-lang es|en", "Idioma de los mensajes (es, en)"},
+	{"-no-color", "Salida sin colores (también con NO_COLOR)"},
+	{"-firmware PRESET", "Preset de firmware de la RTU (sustituye a app.firmware)"},
+}
+
+// helpOutput, si no es nil, recibe las páginas en lugar de la salida del
+// FlagSet (stderr). "dnpgen help" escribe en la salida estándar.
+var helpOutput io.Writer
+
+// manUsage es el Usage de los FlagSet de los subcomandos: la página de
+// ayuda de name con los flags de fs.
+func manUsage(name string, fs *flag.FlagSet) func() {
+	return func() {
+		w := helpOutput
+		if w == nil {
+			w = fs.Output()
+		}
+		h := findHelp(name)
+		if h == nil {
+			h = &commandHelp{Name: name}
+		}
+		printManPage(w, h, fs)
+	}
+}
+
+func printManPage(w io.Writer, h *commandHelp, fs *flag.FlagSet) {
+	title := strings.TrimSpace("dnpgen " + h.Name)
+	fmt.Fprintln(w, boldText(tr("NOMBRE")))
+	fmt.Fprintf(w, "    %s - %s\n\n", title, tr(h.Summary))
+	if h.Synopsis != "" {
+		fmt.Fprintln(w, boldText(tr("SINOPSIS")))
+		fmt.Fprintf(w, "    %s\n\n", h.Synopsis)
+	}
+	if fs != nil {
+		grouped := map[string]string{}
+		for _, g := range flagGroups {
+			for _, f := range g.Flags {
+				grouped[f] = g.Title
+			}
+		}
+		for _, title := range []string{"ENTRADA", "SALIDA", "EJECUCIÓN"} {
+			var flags []*flag.Flag
+			fs.VisitAll(func(f *flag.Flag) {
+				g := grouped[f.Name]
+				if g == "" {
+					g = "EJECUCIÓN"
+				}
+				if g == title && !isGlobalFlag(f.Name) {
+					flags = append(flags, f)
+				}
+			})
+			if len(flags) == 0 {
+				continue
+			}
+			fmt.Fprintln(w, boldText(tr(title)))
+			for _, f := range flags {
+				kind, usage := flag.UnquoteUsage(f)
+				fmt.Fprintf(w, "    -%s", f.Name)
+				if kind != "" {
+					fmt.Fprintf(w, " %s", kind)
+				}
+				fmt.Fprintf(w, "\n        %s", usage)
+				switch f.DefValue {
+				case "", "false", "0":
+				default:
+					fmt.Fprint(w, trf(" (por defecto %s)", f.DefValue))
+				}
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	fmt.Fprintln(w, boldText(tr("OPCIONES GLOBALES")))
+	for _, g := range globalFlagHelp {
+		fmt.Fprintf(w, "    %-18s %s\n", g.Flag, tr(g.Usage))
+	}
+	fmt.Fprintln(w)
+	printExamples(w, tr("EJEMPLOS"), h.Examples)
+}
+
+func isGlobalFlag(name string) bool {
+	for _, f := range completionGlobalFlags {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func printExamples(w io.Writer, title string, examples []helpExample) {
+	if len(examples) == 0 {
+		return
+	}
+	fmt.Fprintln(w, boldText(title))
+	for _, e := range examples {
+		fmt.Fprintf(w, "    %s:\n        %s\n", tr(e.Desc), e.Cmd)
+	}
+	fmt.Fprintln(w)
+}
+
+// helpRunners ejecutan con -h los subcomandos que tienen FlagSet propio,
+// para que la página muestre sus flags reales. La generación normal la
+// resuelve main (sus flags se declaran allí).
+var helpRunners = map[string]func([]string){
+	"server":           runServer,
+	"simulate":         runSimulate,
+	"verify":           runVerify,
+	"query":            runQuery,
+	"nodes":            runNodes,
+	"gen-fixture":      runGenFixture,
+	"bench":            runBench,
+	"rules lint":       func(args []string) { runRules(append([]string{"lint"}, args...)) },
+	"rules names":      runRulesNames,
+	"rules impact":     runRulesImpact,
+	"export-model":     runExportModel,
+	"render":           runRender,
+	"scaffold":         runScaffold,
+	"rename":           runRename,
+	"compare-nodes":    runCompareNodes,
+	"check-roundtrip":  runCheckRoundTrip,
+	"analyze":          runAnalyze,
+	"golden":           runGolden,
+	"selftest":         runSelftest,
+	"gui":              runGUI,
+	"preflight":        runPreflight,
+	"migrate":          runMigrate,
+	"spares":           runSpares,
+	"pair":             runPair,
+	"compact":          runCompact,
+	"update":           runUpdate,
+	"stats":            runStats,
+	"perf report":      func(args []string) { runPerf(append([]string{"report"}, args...)) },
+	"post-build":       runPostBuild,
+	"verify-artifacts": runVerifyArtifacts,
+	"bundle export":    runBundleExport,
+	"bundle import":    runBundleImport,
+	"bundle run":       runBundleRun,
+}
+
+// runHelp implementa "dnpgen help [subcomando]". Devuelve false si la
+// página pedida es la de la generación normal.
+func runHelp(args []string) bool {
+	helpOutput = os.Stdout
+	name := strings.Join(args, " ")
+	if name == "" {
+		printHelpIndex()
+		return true
+	}
+	h := findHelp(name)
+	if h == nil {
+		log.Fatalf(tr("subcomando desconocido %q (dnpgen help muestra la lista)"), name)
+	}
+	if h.Name == "" {
+		return false
+	}
+	if run, ok := helpRunners[h.Name]; ok {
+		run([]string{"-h"})
+		return true
+	}
+	printManPage(os.Stdout, h, nil)
+	return true
+}
+
+// printHelpIndex lista los subcomandos con su resumen.
+func printHelpIndex() {
+	fmt.Println(boldText(tr("SUBCOMANDOS")))
+	for _, h := range commandHelps {
+		name := h.Name
+		if name == "" {
+			name = "generate"
+		}
+		fmt.Printf("    %-16s %s\n", name, tr(h.Summary))
+	}
+	fmt.Println()
+	fmt.Println(boldText(tr("OPCIONES GLOBALES")))
+	for _, g := range globalFlagHelp {
+		fmt.Printf("    %-18s %s\n", g.Flag, tr(g.Usage))
+	}
+	fmt.Println()
+	fmt.Println(tr("dnpgen help <subcomando> muestra su página; dnpgen examples, los recorridos habituales."))
+}
+
+// exampleTopics son los recorridos de "dnpgen examples".
+var exampleTopics = []struct {
+	Name, Title string
+	Examples    []helpExample
+}{
+	{"node", "Un nodo, de la configuración a la RTU", []helpExample{
+		{"Configuración inicial para el tipo de estación", `dnpgen scaffold -type pumping-station`},
+		{"Revisar las reglas contra el .SIG del nodo", `dnpgen rules lint -path "D:\Proyectos\Planta" -node RTU01`},
+		{"Generar las listas y exportaciones", `dnpgen -path "D:\Proyectos\Planta" -node RTU01`},
+		{"Comprobar que __lists.ini se relee igual", `dnpgen check-roundtrip -path "D:\Proyectos\Planta" -node RTU01`},
+		{"Contrastar con la RTU en campo", `dnpgen verify -path "D:\Proyectos\Planta" -node RTU01 -host 10.1.2.21`},
+	}},
+	{"batch", "Todos los nodos de un proyecto", []helpExample{
+		{"Ver los nodos y el estado de sus ficheros", `dnpgen nodes -path "D:\Proyectos\Planta"`},
+		{"Generar todos los nodos", `dnpgen -path "D:\Proyectos\Planta" -all`},
+		{"Informar solo de los cambios respecto a la generación anterior", `dnpgen -path "D:\Proyectos\Planta" -all -incremental`},
+		{"Varios proyectos a la vez", `dnpgen -workspace plantas.yaml`},
+		{"Continuar un workspace interrumpido", `dnpgen -workspace plantas.yaml -resume`},
+		{"Comprobar una pareja redundante", `dnpgen compare-nodes -path "D:\Proyectos\Planta" RTU01A RTU01B`},
+	}},
+	{"ci", "Comprobaciones para integración continua (salen con código distinto de 0 si fallan)", []helpExample{
+		{"Reglas muertas o en conflicto", `dnpgen rules lint -sig RTU01.SIG -strict`},
+		{"Convenciones de nombres de todos los nodos", `dnpgen rules names -path proyecto -strict`},
+		{"Generar sin SIGEXT y en inglés", `dnpgen -lang en -no-color -path proyecto -all -skip-ext`},
+		{"Lectura de ida y vuelta de las listas", `dnpgen check-roundtrip -path proyecto -node RTU01`},
+		{"El modelo publicado sigue igual", `dnpgen compare-nodes RTU01.model.json build/RTU01.model.json`},
+	}},
+	{"server", "Modo servidor y servicio de Windows", []helpExample{
+		{"Servidor HTTP local con la cola en disco", `dnpgen server -queue-dir D:\dnpgen\cola`},
+		{"Escuchar en la red (exige app.server.token y app.server.roots)", `dnpgen server -addr 0.0.0.0:8080 -queue-dir D:\dnpgen\cola`},
+		{"Instalarlo como servicio de Windows", `dnpgen service install -queue-dir D:\dnpgen\cola -log D:\dnpgen\server.log`},
+	}},
+}
+
+// runExamples implementa "dnpgen examples [tema]".
+func runExamples(args []string) {
+	if len(args) > 1 {
+		log.Fatal(tr("Uso: dnpgen.exe examples [node|batch|ci|server]"))
+	}
+	found := false
+	for _, t := range exampleTopics {
+		if len(args) == 1 && t.Name != args[0] {
+			continue
+		}
+		found = true
+		printExamples(os.Stdout, t.Name+": "+tr(t.Title), t.Examples)
+	}
+	if !found {
+		log.Fatalf(tr("tema desconocido %q (node, batch, ci, server)"), args[0])
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// --- EXPORTACIÓN HTML ---
+
+func init() {
+	RegisterExporter(exporterFunc{"html", ".points.html", writePointsHTML})
+}
+
+// htmlPointsTemplate es una página autocontenida (sin recursos externos) que
+// se puede abrir o adjuntar tal cual.
+var htmlPointsTemplate = template.Must(template.New("points").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="generator" content="{{.Generator}}">
+<title>{{.Node}} - DNP3</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+th { background: #eee; }
+tr.spare td { color: #999; }
+</style>
+</head>
+<body>
+<h1>{{.Node}}</h1>
+{{range .Sections}}<h2>{{.Name}} - {{.Title}} ({{len .Items}})</h2>
+<table>
+<tr><th>#</th><th>Punto</th><th>Variable</th><th>Tipo</th><th>Descripción</th><th>Área</th><th>Alarma</th></tr>
+{{range $i, $p := .Items}}<tr{{if $p.Spare}} class="spare"{{end}}><td>{{$i}}</td><td>{{$p.Name}}</td><td>{{$p.Var}}</td><td>{{$p.Type}}</td><td>{{$p.Desc}}</td><td>{{$p.Area}}</td><td>{{$p.Alarm}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// writePointsHTML escribe una tabla por lista con los índices DNP3 y las
+// descripciones del .SIG.
+func writePointsHTML(w io.Writer, ctx ExportContext) error {
+	return htmlPointsTemplate.Execute(w, struct {
+		Lang, Node, Generator string
+		Sections              []listSection
+	}{locale, ctx.Node, generatorLine(), listSections(ctx.Lists)})
+}
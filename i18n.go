@@ -0,0 +1,1025 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// --- IDIOMA DE LOS MENSAJES ---
+//
+// Los textos del programa se escriben en español y se usan como clave del
+// catálogo: tr devuelve la traducción al idioma activo o el propio texto si
+// no hay traducción, de modo que un mensaje nuevo nunca se pierde, como mucho
+// sale en español. Prioridad del idioma: -lang, DNPGEN_LANG y app.locale.
+
+const defaultLocale = "es"
+
+var (
+	locale       = defaultLocale
+	warnedLocale string // evita repetir el aviso en la segunda resolución
+)
+
+// catalogs contiene las traducciones por idioma. El español es el idioma de
+// origen y no necesita catálogo.
+var catalogs = map[string]map[string]string{
+	"en": catalogEN,
+}
+
+// tr traduce un texto (o una cadena de formato) al idioma activo.
+func tr(msg string) string {
+	if t, ok := catalogs[locale][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// trf traduce una cadena de formato y la aplica.
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// normalizeLocale reduce "en_US.UTF-8", "EN-gb"... al código de idioma.
+func normalizeLocale(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-."); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// setLocale activa el primer idioma no vacío de la lista. Un idioma sin
+// catálogo se avisa y se ignora.
+func setLocale(candidates ...string) {
+	for _, c := range candidates {
+		code := normalizeLocale(c)
+		if code == "" {
+			continue
+		}
+		if _, ok := catalogs[code]; !ok && code != defaultLocale {
+			if warnedLocale != c {
+				log.Printf(tr("[WARN] Idioma desconocido %q, se usa %q"), c, defaultLocale)
+				warnedLocale = c
+			}
+			code = defaultLocale
+		}
+		locale = code
+		return
+	}
+	locale = defaultLocale
+}
+
+// initLocale resuelve el idioma con lo disponible en cada momento: al
+// arrancar solo hay flag y entorno; tras leer config.yaml se repite con
+// app.locale.
+func initLocale() {
+	setLocale(globalFlags.Lang, os.Getenv("DNPGEN_LANG"), GlobalConfig.App.Locale)
+}
+
+// listTitle devuelve el título de una lista para __lists.ini: el de
+// app.list_titles si está configurado o el estándar traducido.
+func listTitle(list, def string) string {
+	if t := GlobalConfig.App.ListTitles[list]; t != "" {
+		return t
+	}
+	return tr(def)
+}
+
+var catalogEN = map[string]string{
+	// Títulos de __lists.ini
+	"ENTRADAS ANALOGICAS DNP": "DNP ANALOG INPUTS",
+	"SALIDAS ANALOGICAS DNP":  "DNP ANALOG OUTPUTS",
+	"ENTRADAS DIGITALES DNP":  "DNP DIGITAL INPUTS",
+	"SALIDAS DIGITALES DNP":   "DNP DIGITAL OUTPUTS",
+	"CADENAS DE OCTETOS DNP":  "DNP OCTET STRINGS",
+
+	// Áreas
+	"(sin área)": "(no area)",
+	"Área ":      "Area ",
+	"ÁREA":       "AREA",
+
+	// Workspace
+	"Fichero de workspace con varios proyectos y sus nodos": "Workspace file listing several projects and their nodes",
+	"%s: no declara ningún proyecto":                        "%s: no projects declared",
+	"%s: proyecto %d sin path":                              "%s: project %d has no path",
+	"##### Proyecto %s #####":                               "##### Project %s #####",
+	"todos":                                                 "all",
+	"ERROR":                                                 "ERROR",
+	"\n--- WORKSPACE %s ---":                                "\n--- WORKSPACE %s ---",
+	"PROYECTO\tNODOS\tRESULTADO":                            "PROJECT\tNODES\tRESULT",
+
+	// Modo incremental
+	"Informar solo de los cambios respecto a la generación anterior":         "Report only the changes since the previous generation",
+	"instantánea incremental: %v":                                            "incremental snapshot: %v",
+	"informe incremental: %v":                                                "incremental report: %v",
+	"\nIncremental: sin generación previa, se guarda la instantánea inicial": "\nIncremental: no previous generation, saving the initial snapshot",
+	"\nIncremental: sin cambios desde %s":                                    "\nIncremental: no changes since %s",
+	"\nCambios desde %s: +%d -%d ~%d (índices movidos: %d)":                  "\nChanges since %s: +%d -%d ~%d (moved indices: %d)",
+	"  ... y %d más (ver %s)":                                                "  ... and %d more (see %s)",
+	"%s: +%d -%d ~%d (índices movidos: %d)":                                  "%s: +%d -%d ~%d (moved indices: %d)",
+
+	// Línea de comandos
+	"--- Generador DNP3 CLI v%s (Regex Logic) ---":                       "--- DNP3 Generator CLI v%s (Regex Logic) ---",
+	"Mostrar la versión y salir":                                         "Print the version and exit",
+	"Ruta raíz del proyecto":                                             "Project root path",
+	"Ruta raíz del proyecto o copia .zip":                                "Project root path or .zip backup",
+	"Nombre del Nodo":                                                    "Node name",
+	"Nombre del Nodo (usa su __lists.ini)":                               "Node name (uses its __lists.ini)",
+	"Ruta directa a un __lists.ini (alternativa a -path/-node)":          "Direct path to a __lists.ini (instead of -path/-node)",
+	"Saltar ejecución de SIGEXT":                                         "Skip running SIGEXT",
+	"Generar todos los nodos del proyecto":                               "Generate every node in the project",
+	"Directorio de salida (por defecto app.output.dir o el recurso RTU)": "Output directory (defaults to app.output.dir or the RTU resource)",
+	"Idioma de los mensajes (es, en)":                                    "Message language (es, en)",
+	"Salida sin colores (también con NO_COLOR)":                          "Plain output without colors (also with NO_COLOR)",
+	"LISTA\tPUNTOS\tREALES\tSPARES":                                      "LIST\tPOINTS\tREAL\tSPARES",
+	"Listas generadas: %s":                                               "Lists written: %s",
+	"NODO\tDI\tDO\tAI\tAO\tNOMBRES":                                      "NODE\tDI\tDO\tAI\tAO\tNAMES",
+	"Salida en JSON":                                                     "JSON output",
+	"Uso: dnpgen.exe -path \"C:\\Ruta\" -node \"NombreNodo\" | -all | -workspace plantas.yaml": "Usage: dnpgen.exe -path \"C:\\Path\" -node \"NodeName\" | -all | -workspace plants.yaml",
+	"Uso: dnpgen.exe nodes -path \"C:\\Ruta\" [-json]":                                         "Usage: dnpgen.exe nodes -path \"C:\\Path\" [-json]",
+	"Error ruta absoluta: %v":                  "Absolute path error: %v",
+	"Ruta del proyecto: ":                      "Project path: ",
+	"Nombre del nodo: ":                        "Node name: ",
+	"Nodos del proyecto:":                      "Project nodes:",
+	"No se encontraron nodos en el proyecto.":  "No nodes found in the project.",
+	"[FATAL] No se indicó ningún nodo":         "[FATAL] No node given",
+	"sin .SIG":                                 "no .SIG",
+	"Nodo [1-%d o nombre]: ":                   "Node [1-%d or name]: ",
+	"Número fuera de rango":                    "Number out of range",
+	"No se encontraron nodos (.mwt ni .SIG)":   "No nodes found (neither .mwt nor .SIG)",
+	"NODO\tMWT\tÚLTIMA EXTRACCIÓN SIG\tLISTAS": "NODE\tMWT\tLAST SIG EXTRACTION\tLISTS",
+	"sí": "yes",
+	"no": "no",
+
+	// Configuración
+	"No se encuentra %s":        "%s not found",
+	"Error abriendo config: %v": "Error opening config: %v",
+	"YAML malformado: %v":       "Malformed YAML: %v",
+
+	// Generación
+	"Ejecutando SIGEXT...":                 "Running SIGEXT...",
+	"Ejecutando SIGEXT":                    "Running SIGEXT",
+	"Procesando: %s":                       "Processing: %s",
+	"Procesando %s":                        "Processing %s",
+	"Clasificando puntos":                  "Classifying points",
+	"Generando %s...":                      "Generating %s...",
+	"Generando %s":                         "Generating %s",
+	"Exportando %s...":                     "Exporting %s...",
+	"Exportando":                           "Exporting",
+	"Actualizando base de puntos %s...":    "Updating point database %s...",
+	"Exportando a base de datos (%s)...":   "Exporting to database (%s)...",
+	"ruta absoluta: %v":                    "absolute path: %v",
+	"recurso no encontrado: %s":            "resource not found: %s",
+	"no existe .SIG: %s":                   ".SIG does not exist: %s",
+	"error procesando: %v":                 "processing error: %v",
+	"nombres de punto: %v":                 "point names: %v",
+	"directorio de salida: %v":             "output directory: %v",
+	"error escribiendo INI: %v":            "error writing INI: %v",
+	"error exportando: %v":                 "export error: %v",
+	"error en base de puntos: %v":          "point database error: %v",
+	"error exportando a base de datos: %v": "database export error: %v",
+	"error escribiendo métricas: %v":       "error writing metrics: %v",
+	"error publicando: %v":                 "publish error: %v",
+	"recuento anterior: %v":                "previous point count: %v",
+	"recuento de puntos: %v":               "point count: %v",
+	"caída brusca de puntos (%s): ¿.SIG incompleto? No se sobrescriben las listas": "sudden point count drop (%s): incomplete .SIG? The lists are not overwritten",
+	"[WARN] Caída brusca de puntos respecto a la generación anterior: %s":          "[WARN] Sudden point count drop since the previous run: %s",
+	"[WARN] Publicación incompleta: %v":                                            "[WARN] Incomplete publish: %v",
+	"Publicando %d fichero(s) en %s...":                                            "Publishing %d file(s) to %s...",
+	"Informe enviado por correo a %s":                                              "Report emailed to %s",
+	"tipo de destino %q no soportado (dir, sftp, s3)":                              "unsupported target type %q (dir, sftp, s3)",
+	"SIGEXT correcto en el intento %d/%d":                                          "SIGEXT succeeded on attempt %d/%d",
+	"[WARN] SIGEXT intento %d/%d: %v (reintento en %s)":                            "[WARN] SIGEXT attempt %d/%d: %v (retrying in %s)",
+	".SIG desactualizado: %s (%s) es anterior a %s (%s)":                           "Stale .SIG: %s (%s) is older than %s (%s)",
+	"[WARN] Las listas NO reflejan el proyecto actual":                             "[WARN] The lists do NOT reflect the current project",
+	"caracteres no permitidos":                                                     "characters not allowed",
+	"%d caracteres (máx. %d)":                                                      "%d characters (max. %d)",
+	"línea %d: %s":                                                                 "line %d: %s",
+	"línea de más de %d bytes":                                                     "line longer than %d bytes",
+	"contenido binario o codificación inválida":                                    "binary content or invalid encoding",
+	"línea SIG no reconocida: %.80q":                                               "unrecognized SIG line: %.80q",
+	"... y %d diagnósticos más":                                                    "... and %d more diagnostics",
+	"=== Nodo %s ===":                                                              "=== Node %s ===",
+	" | nombres fuera de norma: %d":                                                " | non-compliant names: %d",
+	"[ERROR] No hay ficheros .SIG en %s":                                           "[ERROR] No .SIG files in %s",
+	"[WARN] Idioma desconocido %q, se usa %q":                                      "[WARN] Unknown language %q, using %q",
+
+	// Diagnóstico
+	"¿Generar paquete de diagnóstico para soporte? [s/N]: ": "Write a diagnostics bundle for support? [y/N]: ",
+	"[ERROR] Paquete de diagnóstico: %v":                    "[ERROR] Diagnostics bundle: %v",
+	"Paquete de diagnóstico: %s":                            "Diagnostics bundle: %s",
+
+	// Informes
+	"\n--- RESUMEN ---":                          "\n--- SUMMARY ---",
+	"¡ATENCIÓN! Se usó un .SIG anterior al .mwt": "WARNING! A .SIG older than the .mwt was used",
+	"\n%d variable(s) duplicadas entre nodos (ver shared_points para permitirlas)\n": "\n%d variable(s) duplicated across nodes (see shared_points to allow them)\n",
+	"Tiempos: %s (total %s)\n":                                       "Timings: %s (total %s)\n",
+	"\n--- VERIFICACIÓN ---":                                         "\n--- VERIFICATION ---",
+	"%s: lista %d | RTU %d  [%s]\n":                                  "%s: list %d | RTU %d  [%s]\n",
+	"DIFERENTE":                                                      "MISMATCH",
+	"la RTU informa %d puntos, la lista tiene %d":                    "the RTU reports %d points, the list has %d",
+	"índices ausentes en la RTU: ":                                   "indexes missing in the RTU: ",
+	"índices fuera de la lista: ":                                    "indexes outside the list: ",
+	"Conectando con RTU %s (outstation %d)...":                       "Connecting to RTU %s (outstation %d)...",
+	"[FATAL] Falta la dirección de la RTU (-host o app.verify.host)": "[FATAL] Missing RTU address (-host or app.verify.host)",
+	"[FATAL] Error leyendo %s: %v":                                   "[FATAL] Error reading %s: %v",
+	"[FATAL] Poll de integridad: %v":                                 "[FATAL] Integrity poll: %v",
+
+	// Modelo de puntos
+	"formato %q no soportado (json, yaml)":                                                                 "unsupported format %q (json, yaml)",
+	"Formato del modelo: json o yaml (por defecto, según la extensión de -o)":                              "Model format: json or yaml (default: from the -o extension)",
+	"Fichero de salida (vacío = salida estándar)":                                                          "Output file (empty = standard output)",
+	"%s: esquema %q no soportado (se espera %s)":                                                           "%s: unsupported schema %q (expected %s)",
+	"%s: el modelo no indica el nodo":                                                                      "%s: the model does not name its node",
+	"lista %s: falta el índice %d":                                                                         "list %s: index %d is missing",
+	"Modelo guardado con export-model (json o yaml)":                                                       "Model saved by export-model (json or yaml)",
+	"Directorio de salida (por defecto, el del modelo)":                                                    "Output directory (defaults to the model's directory)",
+	"Exportadores separados por comas (por defecto app.exports y los protocolos)":                          "Comma-separated exporters (defaults to app.exports and the protocols)",
+	"Escribir también los ficheros de listas":                                                              "Also write the list files",
+	"Uso: dnpgen.exe render -model modelo.json [-out DIR] [-exports csv,html] [-write-lists] [-anonymize]": "Usage: dnpgen.exe render -model model.json [-out DIR] [-exports csv,html] [-write-lists] [-anonymize]",
+	"[FATAL] Error escribiendo %s: %v":                                                                     "[FATAL] Error writing %s: %v",
+	"[FATAL] Error exportando: %v":                                                                         "[FATAL] Export error: %v",
+	"%s: %d fichero(s) generados desde %s":                                                                 "%s: %d file(s) generated from %s",
+	"\nHallazgos (%d):\n":                                                                                  "\nFindings (%d):\n",
+	"%d hallazgo(s) bloqueantes:\n  - %s":                                                                  "%d blocking finding(s):\n  - %s",
+	"findings.policy: tipo de hallazgo desconocido %q":                                                     "findings.policy: unknown finding type %q",
+	"findings.policy.%s: severidad desconocida %q":                                                         "findings.policy.%s: unknown severity %q",
+	"findings.fail_on desconocido %q":                                                                      "unknown findings.fail_on %q",
+	"%d señal(es) de tipo %s sin lista":                                                                    "%d signal(s) of type %s without a list",
+	"lista %s: %d puntos (máx. %d)":                                                                        "list %s: %d points (max. %d)",
+	"%s[%d] %s: sin rango en %s":                                                                           "%s[%d] %s: no range in %s",
+	"variable %s mapeada en varios nodos: %s":                                                              "variable %s mapped in several nodes: %s",
+	"[WARN] No existe sigext_path %s; se usa %s":                                                           "[WARN] sigext_path %s does not exist; using %s",
+	"SIGEXT encontrado en %s":                                                                              "SIGEXT found at %s",
+	"[WARN] No se pudo guardar la salida de SIGEXT: %v":                                                    "[WARN] Could not save the SIGEXT output: %v",
+	"intento %d/%d, %s":                                                                                    "attempt %d/%d, %s",
+	".SIG con %d señales (mínimo %d): ¿SIGEXT incompleto?":                                                 ".SIG with %d signals (minimum %d): incomplete SIGEXT run?",
+	"[WARN] No se puede comprobar el .SIG contra el .mwt: %v":                                              "[WARN] Cannot check the .SIG against the .mwt: %v",
+	"[WARN] No se encontraron variables @GV en %s: no se comprueba el .SIG":                                "[WARN] No @GV variables found in %s: the .SIG is not checked",
+	".SIG con %d de las %d variables del .mwt (%.0f%%, mínimo %.0f%%): ¿SIGEXT incompleto? Faltan, p.ej.: %s": ".SIG with %d of the %d .mwt variables (%.0f%%, minimum %.0f%%): incomplete SIGEXT run? Missing, e.g.: %s",
+	"%s: lista de destino desconocida %q":                            "%s: unknown target list %q",
+	"%s: lista de origen desconocida %q":                             "%s: unknown source list %q",
+	"%s: falta name":                                                 "%s: name is missing",
+	"%s: %d puntos derivados no caben en %s %d-%d":                   "%s: %d derived points do not fit in %s %d-%d",
+	"el grupo %d no corresponde a la lista %s (grupo %d)":            "group %d does not match list %s (group %d)",
+	"falta la variación del grupo %d":                                "missing variation for group %d",
+	"dnp3_objects.defaults: lista desconocida %q":                    "dnp3_objects.defaults: unknown list %q",
+	"%s: lista desconocida %q":                                       "%s: unknown list %q",
+	"firmware desconocido %q (disponibles: %s)":                      "unknown firmware %q (available: %s)",
+	"lista %s: el firmware %s no admite el código *LIST %s":          "list %s: firmware %s does not support *LIST code %s",
+	"lista %s: %d punto(s) de tipo %s, que el firmware %s no admite": "list %s: %d point(s) of type %s, not supported by firmware %s",
+	"Preset de firmware de la RTU (sustituye a app.firmware)":        "RTU firmware preset (overrides app.firmware)",
+	"%s: tipo %s ≠ %s":                                               "%s: type %s ≠ %s",
+	"%s: SOE %t ≠ %t":                                                "%s: SOE %t ≠ %t",
+	"%s: escalado distinto":                                          "%s: different scaling",
+	"%s: objeto DNP3 distinto":                                       "%s: different DNP3 object",
+	"\n--- %s frente a %s ---":                                       "\n--- %s vs %s ---",
+	"Variables idénticas: %d":                                        "Identical variables: %d",
+	"Listas de distinta longitud:":                                   "Lists with different length:",
+	"Solo en %s (%d):":                                               "Only in %s (%d):",
+	"Clasificadas distinto (%d):":                                    "Classified differently (%d):",
+	"%s y %s exponen el mismo mapa":                                  "%s and %s expose the same map",
+	"%s y %s exponen mapas distintos":                                "%s and %s expose different maps",
+	"%s: falta -path para clasificar el nodo":                        "%s: -path is required to classify the node",
+	"Formato del informe: text o json":                               "Report format: text or json",
+	"Uso: dnpgen.exe compare-nodes -path \"C:\\Ruta\" NodoA NodoB | modeloA.json modeloB.json": "Usage: dnpgen.exe compare-nodes -path \"C:\\Path\" NodeA NodeB | modelA.json modelB.json",
+	"formato %q no soportado (text, json)":                                                     "unsupported format %q (text, json)",
+	"LISTA":                                                                                    "LIST",
+	"tipo de estación desconocido %q (disponibles: %s)":                                        "unknown station type %q (available: %s)",
+	"plantilla %s: %v":                                                                         "template %s: %v",
+	"plantilla %s: firmware desconocido %q":                                                    "template %s: unknown firmware %q",
+	"Tipo de estación: ":                                                                       "Station type: ",
+	"Fichero de configuración a escribir (- = salida estándar)":                                "Configuration file to write (- = standard output)",
+	"Sobrescribir el fichero si ya existe":                                                     "Overwrite the file if it already exists",
+	"Mostrar los tipos de estación disponibles":                                                "Show the available station types",
+	"Uso: dnpgen.exe scaffold -type pumping-station [-o config.yaml] [-force] | -types": "Usage: dnpgen.exe scaffold -type pumping-station [-o config.yaml] [-force] | -types",
+	"[FATAL] %s ya existe (use -force para sobrescribirlo)":                             "[FATAL] %s already exists (use -force to overwrite it)",
+	"Configuración inicial de %s escrita en %s":                                         "Starter configuration for %s written to %s",
+	"%s[%d] (fin de la lista)":                                                          "%s[%d] (end of list)",
+	"%s[%d] %s (%s, línea %d del .SIG)":                                                 "%s[%d] %s (%s, .SIG line %d)",
+	"%s[%d] %s (línea %d del .SIG)":                                                     "%s[%d] %s (.SIG line %d)",
+	"%s[%d] %s (%s)":                                                                    "%s[%d] %s (%s)",
+	"%s/%s divergen en el índice %d: %s frente a %s; %s tiene %d entradas y %s %d":      "%s/%s diverge at index %d: %s vs %s; %s has %d entries and %s %d",
+	"no empieza por %s":                                                                 "does not start with %s",
+	"carácter prohibido %q":                                                             "forbidden character %q",
+	"no cumple %s":                                                                      "does not match %s",
+	"%d segmento(s) de los %d de %s":                                                    "%d segment(s) out of the %d of %s",
+	"segmento %s %q no cumple %s":                                                       "segment %s %q does not match %s",
+	"%s: el segmento %s no está en structure %q":                                        "%s: segment %s is not in structure %q",
+	"Nombre del Nodo (vacío = todos los del proyecto)":                                  "Node name (empty = every node in the project)",
+	"Formato del informe: text o csv":                                                   "Report format: text or csv",
+	"Salir con error si hay infracciones":                                               "Exit with an error if there are violations",
+	"Uso: dnpgen.exe rules names -path \"C:\\Ruta\" [-node \"NombreNodo\"] [-format text|csv]":                        "Usage: dnpgen.exe rules names -path \"C:\\Path\" [-node \"NodeName\"] [-format text|csv]",
+	"formato %q no soportado (text, csv)":                                                                             "unsupported format %q (text, csv)",
+	"%s: sin infracciones":                                                                                            "%s: no violations",
+	"\n%s: %d infracción(es) (%s)":                                                                                    "\n%s: %d violation(s) (%s)",
+	"LISTA\tÍNDICE\tVARIABLE\tLINTER\tPROBLEMA":                                                                       "LIST\tINDEX\tVARIABLE\tLINTER\tPROBLEM",
+	"\n%d infracción(es) de nombres en %d nodo(s)":                                                                    "\n%d naming violation(s) in %d node(s)",
+	"%s[%d] %s (retirado el %s, índice reservado hasta el %s)":                                                        "%s[%d] %s (retired on %s, index held until %s)",
+	"Ciclo de vida: se libera el índice %s[%d] de %s (periodo de gracia vencido)":                                     "Lifecycle: releasing index %s[%d] of %s (grace period expired)",
+	"mapa de índices: %v":                                                                                             "index map: %v",
+	"\nRetiradas pendientes (%d):\n":                                                                                  "\nPending retirements (%d):\n",
+	"%s: fila %d incompleta":                                                                                          "%s: row %d is incomplete",
+	"%s: %s aparece dos veces":                                                                                        "%s: %s appears twice",
+	"%s: dos variables se renombran a %s":                                                                             "%s: two variables are renamed to %s",
+	"%s: %s es a la vez nombre antiguo y nuevo (renombrados encadenados)":                                             "%s: %s is both an old and a new name (chained renames)",
+	"%s ya está en el mapa de índices (%s[%d])":                                                                       "%s is already in the index map (%s[%d])",
+	"CSV de renombrado: nombre antiguo,nombre nuevo":                                                                  "Rename CSV: old name,new name",
+	"Fichero CSV para el informe de renombrado (vacío = <salida>/<nodo>.renames.csv)":                                 "CSV file for the rename report (empty = <output>/<node>.renames.csv)",
+	"Solo comprobar: no modificar el mapa de índices ni las listas":                                                   "Check only: do not modify the index map or the lists",
+	"Escribir aunque algún punto cambie de índice":                                                                    "Write even if some point changes index",
+	"Uso: dnpgen.exe rename -path \"C:\\Ruta\" -node \"NombreNodo\" -map renames.csv [-dry-run] [-force]":             "Usage: dnpgen.exe rename -path \"C:\\Path\" -node \"NodeName\" -map renames.csv [-dry-run] [-force]",
+	"%s no contiene renombrados":                                                                                      "%s contains no renames",
+	"Mapa de índices: %d entrada(s) renombradas":                                                                      "Index map: %d entry(ies) renamed",
+	"LISTA\tANTES\tDESPUÉS\tNOMBRE ANTERIOR\tNOMBRE NUEVO\tESTADO":                                                    "LIST\tBEFORE\tAFTER\tOLD NAME\tNEW NAME\tSTATUS",
+	"Simulación: no se modifica el mapa de índices ni las listas":                                                     "Dry run: the index map and the lists are not modified",
+	"[FATAL] %d punto(s) cambiarían de índice o no aparecen con el nombre nuevo; use -force para escribir igualmente": "[FATAL] %d point(s) would change index or do not appear under the new name; use -force to write anyway",
+	"[WARN] Las listas escritas no coinciden con la comprobación previa":                                              "[WARN] The written lists do not match the previous check",
+	"[FATAL] Informe de renombrado: %v":                                                                               "[FATAL] Rename report: %v",
+	"Informe de renombrado para el SCADA: %s":                                                                         "SCADA rename report: %s",
+	"extra_lists %s: rule requiere source: rule":                                                                      "extra_lists %s: rule requires source: rule",
+	"extra_lists %s: source rule usa rule, no patterns ni types":                                                      "extra_lists %s: source rule uses rule, not patterns or types",
+	"extra_lists %s: source static solo admite entries":                                                               "extra_lists %s: source static only accepts entries",
+	"extra_lists %s: source %q desconocido (patterns, rule, static)":                                                  "extra_lists %s: unknown source %q (patterns, rule, static)",
+	"Genera __lists.ini y las exportaciones de uno o varios nodos":                                                    "Generates __lists.ini and the exports of one or more nodes",
+	"Servidor HTTP/gRPC con cola de trabajos de generación":                                                           "HTTP/gRPC server with a generation job queue",
+	"Instala, desinstala o ejecuta el servidor como servicio de Windows":                                              "Installs, uninstalls or runs the server as a Windows service",
+	"Outstation DNP3 simulada con las listas de un nodo":                                                              "Simulated DNP3 outstation with the lists of a node",
+	"Compara las listas de un nodo con lo que expone la RTU real":                                                     "Compares the lists of a node with what the real RTU exposes",
+	"Consulta la base de puntos SQLite del proyecto":                                                                  "Queries the project's SQLite point database",
+	"Lista los nodos del proyecto y el estado de sus ficheros":                                                        "Lists the project nodes and the state of their files",
+	"Genera un .SIG o un proyecto sintético para pruebas":                                                             "Generates a synthetic .SIG or project for testing",
+	"Mide el rendimiento del parseo y la clasificación":                                                               "Measures parsing and classification performance",
+	"Revisión de la configuración: lint (reglas de clasificación), names (nombres) e impact (cambio de reglas)":       "Configuration review: lint (classification rules), names (naming) and impact (rule changes)",
+	"Detecta reglas de clasificación muertas y en conflicto":                                                          "Detects dead and conflicting classification rules",
+	"Informe de infracciones de las convenciones de nombres":                                                          "Report of naming convention violations",
+	"Exporta el modelo de puntos completo (índices, metadatos y regla)":                                               "Exports the full point model (indices, metadata and rule)",
+	"Genera listas y exportaciones a partir de un modelo guardado":                                                    "Generates lists and exports from a saved model",
+	"Escribe un config.yaml inicial para un tipo de estación":                                                         "Writes a starter config.yaml for a station type",
+	"Renombra variables conservando sus índices e informa al SCADA":                                                   "Renames variables keeping their indices and reports to the SCADA",
+	"Compara los mapas DNP3 de dos nodos o dos modelos":                                                               "Compares the DNP3 maps of two nodes or two models",
+	"Comprueba que __lists.ini se relee igual que se generó":                                                          "Checks that __lists.ini reads back as generated",
+	"Compacta los spares de las listas y escribe la tabla de reasignación":                                            "Compacts the list spares and writes the remap table",
+	"Comprueba o instala una versión nueva de dnpgen":                                                                 "Checks for or installs a new dnpgen version",
+	"Script de autocompletado para bash, zsh o PowerShell":                                                            "Completion script for bash, zsh or PowerShell",
+	"Página de ayuda de un subcomando":                                                                                "Help page of a subcommand",
+	"Invocaciones habituales de principio a fin":                                                                      "Common end-to-end invocations",
+	"Un nodo, de la configuración a la RTU":                                                                           "One node, from configuration to RTU",
+	"Todos los nodos de un proyecto":                                                                                  "All the nodes of a project",
+	"Comprobaciones para integración continua (salen con código distinto de 0 si fallan)":                             "Continuous integration checks (non-zero exit code on failure)",
+	"Modo servidor y servicio de Windows":                                                                             "Server mode and Windows service",
+	"Un nodo, con el .SIG existente":                                                                                  "One node, with the existing .SIG",
+	"Todos los nodos del proyecto":                                                                                    "All the project nodes",
+	"Varios proyectos descritos en un workspace":                                                                      "Several projects described in a workspace",
+	"Servidor HTTP local con la cola en disco":                                                                        "Local HTTP server with the queue on disk",
+	"Instalar el servicio con sus flags de server":                                                                    "Install the service with its server flags",
+	"Simular la RTU a partir de un __lists.ini":                                                                       "Simulate the RTU from a __lists.ini",
+	"Verificar una RTU en campo":                                                                                      "Verify an RTU in the field",
+	"Dónde está cada señal de la bomba P101":                                                                          "Where each signal of pump P101 is",
+	"Nodos del proyecto en JSON":                                                                                      "Project nodes as JSON",
+	"Proyecto de prueba con 500 entradas digitales":                                                                   "Test project with 500 digital inputs",
+	"Medir con un .SIG real":                                                                                          "Measure with a real .SIG",
+	"Página de cada revisión":                                                                                         "Page of each review",
+	"Revisar las reglas contra un .SIG":                                                                               "Check the rules against a .SIG",
+	"Infracciones de todos los nodos en CSV":                                                                          "Violations of all nodes as CSV",
+	"Guardar el modelo de un nodo":                                                                                    "Save the model of a node",
+	"Regenerar las exportaciones de un modelo":                                                                        "Regenerate the exports of a model",
+	"Configuración de una estación de bombeo":                                                                         "Configuration for a pumping station",
+	"Comprobar un renombrado sin escribir":                                                                            "Check a rename without writing",
+	"Comprobar una pareja redundante":                                                                                 "Check a redundant pair",
+	"Comprobar un __lists.ini existente":                                                                              "Check an existing __lists.ini",
+	"Ver la reasignación sin tocar las listas":                                                                        "Show the remap without touching the lists",
+	"Solo comprobar si hay versión nueva":                                                                             "Only check for a new version",
+	"Activar el autocompletado en PowerShell":                                                                         "Enable completion in PowerShell",
+	"Ayuda de rules names":                                                                                            "Help for rules names",
+	"Comprobaciones para integración continua":                                                                        "Continuous integration checks",
+	"Configuración inicial para el tipo de estación":                                                                  "Starter configuration for the station type",
+	"Revisar las reglas contra el .SIG del nodo":                                                                      "Check the rules against the node's .SIG",
+	"Generar las listas y exportaciones":                                                                              "Generate the lists and exports",
+	"Comprobar que __lists.ini se relee igual":                                                                        "Check that __lists.ini reads back the same",
+	"Contrastar con la RTU en campo":                                                                                  "Compare against the RTU in the field",
+	"Ver los nodos y el estado de sus ficheros":                                                                       "Show the nodes and the state of their files",
+	"Generar todos los nodos":                                                                                         "Generate all nodes",
+	"Varios proyectos a la vez":                                                                                       "Several projects at once",
+	"Reglas muertas o en conflicto":                                                                                   "Dead or conflicting rules",
+	"Convenciones de nombres de todos los nodos":                                                                      "Naming conventions of all nodes",
+	"Generar sin SIGEXT y en inglés":                                                                                  "Generate without SIGEXT and in English",
+	"Lectura de ida y vuelta de las listas":                                                                           "Round-trip read of the lists",
+	"El modelo publicado sigue igual":                                                                                 "The published model is unchanged",
+	"Instalarlo como servicio de Windows":                                                                             "Install it as a Windows service",
+	"NOMBRE":                                                                                                          "NAME",
+	"SINOPSIS":                                                                                                        "SYNOPSIS",
+	"ENTRADA":                                                                                                         "INPUT",
+	"SALIDA":                                                                                                          "OUTPUT",
+	"EJECUCIÓN":                                                                                                       "EXECUTION",
+	"OPCIONES GLOBALES":                                                                                               "GLOBAL OPTIONS",
+	"EJEMPLOS":                                                                                                        "EXAMPLES",
+	"SUBCOMANDOS":                                                                                                     "SUBCOMMANDS",
+	" (por defecto %s)":                                                                                               " (default %s)",
+	"subcomando desconocido %q (dnpgen help muestra la lista)":                                                        "unknown subcommand %q (dnpgen help shows the list)",
+	"dnpgen help <subcomando> muestra su página; dnpgen examples, los recorridos habituales.":                         "dnpgen help <subcommand> shows its page; dnpgen examples, the common walkthroughs.",
+	"Uso: dnpgen.exe examples [node|batch|ci|server]":                                                                 "Usage: dnpgen.exe examples [node|batch|ci|server]",
+	"tema desconocido %q (node, batch, ci, server)":                                                                   "unknown topic %q (node, batch, ci, server)",
+	"No existe el .SIG del nodo":                                                                                      "The node's .SIG does not exist",
+	"Ejecute SIGEXT (sin -skip-ext) o compruebe el nombre del nodo y app.sigext_paths; \"dnpgen nodes -path\" muestra qué nodos tienen .SIG.": "Run SIGEXT (without -skip-ext) or check the node name and app.sigext_paths; \"dnpgen nodes -path\" shows which nodes have a .SIG.",
+	"No existe el recurso RTU del proyecto": "The project's RTU resource does not exist",
+	"Compruebe que -path es la raíz del proyecto de ControlWave (con C\\CWave_Micro\\R\\RTU_RESOURCE) o configure app.layout si la estructura es otra.": "Check that -path is the ControlWave project root (containing C\\CWave_Micro\\R\\RTU_RESOURCE) or configure app.layout if the structure differs.",
+	"Fallaron las comprobaciones previas": "Preflight checks failed",
+	"Revise los problemas del mensaje: permisos de escritura, ficheros de solo lectura o bloqueados por ControlWave Designer y rutas inexistentes.": "Review the problems in the message: write permissions, read-only files or files locked by ControlWave Designer, and missing paths.",
+	"No se pudo leer o interpretar el .SIG": "The .SIG could not be read or parsed",
+	"Regenere el .SIG con SIGEXT; si persiste, adjunte el .SIG y el paquete de diagnóstico al informe.": "Regenerate the .SIG with SIGEXT; if it persists, attach the .SIG and the diagnostic bundle to the report.",
+	"El .SIG es anterior al .mwt": "The .SIG is older than the .mwt",
+	"Ejecute SIGEXT para regenerar el .SIG, o app.stale_sig: warn si usar el .SIG antiguo es intencionado.":                                                  "Run SIGEXT to regenerate the .SIG, or set app.stale_sig: warn if using the old .SIG is intentional.",
+	"La ruta del proyecto o la copia .zip no es válida":                                                                                                      "The project path or .zip copy is not valid",
+	"Compruebe -path: tiene que existir y, si es un .zip, contener un proyecto con ficheros .SIG.":                                                           "Check -path: it must exist and, if it is a .zip, contain a project with .SIG files.",
+	"Una sección de config.yaml no es válida":                                                                                                                "A config.yaml section is not valid",
+	"Corrija la sección citada en el mensaje (clasificación, reservas, áreas, objetos DNP3, nombres o findings); \"dnpgen rules lint\" revisa los patrones.": "Fix the section named in the message (classification, reservations, areas, DNP3 objects, naming or findings); \"dnpgen rules lint\" checks the patterns.",
+	"No se encuentra config.yaml": "config.yaml not found",
+	"Coloque config.yaml junto al ejecutable o en el directorio actual; \"dnpgen scaffold\" escribe uno inicial.": "Place config.yaml next to the executable or in the current directory; \"dnpgen scaffold\" writes a starter one.",
+	"config.yaml no es YAML válido": "config.yaml is not valid YAML",
+	"Corrija la línea indicada: sangría con espacios (no tabuladores) y comillas simples en las rutas con \\.": "Fix the reported line: indent with spaces (not tabs) and use single quotes for paths containing \\.",
+	"Hay hallazgos con severidad de fallo": "There are findings with failing severity",
+	"Revise los hallazgos del mensaje y corríjalos, o ajuste app.findings.fail_on y app.findings.policy.":                                            "Review and fix the findings in the message, or adjust app.findings.fail_on and app.findings.policy.",
+	"Caída brusca de puntos respecto a la generación anterior":                                                                                       "Sharp point-count drop compared with the previous generation",
+	"Compruebe que el .SIG está completo; si la caída es real, genere una vez sin app.count_alarm.fail o borre <salida>/.dnpgen/<nodo>.counts.json.": "Check that the .SIG is complete; if the drop is real, generate once without app.count_alarm.fail or delete <output>/.dnpgen/<node>.counts.json.",
+	"__lists.ini no se relee igual que se generó":                                                                                                    "__lists.ini does not read back as generated",
+	"Busque nombres con caracteres no admitidos o códigos *LIST repetidos; \"dnpgen check-roundtrip\" detalla las diferencias.":                      "Look for names with unsupported characters or repeated *LIST codes; \"dnpgen check-roundtrip\" details the differences.",
+	"No se pudo escribir un fichero de salida":                                                                                                       "An output file could not be written",
+	"Compruebe permisos y espacio libre en el directorio de salida y que ControlWave Designer no tiene abiertas las listas.":                         "Check permissions and free space in the output directory, and that ControlWave Designer does not have the lists open.",
+	"Falló una exportación": "An export failed",
+	"Revise el exportador citado en el mensaje y su sección de config.yaml (exports, protocols).":                                                                "Review the exporter named in the message and its config.yaml section (exports, protocols).",
+	"Falló la base de puntos o la exportación a base de datos":                                                                                                   "The point database or database export failed",
+	"Compruebe app.pointdb y app.database (ruta, DSN, credenciales) y que ningún otro proceso bloquea la base.":                                                  "Check app.pointdb and app.database (path, DSN, credentials) and that no other process is locking the database.",
+	"No se pudieron publicar los artefactos":                                                                                                                     "The artifacts could not be published",
+	"Compruebe los destinos de app.publish.targets y su acceso; sin app.publish.required la generación no falla.":                                                "Check the app.publish.targets destinations and their access; without app.publish.required the generation does not fail.",
+	"Un fichero de estado de <salida>/.dnpgen está dañado":                                                                                                       "A state file in <output>/.dnpgen is corrupt",
+	"Borre el fichero citado (instantánea, recuento o mapa de índices) y la siguiente generación lo recrea; el mapa de índices pierde las retiradas pendientes.": "Delete the named file (snapshot, count or index map) and the next generation recreates it; the index map loses its pending retirements.",
+	"SIGEXT terminó con error": "SIGEXT finished with an error",
+	"Revise <nodo>.sigext.log, app.sigext_path y la licencia de ControlWave; con -skip-ext se usa el .SIG existente.": "Check <node>.sigext.log, app.sigext_path and the ControlWave license; with -skip-ext the existing .SIG is used.",
+	"La cola de trabajos del servidor está llena":                                                                     "The server job queue is full",
+	"Espere a que terminen los trabajos en curso o aumente -queue-limit.":                                             "Wait for the running jobs to finish or raise -queue-limit.",
+	"Error sin clasificar": "Unclassified error",
+	"Adjunte el log y el paquete de diagnóstico al informar del problema.":    "Attach the log and the diagnostic bundle when reporting the problem.",
+	"Uso: dnpgen.exe errors list [-json] | errors explain CW1001":             "Usage: dnpgen.exe errors list [-json] | errors explain CW1001",
+	"CÓDIGO\tNOMBRE\tDESCRIPCIÓN":                                             "CODE\tNAME\tDESCRIPTION",
+	"código de error desconocido %q (dnpgen errors list muestra el catálogo)": "unknown error code %q (dnpgen errors list shows the catalog)",
+	"CÓMO RESOLVERLO": "HOW TO FIX IT",
+	"Catálogo de códigos de error y cómo resolverlos":             "Error code catalog and how to fix each one",
+	"Ver la causa y la solución de un código":                     "Show the cause and fix for a code",
+	"Catálogo en JSON para scripts de soporte":                    "JSON catalog for support scripts",
+	"[WARN] No se pudieron guardar las estadísticas de uso: %v":   "[WARN] Could not save usage statistics: %v",
+	"-since %q no válido (30d, 12h o 2006-01-02)":                 "invalid -since %q (30d, 12h or 2006-01-02)",
+	"Solo ejecuciones recientes: 30d, 12h o una fecha 2006-01-02": "Only recent runs: 30d, 12h or a date 2006-01-02",
+	"Solo este proyecto": "Only this project",
+	"Salida JSON":        "JSON output",
+	"No hay estadísticas en %s (active app.stats.enabled)":                                "No statistics in %s (enable app.stats.enabled)",
+	"[WARN] %s: %d línea(s) dañadas ignoradas":                                            "[WARN] %s: %d corrupt line(s) ignored",
+	"No hay ejecuciones registradas en el periodo":                                        "No runs recorded in the period",
+	"PROYECTO\tGENERACIONES\tCOMPROBACIONES\tFALLOS\tTIEMPO TOTAL\tMEDIA\tMÁXIMO\tÚLTIMA": "PROJECT\tGENERATIONS\tCHECKS\tFAILURES\tTOTAL TIME\tAVERAGE\tMAXIMUM\tLAST",
+	"\nFallos por código":                                 "\nFailures by code",
+	"CÓDIGO\tNOMBRE\tFALLOS":                              "CODE\tNAME\tFAILURES",
+	"Resume las estadísticas de uso locales por proyecto": "Summarizes local usage statistics per project",
+	"Tiempo dedicado en el último mes":                    "Time spent in the last month",
+	"Juntar los ficheros de varios equipos":               "Merge the files from several machines",
+	"No se pudo firmar el manifiesto":                     "The manifest could not be signed",
+	"Compruebe app.signing.private_key o private_key_file (Ed25519 en base64, hex o PEM); \"dnpgen verify-artifacts -keygen\" genera un par de claves.": "Check app.signing.private_key or private_key_file (Ed25519 in base64, hex or PEM); \"dnpgen verify-artifacts -keygen\" generates a key pair.",
+	"clave de firma: %v":                                            "signing key: %v",
+	"error firmando el manifiesto: %v":                              "error signing the manifest: %v",
+	"falta app.signing.private_key o private_key_file":              "app.signing.private_key or private_key_file is missing",
+	"la clave PEM no es Ed25519":                                    "the PEM key is not Ed25519",
+	"clave privada de %d bytes (se esperan %d o %d)":                "private key of %d bytes (expected %d or %d)",
+	"clave pública de %d bytes (se esperan %d)":                     "public key of %d bytes (expected %d)",
+	"Nombre del Nodo (usa su <nodo>.manifest.json)":                 "Node name (uses its <node>.manifest.json)",
+	"Ruta directa a un manifiesto (alternativa a -path/-node)":      "Direct path to a manifest (alternative to -path/-node)",
+	"Clave pública Ed25519 (anula app.signing.public_key)":          "Ed25519 public key (overrides app.signing.public_key)",
+	"Aceptar manifiestos sin firma (solo se comprueban los hashes)": "Accept unsigned manifests (only the hashes are checked)",
+	"Generar un par de claves Ed25519 y salir":                      "Generate an Ed25519 key pair and exit",
+	"Uso: dnpgen.exe verify-artifacts -path \"C:\\Ruta\" -node \"NombreNodo\" | -manifest FICHERO [-public-key CLAVE]":          "Usage: dnpgen.exe verify-artifacts -path \"C:\\Path\" -node \"NodeName\" | -manifest FILE [-public-key KEY]",
+	"[FATAL] Falta la clave pública (-public-key o app.signing.public_key); use -allow-unsigned para comprobar solo los hashes": "[FATAL] Missing public key (-public-key or app.signing.public_key); use -allow-unsigned to check only the hashes",
+	"válida":                        "valid",
+	"NO VÁLIDA":                     "INVALID",
+	"sin comprobar":                 "not checked",
+	"sin firma":                     "unsigned",
+	"Manifiesto: %s\nFirma: %s\n\n": "Manifest: %s\nSignature: %s\n\n",
+	"FICHERO\tESTADO":               "FILE\tSTATUS",
+	"\nArtefactos verificados":      "\nArtifacts verified",
+	"\nLa verificación de los artefactos ha fallado":                                                        "\nArtifact verification failed",
+	"Comprueba la firma del manifiesto y los hashes de los ficheros generados":                              "Checks the manifest signature and the hashes of the generated files",
+	"Comprobar las listas antes de descargarlas a la RTU":                                                   "Check the lists before downloading them to the RTU",
+	"Comprobar una copia publicada":                                                                         "Check a published copy",
+	"Generar el par de claves de firma":                                                                     "Generate the signing key pair",
+	"no hay config.yaml cargado":                                                                            "no config.yaml loaded",
+	"%s está fuera del proyecto (app.layout)":                                                               "%s is outside the project (app.layout)",
+	"[WARN] %s: las listas del proyecto no coinciden con el .SIG actual; el paquete reproduce las del .SIG": "[WARN] %s: the project lists do not match the current .SIG; the bundle reproduces the .SIG ones",
+	"%s no es un paquete de dnpgen (falta %s)":                                                              "%s is not a dnpgen bundle (%s missing)",
+	"formato de paquete %d no soportado (esta versión admite hasta %d)":                                     "bundle format %d not supported (this version supports up to %d)",
+	"%s: fichero no declarado en %s":                                                                        "%s: file not declared in %s",
+	"entrada fuera del directorio: %s":                                                                      "entry outside the directory: %s",
+	"%s: el hash no coincide con %s (paquete dañado o modificado)":                                          "%s: hash does not match %s (bundle corrupt or modified)",
+	"falta %s en el paquete":                                                                                "%s is missing from the bundle",
+	"el paquete requiere dnpgen %s y esta es la %s (use -force para ejecutarlo igualmente)":                 "the bundle requires dnpgen %s and this is %s (use -force to run it anyway)",
+	"[WARN] El paquete requiere dnpgen %s; se ejecuta con la %s":                                            "[WARN] The bundle requires dnpgen %s; running with %s",
+	"[WARN] El paquete se creó con el commit %s y este ejecutable es del %s":                                "[WARN] The bundle was created with commit %s and this executable is from %s",
+	"Uso: dnpgen.exe bundle export|import|run [opciones] (dnpgen help bundle)":                              "Usage: dnpgen.exe bundle export|import|run [options] (dnpgen help bundle)",
+	"Nodos del paquete, separados por comas":                                                                "Bundle nodes, comma-separated",
+	"Fichero del paquete (vacío = <proyecto>-<nodo>.bundle.zip)":                                            "Bundle file (empty = <project>-<node>.bundle.zip)",
+	"Uso: dnpgen.exe bundle export -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" [-o paquete.zip]":               "Usage: dnpgen.exe bundle export -path \"C:\\Path\" -node \"Node1,Node2\" [-o bundle.zip]",
+	"Paquete %s: %d fichero(s), %d lista(s) esperadas, dnpgen %s":                                           "Bundle %s: %d file(s), %d expected list(s), dnpgen %s",
+	"Directorio donde extraer el paquete (vacío = carpeta con el nombre del paquete)":                       "Directory to extract the bundle into (empty = folder named after the bundle)",
+	"Uso: dnpgen.exe bundle import [-dest DIR] paquete.bundle.zip":                                          "Usage: dnpgen.exe bundle import [-dest DIR] bundle.bundle.zip",
+	"Paquete extraído en %s (%s: %s)":                                                                       "Bundle extracted to %s (%s: %s)",
+	"Ejecute: dnpgen bundle run %s":                                                                         "Run: dnpgen bundle run %s",
+	"Directorio de salida (vacío = <paquete>.out)":                                                          "Output directory (empty = <bundle>.out)",
+	"Ejecutar aunque la versión del generador no coincida":                                                  "Run even if the generator version does not match",
+	"Uso: dnpgen.exe bundle run [-out DIR] [-force] paquete.bundle.zip|DIR":                                 "Usage: dnpgen.exe bundle run [-out DIR] [-force] bundle.bundle.zip|DIR",
+	"idéntica":               "identical",
+	"NODO\tLISTA\tRESULTADO": "NODE\tLIST\tRESULT",
+	"\n%d lista(s) no coinciden con las del paquete":                             "\n%d list(s) do not match the bundle",
+	"\nSalidas idénticas a las del paquete en %s":                                "\nOutputs identical to the bundle in %s",
+	"Paquetes para redes aisladas: export, import y run":                         "Bundles for air-gapped networks: export, import and run",
+	"Página de cada operación":                                                   "Page for each operation",
+	"Empaqueta .SIG, config.yaml, estado y versión del generador en un zip":      "Packages .SIG, config.yaml, state and generator version into a zip",
+	"Paquete de un nodo para la puesta en marcha":                                "Bundle a node for commissioning",
+	"Extrae un paquete comprobando sus hashes":                                   "Extracts a bundle checking its hashes",
+	"Extraer en el portátil":                                                     "Extract on the laptop",
+	"Genera desde un paquete y comprueba que las listas son idénticas":           "Generates from a bundle and checks that the lists are identical",
+	"Generar en el portátil aislado":                                             "Generate on the air-gapped laptop",
+	"Regenerar solo estas listas (p.ej. ai,ao); las demás se conservan tal cual": "Regenerate only these lists (e.g. ai,ao); the rest are kept verbatim",
+	"Regeneración parcial: se regeneran %s; se conservan %s":                     "Partial regeneration: regenerating %s; keeping %s",
+	"-only: lista %q desconocida (%s)":                                           "-only: unknown list %q (%s)",
+	"-only: no existe %s del que conservar %s":                                   "-only: %s does not exist to keep %s from",
+	"-only: %s no contiene la lista %s (*LIST %s)":                               "-only: %s does not contain list %s (*LIST %s)",
+	"La regeneración parcial (-only) no es posible":                              "Partial regeneration (-only) is not possible",
+	"Compruebe los nombres de -only (AI, AO, DI, DO, OS o las de extra_lists) y que existe el fichero de listas del que conservar las demás; si no, genere una vez completo.": "Check the -only names (AI, AO, DI, DO, OS or those in extra_lists) and that the lists file to keep the others from exists; otherwise run one full generation.",
+	"Regenerar solo las analógicas y conservar las digitales":         "Regenerate only the analog lists and keep the digital ones",
+	"merge.conflicts desconocido %q (ask, ours, theirs, both o fail)": "unknown merge.conflicts %q (ask, ours, theirs, both or fail)",
+	"base de la fusión: %v": "merge base: %v",
+	"%s, entrada %d: la edición a mano (%s) y la generación (%s) cambian las mismas líneas": "%s, entry %d: the hand edit (%s) and the generation (%s) change the same lines",
+	"(nada)":                                       "(nothing)",
+	"\nConflicto en %s, entrada %d:":               "\nConflict in %s, entry %d:",
+	"base (última generación):":                    "base (last generation):",
+	"editado a mano:":                              "edited by hand:",
+	"generado del .SIG:":                           "generated from the .SIG:",
+	"¿Conservar [e]dición, [g]enerado o [a]mbos? ": "Keep the [e]dit, the [g]enerated lines or [b]oth? ",
+	"\nEdiciones a mano fusionadas (%s):\n":        "\nHand edits merged (%s):\n",
+	"%s, entrada %d: conflicto resuelto con %s":    "%s, entry %d: conflict resolved with %s",
+	"[WARN] Listas editadas a mano desde la última generación: %s; se fusionan las ediciones (%d conflicto(s))": "[WARN] Lists edited by hand since the last generation: %s; merging the edits (%d conflict(s))",
+	"Las ediciones a mano chocan con la generación":                                                             "The hand edits conflict with the generation",
+	"Ejecute en consola para resolver los conflictos uno a uno, o fije app.merge.conflicts (ours conserva la edición, theirs lo generado); la base es <salida>/.dnpgen/<nodo>.base.ini.": "Run in a console to resolve the conflicts one by one, or set app.merge.conflicts (ours keeps the edit, theirs the generated lines); the base is <output>/.dnpgen/<node>.base.ini.",
+	"Nodos a analizar, separados por comas":                                                          "Nodes to analyze, comma-separated",
+	"Analizar todos los nodos con .SIG":                                                              "Analyze every node with a .SIG",
+	"Informar de los cambios respecto a la última generación":                                        "Report the changes since the last generation",
+	"Uso: dnpgen.exe analyze -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-incremental] [-json]": "Usage: dnpgen.exe analyze -path \"C:\\Path\" -node \"Node1,Node2\" | -all [-incremental] [-json]",
+	"[FATAL] analyze no admite copias .zip: extraerlas escribe en disco":                             "[FATAL] analyze does not accept .zip copies: extracting them writes to disk",
+	"[FATAL] No hay nodos con .SIG en %s":                                                            "[FATAL] No nodes with a .SIG in %s",
+	"[ERROR] %d de %d nodo(s) con errores":                                                           "[ERROR] %d of %d node(s) failed",
+	"%s no existe: el nodo no se ha generado":                                                        "%s does not exist: the node has not been generated",
+	"Las listas del disco no coinciden con el .SIG actual (deriva)":                                  "The lists on disk do not match the current .SIG (drift)",
+	"Las listas del disco coinciden con el .SIG actual":                                              "The lists on disk match the current .SIG",
+	"Caída brusca de puntos respecto a la generación anterior: %s":                                   "Sharp point drop since the previous generation: %s",
+	"Analiza un proyecto en memoria, sin SIGEXT y sin escribir nada":                                 "Analyzes a project in memory, without SIGEXT and without writing anything",
+	"Revisar todos los nodos en la carpeta compartida de producción":                                 "Review every node on the production share",
+	"%s: solo en el principal":                                                                       "%s: only in the primary",
+	"%s: %d entradas ≠ %d":                                                                           "%s: %d entries ≠ %d",
+	"%s: solo en el reserva":                                                                         "%s: only in the standby",
+	"redundancy %s: faltan primary o standby":                                                        "redundancy %s: primary or standby missing",
+	"%s y %s no exponen el mismo mapa DNP3 (%d diferencia(s)); no se escribe ninguno":                "%s and %s do not expose the same DNP3 map (%d difference(s)); neither is written",
+	"Pareja de app.redundancy (nombre o nodo); vacío = todas":                                        "Pair from app.redundancy (name or node); empty = all",
+	"Solo comprobar que los dos mapas coinciden, sin escribir":                                       "Only check that both maps match, without writing",
+	"Uso: dnpgen.exe pair -path \"C:\\Ruta\" [-pair NOMBRE] [-check] [-skip-ext]":                    "Usage: dnpgen.exe pair -path \"C:\\Path\" [-pair NAME] [-check] [-skip-ext]",
+	"[FATAL] No hay ninguna pareja %q en app.redundancy.pairs":                                       "[FATAL] No pair %q in app.redundancy.pairs",
+	"[FATAL] No hay parejas configuradas en app.redundancy.pairs":                                    "[FATAL] No pairs configured in app.redundancy.pairs",
+	"\n=== Pareja %s: %s / %s ===":                                                                   "\n=== Pair %s: %s / %s ===",
+	"Mapas idénticos; listas generadas: %s y %s":                                                     "Identical maps; lists generated: %s and %s",
+	"%s y %s exponen el mismo mapa DNP3":                                                             "%s and %s expose the same DNP3 map",
+	"Genera una pareja de RTU redundantes desde un .SIG y comprueba que su mapa DNP3 es idéntico":    "Generates a redundant RTU pair from one .SIG and checks that their DNP3 map is identical",
+	"Comprobar la pareja sin escribir":                                                               "Check the pair without writing",
+	"Generar todas las parejas de app.redundancy":                                                    "Generate every pair in app.redundancy",
+	"Los dos nodos de una pareja redundante no exponen el mismo mapa DNP3":                           "The two nodes of a redundant pair do not expose the same DNP3 map",
+	"Revise las diferencias por índice del mensaje y app.redundancy (primary_only, standby_only, rename); no se escribe ninguno de los dos nodos hasta que coincidan.": "Review the per-index differences in the message and app.redundancy (primary_only, standby_only, rename); neither node is written until they match.",
+	"%s y %s escribirían el mismo %s: configure app.output.dir con {node}":                                                                                             "%s and %s would write the same %s: set app.output.dir with {node}",
+	"Alarmas":                                              "Alarms",
+	"Alarmas: %s":                                          "Alarms: %s",
+	"contenido binario distinto":                           "binary content differs",
+	"Nodos a comprobar, separados por comas":               "Nodes to check, comma separated",
+	"Comprobar todos los nodos con .SIG":                   "Check every node with a .SIG",
+	"Directorio de referencia (un subdirectorio por nodo)": "Reference directory (one subdirectory per node)",
+	"Exportaciones a comparar, separadas por comas (por defecto las de app.exports)":                                     "Exports to compare, comma separated (default: those in app.exports)",
+	"Reescribir la referencia con lo generado":                                                                           "Rewrite the reference with the generated output",
+	"Uso: dnpgen.exe golden -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-dir golden] [-exports csv,json] [-update]": "Usage: dnpgen.exe golden -path \"C:\\Path\" -node \"Node1,Node2\" | -all [-dir golden] [-exports csv,json] [-update]",
+	"  ~ %s (referencia actualizada)":                                                                                    "  ~ %s (reference updated)",
+	"  + %s (sin referencia: ejecute con -update)":                                                                       "  + %s (no reference: run with -update)",
+	"  - %s (solo en la referencia)":                                                                                     "  - %s (only in the reference)",
+	"[ERROR] %d de %d nodo(s) no coinciden con la referencia":                                                            "[ERROR] %d of %d node(s) do not match the reference",
+	"Compara las listas y exportaciones con una referencia (pruebas de regresión)":                                       "Compares lists and exports with a reference (regression tests)",
+	"Crear la referencia del proyecto de prueba":                                                                         "Create the reference for the test project",
+	"Comprobar en el CI que las reglas no cambian el resultado":                                                          "Check in CI that the rules do not change the output",
+	"Uso: dnpgen.exe config schema [-o config.schema.json] [-from config.yaml]":                                          "Usage: dnpgen.exe config schema [-o config.schema.json] [-from config.yaml]",
+	"Fichero de salida (por defecto, la salida estándar)":                                                                "Output file (default: standard output)",
+	"config.yaml documentado del que tomar las descripciones (por defecto, el que se cargaría)":                          "Documented config.yaml to take descriptions from (default: the one that would be loaded)",
+	"[WARN] Esquema sin descripciones: %v":                                                                               "[WARN] Schema without descriptions: %v",
+	"Esquema escrito en %s (%d claves documentadas)":                                                                     "Schema written to %s (%d documented keys)",
+	"Escribe el JSON Schema de config.yaml para validarlo y autocompletarlo en el editor":                                "Writes the JSON Schema of config.yaml for validation and autocompletion in the editor",
+	"Esquema junto a config.yaml (VS Code: \"# yaml-language-server: $schema=config.schema.json\" en la primera línea)":  "Schema next to config.yaml (VS Code: \"# yaml-language-server: $schema=config.schema.json\" on the first line)",
+	"BOM UTF-8 eliminado":                          "UTF-8 BOM removed",
+	"finales de línea mezclados normalizados (%s)": "mixed line endings normalized (%s)",
+	"finales de línea CR normalizados (%d)":        "CR line endings normalized (%d)",
+	"timezone desconocida %q (local o utc)":        "unknown timezone %q (local or utc)",
+	"%s: capacidad agotada (%d/%d)":                "%s: capacity exhausted (%d/%d)",
+	"%s: capacidad agotada en ~%.0f días (%s) al ritmo de %+.1f posiciones/30 días; quedan %d de %d": "%s: capacity exhausted in ~%.0f days (%s) at %+.1f positions/30 days; %d of %d left",
+	"Nodos, separados por comas":                                                                                  "Nodes, comma separated",
+	"Directorio de salida, si no es el del recurso":                                                               "Output directory, if not the resource directory",
+	"Uso: dnpgen.exe spares -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-json]":                              "Usage: dnpgen.exe spares -path \"C:\\Path\" -node \"Node1,Node2\" | -all [-json]",
+	"Sin historial de spares: active app.spare_forecast y genere los nodos":                                       "No spare history: enable app.spare_forecast and generate the nodes",
+	"NODO\tLISTA\tPOSICIONES\tSPARES\tCAPACIDAD\tLIBRES\tCRECIMIENTO/30D\tSPARES OCUPADOS\tMUESTRAS\tAGOTAMIENTO": "NODE\tLIST\tPOSITIONS\tSPARES\tCAPACITY\tFREE\tGROWTH/30D\tSPARES USED\tSAMPLES\tEXHAUSTION",
+	"historial de spares: %v":                                                                                     "spare history: %v",
+	"Consumo de spares y previsión de agotamiento de la capacidad de cada lista":                                  "Spare consumption and capacity exhaustion forecast per list",
+	"Ver qué listas se agotarán primero":                                                                          "See which lists will run out first",
+	"Resumen":                                                                                                     "Summary",
+	"Índice":                                                                                                      "Index",
+	"Índice de variables":                                                                                         "Variable index",
+	"point_book: formato desconocido %q (xlsx o html)":                                                            "point_book: unknown format %q (xlsx or html)",
+	"Escribiendo el libro de puntos %s...":                                                                        "Writing point book %s...",
+	"Libro de puntos: %s":                                                                                         "Point book: %s",
+	"%s está vacío":                                                                                               "%s is empty",
+	"se requieren proyecto y nodo, o un .SIG":                                                                     "project and node, or a .SIG, are required",
+	"Reglas de clasificación guardadas en %s":                                                                     "Classification rules saved to %s",
+	"se requieren proyecto y nodo":                                                                                "project and node are required",
+	"origen no permitido":                                                                                         "origin not allowed",
+	"Proyecto que se abre al arrancar":                                                                            "Project opened at startup",
+	"Dirección de escucha (por defecto, un puerto libre local)":                                                   "Listen address (default: a free local port)",
+	"No abrir el navegador":                                                                                       "Do not open the browser",
+	"Interfaz en %s (Ctrl+C para salir)":                                                                          "Interface at %s (Ctrl+C to quit)",
+	"[WARN] No se pudo abrir el navegador: %v":                                                                    "[WARN] Could not open the browser: %v",
+	"Interfaz gráfica en el navegador: nodos, reglas con vista previa y generación":                               "Browser-based GUI: nodes, rules with live preview and generation",
+	"Abrir la interfaz con un proyecto":                                                                           "Open the interface with a project",
+	"%s no tiene app.classification":                                                                              "%s has no app.classification",
+	"no se puede leer %s: %v":                                                                                     "cannot read %s: %v",
+	"SIGEXT no podrá escribir en %s: %v":                                                                          "SIGEXT will not be able to write to %s: %v",
+	"SIGEXT no podrá sobrescribir %s: %v":                                                                         "SIGEXT will not be able to overwrite %s: %v",
+	"no se puede escribir en el directorio de salida %s: %v":                                                      "cannot write to output directory %s: %v",
+	"no se puede sobrescribir %s: %v":                                                                             "cannot overwrite %s: %v",
+	"fichero de solo lectura":                                                                                     "read-only file",
+	"bloqueado o sin permiso (¿abierto en otro programa?): %v":                                                    "locked or no permission (open in another program?): %v",
+	"no existe":                                           "does not exist",
+	".SIG legible y al día":                               "Readable and up-to-date .SIG",
+	"formato normalizado al leer: %s":                     "format normalized on read: %s",
+	"Listas del disco iguales a las del .SIG":             "Lists on disk match the .SIG",
+	"no existe %s: genere el nodo":                        "%s does not exist: generate the node",
+	"%s no coincide con el .SIG actual: regenere el nodo": "%s does not match the current .SIG: regenerate the node",
+	"Codificación de las listas":                          "List encoding",
+	"sin listas en el disco":                              "no lists on disk",
+	"Capacidad de las listas y límites del firmware":      "List capacity and firmware limits",
+	"sin findings.capacity ni firmware configurados":      "no findings.capacity or firmware configured",
+	"Simetría de las listas espejo":                       "Mirror list symmetry",
+	"Coherencia con __vardef.ini":                         "Consistency with __vardef.ini",
+	"app.scaling.from_vardef desactivado":                 "app.scaling.from_vardef disabled",
+	"Mapa de índices":                                     "Index map",
+	"app.lifecycle desactivado":                           "app.lifecycle disabled",
+	"Otros hallazgos":                                     "Other findings",
+	"empieza con BOM UTF-8":                               "starts with a UTF-8 BOM",
+	"contiene bytes nulos":                                "contains NUL bytes",
+	"línea %d: caracteres no ASCII":                       "line %d: non-ASCII characters",
+	"finales de línea mezclados (%d CRLF, %d LF)":         "mixed line endings (%d CRLF, %d LF)",
+	"no hay mapa de índices en %s: genere el nodo":        "no index map at %s: generate the node",
+	"%s asignado a %s y a %s":                             "%s assigned to %s and %s",
+	"%s %s: fuera de la lista (%d posiciones)":            "%s %s: outside the list (%d positions)",
+	"%s: el mapa tiene %s y la lista %s":                  "%s: the map has %s and the list %s",
+	"%s: índice retirado de %s ocupado por %s":            "%s: retired index of %s taken by %s",
+	"... y %d más":                                        "... and %d more",
+	"LISTO PARA DESCARGAR":                                "READY TO DOWNLOAD",
+	"NO DESCARGAR":                                        "DO NOT DOWNLOAD",
+	"Comprobación antes de descargar al controlador":      "Pre-download check for the controller",
+	"Comprobación":                                        "Check",
+	"Detalle":                                             "Details",
+	"Revisado por":                                        "Reviewed by",
+	"Fecha":                                               "Date",
+	"Firma":                                               "Signature",
+	"Informe HTML imprimible de la comprobación":          "Printable HTML report of the check",
+	"Uso: dnpgen.exe preflight -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-report comprobacion.html] [-json]": "Usage: dnpgen.exe preflight -path \"C:\\Path\" -node \"Node1,Node2\" | -all [-report check.html] [-json]",
+	"Informe de comprobación: %s":                                                                                       "Check report: %s",
+	"%d de %d nodo(s) no están listos para descargar":                                                                   "%d of %d node(s) are not ready to download",
+	"Comprobación antes de descargar al controlador, con lista imprimible":                                              "Pre-download check for the controller, with a printable checklist",
+	"Revisar el nodo e imprimir la lista antes de la descarga":                                                          "Check the node and print the checklist before downloading",
+	"comprobación de ida y vuelta: %v":                                                                                  "round-trip check: %v",
+	"%s no se relee igual: %s":                                                                                          "%s does not read back the same: %s",
+	"el nodo se generó con las reglas %s y config.yaml tiene las %s: revise los cambios con dnpgen migrate":             "the node was generated with rules %s and config.yaml has %s: review the changes with dnpgen migrate",
+	"las reglas han cambiado sin cambiar app.rules_version (%s, huella %s → %s): revise los cambios con dnpgen migrate": "the rules changed without changing app.rules_version (%s, fingerprint %s → %s): review the changes with dnpgen migrate",
+	"(sin versión)": "(no version)",
+	"[WARN] Los puntos pueden cambiar de lista o de índice respecto a lo descargado":           "[WARN] Points may change list or index compared to what was downloaded",
+	"Regenerar con las reglas nuevas y aceptarlas (sin -apply solo se muestran los cambios)":   "Regenerate with the new rules and accept them (without -apply the changes are only shown)",
+	"Uso: dnpgen.exe migrate -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-apply] [-json]": "Usage: dnpgen.exe migrate -path \"C:\\Path\" -node \"Node1,Node2\" | -all [-apply] [-json]",
+	"Reglas %s: sin cambios":                                                            "Rules %s: no changes",
+	"Ningún punto cambia de lista ni de índice":                                         "No point changes list or index",
+	"VARIABLE\tANTES\tDESPUÉS":                                                          "VARIABLE\tBEFORE\tAFTER",
+	"Migrado: %d punto(s) cambian de posición; reglas %s aceptadas":                     "Migrated: %d point(s) change position; rules %s accepted",
+	"\nEjecute con -apply para regenerar los nodos con las reglas nuevas y aceptarlas.": "\nRun with -apply to regenerate the nodes with the new rules and accept them.",
+	"¡ATENCIÓN! %s":          "WARNING! %s",
+	"registro de reglas: %v": "rules record: %v",
+	"Versión de las reglas":  "Rules version",
+	"Muestra y acepta los cambios de posición al cambiar las reglas (app.rules_version)": "Shows and accepts position changes when the rules change (app.rules_version)",
+	"Ver qué puntos mueven las reglas nuevas":                                            "See which points the new rules move",
+	"Regenerar y aceptar las reglas nuevas":                                              "Regenerate and accept the new rules",
+	"%s: %q no es un tipo analógico del .SIG":                                            "%s: %q is not an analog .SIG type",
+	"%s: tipo repetido":                                                "%s: duplicate type",
+	"%s: event_class %d fuera de 0..3":                                 "%s: event_class %d out of 0..3",
+	"analog_subtypes.%s: la lista %q no está en extra_lists":           "analog_subtypes.%s: list %q is not in extra_lists",
+	"%s: faltan trip o close":                                          "%s: trip or close is missing",
+	"%s: keep desconocido %q (trip o close)":                           "%s: unknown keep %q (trip or close)",
+	"%s: %s sin su close":                                              "%s: %s without its close",
+	"%s: %s sin su trip":                                               "%s: %s without its trip",
+	"rango %s %d-%d: lista desconocida":                                "range %s %d-%d: unknown list",
+	"rango %s %d-%d: fuera de la lista (%d entradas)":                  "range %s %d-%d: outside the list (%d entries)",
+	"el punto %s no está en el fichero de listas":                      "point %s is not in the lists file",
+	"%s necesita el fichero de listas existente: %v":                   "%s needs the existing lists file: %v",
+	"%s[%d] %s: las reglas lo llevan ahora a %s":                       "%s[%d] %s: the rules now place it in %s",
+	"%s[%d] %s: ya no está en el .SIG":                                 "%s[%d] %s: no longer in the .SIG",
+	"%d punto(s) congelado(s) en %s%s no se pueden conservar:\n  - %s": "%d frozen point(s) in %s%s cannot be preserved:\n  - %s",
+	"congelación %s[%d]: %v":                                           "freeze %s[%d]: %v",
+	"Entradas congeladas conservadas: %d":                              "Frozen entries preserved: %d",
+	"Un punto congelado ya no se puede conservar en su índice":         "A frozen point can no longer be kept at its index",
+	"El .SIG ya no trae el punto o las reglas lo llevan a otra lista: restaure la variable o la regla, o, si el cambio está aprobado, quite el punto del fichero de congelación (<salida>/<nodo>.freeze.yaml).": "The .SIG no longer has the point or the rules move it to another list: restore the variable or the rule or, if the change is approved, remove the point from the freeze file (<output>/<node>.freeze.yaml).",
+	"configuración de referencia: %v":                                               "reference configuration: %v",
+	"%s: DI %d, DO %d, AI %d, AO %d; %d hallazgo(s)":                                "%s: DI %d, DO %d, AI %d, AO %d; %d finding(s)",
+	"Conservar el proyecto de muestra y mostrar dónde está":                         "Keep the sample project and show where it is",
+	"Proyecto de muestra: %s":                                                       "Sample project: %s",
+	"La instalación no supera la autocomprobación":                                  "The installation fails the self-test",
+	"Instalación correcta":                                                          "Installation OK",
+	"Genera un proyecto de muestra incluido y comprueba el resultado (instalación)": "Generates a bundled sample project and checks the result (installation)",
+	"Comprobar la instalación en un puesto nuevo":                                   "Check the installation on a new workstation",
+	"Dejar el proyecto de muestra para revisarlo":                                   "Keep the sample project for review",
+	"%d exportadores fallaron: %s":                                                  "%d exporters failed: %s",
+	"pánico en el exportador: %v":                                                   "exporter panicked: %v",
+	"sig_source: origen desconocido %q (disponibles: %s)":                           "sig_source: unknown source %q (available: %s)",
+	"sig_source.backends.%s: tipo desconocido %q (disponibles: %s)":                 "sig_source.backends.%s: unknown type %q (available: %s)",
+	"el .SIG recibido está vacío":                                                   "the received .SIG is empty",
+	"falta url":                                                                     "url is missing",
+	"falta path":                                                                    "path is missing",
+	"%s no contiene %s":                                                             "%s does not contain %s",
+	"falta command":                                                                 "command is missing",
+	"%s: sin terminar tras %s":                                                      "%s: not finished after %s",
+	"%s terminó sin escribir {sig}":                                                 "%s finished without writing {sig}",
+	"Obteniendo el .SIG (%s)...":                                                    "Fetching the .SIG (%s)...",
+	"Obteniendo el .SIG (%s)":                                                       "Fetching the .SIG (%s)",
+	"no se podrá escribir el .SIG en %s: %v":                                        "the .SIG cannot be written to %s: %v",
+	"no se podrá sobrescribir %s: %v":                                               "%s cannot be overwritten: %v",
+	"No se pudo traer el .SIG del origen configurado":                               "The .SIG could not be fetched from the configured source",
+	"Revise <nodo>.sigext.log y el backend de app.sig_source (URL, token, paquete o comando); con -skip-ext se usa el .SIG existente.": "Check <node>.sigext.log and the app.sig_source backend (URL, token, archive or command); with -skip-ext the existing .SIG is used.",
+	"versión de formato .SIG %s no soportada (soportadas: %s)":                                                                         ".SIG format version %s not supported (supported: %s)",
+	"Formato del .SIG: versión %s":                                                                         ".SIG format: version %s",
+	"comm_health: la lista %q no está en extra_lists":                                                      "comm_health: list %q is not in extra_lists",
+	"comm_health.devices[%d]: el patrón necesita un grupo con el nombre del equipo":                        "comm_health.devices[%d]: the pattern needs a group with the device name",
+	"Comunicación con {device}":                                                                            "Communication with {device}",
+	"Puntos de estado de comunicación: %d en %s":                                                           "Communication status points: %d in %s",
+	"spares.duplicates desconocido %q (allow, warn, unique-required)":                                      "unknown spares.duplicates %q (allow, warn, unique-required)",
+	"%s aparece %d veces (%s%s)":                                                                           "%s appears %d times (%s%s)",
+	"%s: %dms frente a 0ms de mediana (%d ejecuciones anteriores)":                                         "%s: %dms against a 0ms median (%d previous runs)",
+	"%s: %dms frente a %dms de mediana (x%.1f, %d ejecuciones anteriores)":                                 "%s: %dms against a %dms median (x%.1f, %d previous runs)",
+	"Uso: dnpgen.exe perf report -path \"C:\\Ruta\" [-node Nodo1,Nodo2] [-last 10] [-threshold 2] [-json]": "Usage: dnpgen.exe perf report -path \"C:\\Path\" [-node Node1,Node2] [-last 10] [-threshold 2] [-json]",
+	"Ejecuciones anteriores de referencia (por defecto app.perf.window)":                                   "Previous runs to compare against (default app.perf.window)",
+	"Factor sobre la mediana que se marca como regresión (por defecto app.perf.threshold)":                 "Factor over the median flagged as a regression (default app.perf.threshold)",
+	"[FATAL] No hay historial de rendimiento en %s: active app.perf y genere los nodos":                    "[FATAL] No performance history in %s: enable app.perf and generate the nodes",
+	"Sin historial de rendimiento: active app.perf y genere los nodos":                                     "No performance history: enable app.perf and generate the nodes",
+	"REGRESIÓN":      "REGRESSION",
+	"pocas muestras": "few samples",
+	"NODO\tETAPA\tÚLTIMA MS\tMEDIANA MS\tFACTOR\tMUESTRAS\tSEÑALES\tFECHA\tESTADO":          "NODE\tSTAGE\tLAST MS\tMEDIAN MS\tFACTOR\tSAMPLES\tSIGNALS\tDATE\tSTATUS",
+	"%d etapa(s) más lentas que x%.1f la mediana":                                           "%d stage(s) slower than x%.1f the median",
+	"Compare la versión del programa, la del .SIG y las señales con \"perf report -json\".": "Compare the program version, the .SIG version and the signals with \"perf report -json\".",
+	"[WARN] No se pudo guardar el historial de rendimiento: %v":                             "[WARN] Could not save the performance history: %v",
+	"[WARN] Rendimiento: %s":                                  "[WARN] Performance: %s",
+	"Historial de rendimiento de las generaciones (app.perf)": "Performance history of the generations (app.perf)",
+	"Página del informe":                                      "Report page",
+	"Compara las etapas de la última generación con las anteriores y marca regresiones":                  "Compares the stages of the last generation with the previous ones and flags regressions",
+	"¿Qué etapa se ha vuelto más lenta?":                                                                 "Which stage has become slower?",
+	"Un nodo contra sus 20 ejecuciones anteriores":                                                       "One node against its 20 previous runs",
+	"anonimización: %q y %q dan el mismo hash %s":                                                        "anonymization: %q and %q give the same hash %s",
+	"Sustituir variables, descripciones, áreas y nodo por hashes (app.anonymize)":                        "Replace variables, descriptions, areas and node with hashes (app.anonymize)",
+	"Modelo sin nombres del cliente para soporte":                                                        "Model without customer names for support",
+	"CSV anonimizado a partir de un modelo guardado":                                                     "Anonymized CSV from a saved model",
+	"%q: campo desconocido %q (disponibles: %s)":                                                         "%q: unknown field %q (available: %s)",
+	"%q: función desconocida %q":                                                                         "%q: unknown function %q",
+	"sorting.%s: groups sin group":                                                                       "sorting.%s: groups without group",
+	"sorting: lista desconocida %q":                                                                      "sorting: unknown list %q",
+	"sorting: %s y %s son listas espejo y se ordenan juntas: configure solo una (o la misma en las dos)": "sorting: %s and %s are mirror lists and are sorted together: configure only one (or the same in both)",
+	"Orden de %s: %d punto(s) nuevo(s) ordenados detrás de los %d existentes para no mover sus índices":  "Order of %s: %d new point(s) sorted after the %d existing ones so their indexes do not move",
+	"%s: YAML malformado: %v":                                                                            "%s: malformed YAML: %v",
+	"alta":                                                                                               "added",
+	"baja":                                                                                               "removed",
+	"lista":                                                                                              "list",
+	"índice":                                                                                             "index",
+	"Reglas actuales (vacío = config.yaml)":                                                              "Current rules (empty = config.yaml)",
+	"Reglas propuestas, completas o solo las secciones que cambian":                                      "Proposed rules, complete or only the sections that change",
+	"Salir con error si algún punto cambia de posición":                                                  "Exit with an error if any point changes position",
+	"Uso: dnpgen.exe rules impact -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all -new reglas_b.yaml [-old reglas_a.yaml] [-json] [-strict]": "Usage: dnpgen.exe rules impact -path \"C:\\Path\" -node \"Node1,Node2\" | -all -new rules_b.yaml [-old rules_a.yaml] [-json] [-strict]",
+	"reglas actuales: %v":                                                                                                "current rules: %v",
+	"reglas propuestas: %v":                                                                                              "proposed rules: %v",
+	"VARIABLE\tANTES\tDESPUÉS\tCAMBIO":                                                                                   "VARIABLE\tBEFORE\tAFTER\tCHANGE",
+	"\n%d de %d nodo(s) cambian; %d punto(s) en total":                                                                   "\n%d of %d node(s) change; %d point(s) in total",
+	"Puntos que cambian de lista o de índice entre dos juegos de reglas":                                                 "Points that change list or index between two rule sets",
+	"Revisar unas reglas nuevas en todos los nodos":                                                                      "Review new rules on every node",
+	"Comparar dos propuestas entre sí":                                                                                   "Compare two proposals with each other",
+	"disabled_lists: lista desconocida %q (disponibles: AI, AO, DI, DO, OS)":                                             "disabled_lists: unknown list %q (available: AI, AO, DI, DO, OS)",
+	"disabled_lists: el nodo %s se queda sin listas DNP3":                                                                "disabled_lists: node %s is left without DNP3 lists",
+	"%s (línea %d del .SIG, %s) va a %s, desactivada en este nodo: no se genera":                                         "%s (.SIG line %d, %s) goes to %s, disabled on this node: not generated",
+	"No hay ninguna ejecución interrumpida de %s: se generan todos los nodos":                                            "No interrupted run of %s: generating every node",
+	"la ejecución interrumpida de %s usaba otras opciones o reglas; ejecute sin -resume":                                 "the interrupted run of %s used other options or rules; run without -resume",
+	"[WARN] Lote incompleto: %d nodo(s) terminados; repita la orden con -resume para seguir por el primero sin terminar": "[WARN] Incomplete batch: %d node(s) finished; repeat the command with -resume to continue from the first unfinished one",
+	"[WARN] No se pudo borrar el fichero de control %s: %v":                                                              "[WARN] Could not delete the checkpoint file %s: %v",
+	"[WARN] %s: ya generado, pero no se pudo leer su lista (%v); se genera de nuevo":                                     "[WARN] %s: already generated, but its list could not be read (%v); generating it again",
+	"=== Nodo %s: ya generado en la ejecución interrumpida, se salta ===":                                                "=== Node %s: already generated in the interrupted run, skipped ===",
+	"[WARN] No se pudo guardar el punto de control: %v":                                                                  "[WARN] Could not save the checkpoint: %v",
+	"Con -all o -workspace, continuar la ejecución interrumpida por el primer nodo sin terminar":                         "With -all or -workspace, resume the interrupted run from the first unfinished node",
+	"-resume solo tiene sentido con -all o -workspace":                                                                   "-resume only makes sense with -all or -workspace",
+	"Continuar un lote interrumpido por el primer nodo sin terminar":                                                     "Resume an interrupted batch from the first unfinished node",
+	"Continuar un workspace interrumpido":                                                                                "Resume an interrupted workspace",
+	"OK: %s generado (%s)\n":                                                                                             "OK: %s generated (%s)\n",
+	"AVISOS: %s generado con %d aviso(s) (%s)\n":                                                                         "WARNINGS: %s generated with %d warning(s) (%s)\n",
+	"ERROR: no se ha generado ningún nodo\n":                                                                             "ERROR: no node was generated\n",
+	"ERROR: %s no se ha generado\n":                                                                                      "ERROR: %s was not generated\n",
+	"Listas: %s\n":                                                                                                       "Lists: %s\n",
+	"  ... y %d más (ver el log)\n":                                                                                      "  ... and %d more (see the log)\n",
+	"Log: %s\n":                                                                                                          "Log: %s\n",
+	"[WARN] post-build: se ignora %q, no es una ruta existente":                                                          "[WARN] post-build: ignoring %q, not an existing path",
+	"[WARN] post-build: se ignora %q, no es un .mwt ni un .SIG":                                                          "[WARN] post-build: ignoring %q, not a .mwt or .SIG",
+	"-path %s: no es el directorio del proyecto ni un .mwt o .SIG":                                                       "-path %s: not the project directory, a .mwt or a .SIG",
+	"%s tiene %d nodo(s): indique el nodo con -node, el .mwt o %s":                                                       "%s has %d node(s): give the node with -node, the .mwt or %s",
+	"post-build: proyecto %s, nodo %s":                                                                                   "post-build: project %s, node %s",
+	"Ruta raíz del proyecto (por defecto, la que pase el IDE)":                                                           "Project root path (default: the one the IDE passes)",
+	"Nombre del Nodo (por defecto, el que pase el IDE)":                                                                  "Node name (default: the one the IDE passes)",
+	"Fichero de resultado (por defecto app.post_build.result)":                                                           "Result file (default: app.post_build.result)",
+	"Resultado en JSON": "Result as JSON",
+	"app.post_build.format desconocido %q; se usa text": "unknown app.post_build.format %q; using text",
+	"post-build: %s en %d ms":                           "post-build: %s in %d ms",
+	"Genera el nodo desde la herramienta externa del IDE de CWave, sin consola, con fichero de resultado": "Generates the node from the CWave IDE external tool, without console output, with a result file",
+	"Con la ruta del .mwt que pasa el IDE":                           "With the .mwt path the IDE passes",
+	"Resultado en JSON en una ruta fija":                             "JSON result at a fixed path",
+	"la configuración redactada aún contiene una clave privada":      "the redacted configuration still contains a private key",
+	"línea %s: clave desconocida %s":                                 "line %s: unknown key %s",
+	" (ahora se llama %s)":                                           " (now called %s)",
+	"Escuchar en la red (exige app.server.token y app.server.roots)": "Listen on the network (requires app.server.token and app.server.roots)",
+	"entrada analógica (AI)":                                         "analog input (AI)",
+	"entrada digital (DI)":                                           "digital input (DI)",
+	"estrategia por defecto (%s)":                                    "default strategy (%s)",
+	"__vardef.ini o sin escalado":                                    "__vardef.ini or no scaling",
+	"área por prefijo o por defecto":                                 "area by prefix or default",
+	"Uso: dnpgen.exe rules lint -path \"C:\\Ruta\" -node \"NombreNodo\" | -sig fichero.SIG":        "Usage: dnpgen.exe rules lint -path \"C:\\Path\" -node \"NodeName\" | -sig file.SIG",
+	"Ruta directa a un .SIG (alternativa a -path/-node)":                                           "Direct path to a .SIG (alternative to -path/-node)",
+	"Salir con error si hay reglas muertas o conflictos":                                           "Exit with an error if there are dead rules or conflicts",
+	"\n--- ANÁLISIS DE REGLAS: %s (%d señales) ---":                                                "\n--- RULE ANALYSIS: %s (%d signals) ---",
+	"No hay reglas configuradas":                                                                   "No rules configured",
+	" (%d reglas, %d señales evaluadas)":                                                           " (%d rules, %d signals evaluated)",
+	"  patrón inválido %q: %v":                                                                     "  invalid pattern %q: %v",
+	"  sin coincidencias: %q -> %s":                                                                "  no matches: %q -> %s",
+	"  %d variable(s) con reglas en conflicto (gana la primera):":                                  "  %d variable(s) with conflicting rules (the first one wins):",
+	"    ... y %d más":                                                                             "    ... and %d more",
+	"  %d sin regla -> %s: %s":                                                                     "  %d without a rule -> %s: %s",
+	"\n%d aviso(s) en las reglas":                                                                  "\n%d warning(s) in the rules",
+	"\nTodas las reglas coinciden y no hay conflictos":                                             "\nAll rules match and there are no conflicts",
+	"%s: la sección %s no se encontró al releer":                                                   "%s: section %s was not found when reading back",
+	"%s: %d puntos generados, %d al releer":                                                        "%s: %d points generated, %d when reading back",
+	"%s[%d]: generado %q, releído %q":                                                              "%s[%d]: generated %q, read back %q",
+	"%s: sección inesperada al releer (%d puntos)":                                                 "%s: unexpected section when reading back (%d points)",
+	"Comprobar un __lists.ini existente (releer y reescribir) en lugar de generar":                 "Check an existing __lists.ini (read back and rewrite) instead of generating",
+	"Ejecutar SIGEXT antes de generar":                                                             "Run SIGEXT before generating",
+	"el fichero releído no se reescribe igual (formato o espacios distintos)":                      "the file read back is not rewritten identically (different format or whitespace)",
+	"Uso: dnpgen.exe check-roundtrip -path \"C:\\Ruta\" -node \"NombreNodo\" | -lists __lists.ini": "Usage: dnpgen.exe check-roundtrip -path \"C:\\Path\" -node \"NodeName\" | -lists __lists.ini",
+	"Ida y vuelta de %s: %d diferencia(s)":                                                         "Round trip of %s: %d difference(s)",
+	"Ida y vuelta de %s: OK":                                                                       "Round trip of %s: OK",
+	"Fichero .SIG a escribir (por defecto <nodo>.SIG)":                                             ".SIG file to write (default <node>.SIG)",
+	"Crear un proyecto completo en este directorio (recurso RTU, .SIG y .mwt)":                     "Create a complete project in this directory (RTU resource, .SIG and .mwt)",
+	"Nombre del nodo":                                           "Node name",
+	"Dígitos de {n} en los nombres":                             "Digits of {n} in the names",
+	"Prefijos de área para {area}, separados por comas":         "Area prefixes for {area}, comma-separated",
+	"Añadir DESC= a cada señal":                                 "Add DESC= to every signal",
+	"Mezclar las categorías en lugar de escribirlas en bloques": "Mix the categories instead of writing them in blocks",
+	"Semilla de -shuffle (misma semilla, mismo fichero)":        "-shuffle seed (same seed, same file)",
+	"Finales de línea LF en lugar de CRLF":                      "LF line endings instead of CRLF",
+	"Número de señales %s":                                      "Number of %s signals",
+	"Plantilla de nombre %s ({n}, {area})":                      "%s name template ({n}, {area})",
+	"%s: %d señales (%s)":                                       "%s: %d signals (%s)",
+	"Proyecto comprimido %s: %d fichero(s) extraídos en %s":     "Compressed project %s: %d file(s) extracted to %s",
+	"no contiene el nodo %s":                                    "does not contain node %s",
+	"no contiene ningún proyecto con ficheros .SIG":             "does not contain any project with .SIG files",
+	"-only regenera %s pero conserva %s, que también ha cambiado en el .SIG: sus índices espejo ya no se corresponden; regenere ambas (-only %s,%s)": "-only regenerates %s but keeps %s, which has also changed in the .SIG: their mirror indices no longer match; regenerate both (-only %s,%s)",
+	"comprobaciones previas, %d problema(s):\n  - %s":                                                                    "preflight checks, %d problem(s):\n  - %s",
+	"error escribiendo la configuración efectiva: %v":                                                                    "error writing the effective configuration: %v",
+	"error escribiendo el manifiesto: %v":                                                                                "error writing the manifest: %v",
+	"[WARN] SIGEXT recibe rutas UNC (%s); si falla, mapee una unidad en app.sigext_paths.map":                            "[WARN] SIGEXT receives UNC paths (%s); if it fails, map a drive in app.sigext_paths.map",
+	"%s: falta password o key_file":                                                                                      "%s: password or key_file is missing",
+	"[WARN] %s: no se verifica la clave del host %s (insecure_ignore_host_key)":                                          "[WARN] %s: the host key of %s is not verified (insecure_ignore_host_key)",
+	"%s: falta known_hosts; sin él no se verifica la clave del host (use insecure_ignore_host_key: true para aceptarlo)": "%s: known_hosts is missing; without it the host key is not verified (set insecure_ignore_host_key: true to accept that)",
+	"Origen de las versiones (anula app.update.url)":                                                                     "Release source (overrides app.update.url)",
+	"Solo comprobar si hay una versión nueva":                                                                            "Only check whether a newer version exists",
+	"Instalar aunque la versión no sea más reciente":                                                                     "Install even if the version is not newer",
+	"[FATAL] Falta el origen de las versiones (-url o app.update.url)":                                                   "[FATAL] Missing release source (-url or app.update.url)",
+	"Versión instalada: %s | publicada: %s\n":                                                                            "Installed version: %s | published: %s\n",
+	"Hay una versión nueva disponible":                                                                                   "A newer version is available",
+	"Ya está actualizado":                                                                                                "Already up to date",
+	"[FATAL] No se pudo reemplazar %s: %v":                                                                               "[FATAL] Could not replace %s: %v",
+	"Actualizado a %s":                                                                                                   "Updated to %s",
+	"SHA256SUMS.sig: firma de %d bytes (se esperan %d)":                                                                  "SHA256SUMS.sig: %d-byte signature (expected %d)",
+	"la firma de SHA256SUMS no es válida":                                                                                "the SHA256SUMS signature is not valid",
+	"sin app.update.public_key no se instalan versiones (use allow_unsigned para aceptar solo el hash)":                  "without app.update.public_key no version is installed (use allow_unsigned to accept the hash alone)",
+	"hash de %s incorrecto: %s, se esperaba %s":                                                                          "wrong hash for %s: %s, expected %s",
+	"%s no aparece en SHA256SUMS":                                                                                        "%s is not listed in SHA256SUMS",
+	"respuesta de GitHub: %v":                                                                                            "GitHub response: %v",
+	"la release %s no incluye %s":                                                                                        "release %s does not include %s",
+	"Uso: dnpgen.exe compact -path \"C:\\Ruta\" -node \"NombreNodo\" [-policy trailing|runs|all]":                        "Usage: dnpgen.exe compact -path \"C:\\Path\" -node \"NodeName\" [-policy trailing|runs|all]",
+	"Uso: dnpgen.exe query -path \"C:\\Ruta\" [-name \"LIT*\"] [-list DI] [-node N] [-from 0 -to 15]":                    "Usage: dnpgen.exe query -path \"C:\\Path\" [-name \"LIT*\"] [-list DI] [-node N] [-from 0 -to 15]",
+	"Uso: dnpgen.exe simulate -path \"C:\\Ruta\" -node \"NombreNodo\" [-addr 0.0.0.0:20000]":                             "Usage: dnpgen.exe simulate -path \"C:\\Path\" -node \"NodeName\" [-addr 0.0.0.0:20000]",
+	"Uso: dnpgen.exe verify -path \"C:\\Ruta\" -node \"NombreNodo\" -host 10.0.0.5":                                      "Usage: dnpgen.exe verify -path \"C:\\Path\" -node \"NodeName\" -host 10.0.0.5",
+	"%s compactado (política %s)":                                                                                        "%s compacted (policy %s)",
+	"%s no admite STARTTLS (use tls: none para enviar sin cifrar)":                                                       "%s does not support STARTTLS (use tls: none to send unencrypted)",
+	"%s no es una dirección de loopback: configure app.server.roots":                                                     "%s is not a loopback address: configure app.server.roots",
+	"%s no es una dirección de loopback: configure app.server.token":                                                     "%s is not a loopback address: configure app.server.token",
+	"%s: %d -> %d entradas":                                                                                              "%s: %d -> %d entries",
+	"Descargando %s...":                                                                                                  "Downloading %s...",
+	"Dirección DNP3 de la outstation":                                                                                    "DNP3 address of the outstation",
+	"Dirección DNP3 local (master)":                                                                                      "Local DNP3 address (master)",
+	"Dirección TCP de escucha":                                                                                           "TCP listen address",
+	"Dirección de escucha HTTP (fuera de loopback exige app.server.token y app.server.roots)":                            "HTTP listen address (outside loopback requires app.server.token and app.server.roots)",
+	"Dirección de escucha gRPC (vacío = deshabilitado)":                                                                  "gRPC listen address (empty = disabled)",
+	"Directorio para persistir la cola de trabajos (vacío = solo memoria)":                                               "Directory to persist the job queue (empty = memory only)",
+	"ETAPA\tMEJOR\tns/señal\tMB asignados\tasignaciones\t":                                                               "STAGE\tBEST\tns/signal\tMB allocated\tallocations\t",
+	"Error abriendo log: %v":                                                                                             "Error opening log: %v",
+	"Error en schedule: %v":                                                                                              "Error in schedule: %v",
+	"Error inicializando cola de trabajos: %v":                                                                           "Error initialising job queue: %v",
+	"Fichero CSV para la tabla de reasignación (vacío = salida estándar)":                                                "CSV file for the remapping table (empty = standard output)",
+	"Fichero de log (útil al ejecutarse como servicio)":                                                                  "Log file (useful when running as a service)",
+	"IP o nombre de la RTU":                                                                                              "IP or name of the RTU",
+	"Incluir spares":                                                                                                     "Include spares",
+	"JSON inválido: ":                                                                                                    "invalid JSON: ",
+	"Lista: DI, DO, AI, AO, OS o una de classification.extra_lists":                                                      "List: DI, DO, AI, AO, OS or one of classification.extra_lists",
+	"Master %s desconectado: %v":                                                                                         "Master %s disconnected: %v",
+	"Master conectado desde %s":                                                                                          "Master connected from %s",
+	"Máximo de trabajos en espera (0 = sin límite)":                                                                      "Maximum queued jobs (0 = no limit)",
+	"NODO\tLISTA\tÍNDICE\tPUNTO\tTIPO":                                                                                   "NODE\tLIST\tINDEX\tPOINT\tTYPE",
+	"No reescribir el fichero de listas":                                                                                 "Do not rewrite the lists file",
+	"Número de señales del .SIG sintético":                                                                               "Number of signals in the synthetic .SIG",
+	"Outstation %d escuchando en %s (DI: %d | DO: %d | AI: %d | AO: %d)":                                                 "Outstation %d listening on %s (DI: %d | DO: %d | AI: %d | AO: %d)",
+	"Patrón de variable (* y ? como comodines)":                                                                          "Variable pattern (* and ? as wildcards)",
+	"Política: trailing, runs o all":                                                                                     "Policy: trailing, runs or all",
+	"Puerto TCP DNP3 de la RTU":                                                                                          "DNP3 TCP port of the RTU",
+	"Recuperados %d trabajos pendientes de %s":                                                                           "Recovered %d pending jobs from %s",
+	"Repeticiones por etapa (se informa la mejor)":                                                                       "Repetitions per stage (the best is reported)",
+	"Ruta directa a la base de puntos (alternativa a -path)":                                                             "Direct path to the point database (alternative to -path)",
+	"SIG: %s (%d señales, %.1f MB)\n\n":                                                                                  "SIG: %s (%d signals, %.1f MB)\n\n",
+	"SIGEXT remoto: %v: %s":                                                                                              "remote SIGEXT: %v: %s",
+	"Servidor escuchando en %s":                                                                                          "Server listening on %s",
+	"Simulación: %s no se modifica":                                                                                      "Dry run: %s is not modified",
+	"Spares que se conservan de cada racha interna (policy runs)":                                                        "Spares kept from each inner run (policy runs)",
+	"Subiendo %s a %s:%s...":                                                                                             "Uploading %s to %s:%s...",
+	"Trabajos simultáneos (nunca dos del mismo proyecto)":                                                                "Concurrent jobs (never two of the same project)",
+	"Usar este .SIG en lugar de uno sintético":                                                                           "Use this .SIG instead of a synthetic one",
+	"[CONTROL] FC 0x%02X CROB índice %d código 0x%02X":                                                                   "[CONTROL] FC 0x%02X CROB index %d code 0x%02X",
+	"[CONTROL] FC 0x%02X salida analógica índice %d valor %v":                                                            "[CONTROL] FC 0x%02X analog output index %d value %v",
+	"[ERROR] accept: %v":                                                                                                 "[ERROR] accept: %v",
+	"[FATAL] No existe la base de puntos %s (habilite app.pointdb y genere los nodos)":                                   "[FATAL] Point database %s does not exist (enable app.pointdb and generate the nodes)",
+	"[FATAL] Política desconocida %q":                                                                                    "[FATAL] Unknown policy %q",
+	"[FATAL] Tabla de reasignación: %v":                                                                                  "[FATAL] Remapping table: %v",
+	"[JOB %s] Generando nodo %s (%s)":                                                                                    "[JOB %s] Generating node %s (%s)",
+	"[JOB %s] [ALARM] %s/%s: caída brusca de puntos (%s)":                                                                "[JOB %s] [ALARM] %s/%s: sharp drop in points (%s)",
+	"[JOB %s] [DRIFT] %s/%s: %s no coincide con el SIG actual":                                                           "[JOB %s] [DRIFT] %s/%s: %s does not match the current SIG",
+	"[JOB %s] [ERROR] %v":                                                                                                "[JOB %s] [ERROR] %v",
+	"[SCHEDULE] %s (%s): próxima ejecución %s":                                                                           "[SCHEDULE] %s (%s): next run %s",
+	"[SCHEDULE] Encolado %s/%s como trabajo %s":                                                                          "[SCHEDULE] Queued %s/%s as job %s",
+	"[SCHEDULE] [ERROR] %s/%s: %v":                                                                                       "[SCHEDULE] [ERROR] %s/%s: %v",
+	"[SCHEDULE] [ERROR] %s: %v":                                                                                          "[SCHEDULE] [ERROR] %s: %v",
+	"[WARN] Informe por correo: %v":                                                                                      "[WARN] Email report: %v",
+	"[WARN] No se pudo persistir el trabajo %s: %v":                                                                      "[WARN] Could not persist job %s: %v",
+	"[WARN] Trabajo ilegible %s: %v":                                                                                     "[WARN] Unreadable job %s: %v",
+	"\n%d puntos\n":                                                                                                      "\n%d points\n",
+	"\nSeñales leídas: %d | DI: %d | DO: %d | AI: %d | AO: %d | heap en uso: %.1f MB\n":                                  "\nSignals read: %d | DI: %d | DO: %d | AI: %d | AO: %d | heap in use: %.1f MB\n",
+	"clave ssh: %v":                                      "ssh key: %v",
+	"cron %q: se esperan 5 campos":                       "cron %q: 5 fields expected",
+	"fuera de rango %q (%d-%d)":                          "out of range %q (%d-%d)",
+	"la RTU rechazó la lectura de clase 0 (IIN2 0x%02X)": "the RTU rejected the class 0 read (IIN2 0x%02X)",
+	"nodo %q no válido":                                  "invalid node %q",
+	"paso inválido en %q":                                "invalid step in %q",
+	"reserva %s %d-%d: %v":                               "reservation %s %d-%d: %v",
+	"reserva %s: los rangos %d-%d y %d-%d se solapan":    "reservation %s: ranges %d-%d and %d-%d overlap",
+	"reserva %s: rango inválido %d-%d":                   "reservation %s: invalid range %d-%d",
+	"reserva: lista desconocida %q":                      "reservation: unknown list %q",
+	"respuesta no interpretable: %v":                     "unparseable response: %v",
+	"se esperaba CONFIRM, recibido FC 0x%02X":            "expected CONFIRM, received FC 0x%02X",
+	"se requieren 'project' y 'node'":                    "'project' and 'node' are required",
+	"se requieren host, from y to":                       "host, from and to are required",
+	"sigext_remote: se requieren host y work_dir":        "sigext_remote: host and work_dir are required",
+	"sigext_remote: tipo %q no soportado (solo ssh)":     "sigext_remote: type %q not supported (ssh only)",
+	"system_points: falta name en %s %d":                 "system_points: missing name in %s %d",
+	"token no válido":                                    "invalid token",
+	"trabajo no encontrado":                              "job not found",
+	"trabajo no finalizado":                              "job not finished",
+	"valor inválido %q":                                  "invalid value %q",
+	"Índice máximo":                                      "Maximum index",
+	"Índice mínimo":                                      "Minimum index",
+	"rango bruto vacío (%g..%g)":                         "empty raw range (%g..%g)",
+	"scaling: %v":                                        "scaling: %v",
+	"scaling %s: %v":                                     "scaling %s: %v",
+	"Validando el modelo de puntos":                      "Validating the point model",
+	"sin respuesta en %s":                                "no answer within %s",
+	"modelo de puntos: %v":                               "point model: %v",
+	"Modelo de %s escrito en %s":                         "Model of %s written to %s",
+	"Uso: dnpgen.exe export-model -path \"C:\\Ruta\" -node \"NombreNodo\" [-o modelo.json|modelo.yaml] [-anonymize]": "Usage: dnpgen.exe export-model -path \"C:\\Path\" -node \"NodeName\" [-o model.json|model.yaml] [-anonymize]",
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// stringConst evalúa un literal de cadena o una concatenación de literales.
+func stringConst(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := stringConst(e.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := stringConst(e.Y)
+		return x + y, ok
+	case *ast.ParenExpr:
+		return stringConst(e.X)
+	}
+	return "", false
+}
+
+// TestCatalogCoversTrLiterals recorre el árbol sintáctico del paquete y
+// falla con cada tr/trf de un texto literal sin entrada en catalogEN.
+func TestCatalogCoversTrLiterals(t *testing.T) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing []string
+	calls := 0
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			id, ok := call.Fun.(*ast.Ident)
+			if !ok || (id.Name != "tr" && id.Name != "trf") {
+				return true
+			}
+			msg, ok := stringConst(call.Args[0])
+			if !ok {
+				return true // texto de una variable: ver TestCatalogCoversHelp
+			}
+			calls++
+			if _, ok := catalogEN[msg]; !ok {
+				missing = append(missing, fset.Position(call.Pos()).String()+": "+strconv.Quote(msg))
+			}
+			return true
+		})
+	}
+	if calls == 0 {
+		t.Fatal("no se encontró ninguna llamada a tr/trf")
+	}
+	if len(missing) > 0 {
+		t.Errorf("%d de %d textos sin traducción en catalogEN:\n  %s", len(missing), calls, strings.Join(missing, "\n  "))
+	}
+}
+
+// TestCatalogCoversHelp comprueba los textos de ayuda que se traducen al
+// mostrarse (tr de un campo, no de un literal).
+func TestCatalogCoversHelp(t *testing.T) {
+	var texts []string
+	for _, h := range commandHelps {
+		texts = append(texts, h.Summary)
+		for _, e := range h.Examples {
+			texts = append(texts, e.Desc)
+		}
+	}
+	for _, g := range globalFlagHelp {
+		texts = append(texts, g.Usage)
+	}
+	for _, g := range flagGroups {
+		texts = append(texts, g.Title)
+	}
+	for _, topic := range exampleTopics {
+		texts = append(texts, topic.Title)
+		for _, e := range topic.Examples {
+			texts = append(texts, e.Desc)
+		}
+	}
+	var missing []string
+	for _, s := range texts {
+		if _, ok := catalogEN[s]; !ok && !slices.Contains(missing, s) {
+			missing = append(missing, strconv.Quote(s))
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("%d textos de ayuda sin traducción en catalogEN:\n  %s", len(missing), strings.Join(missing, "\n  "))
+	}
+}
+
+// formatVerb reconoce los verbos de fmt (sin índices explícitos).
+var formatVerb = regexp.MustCompile(`%[-+# 0]*(\*|[0-9]+)?(\.(\*|[0-9]+))?[a-zA-Z%]`)
+
+// TestCatalogKeepsFormatVerbs comprueba que cada traducción usa los mismos
+// verbos de formato, en el mismo orden, que el texto original.
+func TestCatalogKeepsFormatVerbs(t *testing.T) {
+	keys := make([]string, 0, len(catalogEN))
+	for k := range catalogEN {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		want, got := formatVerb.FindAllString(k, -1), formatVerb.FindAllString(catalogEN[k], -1)
+		if !slices.Equal(want, got) {
+			t.Errorf("%q -> %q: verbos %v, se esperaban %v", k, catalogEN[k], got, want)
+		}
+	}
+}
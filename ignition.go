@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// --- EXPORTACIÓN DE TAGS SCADA (IGNITION) ---
+
+// IgnitionConfig configura la exportación de tags para Ignition. Las rutas
+// admiten las variables {node}, {list}, {var}, {name}, {type} e {index}.
+type IgnitionConfig struct {
+	TagPath     string `yaml:"tag_path"`      // p.ej. "{node}/{list}/{var}"
+	OPCServer   string `yaml:"opc_server"`    // servidor OPC de Ignition
+	OPCItemPath string `yaml:"opc_item_path"` // p.ej. "ns=1;s={node}.{list}.{index}"
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"ignition-json", ".ignition.json", writeIgnitionJSON})
+	RegisterExporter(exporterFunc{"ignition-csv", ".ignition.csv", writeIgnitionCSV})
+}
+
+// ignitionTag es un tag (o carpeta) del formato JSON de exportación de Ignition.
+type ignitionTag struct {
+	Name          string         `json:"name"`
+	TagType       string         `json:"tagType"`
+	ValueSource   string         `json:"valueSource,omitempty"`
+	DataType      string         `json:"dataType,omitempty"`
+	OPCServer     string         `json:"opcServer,omitempty"`
+	OPCItemPath   string         `json:"opcItemPath,omitempty"`
+	Documentation string         `json:"documentation,omitempty"`
+	ScaleMode     string         `json:"scaleMode,omitempty"`
+	RawLow        *float64       `json:"rawLow,omitempty"`
+	RawHigh       *float64       `json:"rawHigh,omitempty"`
+	ScaledLow     *float64       `json:"scaledLow,omitempty"`
+	ScaledHigh    *float64       `json:"scaledHigh,omitempty"`
+	EngUnit       string         `json:"engUnit,omitempty"`
+	Tags          []*ignitionTag `json:"tags,omitempty"`
+}
+
+// ignitionRow es un tag ya resuelto, común a ambos formatos.
+type ignitionRow struct {
+	folder, name, dataType, itemPath, doc string
+	scaling                               *Scaling
+}
+
+func ignitionRows(node string, l *Lists) []ignitionRow {
+	cfg := GlobalConfig.App.Ignition
+	tagPath := cfg.TagPath
+	if tagPath == "" {
+		tagPath = "{node}/{list}/{var}"
+	}
+	itemPath := cfg.OPCItemPath
+	if itemPath == "" {
+		itemPath = "ns=1;s={node}.{list}.{index}"
+	}
+
+	lists := []struct {
+		name, dataType string
+		items          []Point
+	}{
+		{"DI", "Boolean", l.DI},
+		{"DO", "Boolean", l.DO},
+		{"AI", "Float4", l.AI},
+		{"AO", "Float4", l.AO},
+		{"OS", "String", l.OS},
+	}
+	var rows []ignitionRow
+	for _, list := range lists {
+		for idx, p := range list.items {
+			if p.Spare {
+				continue
+			}
+			vars := map[string]string{
+				"node": node, "list": list.name, "var": p.Var, "name": p.Name,
+				"type": p.Type, "index": strconv.Itoa(idx),
+			}
+			path := strings.Trim(expandTemplate(tagPath, vars), "/")
+			folder, name := "", path
+			if i := strings.LastIndex(path, "/"); i >= 0 {
+				folder, name = path[:i], path[i+1:]
+			}
+			rows = append(rows, ignitionRow{
+				folder:   folder,
+				name:     name,
+				dataType: list.dataType,
+				itemPath: expandTemplate(itemPath, vars),
+				doc:      "DNP3 " + list.name + " " + strconv.Itoa(idx),
+				scaling:  p.Scaling,
+			})
+		}
+	}
+	return rows
+}
+
+// writeIgnitionJSON escribe el árbol de tags en el formato de importación
+// JSON de Ignition 8.
+func writeIgnitionJSON(w io.Writer, ctx ExportContext) error {
+	node, l := ctx.Node, ctx.Lists
+	cfg := GlobalConfig.App.Ignition
+	root := &ignitionTag{Name: "", TagType: "Folder"}
+	folders := map[string]*ignitionTag{"": root}
+	var folderFor func(p string) *ignitionTag
+	folderFor = func(p string) *ignitionTag {
+		if f, ok := folders[p]; ok {
+			return f
+		}
+		parent, name := "", p
+		if i := strings.LastIndex(p, "/"); i >= 0 {
+			parent, name = p[:i], p[i+1:]
+		}
+		f := &ignitionTag{Name: name, TagType: "Folder"}
+		pf := folderFor(parent)
+		pf.Tags = append(pf.Tags, f)
+		folders[p] = f
+		return f
+	}
+
+	for _, r := range ignitionRows(node, l) {
+		f := folderFor(r.folder)
+		tag := &ignitionTag{
+			Name:          r.name,
+			TagType:       "AtomicTag",
+			ValueSource:   "opc",
+			DataType:      r.dataType,
+			OPCServer:     cfg.OPCServer,
+			OPCItemPath:   r.itemPath,
+			Documentation: r.doc,
+		}
+		if s := r.scaling; s != nil {
+			tag.ScaleMode, tag.EngUnit = "Linear", s.Units
+			tag.RawLow, tag.RawHigh, tag.ScaledLow, tag.ScaledHigh = &s.RawMin, &s.RawMax, &s.EngMin, &s.EngMax
+		}
+		f.Tags = append(f.Tags, tag)
+	}
+
+	var out any
+	if len(root.Tags) == 1 && root.Tags[0].TagType == "Folder" {
+		out = root.Tags[0]
+	} else {
+		out = map[string]any{"tags": root.Tags}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeIgnitionCSV escribe los mismos tags en formato CSV plano.
+func writeIgnitionCSV(out io.Writer, ctx ExportContext) error {
+	server := GlobalConfig.App.Ignition.OPCServer
+	w := csv.NewWriter(out)
+	w.Write([]string{"Path", "Name", "TagType", "DataType", "ValueSource", "OpcServer", "OpcItemPath", "Documentation"})
+	for _, r := range ignitionRows(ctx.Node, ctx.Lists) {
+		w.Write([]string{r.folder, r.name, "AtomicTag", r.dataType, "opc", server, r.itemPath, r.doc})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// --- ESTRUCTURA DEL PROYECTO ---
+
+// LayoutConfig describe dónde están el recurso RTU, el .SIG y el .mwt de cada
+// nodo. Las plantillas admiten {project}, {node} y, en sig_file y mwt_file,
+// {resource}; las rutas relativas parten de la raíz del proyecto.
+type LayoutConfig struct {
+	ResourceDir string `yaml:"resource_dir"` // vacío = C\CWave_Micro\R\RTU_RESOURCE
+	SigFile     string `yaml:"sig_file"`     // vacío = {resource}/{node}.SIG
+	MwtFile     string `yaml:"mwt_file"`     // vacío = {project}/{node}.mwt o {resource}/{node}.mwt
+}
+
+// nodePaths son las rutas de trabajo de un nodo.
+type nodePaths struct {
+	Resource, Sig, Mwt string
+}
+
+func layoutPath(absProjectPath, tpl string, vars map[string]string) string {
+	p := expandTemplate(tpl, vars)
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(absProjectPath, p)
+	}
+	return filepath.Clean(p)
+}
+
+// resourceDirFor devuelve el directorio de recursos RTU de un nodo.
+func resourceDirFor(absProjectPath, node string) string {
+	tpl := GlobalConfig.App.Layout.ResourceDir
+	if tpl == "" {
+		tpl = RelativePathToResource
+	}
+	return layoutPath(absProjectPath, tpl, map[string]string{"project": absProjectPath, "node": node})
+}
+
+func nodePathsFor(absProjectPath, node string) nodePaths {
+	cfg := GlobalConfig.App.Layout
+	p := nodePaths{Resource: resourceDirFor(absProjectPath, node)}
+	vars := map[string]string{"project": absProjectPath, "node": node, "resource": p.Resource}
+
+	if cfg.SigFile != "" {
+		p.Sig = layoutPath(absProjectPath, cfg.SigFile, vars)
+	} else {
+		p.Sig = filepath.Join(p.Resource, node+".SIG")
+	}
+
+	if cfg.MwtFile != "" {
+		p.Mwt = layoutPath(absProjectPath, cfg.MwtFile, vars)
+	} else {
+		p.Mwt = filepath.Join(absProjectPath, node+".mwt")
+		if _, err := os.Stat(p.Mwt); os.IsNotExist(err) {
+			p.Mwt = filepath.Join(p.Resource, node+".mwt")
+		}
+	}
+	return p
+}
+
+// discoverNodes lista los nodos de un proyecto a partir de los .SIG que
+// cumplen la plantilla de la estructura configurada.
+func discoverNodes(projectPath string) ([]string, error) {
+	abs, err := filepath.Abs(normalizeLongPath(projectPath))
+	if err != nil {
+		return nil, err
+	}
+	return matchNodeFiles(func(node string) string { return nodePathsFor(abs, node).Sig })
+}
+
+// matchNodeFiles busca los nodos cuyo fichero, según pathFor, existe.
+func matchNodeFiles(pathFor func(node string) string) ([]string, error) {
+	var err error
+	const marker = "\x00"
+	tpl := pathFor(marker)
+	dir, base := filepath.Dir(tpl), filepath.Base(tpl)
+
+	// El nombre del fichero se compara sin distinguir mayúsculas, como hace
+	// Windows (NODO.SIG / nodo.sig).
+	parts := strings.Split(base, marker)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	nameRe := regexp.MustCompile("(?i)^" + strings.Join(parts, "(.+)") + "$")
+
+	var dirs []string
+	if strings.Contains(dir, marker) {
+		// El nodo forma parte del directorio: se recorren los candidatos.
+		if dirs, err = filepath.Glob(strings.ReplaceAll(globEscape(dir), marker, "*")); err != nil {
+			return nil, err
+		}
+	} else {
+		dirs = []string{dir}
+	}
+
+	seen := map[string]bool{}
+	var nodes []string
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			if len(dirs) == 1 {
+				return nil, err
+			}
+			continue
+		}
+		for _, e := range entries {
+			m := nameRe.FindStringSubmatch(e.Name())
+			if e.IsDir() || m == nil {
+				continue
+			}
+			node := m[1]
+			// Si el nodo aparece también en el directorio, ambos deben coincidir.
+			if !strings.EqualFold(pathFor(node), filepath.Join(d, e.Name())) {
+				continue
+			}
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// globEscape protege los metacaracteres de filepath.Glob en una ruta literal.
+func globEscape(p string) string {
+	meta := `*?[`
+	if runtime.GOOS != "windows" {
+		meta += `\`
+	}
+	var b strings.Builder
+	for _, r := range p {
+		if strings.ContainsRune(meta, r) {
+			if runtime.GOOS == "windows" {
+				b.WriteString("[" + string(r) + "]")
+				continue
+			}
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- CICLO DE VIDA DE LOS PUNTOS ---
+//
+// Cuando una variable desaparece del .SIG, los puntos posteriores de su lista
+// suben un índice y el SCADA lee señales cambiadas. Con app.lifecycle cada
+// generación guarda el mapa de índices del nodo (<salida>/.dnpgen/
+// <nodo>.lifecycle.json) con el estado de cada entrada: active (punto del
+// .SIG), retired (variable eliminada cuyo índice se mantiene reservado) o
+// reserved (reservas y puntos de sistema). Un punto retirado conserva su
+// índice como spare durante grace_days; pasado el plazo el índice se libera
+// en la siguiente generación. El resumen muestra las retiradas pendientes.
+
+// LifecycleConfig controla el mapa de índices persistente.
+type LifecycleConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	GraceDays int  `yaml:"grace_days"` // días que un punto retirado conserva su índice; 30 por defecto
+	// Name es el nombre de la entrada que ocupa el índice retirado; admite
+	// {var}, {list}, {index}, {node} y {spare}. Vacío = "{spare}".
+	Name string `yaml:"name"`
+}
+
+func (c LifecycleConfig) grace() time.Duration {
+	days := c.GraceDays
+	if days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Estados del ciclo de vida de una entrada.
+const (
+	LifecycleActive   = "active"
+	LifecycleRetired  = "retired"
+	LifecycleReserved = "reserved"
+)
+
+// LifecyclePoint es una entrada del mapa de índices.
+type LifecyclePoint struct {
+	Var   string    `json:"var"`
+	List  string    `json:"list"`
+	Index int       `json:"index"`
+	State string    `json:"state"`
+	Spare bool      `json:"spare,omitempty"` // espejo spare de la variable en la lista opuesta
+	Since time.Time `json:"since"`           // desde cuándo está en este estado
+	// Until es el fin del periodo de gracia de un punto retirado.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+func (p LifecyclePoint) String() string {
+	return trf("%s[%d] %s (retirado el %s, índice reservado hasta el %s)",
+		p.List, p.Index, p.Var, p.Since.Local().Format("2006-01-02"), p.Until.Local().Format("2006-01-02"))
+}
+
+// lifecycleMap es el mapa de índices guardado tras cada generación.
+type lifecycleMap struct {
+	Generator string    `json:"generator"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// RulesVersion es app.rules_version al escribir el mapa.
+	RulesVersion string           `json:"rules_version,omitempty"`
+	Points       []LifecyclePoint `json:"points"`
+}
+
+func lifecyclePath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".lifecycle.json")
+}
+
+// readLifecycle lee el mapa anterior del nodo; vacío si es la primera vez.
+func readLifecycle(outDir, node string) (*lifecycleMap, error) {
+	path := lifecyclePath(outDir, node)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &lifecycleMap{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m lifecycleMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &m, nil
+}
+
+func writeLifecycle(outDir, node string, m *lifecycleMap) error {
+	path := lifecyclePath(outDir, node)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// lifecycleKey identifica una entrada: la misma variable aparece en su lista
+// y, como spare espejo, en la opuesta.
+func lifecycleKey(list, v string, spare bool) string {
+	return fmt.Sprintf("%s\x00%s\x00%t", list, v, spare)
+}
+
+// trackedPoint indica si una entrada de las listas es un punto del .SIG (o
+// su espejo) cuyo índice sigue el mapa.
+func trackedPoint(p Point) bool {
+	return p.Var != "" && !p.Reserved && !p.System
+}
+
+// reservableList indica si la lista admite reservas de índice (las cuatro
+// listas DNP3 básicas).
+func reservableList(list string) bool {
+	switch list {
+	case "AI", "AO", "DI", "DO":
+		return true
+	}
+	return false
+}
+
+// lifecycleHolds son los puntos retirados que conservan su índice en esta
+// generación, por lista e índice.
+type lifecycleHolds map[string]map[int]LifecyclePoint
+
+// retiredReservations compara el mapa anterior con las listas recién
+// clasificadas (antes de aplicar las reservas) y devuelve una reserva de un
+// índice por cada punto retirado dentro de su periodo de gracia. Los que
+// caen en una reserva configurada o ya vencieron se descartan.
+func retiredReservations(prev *lifecycleMap, l *Lists, configured []IndexReservation, now time.Time) ([]IndexReservation, lifecycleHolds) {
+	cfg := GlobalConfig.App.Lifecycle
+	present := map[string]bool{}
+	for _, sec := range listSections(l) {
+		for _, p := range sec.Items {
+			if trackedPoint(p) {
+				present[lifecycleKey(sec.Name, p.Var, p.Spare)] = true
+			}
+		}
+	}
+	taken := func(list string, idx int) bool {
+		for _, r := range configured {
+			if strings.EqualFold(r.List, list) && idx >= r.From && idx <= r.To {
+				return true
+			}
+		}
+		return false
+	}
+
+	holds := lifecycleHolds{}
+	var out []IndexReservation
+	for _, p := range prev.Points {
+		if p.State == LifecycleReserved || !reservableList(p.List) || present[lifecycleKey(p.List, p.Var, p.Spare)] {
+			continue
+		}
+		if p.State == LifecycleActive {
+			until := now.Add(cfg.grace())
+			p.State, p.Since, p.Until = LifecycleRetired, now, &until
+		}
+		if p.Until == nil || !now.Before(*p.Until) {
+			if !p.Spare {
+				log.Printf(tr("Ciclo de vida: se libera el índice %s[%d] de %s (periodo de gracia vencido)"), p.List, p.Index, p.Var)
+			}
+			continue
+		}
+		if taken(p.List, p.Index) || holds[p.List][p.Index].Var != "" {
+			continue
+		}
+		if holds[p.List] == nil {
+			holds[p.List] = map[int]LifecyclePoint{}
+		}
+		holds[p.List][p.Index] = p
+		tpl := cfg.Name
+		if tpl == "" {
+			tpl = "{spare}"
+		}
+		out = append(out, IndexReservation{List: p.List, From: p.Index, To: p.Index, Name: expandTemplate(tpl, map[string]string{"var": p.Var})})
+	}
+	return out, holds
+}
+
+// lifecycleAfter construye el mapa nuevo a partir de las listas definitivas.
+// Los puntos activos conservan su fecha si ya lo estaban.
+func lifecycleAfter(prev *lifecycleMap, l *Lists, holds lifecycleHolds, now time.Time) *lifecycleMap {
+	since := map[string]time.Time{}
+	for _, p := range prev.Points {
+		if p.State == LifecycleActive {
+			since[lifecycleKey(p.List, p.Var, p.Spare)] = p.Since
+		}
+	}
+	m := &lifecycleMap{Generator: generatorLine(), UpdatedAt: now}
+	for _, sec := range listSections(l) {
+		for idx, p := range sec.Items {
+			e := LifecyclePoint{Var: p.Var, List: sec.Name, Index: idx, Since: now}
+			switch held, ok := holds[sec.Name][idx]; {
+			case ok:
+				e = held
+			case trackedPoint(p):
+				e.State, e.Spare = LifecycleActive, p.Spare
+				if t, ok := since[lifecycleKey(sec.Name, p.Var, p.Spare)]; ok {
+					e.Since = t
+				}
+			case p.Reserved || p.System:
+				e.State = LifecycleReserved
+			default:
+				continue // spares de relleno
+			}
+			m.Points = append(m.Points, e)
+		}
+	}
+	return m
+}
+
+// pendingRetirements son los puntos retirados de un mapa, por fecha de
+// vencimiento.
+func pendingRetirements(m *lifecycleMap) []LifecyclePoint {
+	var out []LifecyclePoint
+	for _, p := range m.Points {
+		if p.State == LifecycleRetired && !p.Spare {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Until.Before(*out[j].Until) })
+	return out
+}
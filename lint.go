@@ -0,0 +1,283 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- ANÁLISIS DE REGLAS (rules lint) ---
+
+// lintRule es un patrón de un conjunto de reglas con sus coincidencias.
+type lintRule struct {
+	pattern, outcome string
+	re               *regexp.Regexp
+	err              error
+	matches          int
+}
+
+// lintSet es un conjunto de reglas de la configuración. applies filtra las
+// señales a las que se evalúa y fallback describe lo que ocurre a las que no
+// cumplen ninguna regla ("" = no se informa).
+type lintSet struct {
+	name      string
+	rules     []*lintRule
+	applies   func(Signal) bool
+	fallback  string
+	evaluated int
+	fell      []string
+	conflicts []lintConflict
+}
+
+// lintConflict es una variable que cumple reglas con resultados distintos;
+// gana la primera.
+type lintConflict struct {
+	Var   string
+	Rules []*lintRule
+}
+
+func (s *lintSet) add(pattern, outcome string) {
+	re, err := regexp.Compile(pattern)
+	s.rules = append(s.rules, &lintRule{pattern: pattern, outcome: outcome, re: re, err: err})
+}
+
+func (s *lintSet) eval(sig Signal) {
+	if s.applies != nil && !s.applies(sig) {
+		return
+	}
+	s.evaluated++
+	var matched []*lintRule
+	outcomes := map[string]bool{}
+	for _, r := range s.rules {
+		if r.re != nil && r.re.MatchString(sig.Var) {
+			r.matches++
+			matched = append(matched, r)
+			outcomes[r.outcome] = true
+		}
+	}
+	if len(matched) == 0 {
+		s.fell = append(s.fell, sig.Var)
+	}
+	if len(outcomes) > 1 {
+		s.conflicts = append(s.conflicts, lintConflict{Var: sig.Var, Rules: matched})
+	}
+}
+
+// Los filtros siguen a processSigFile: los tipos AO y DO van siempre a su
+// lista de salida, sin pasar por los patrones.
+func analogByName(s Signal) bool {
+	return isAnalogType(s.Type) && subtypeOutputRegex(s.Type) == nil
+}
+
+// subtypeOutputRegex son los output_regex propios del tipo (analog_subtypes).
+func subtypeOutputRegex(t string) []string {
+	st, _ := analogSubtypeFor(t)
+	return st.OutputRegex
+}
+
+func digitalByName(s Signal) bool {
+	return !isAnalogType(s.Type) && (strings.Contains(s.Type, "LA") || strings.Contains(s.Type, "BOOL"))
+}
+
+func isAnalogSignal(s Signal) bool  { return isAnalogType(s.Type) || s.Type == "AO" }
+func isDigitalSignal(s Signal) bool { return digitalByName(s) || s.Type == "DO" }
+func isClassified(s Signal) bool    { return isAnalogSignal(s) || isDigitalSignal(s) }
+
+// lintSets construye los conjuntos de reglas de la configuración cargada.
+func lintSets() []*lintSet {
+	app := GlobalConfig.App
+	c := app.Classification
+	var sets []*lintSet
+
+	analog := &lintSet{name: "classification.analog_output_regex", applies: analogByName, fallback: tr("entrada analógica (AI)")}
+	for _, p := range c.AnalogRegex {
+		analog.add(p, "AO")
+	}
+	sets = append(sets, analog)
+	names := make([]string, 0, len(c.AnalogSubtypes))
+	for name := range c.AnalogSubtypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		patterns := c.AnalogSubtypes[name].OutputRegex
+		if len(patterns) == 0 {
+			continue
+		}
+		set := &lintSet{name: "classification.analog_subtypes." + name + ".output_regex", fallback: tr("entrada analógica (AI)"),
+			applies: func(s Signal) bool { return strings.EqualFold(s.Type, name) }}
+		for _, p := range patterns {
+			set.add(p, "AO")
+		}
+		sets = append(sets, set)
+	}
+	digital := &lintSet{name: "classification.digital_output_regex", applies: digitalByName, fallback: tr("entrada digital (DI)")}
+	for _, p := range c.DigitalRegex {
+		digital.add(p, "DO")
+	}
+	def := c.Mirror
+	if def == "" {
+		def = MirrorSpare
+	}
+	mirror := &lintSet{name: "classification.mirror_rules", applies: isClassified, fallback: trf("estrategia por defecto (%s)", def)}
+	for _, r := range c.MirrorRules {
+		mirror.add(r.Pattern, string(r.Mirror))
+	}
+	sets = append(sets, digital, mirror)
+
+	extra := &lintSet{name: "classification.extra_lists"}
+	for _, e := range c.ExtraLists {
+		for _, p := range e.Patterns {
+			extra.add(p, strings.ToUpper(e.Name))
+		}
+	}
+	soe := &lintSet{name: "classification.soe", applies: isDigitalSignal}
+	for _, p := range c.SOE.Patterns {
+		soe.add(p, "SOE")
+	}
+	octets := &lintSet{name: "classification.strings"}
+	for _, p := range c.Strings.Patterns {
+		octets.add(p, "OS")
+	}
+	scaling := &lintSet{name: "scaling.rules", applies: isAnalogSignal}
+	if app.Scaling.FromVarDef {
+		scaling.fallback = tr("__vardef.ini o sin escalado")
+	}
+	for i, r := range app.Scaling.Rules {
+		scaling.add(r.Pattern, fmt.Sprintf("#%d %g..%g %s", i+1, r.EngMin, r.EngMax, r.Units))
+	}
+	areas := &lintSet{name: "areas.rules"}
+	if app.Areas.enabled() {
+		areas.fallback = tr("área por prefijo o por defecto")
+	}
+	for _, r := range app.Areas.Rules {
+		for _, p := range r.Patterns {
+			areas.add(p, r.Name)
+		}
+	}
+	sets = append(sets, extra, soe, octets, scaling, areas)
+
+	// Un conjunto sin reglas pero con fallback se conserva: p.ej. con
+	// digital_output_regex vacío todas las salidas por nombre acaban en DI, y
+	// eso hay que verlo.
+	var out []*lintSet
+	for _, s := range sets {
+		if len(s.rules) > 0 || s.fallback != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// maxLintExamples limita las variables que se listan por apartado.
+const maxLintExamples = 10
+
+func runRules(args []string) {
+	if len(args) > 0 && args[0] == "names" {
+		runRulesNames(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "impact" {
+		runRulesImpact(args[1:])
+		return
+	}
+	if len(args) == 0 || args[0] != "lint" {
+		log.Fatal(tr("Uso: dnpgen.exe rules lint -path \"C:\\Ruta\" -node \"NombreNodo\" | -sig fichero.SIG"))
+	}
+	fs := flag.NewFlagSet("rules lint", flag.ExitOnError)
+	fs.Usage = manUsage("rules lint", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo"))
+	sigPath := fs.String("sig", "", tr("Ruta directa a un .SIG (alternativa a -path/-node)"))
+	strict := fs.Bool("strict", false, tr("Salir con error si hay reglas muertas o conflictos"))
+	fs.Parse(args[1:])
+
+	file := *sigPath
+	if file == "" {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe rules lint -path \"C:\\Ruta\" -node \"NombreNodo\" | -sig fichero.SIG"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		file = nodePathsFor(abs, *nodeName).Sig
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer f.Close()
+
+	sets := lintSets()
+	signals := 0
+	if _, _, err := ParseSIG(f, func(sig Signal) {
+		signals++
+		for _, s := range sets {
+			s.eval(sig)
+		}
+	}); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	fmt.Println(boldText(trf("\n--- ANÁLISIS DE REGLAS: %s (%d señales) ---", filepath.Base(file), signals)))
+	rules := 0
+	for _, s := range sets {
+		rules += len(s.rules)
+	}
+	if rules == 0 {
+		fmt.Println(tr("No hay reglas configuradas"))
+	}
+	problems := 0
+	for _, s := range sets {
+		fmt.Println(boldText("\n"+s.name) + trf(" (%d reglas, %d señales evaluadas)", len(s.rules), s.evaluated))
+		for _, r := range s.rules {
+			switch {
+			case r.err != nil:
+				fmt.Println(errText(trf("  patrón inválido %q: %v", r.pattern, r.err)))
+				problems++
+			case r.matches == 0:
+				fmt.Println(warnText(trf("  sin coincidencias: %q -> %s", r.pattern, r.outcome)))
+				problems++
+			default:
+				fmt.Printf("  %6d  %q -> %s\n", r.matches, r.pattern, r.outcome)
+			}
+		}
+		if len(s.conflicts) > 0 {
+			problems += len(s.conflicts)
+			fmt.Println(warnText(trf("  %d variable(s) con reglas en conflicto (gana la primera):", len(s.conflicts))))
+			for i, c := range s.conflicts {
+				if i == maxLintExamples {
+					fmt.Println(trf("    ... y %d más", len(s.conflicts)-i))
+					break
+				}
+				var parts []string
+				for _, r := range c.Rules {
+					parts = append(parts, fmt.Sprintf("%q -> %s", r.pattern, r.outcome))
+				}
+				fmt.Printf("    %s: %s\n", c.Var, strings.Join(parts, ", "))
+			}
+		}
+		if s.fallback != "" && len(s.fell) > 0 {
+			sort.Strings(s.fell)
+			examples := s.fell[:min(len(s.fell), maxLintExamples)]
+			fmt.Println(trf("  %d sin regla -> %s: %s", len(s.fell), s.fallback, strings.Join(examples, ", ")))
+			if len(s.fell) > len(examples) {
+				fmt.Println(trf("    ... y %d más", len(s.fell)-len(examples)))
+			}
+		}
+	}
+	if problems > 0 {
+		fmt.Println(warnText(trf("\n%d aviso(s) en las reglas", problems)))
+		if *strict {
+			os.Exit(1)
+		}
+	} else if rules > 0 {
+		fmt.Println(okText(tr("\nTodas las reglas coinciden y no hay conflictos")))
+	}
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,19 +23,102 @@ import (
 // --- CONFIGURACIÓN YAML ---
 type Config struct {
 	App struct {
-		SigExtPath     string `yaml:"sigext_path"`
-		SigExtFlags    string `yaml:"sigext_flags"`
+		SigExtPath     string          `yaml:"sigext_path"`
+		SigExtFlags    string          `yaml:"sigext_flags"`
+		SigExtArgs     string          `yaml:"sigext_args"`
+		SigExtRetry    SigExtRetry     `yaml:"sigext_retry"`
+		SigExtRemote   SigExtRemote    `yaml:"sigext_remote"`
+		SigExtWrapper  SigExtWrapper   `yaml:"sigext_wrapper"`
+		SigExtPaths    SigExtPaths     `yaml:"sigext_paths"`
+		SigSource      SigSourceConfig `yaml:"sig_source"`
+		Layout         LayoutConfig    `yaml:"layout"`
+		StaleSig       string          `yaml:"stale_sig"`
 		Classification struct {
 			// Cambiamos el nombre en el struct para reflejar que son REGEX
 			AnalogRegex  []string `yaml:"analog_output_regex"`
 			DigitalRegex []string `yaml:"digital_output_regex"`
+
+			// Mirror es la estrategia de espejo por defecto y MirrorRules la
+			// cambia para las variables que cumplen un patrón.
+			Mirror      MirrorStrategy `yaml:"mirror"`
+			MirrorRules []MirrorRule   `yaml:"mirror_rules"`
+
+			// ExtraLists son secciones *LIST adicionales con sus reglas.
+			ExtraLists []ExtraList `yaml:"extra_lists"`
+
+			// SOE marca entradas digitales como puntos de secuencia de eventos.
+			SOE SOEConfig `yaml:"soe"`
+
+			// AOStatus genera el estado de cada salida analógica.
+			AOStatus AOSConfig `yaml:"ao_status"`
+
+			// Strings lleva las cadenas de octetos a su propia lista.
+			Strings StringConfig `yaml:"strings"`
+
+			// AnalogSubtypes da a un tipo analógico del .SIG (p.ej. AAR) sus
+			// propias reglas, clase de evento y lista.
+			AnalogSubtypes map[string]AnalogSubtype `yaml:"analog_subtypes"`
+
+			// ControlPairs funde las parejas trip/close de DO en un punto.
+			ControlPairs []ControlPairRule `yaml:"control_pairs"`
 		} `yaml:"classification"`
-		Spares struct {
-			DO string `yaml:"do"`
-			DI string `yaml:"di"`
-			AO string `yaml:"ao"`
-			AI string `yaml:"ai"`
-		} `yaml:"spares"`
+		Spares        SparesConfig        `yaml:"spares"`
+		Scaling       ScalingConfig       `yaml:"scaling"`
+		Areas         AreasConfig         `yaml:"areas"`
+		Alarms        AlarmsConfig        `yaml:"alarms"`
+		PointBook     PointBookConfig     `yaml:"point_book"`
+		Freeze        FreezeConfig        `yaml:"freeze"`
+		DNP3Objects   DNP3ObjectsConfig   `yaml:"dnp3_objects"`
+		Incremental   IncrementalConfig   `yaml:"incremental"`
+		CountAlarm    CountAlarmConfig    `yaml:"count_alarm"`
+		SpareForecast SpareForecastConfig `yaml:"spare_forecast"`
+		Lifecycle     LifecycleConfig     `yaml:"lifecycle"`
+		Merge         MergeConfig         `yaml:"merge"`
+		Redundancy    RedundancyConfig    `yaml:"redundancy"`
+		SigCheck      SigCheckConfig      `yaml:"sig_check"`
+		Output        OutputConfig        `yaml:"output"`
+		Server        ServerConfig        `yaml:"server"`
+		Schedule      []ScheduleEntry     `yaml:"schedule"`
+		Notifications NotifyConfig        `yaml:"notifications"`
+		Publish       PublishConfig       `yaml:"publish"`
+		Email         EmailConfig         `yaml:"email"`
+		Verify        VerifyConfig        `yaml:"verify"`
+		Reserved      []IndexReservation  `yaml:"reserved"`
+		SystemPoints  []SystemPoint       `yaml:"system_points"`
+		DerivedPoints []DerivedPoint      `yaml:"derived_points"`
+		CommHealth    CommHealthConfig    `yaml:"comm_health"`
+		NameRules     NameRules           `yaml:"names"`
+		Naming        NamingConfig        `yaml:"naming"`
+		Findings      FindingsConfig      `yaml:"findings"`
+		Firmware      string              `yaml:"firmware"`
+		RulesVersion  string              `yaml:"rules_version"`
+		Validators    []ValidatorCommand  `yaml:"validators"`
+		SharedPoints  []string            `yaml:"shared_points"`
+		Locale        string              `yaml:"locale"`
+		Timezone      string              `yaml:"timezone"`
+		ListTitles    map[string]string   `yaml:"list_titles"`
+		Diagnostics   DiagnosticsConfig   `yaml:"diagnostics"`
+		Update        UpdateConfig        `yaml:"update"`
+		Metrics       MetricsConfig       `yaml:"metrics"`
+		Stats         UsageStatsConfig    `yaml:"stats"`
+		Checkpoints   CheckpointConfig    `yaml:"checkpoints"`
+		PostBuild     PostBuildConfig     `yaml:"post_build"`
+		Signing       SigningConfig       `yaml:"signing"`
+		Exports       []string            `yaml:"exports"`
+		ExportWorkers int                 `yaml:"export_workers"`
+		Protocols     ProtocolsConfig     `yaml:"protocols"`
+		SCL           SCLConfig           `yaml:"scl"`
+		OPCUA         OPCUAConfig         `yaml:"opcua"`
+		Ignition      IgnitionConfig      `yaml:"ignition"`
+		PointDB       PointDBConfig       `yaml:"pointdb"`
+		Perf          PerfConfig          `yaml:"perf"`
+		Anonymize     AnonymizeConfig     `yaml:"anonymize"`
+		Sorting       map[string]ListSort `yaml:"sorting"`
+		DisabledLists DisabledListsConfig `yaml:"disabled_lists"`
+		Database      DatabaseConfig      `yaml:"database"`
+
+		// FirmwarePresets añade presets de firmware o sustituye los incluidos.
+		FirmwarePresets map[string]FirmwarePreset `yaml:"firmware_presets"`
 	} `yaml:"app"`
 }
 
@@ -41,224 +129,1614 @@ const (
 	ListFile               = "__lists.ini"
 )
 
-var (
-	GlobalConfig                   Config
-	ListAO, ListAI, ListDO, ListDI []string
+// Códigos *LIST del controlador para cada lista DNP3.
+const (
+	ListCodeAI = "32761"
+	ListCodeAO = "32762"
+	ListCodeDI = "32763"
+	ListCodeDO = "32764"
 )
 
+var GlobalConfig Config
+
+// Point es una entrada de una lista DNP3. Los spares conservan la variable
+// que los originó, igual que en el texto "SPARE(VAR)" que se escribe.
+type Point struct {
+	Name  string `json:"name"`           // línea tal como se escribe en __lists.ini
+	Var   string `json:"var"`            // variable de origen, sin "@GV."
+	Type  string `json:"type,omitempty"` // tipo SIG (AA, LA, REAL...)
+	Desc  string `json:"desc,omitempty"` // descripción del .SIG, si la trae
+	Spare bool   `json:"spare,omitempty"`
+
+	// Reserved marca los marcadores de un rango reservado (app.reserved);
+	// también son Spare.
+	Reserved bool `json:"reserved,omitempty"`
+
+	// SOE marca las entradas digitales de secuencia de eventos
+	// (classification.soe).
+	SOE bool `json:"soe,omitempty"`
+
+	// System marca los puntos fijos de app.system_points.
+	System bool `json:"system,omitempty"`
+
+	// AOS marca el estado de una salida analógica (classification.ao_status).
+	AOS bool `json:"aos,omitempty"`
+
+	// Pair son las variables trip y close fundidas en este punto de DO
+	// (classification.control_pairs).
+	Pair *ControlPair `json:"pair,omitempty"`
+
+	// Scaling es el escalado bruto↔ingeniería de las analógicas (app.scaling).
+	Scaling *Scaling `json:"scaling,omitempty"`
+
+	// Area es el área o subsistema del punto (app.areas).
+	Area string `json:"area,omitempty"`
+
+	// Alarm es la prioridad de alarma del punto (app.alarms).
+	Alarm string `json:"alarm,omitempty"`
+
+	// Object es el grupo y la variación DNP3 del punto (app.dnp3_objects).
+	Object *DNP3Object `json:"object,omitempty"`
+
+	// Rule es la regla que llevó el punto a su lista, p.ej.
+	// "analog_output_regex[2]" o "type:REAL" (ver export-model).
+	Rule string `json:"-"`
+
+	// line es la línea del .SIG de la que procede el punto (también sus
+	// espejos y spares); 0 si no viene del .SIG.
+	line int
+}
+
+// Lists agrupa las cuatro listas DNP3 generadas para un nodo.
+type Lists struct {
+	AI, AO, DI, DO []Point
+
+	// OS son las cadenas de octetos (classification.strings).
+	OS []Point
+
+	// Extra son las listas de classification.extra_lists, en su orden.
+	Extra []CustomList
+
+	// disabled son las listas sin sección *LIST (app.disabled_lists).
+	disabled map[string]bool
+}
+
+// pointFromLine reconstruye un punto a partir de una línea de __lists.ini.
+func pointFromLine(line string) Point {
+	if i := strings.IndexByte(line, '('); i > 0 && strings.HasSuffix(line, ")") {
+		return Point{Name: line, Var: line[i+1 : len(line)-1], Spare: true}
+	}
+	return Point{Name: line, Var: strings.TrimPrefix(line, "@GV.")}
+}
+
+// GenerateRequest describe una ejecución del generador sobre un nodo.
+type GenerateRequest struct {
+	ProjectPath string `json:"project"`
+	NodeName    string `json:"node"`
+	SkipExt     bool   `json:"skip_ext"`
+
+	// CheckOnly genera las listas en memoria y las compara con el __lists.ini
+	// existente sin sobrescribirlo (detección de deriva).
+	CheckOnly bool `json:"check_only"`
+
+	// Incremental compara el modelo con la instantánea de la generación
+	// anterior e informa de los cambios (también con app.incremental).
+	Incremental bool `json:"incremental,omitempty"`
+
+	// OutDir, si no está vacío, sustituye al directorio de salida configurado
+	// (app.output.dir) para esta ejecución.
+	OutDir string `json:"out_dir,omitempty"`
+
+	// Only limita la generación a estas listas (AI, DI, ...); las demás se
+	// conservan tal cual del fichero existente.
+	Only []string `json:"only,omitempty"`
+
+	// Progress, si no es nil, recibe cada etapa del pipeline a medida que
+	// comienza. Lo usa el modo servidor para informar el avance del trabajo.
+	Progress func(stage, message string) `json:"-"`
+
+	// Events, si no es nil, recibe los eventos de la generación (etapas,
+	// avisos y avance de la lectura del .SIG cada ProgressEvery señales, 1000
+	// por defecto). Ver GenerateEvent.
+	Events        func(GenerateEvent) `json:"-"`
+	ProgressEvery int                 `json:"progress_every,omitempty"`
+
+	// lifecycle, si no es nil, sustituye al mapa de índices guardado (lo usa
+	// rename para comprobar el resultado antes de escribir el mapa).
+	lifecycle *lifecycleMap
+
+	// resolve, si no es nil, decide los conflictos de app.merge (modo
+	// consola).
+	resolve mergeResolver
+
+	// pair, si no es nil, genera el nodo como miembro de una pareja
+	// redundante (dnpgen pair).
+	pair *pairMember
+
+	// migrate acepta un cambio de app.rules_version o de las reglas (dnpgen
+	// migrate): no se avisa y se actualiza el registro de reglas del nodo.
+	migrate bool
+}
+
+func (r GenerateRequest) progress(stage, message string) {
+	if r.Progress != nil {
+		r.Progress(stage, message)
+	}
+}
+
+// GenerateResult resume lo producido por una ejecución.
+type GenerateResult struct {
+	SigFile  string `json:"sig_file"`
+	ListFile string `json:"list_file"`
+	DI       int    `json:"di"`
+	DO       int    `json:"do"`
+	AI       int    `json:"ai"`
+	AO       int    `json:"ao"`
+	OS       int    `json:"os,omitempty"`
+
+	// SigSource es el origen del .SIG (app.sig_source): sigext, local o el
+	// nombre de un backend.
+	SigSource string `json:"sig_source"`
+	// SigExtAttempt es el intento en que SIGEXT terminó bien; 0 si no se
+	// ejecutó o falló y se usó el .SIG existente.
+	SigExtAttempt int `json:"sigext_attempt"`
+
+	// StaleSig indica que se usó un .SIG anterior al .mwt.
+	StaleSig bool `json:"stale_sig,omitempty"`
+
+	// Drift indica que el __lists.ini existente no coincidía con lo generado
+	// a partir del SIG actual.
+	Drift bool `json:"drift"`
+
+	// SigDiagnostics son las líneas del .SIG que no se pudieron interpretar.
+	SigDiagnostics []SigDiagnostic `json:"sig_diagnostics,omitempty"`
+
+	// SigVersion es la versión del formato .SIG detectada.
+	SigVersion string `json:"sig_version"`
+	// SigFormat es el BOM y los finales de línea encontrados en el .SIG,
+	// solo si hubo que normalizarlos.
+	SigFormat *SigFormat `json:"sig_format,omitempty"`
+
+	// NameIssues son los nombres que no cumplen app.names (corregidos si la
+	// acción es sanitize).
+	NameIssues []NameIssue `json:"name_issues,omitempty"`
+
+	// Findings son los hallazgos de todas las validaciones con su severidad
+	// según app.findings.
+	Findings []Finding `json:"findings,omitempty"`
+
+	// Timings es la duración de cada etapa del pipeline, en orden.
+	Timings []StageTiming `json:"timings,omitempty"`
+
+	// Delta son los cambios respecto a la generación anterior (modo
+	// incremental); nil si no se pidió.
+	Delta *SigDelta `json:"delta,omitempty"`
+
+	// CountAlarms son las listas cuyo número de puntos reales cayó
+	// bruscamente respecto a la generación anterior (app.count_alarm).
+	CountAlarms []CountAlarm `json:"count_alarms,omitempty"`
+
+	// SpareForecast es el consumo de spares y la previsión de agotamiento de
+	// cada lista (app.spare_forecast).
+	SpareForecast []SpareForecast `json:"spare_forecast,omitempty"`
+
+	// Retirements son los puntos retirados que conservan su índice hasta que
+	// venza su periodo de gracia (app.lifecycle).
+	Retirements []LifecyclePoint `json:"retirements,omitempty"`
+
+	// RulesChange es el cambio de reglas respecto a las que generaron el
+	// estado del nodo (app.rules_version); nil si son las mismas.
+	RulesChange *RulesChange `json:"rules_change,omitempty"`
+
+	// Frozen es el número de entradas que fija el fichero de congelación
+	// (app.freeze); 0 si el nodo no lo tiene.
+	Frozen int `json:"frozen,omitempty"`
+
+	// Merge son las ediciones a mano fusionadas con la generación
+	// (app.merge); nil si no las había.
+	Merge *MergeReport `json:"merge,omitempty"`
+
+	// Artifacts enumera los ficheros de exportación escritos además de ListFile.
+	Artifacts []string `json:"artifacts,omitempty"`
+
+	// Published son los destinos de app.publish a los que se copiaron.
+	Published []string `json:"published,omitempty"`
+
+	content []byte
+	lists   *Lists
+}
+
 func main() {
-	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-	fmt.Println("--- Generador DNP3 CLI v3.2 (Regex Logic) ---")
+	log.SetFlags(log.Lshortfile)
+	setLogOutput(os.Stderr)
+	os.Args = append(os.Args[:1], extractGlobalFlags(os.Args[1:])...)
+	initLocale()
+	initColor()
+	// post-build no escribe nada en la consola (ver runPostBuild).
+	if len(os.Args) < 2 || os.Args[1] != "post-build" {
+		fmt.Fprintln(os.Stderr, trf("--- Generador DNP3 CLI v%s (Regex Logic) ---", version))
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate":
+			// Alias explícito de la generación normal (dnpgen generate -workspace ...).
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		case "server":
+			loadConfiguration()
+			runServer(os.Args[2:])
+			return
+		case "service":
+			runServiceCommand(os.Args[2:])
+			return
+		case "simulate":
+			runSimulate(os.Args[2:])
+			return
+		case "verify":
+			loadConfiguration()
+			runVerify(os.Args[2:])
+			return
+		case "query":
+			loadConfiguration()
+			runQuery(os.Args[2:])
+			return
+		case "nodes":
+			loadConfiguration()
+			runNodes(os.Args[2:])
+			return
+		case "gen-fixture":
+			runGenFixture(os.Args[2:])
+			return
+		case "bench":
+			loadConfiguration()
+			runBench(os.Args[2:])
+			return
+		case "rules":
+			loadConfiguration()
+			runRules(os.Args[2:])
+			return
+		case "export-model":
+			loadConfiguration()
+			runExportModel(os.Args[2:])
+			return
+		case "render":
+			loadConfiguration()
+			runRender(os.Args[2:])
+			return
+		case "scaffold":
+			runScaffold(os.Args[2:])
+			return
+		case "rename":
+			loadConfiguration()
+			runRename(os.Args[2:])
+			return
+		case "compare-nodes":
+			loadConfiguration()
+			runCompareNodes(os.Args[2:])
+			return
+		case "check-roundtrip":
+			loadConfiguration()
+			runCheckRoundTrip(os.Args[2:])
+			return
+		case "analyze":
+			loadConfiguration()
+			runAnalyze(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:])
+			return
+		case "spares":
+			loadConfiguration()
+			runSpares(os.Args[2:])
+			return
+		case "golden":
+			loadConfiguration()
+			runGolden(os.Args[2:])
+			return
+		case "selftest":
+			runSelftest(os.Args[2:])
+			return
+		case "migrate":
+			loadConfiguration()
+			runMigrate(os.Args[2:])
+			return
+		case "preflight":
+			loadConfiguration()
+			runPreflight(os.Args[2:])
+			return
+		case "gui":
+			loadConfiguration()
+			runGUI(os.Args[2:])
+			return
+		case "pair":
+			loadConfiguration()
+			runPair(os.Args[2:])
+			return
+		case "compact":
+			loadConfiguration()
+			runCompact(os.Args[2:])
+			return
+		case "update":
+			loadConfiguration()
+			runUpdate(os.Args[2:])
+			return
+		case "completion":
+			runCompletion(os.Args[2:])
+			return
+		case "errors":
+			runErrors(os.Args[2:])
+			return
+		case "perf":
+			loadConfiguration()
+			runPerf(os.Args[2:])
+			return
+		case "post-build":
+			runPostBuild(os.Args[2:])
+			return
+		case "stats":
+			loadConfiguration()
+			runStats(os.Args[2:])
+			return
+		case "bundle":
+			runBundle(os.Args[2:])
+			return
+		case "verify-artifacts":
+			loadConfiguration()
+			runVerifyArtifacts(os.Args[2:])
+			return
+		case "examples":
+			runExamples(os.Args[2:])
+			return
+		case "help":
+			// La página de la generación normal sale de los flags declarados
+			// abajo, como con -h.
+			if runHelp(os.Args[2:]) {
+				return
+			}
+			os.Args = []string{os.Args[0], "-h"}
+		case "__complete":
+			loadConfiguration()
+			runCompleteHelper(os.Args[2:])
+			return
+		}
+	}
 
-	projectPathPtr := flag.String("path", "", "Ruta raíz del proyecto")
-	nodeNamePtr := flag.String("node", "", "Nombre del Nodo")
-	skipExtPtr := flag.Bool("skip-ext", false, "Saltar ejecución de SIGEXT")
+	projectPathPtr := flag.String("path", "", tr("Ruta raíz del proyecto o copia .zip"))
+	nodeNamePtr := flag.String("node", "", tr("Nombre del Nodo"))
+	skipExtPtr := flag.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	allPtr := flag.Bool("all", false, tr("Generar todos los nodos del proyecto"))
+	workspacePtr := flag.String("workspace", "", tr("Fichero de workspace con varios proyectos y sus nodos"))
+	// -lang, -no-color y -firmware ya se extrajeron en extractGlobalFlags; se
+	// declaran aquí solo para que aparezcan en la ayuda.
+	flag.String("lang", "", tr("Idioma de los mensajes (es, en)"))
+	flag.Bool("no-color", false, tr("Salida sin colores (también con NO_COLOR)"))
+	flag.String("firmware", "", tr("Preset de firmware de la RTU (sustituye a app.firmware)"))
+	versionPtr := flag.Bool("version", false, tr("Mostrar la versión y salir"))
+	incrementalPtr := flag.Bool("incremental", false, tr("Informar solo de los cambios respecto a la generación anterior"))
+	outPtr := flag.String("out", "", tr("Directorio de salida (por defecto app.output.dir o el recurso RTU)"))
+	onlyPtr := flag.String("only", "", tr("Regenerar solo estas listas (p.ej. ai,ao); las demás se conservan tal cual"))
+	resumePtr := flag.Bool("resume", false, tr("Con -all o -workspace, continuar la ejecución interrumpida por el primer nodo sin terminar"))
+	flag.Usage = manUsage("", flag.CommandLine)
 
 	flag.Parse()
 
-	if *projectPathPtr == "" || *nodeNamePtr == "" {
-		// Fallback para desarrollo (Opcional)
+	if *versionPtr {
+		b := buildInfo()
+		fmt.Printf("dnpgen %s %s/%s %s\n", b, runtime.GOOS, runtime.GOARCH, b.Go)
+		return
+	}
+
+	if *workspacePtr != "" {
+		loadConfiguration()
+		if !runWorkspace(*workspacePtr, GenerateRequest{SkipExt: *skipExtPtr, OutDir: *outPtr, Incremental: *incrementalPtr, Only: parseOnly(*onlyPtr)}, *resumePtr) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	const usage = "Uso: dnpgen.exe -path \"C:\\Ruta\" -node \"NombreNodo\" | -all | -workspace plantas.yaml"
+	interactive := isTerminal(os.Stdin)
+	if *projectPathPtr == "" {
+		if !interactive {
+			log.Fatal(tr(usage))
+		}
+		*projectPathPtr = promptLine(tr("Ruta del proyecto: "))
 		if *projectPathPtr == "" {
-			log.Fatal("Uso: dnpgen.exe -path \"C:\\Ruta\" -node \"NombreNodo\"")
+			log.Fatal(tr(usage))
 		}
 	}
 
-	absProjectPath, err := filepath.Abs(*projectPathPtr)
+	loadConfiguration()
+
+	if *nodeNamePtr == "" && !*allPtr {
+		if !interactive {
+			log.Fatal(tr(usage))
+		}
+		*nodeNamePtr = promptNode(*projectPathPtr)
+	}
+
+	if *allPtr {
+		base := GenerateRequest{ProjectPath: *projectPathPtr, SkipExt: *skipExtPtr, OutDir: *outPtr, Incremental: *incrementalPtr, Only: parseOnly(*onlyPtr)}
+		cp, err := openCheckpoint(*projectPathPtr, base, *resumePtr)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		ok := runBatch(base, nil, cp)
+		cp.close(ok)
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+	if *resumePtr {
+		log.Fatal(tr("-resume solo tiene sentido con -all o -workspace"))
+	}
+
+	req := GenerateRequest{
+		ProjectPath: *projectPathPtr,
+		NodeName:    *nodeNamePtr,
+		SkipExt:     *skipExtPtr,
+		OutDir:      *outPtr,
+		Incremental: *incrementalPtr,
+		Only:        parseOnly(*onlyPtr),
+	}
+	captureRunLog()
+	progress := newConsoleProgress(!req.SkipExt)
+	req.Progress = progress.update
+	if isTerminal(os.Stdin) {
+		req.resolve = progress.pause(promptMergeConflict)
+	}
+	res, err := runGenerate(req)
+	progress.stop()
+	notifyResult(req, "", res, err)
 	if err != nil {
-		log.Fatalf("Error ruta absoluta: %v", err)
+		log.Printf("[FATAL] %v", err)
+		offerDiagnostics(req, res, err)
+		os.Exit(1)
 	}
 
-	loadConfiguration()
+	printSummary(res)
 
-	resourceDir := filepath.Join(absProjectPath, RelativePathToResource)
-	sigFile := filepath.Join(resourceDir, *nodeNamePtr+".SIG")
-	mwtFile := filepath.Join(absProjectPath, *nodeNamePtr+".mwt")
+	time.Sleep(1 * time.Second)
+}
 
-	if _, err := os.Stat(mwtFile); os.IsNotExist(err) {
-		mwtFile = filepath.Join(resourceDir, *nodeNamePtr+".mwt")
+// printSummary muestra el resultado de un nodo: tabla por lista y avisos.
+func printSummary(res *GenerateResult) {
+	printReport(res)
+	if res.ListFile != "" {
+		fmt.Println(okText(trf("Listas generadas: %s", res.ListFile)))
 	}
+}
 
-	if _, err := os.Stat(resourceDir); os.IsNotExist(err) {
-		log.Fatalf("[FATAL] Recurso no encontrado: %s", resourceDir)
+// printReport es el resumen sin la línea de ficheros escritos (lo comparte
+// analyze).
+func printReport(res *GenerateResult) {
+	fmt.Println(boldText(tr("\n--- RESUMEN ---")))
+	var rows [][]string
+	for _, s := range listSections(res.lists) {
+		spares := 0
+		for _, p := range s.Items {
+			if p.Spare {
+				spares++
+			}
+		}
+		rows = append(rows, []string{s.Name, strconv.Itoa(len(s.Items)), strconv.Itoa(len(s.Items) - spares), strconv.Itoa(spares)})
+	}
+	printTable(tr("LISTA\tPUNTOS\tREALES\tSPARES"), rows, nil)
+	if GlobalConfig.App.Areas.enabled() {
+		printAreaSummary(res.lists)
 	}
+	if GlobalConfig.App.Alarms.enabled() {
+		printAlarmSummary(res.lists)
+	}
+	printTimings(res.Timings)
+	if res.Delta != nil {
+		printDelta(res.Delta)
+	}
+	printSpareWarnings(res.SpareForecast)
+	if len(res.Retirements) > 0 {
+		fmt.Print(boldText(trf("\nRetiradas pendientes (%d):\n", len(res.Retirements))))
+		for _, p := range res.Retirements {
+			fmt.Println("  " + warnText(p.String()))
+		}
+	}
+	if res.Frozen > 0 {
+		fmt.Println(trf("Entradas congeladas conservadas: %d", res.Frozen))
+	}
+	if res.Merge != nil {
+		fmt.Print(boldText(trf("\nEdiciones a mano fusionadas (%s):\n", strings.Join(res.Merge.Lists, ", "))))
+		for _, c := range res.Merge.Conflicts {
+			fmt.Println("  " + warnText(trf("%s, entrada %d: conflicto resuelto con %s", c.List, c.Index, c.Resolution)))
+		}
+	}
+	if res.StaleSig {
+		fmt.Println(warnText(tr("¡ATENCIÓN! Se usó un .SIG anterior al .mwt")))
+	}
+	if res.RulesChange != nil {
+		fmt.Println(warnText(trf("¡ATENCIÓN! %s", res.RulesChange)))
+	}
+	if len(res.Findings) > 0 {
+		fmt.Print(boldText(trf("\nHallazgos (%d):\n", len(res.Findings))))
+		for _, f := range res.Findings {
+			color := func(s string) string { return s }
+			switch f.Severity {
+			case SeverityWarning:
+				color = warnText
+			case SeverityError:
+				color = errText
+			}
+			fmt.Println("  " + color(f.String()))
+		}
+	}
+}
 
-	if err := os.Chdir(resourceDir); err != nil {
-		log.Fatalf("Error accediendo a directorio: %v", err)
+// runGenerate ejecuta el pipeline completo (SIGEXT, parseo y escritura) para
+// un nodo. No cambia el directorio de trabajo, de modo que puede invocarse
+// desde el modo servidor.
+func runGenerate(req GenerateRequest) (*GenerateResult, error) {
+	start := time.Now()
+	res, err := generate(req)
+	recordUsage(req, time.Since(start), res, err)
+	return res, err
+}
+
+func generate(req GenerateRequest) (*GenerateResult, error) {
+	cleanup, err := openZipProject(&req)
+	if err != nil {
+		return nil, withCode(ErrProjectInvalid, err)
 	}
+	defer cleanup()
+	absProjectPath, err := filepath.Abs(normalizeLongPath(req.ProjectPath))
+	if err != nil {
+		return nil, codedErrorf(ErrProjectInvalid, tr("ruta absoluta: %v"), err)
+	}
+
+	paths := nodePathsFor(absProjectPath, req.NodeName)
+	if req.pair != nil && req.pair.standby {
+		// El reserva se genera desde el .SIG (y el recurso) del principal.
+		paths = nodePathsFor(absProjectPath, req.pair.pair.Primary)
+	}
+	resourceDir, sigFile, mwtFile := paths.Resource, paths.Sig, paths.Mwt
+	outDir := outputDirFor(absProjectPath, req.NodeName, req.OutDir)
+	listFile := filepath.Join(outDir, GlobalConfig.App.Output.fileName())
+
+	if _, err := os.Stat(resourceDir); os.IsNotExist(err) {
+		return nil, codedErrorf(ErrResourceNotFound, tr("recurso no encontrado: %s"), resourceDir)
+	}
+	sigSource := sigSourceName(req)
+	var fetcher SigSource
+	if sigSource != SigSourceSigExt && sigSource != SigSourceLocal {
+		if fetcher, err = sigSourceFor(sigSource); err != nil {
+			return nil, withCode(ErrConfigInvalid, err)
+		}
+	}
+	if problems := preflightProblems(req, paths, outDir); len(problems) > 0 {
+		return nil, codedErrorf(ErrPreflightFailed, tr("comprobaciones previas, %d problema(s):\n  - %s"), len(problems), strings.Join(problems, "\n  - "))
+	}
+
+	timer := &stageTimer{req: req}
 
-	if !*skipExtPtr {
-		log.Println("Ejecutando SIGEXT...")
-		err := runSigExt(GlobalConfig.App.SigExtPath, GlobalConfig.App.SigExtFlags, mwtFile, *nodeNamePtr, sigFile)
+	sigExtAttempt, sigExtLog, sigFetched := 0, "", false
+	var transcript bytes.Buffer
+	switch sigSource {
+	case SigSourceLocal:
+	case SigSourceSigExt:
+		log.Println(tr("Ejecutando SIGEXT..."))
+		timer.stage("sigext", tr("Ejecutando SIGEXT"))
+		sigExtAttempt, err = runSigExtRetry(resourceDir, mwtFile, req.NodeName, sigFile, &transcript)
 		if err != nil {
-			log.Printf("[ERROR] SIGEXT: %v", err)
+			err = withCode(ErrSigExtFailed, fmt.Errorf("SIGEXT: %v", err))
+			log.Printf("[ERROR] %v", err)
+			req.emit(GenerateEvent{Kind: EventWarning, Stage: "sigext", Code: errorCode(err), Message: err.Error()})
+		}
+	default:
+		log.Printf(tr("Obteniendo el .SIG (%s)..."), sigSource)
+		timer.stage("sigext", trf("Obteniendo el .SIG (%s)", sigSource))
+		f := SigFetch{Project: absProjectPath, Resource: resourceDir, Mwt: mwtFile, Node: req.NodeName, Sig: sigFile}
+		if err := fetchSig(fetcher, f, &transcript); err != nil {
+			err = withCode(ErrSigSourceFailed, fmt.Errorf("%s: %v", sigSource, err))
+			log.Printf("[ERROR] %v", err)
+			req.emit(GenerateEvent{Kind: EventWarning, Stage: "sigext", Code: errorCode(err), Message: err.Error()})
+		} else {
+			sigFetched = true
+		}
+	}
+	if sigSource != SigSourceLocal && !req.CheckOnly {
+		if sigExtLog, err = writeSigExtLog(outDir, req.NodeName, transcript.Bytes()); err != nil {
+			req.warnf(tr("[WARN] No se pudo guardar la salida de SIGEXT: %v"), err)
 		}
 	}
 
 	if _, err := os.Stat(sigFile); os.IsNotExist(err) {
-		log.Fatalf("[FATAL] No existe .SIG: %s", sigFile)
+		return nil, codedErrorf(ErrSigNotFound, tr("no existe .SIG: %s"), sigFile)
+	}
+	staleSig := false
+	if sigExtAttempt == 0 && !sigFetched {
+		if staleSig, err = checkStaleSig(sigFile, mwtFile, sigSource == SigSourceLocal); err != nil {
+			return nil, withCode(ErrStaleSig, err)
+		}
+		if staleSig {
+			req.emit(GenerateEvent{Kind: EventWarning, Code: ErrStaleSig, Message: tr("El .SIG es anterior al .mwt")})
+		}
 	}
 
-	log.Printf("Procesando: %s", filepath.Base(sigFile))
-	if err := processSigFile(sigFile); err != nil {
-		log.Fatalf("[FATAL] Error procesando: %v", err)
+	log.Printf(tr("Procesando: %s"), filepath.Base(sigFile))
+	timer.stage("parse", trf("Procesando %s", filepath.Base(sigFile)))
+	lists, sigRep, err := processSigFile(sigFile, req.sigProgress("parse"))
+	if err != nil {
+		return nil, codedErrorf(ErrSigParseFailed, tr("error procesando: %v"), err)
+	}
+	if v := sigRep.Format.Version; v != "1" {
+		log.Printf(tr("Formato del .SIG: versión %s"), v)
+	}
+	disabled, err := GlobalConfig.App.DisabledLists.forNode(req.NodeName)
+	if err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	outsideLists := disableLists(lists, sigRep, disabled)
+	timer.stage("classify", tr("Clasificando puntos"))
+	if _, err := markSOE(lists, GlobalConfig.App.Classification.SOE); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if _, err := copyAnalogSubtypes(lists, GlobalConfig.App.Classification.AnalogSubtypes); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	_, unpaired, err := pairControls(lists, GlobalConfig.App.Classification.ControlPairs)
+	if err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if _, err := applyScaling(lists, resourceDir, GlobalConfig.App.Scaling); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	system, err := systemReservations(GlobalConfig.App.SystemPoints)
+	if err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	derived, err := derivedPoints(lists, req.NodeName, GlobalConfig.App.DerivedPoints)
+	if err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if n, err := commHealthPoints(lists, req.NodeName, GlobalConfig.App.CommHealth); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	} else if n > 0 {
+		log.Printf(tr("Puntos de estado de comunicación: %d en %s"), n, strings.ToUpper(GlobalConfig.App.CommHealth.List))
+	}
+	reservations := append(append(system, derived...), GlobalConfig.App.Reserved...)
+	var lifecycle *lifecycleMap
+	var holds lifecycleHolds
+	now := time.Now().UTC()
+	if GlobalConfig.App.Lifecycle.Enabled {
+		if lifecycle = req.lifecycle; lifecycle == nil {
+			if lifecycle, err = readLifecycle(outDir, req.NodeName); err != nil {
+				return nil, codedErrorf(ErrStateInvalid, tr("mapa de índices: %v"), err)
+			}
+		}
+		var retired []IndexReservation
+		retired, holds = retiredReservations(lifecycle, lists, reservations, now)
+		reservations = append(reservations, retired...)
+	}
+	reservations = enabledReservations(reservations, disabled)
+	// El orden va antes de las reservas: solo mueve puntos del .SIG. Al migrar
+	// se reordena todo.
+	sortPrev := lifecycle
+	if req.migrate {
+		sortPrev = nil
+	}
+	if err := sortLists(lists, GlobalConfig.App.Sorting, sortPrev); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if err := applyReservations(lists, req.NodeName, reservations); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if req.pair != nil {
+		if err := req.pair.apply(lists); err != nil {
+			return nil, withCode(ErrConfigInvalid, err)
+		}
+	}
+	frozen, err := applyFreeze(lists, outDir, req.NodeName)
+	if err != nil {
+		return nil, withCode(ErrStateInvalid, err)
+	}
+	var partial *partialRegen
+	if len(req.Only) > 0 {
+		if partial, err = keepExistingLists(lists, req.Only, outDir); err != nil {
+			return nil, withCode(ErrPartialInvalid, err)
+		}
+		var regen, kept []string
+		for _, s := range listSections(lists) {
+			if partial.kept(s.Name) {
+				kept = append(kept, s.Name)
+			} else {
+				regen = append(regen, s.Name)
+			}
+		}
+		log.Printf(tr("Regeneración parcial: se regeneran %s; se conservan %s"), strings.Join(regen, ","), strings.Join(kept, ","))
+		// Las listas conservadas no cambian: ningún índice retirado en ellas.
+		for name := range holds {
+			if partial.kept(name) {
+				delete(holds, name)
+			}
+		}
+	}
+	if lifecycle != nil {
+		lifecycle = lifecycleAfter(lifecycle, lists, holds, now)
+	}
+	if err := tagAreas(lists, GlobalConfig.App.Areas); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if err := tagAlarms(lists, GlobalConfig.App.Alarms); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if err := assignObjects(lists, GlobalConfig.App.DNP3Objects); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if err := fillRuleLists(lists, GlobalConfig.App.Classification.ExtraLists); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	var merge *MergeReport
+	var mergeBase []byte
+	if GlobalConfig.App.Merge.Enabled && !req.CheckOnly {
+		if err := GlobalConfig.App.Merge.validate(); err != nil {
+			return nil, withCode(ErrConfigInvalid, err)
+		}
+		if merge, mergeBase, err = mergeHandEdits(lists, outDir, req.NodeName, partial, req.resolve); err != nil {
+			return nil, withCode(ErrMergeConflict, err)
+		}
+		if merge != nil {
+			req.warnf(tr("[WARN] Listas editadas a mano desde la última generación: %s; se fusionan las ediciones (%d conflicto(s))"), strings.Join(merge.Lists, ","), len(merge.Conflicts))
+		}
+	}
+	nameIssues, err := checkPointNames(lists, GlobalConfig.App.NameRules)
+	if err != nil {
+		return nil, codedErrorf(ErrConfigInvalid, tr("nombres de punto: %v"), err)
+	}
+	naming, err := lintNames(lists)
+	if err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if err := GlobalConfig.App.Findings.validate(); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	if _, err := GlobalConfig.App.Spares.duplicateSeverity(); err != nil {
+		return nil, withCode(ErrConfigInvalid, err)
+	}
+	findings := newFindingSet(req.NodeName)
+	collectFindings(findings, lists, sigRep, nameIssues, naming)
+	checkSigCompleteness(findings, sigRep, mwtFile)
+	for _, u := range unpaired {
+		findings.add(FindingControlPair, SeverityWarning, "%s", u)
+	}
+	for _, d := range outsideLists {
+		findings.add(FindingDisabledList, SeverityError, "%s", d)
+	}
+	for _, m := range partial.mirrorSplits(sigRep.asymmetric) {
+		findings.add(FindingMirrorSplit, SeverityWarning, "%s", m)
+	}
+	rulesPrev, rulesChange, err := checkRules(outDir, req.NodeName)
+	if err != nil {
+		return nil, codedErrorf(ErrStateInvalid, tr("registro de reglas: %v"), err)
+	}
+	if req.migrate {
+		rulesChange = nil
+	} else if rulesChange != nil {
+		warnRulesChange(findings, rulesChange)
+	}
+	if hasValidators() {
+		timer.stage("classify", tr("Validando el modelo de puntos"))
+		runValidators(buildModel(ExportContext{Project: absProjectPath, Node: req.NodeName, Lists: lists, Sig: sigFile}), findings)
+	}
+	if err := findings.verdict(); err != nil {
+		return nil, withCode(ErrFindingsFailed, err)
+	}
+	for _, f := range findings.list {
+		if f.Severity == SeverityWarning {
+			req.emit(GenerateEvent{Kind: EventWarning, Code: f.Code, Message: f.Message})
+		}
 	}
 
-	log.Println("Generando __lists.ini...")
-	if err := generateListsFile(); err != nil {
-		log.Fatalf("[FATAL] Error escribiendo INI: %v", err)
+	res := &GenerateResult{
+		SigFile:  sigFile,
+		ListFile: listFile,
+		DI:       len(lists.DI),
+		DO:       len(lists.DO),
+		AI:       len(lists.AI),
+		AO:       len(lists.AO),
+		OS:       len(lists.OS),
+		lists:    lists,
+		Merge:    merge,
+
+		SigSource:     sigSource,
+		SigExtAttempt: sigExtAttempt,
+		StaleSig:      staleSig,
+
+		NameIssues:     nameIssues,
+		SigDiagnostics: sigRep.Diagnostics,
+		SigVersion:     sigRep.Format.Version,
+		Findings:       findings.list,
+	}
+	if lifecycle != nil {
+		res.Retirements = pendingRetirements(lifecycle)
+	}
+	res.RulesChange = rulesChange
+	res.Frozen = frozen
+	if sigRep.Format.normalized() {
+		res.SigFormat = &sigRep.Format
 	}
 
-	fmt.Println("\n--- RESUMEN ---")
-	fmt.Printf("DI: %d | DO: %d | AI: %d | AO: %d\n", len(ListDI), len(ListDO), len(ListAI), len(ListAO))
+	if sigExtLog != "" {
+		res.Artifacts = append(res.Artifacts, sigExtLog)
+	}
+	if partial == nil {
+		res.content = withListHeader(renderLists(lists))
+	} else {
+		res.content = withListHeader(partial.render(lists))
+	}
+	outputs := listOutputs(outDir, lists, res.content)
+	if partial != nil {
+		outputs = partial.outputs(outputs)
+	}
+	if !GlobalConfig.App.Output.combined() {
+		res.ListFile = ""
+	}
+	for _, o := range outputs {
+		// La cabecera cambia con cada versión del generador: no cuenta como deriva.
+		if existing, err := os.ReadFile(o.path); err == nil && !bytes.Equal(stripListHeader(existing), stripListHeader(o.content)) {
+			res.Drift = true
+		}
+	}
+	var snapshot *sigSnapshot
+	if req.Incremental || GlobalConfig.App.Incremental.Enabled {
+		snapshot = takeSnapshot(sigFile, lists)
+		if res.Delta, err = computeDelta(outDir, req.NodeName, snapshot); err != nil {
+			return nil, codedErrorf(ErrStateInvalid, tr("instantánea incremental: %v"), err)
+		}
+	}
+	var counts *pointCounts
+	if GlobalConfig.App.CountAlarm.Enabled {
+		counts = countRealPoints(lists)
+		if res.CountAlarms, err = checkCountDrop(outDir, req.NodeName, counts); err != nil {
+			return nil, codedErrorf(ErrStateInvalid, tr("recuento anterior: %v"), err)
+		}
+		if len(res.CountAlarms) > 0 {
+			if GlobalConfig.App.CountAlarm.Fail {
+				return nil, codedErrorf(ErrCountDrop, tr("caída brusca de puntos (%s): ¿.SIG incompleto? No se sobrescriben las listas"), formatCountAlarms(res.CountAlarms))
+			}
+			req.warnf(tr("[WARN] Caída brusca de puntos respecto a la generación anterior: %s"), formatCountAlarms(res.CountAlarms))
+		}
+	}
+	var spareHistory []spareSample
+	var spareCur spareSample
+	if GlobalConfig.App.SpareForecast.Enabled {
+		if spareHistory, err = readSpareHistory(outDir, req.NodeName); err != nil {
+			return nil, codedErrorf(ErrStateInvalid, tr("historial de spares: %v"), err)
+		}
+		spareCur = takeSpareSample(lists)
+		res.SpareForecast = forecastSpares(append(spareHistory[:len(spareHistory):len(spareHistory)], spareCur))
+	}
+	if req.CheckOnly {
+		res.Timings = timer.done()
+		return res, nil
+	}
 
-	time.Sleep(1 * time.Second)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, codedErrorf(ErrWriteFailed, tr("directorio de salida: %v"), err)
+	}
+	for _, o := range outputs {
+		name := filepath.Base(o.path)
+		log.Printf(tr("Generando %s..."), name)
+		timer.stage("write", trf("Generando %s", name))
+		if err := os.WriteFile(o.path, o.content, 0o644); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("error escribiendo INI: %v"), err)
+		}
+		if o.path != res.ListFile {
+			res.Artifacts = append(res.Artifacts, o.path)
+		}
+	}
+	if GlobalConfig.App.Output.RoundTrip && res.ListFile != "" {
+		problems, err := checkRoundTripFile(res.ListFile, lists)
+		if err != nil {
+			return nil, codedErrorf(ErrRoundTripMismatch, tr("comprobación de ida y vuelta: %v"), err)
+		}
+		if len(problems) > 0 {
+			return nil, codedErrorf(ErrRoundTripMismatch, tr("%s no se relee igual: %s"), filepath.Base(res.ListFile), strings.Join(problems, "; "))
+		}
+	}
+
+	timer.stage("export", tr("Exportando"))
+	exportCtx := ExportContext{Project: absProjectPath, Node: req.NodeName, Lists: lists, Sig: sigFile}
+	exported, err := writeExports(outDir, exportCtx)
+	if err != nil {
+		return nil, codedErrorf(ErrExportFailed, tr("error exportando: %v"), err)
+	}
+	res.Artifacts = append(res.Artifacts, exported...)
+
+	if GlobalConfig.App.PointDB.Enabled {
+		dbPath := pointDBPath(absProjectPath)
+		log.Printf(tr("Actualizando base de puntos %s..."), filepath.Base(dbPath))
+		if err := updatePointDB(dbPath, req.NodeName, lists); err != nil {
+			return nil, codedErrorf(ErrDatabaseFailed, tr("error en base de puntos: %v"), err)
+		}
+	}
+
+	if GlobalConfig.App.Database.Enabled {
+		log.Printf(tr("Exportando a base de datos (%s)..."), GlobalConfig.App.Database.Driver)
+		if err := exportToDatabase(absProjectPath, req.NodeName, lists); err != nil {
+			return nil, codedErrorf(ErrDatabaseFailed, tr("error exportando a base de datos: %v"), err)
+		}
+	}
+	if snapshot != nil {
+		if !res.Delta.First {
+			if res.Delta.ReportFile, err = writeDeltaReport(outDir, req.NodeName, res.Delta); err != nil {
+				return nil, codedErrorf(ErrWriteFailed, tr("informe incremental: %v"), err)
+			}
+			res.Artifacts = append(res.Artifacts, res.Delta.ReportFile)
+		}
+		if err := writeSnapshot(snapshotPath(outDir, req.NodeName), snapshot); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("instantánea incremental: %v"), err)
+		}
+	}
+	if mergeBase != nil {
+		if err := writeMergeBase(outDir, req.NodeName, mergeBase); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("base de la fusión: %v"), err)
+		}
+	}
+	if counts != nil {
+		if err := writeCounts(outDir, req.NodeName, counts); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("recuento de puntos: %v"), err)
+		}
+	}
+	if GlobalConfig.App.SpareForecast.Enabled {
+		if err := writeSpareHistory(outDir, req.NodeName, spareHistory, spareCur); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("historial de spares: %v"), err)
+		}
+	}
+	if lifecycle != nil {
+		lifecycle.RulesVersion = GlobalConfig.App.RulesVersion
+		if err := writeLifecycle(outDir, req.NodeName, lifecycle); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("mapa de índices: %v"), err)
+		}
+	}
+	// El registro solo cambia en la primera generación o al migrar: mientras
+	// tanto el aviso se repite.
+	if rulesPrev == nil || req.migrate {
+		if err := writeRulesStamp(outDir, req.NodeName, currentRules(lists)); err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("registro de reglas: %v"), err)
+		}
+	}
+	res.Timings = timer.done()
+	// Va después del veredicto: una etapa lenta se avisa, no bloquea.
+	if GlobalConfig.App.Perf.Enabled {
+		regressions, err := trackPerf(absProjectPath, req.NodeName, res, sigRep.Signals)
+		if err != nil {
+			req.warnf(tr("[WARN] No se pudo guardar el historial de rendimiento: %v"), err)
+		}
+		for _, r := range regressions {
+			log.Printf(tr("[WARN] Rendimiento: %s"), r)
+			findings.add(FindingPerfRegression, SeverityWarning, "%s", r)
+		}
+		res.Findings = findings.list
+	}
+
+	if GlobalConfig.App.Metrics.Enabled {
+		path, err := writeRunMetrics(absProjectPath, outDir, req.NodeName, res, sigRep)
+		if err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("error escribiendo métricas: %v"), err)
+		}
+		res.Artifacts = append(res.Artifacts, path)
+	}
+	if GlobalConfig.App.Output.EffectiveConfig {
+		path, err := writeEffectiveConfig(outDir, req)
+		if err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("error escribiendo la configuración efectiva: %v"), err)
+		}
+		res.Artifacts = append(res.Artifacts, path)
+	}
+	// La firma va en el manifiesto: firmar lo activa aunque output.manifest
+	// esté desactivado.
+	if signing := GlobalConfig.App.Signing; GlobalConfig.App.Output.Manifest || signing.Enabled {
+		key, signer, err := signing.signer()
+		if err != nil {
+			return nil, codedErrorf(ErrSigningFailed, tr("clave de firma: %v"), err)
+		}
+		path, err := writeManifest(absProjectPath, outDir, req.NodeName, res, signer)
+		if err != nil {
+			return nil, codedErrorf(ErrWriteFailed, tr("error escribiendo el manifiesto: %v"), err)
+		}
+		res.Artifacts = append(res.Artifacts, path)
+		if key != nil {
+			sig, err := signManifest(path, key)
+			if err != nil {
+				return nil, codedErrorf(ErrSigningFailed, tr("error firmando el manifiesto: %v"), err)
+			}
+			res.Artifacts = append(res.Artifacts, sig)
+		}
+	}
+	if len(GlobalConfig.App.Publish.Targets) > 0 {
+		res.Published, err = publishArtifacts(absProjectPath, req.NodeName, res)
+		if err != nil {
+			if GlobalConfig.App.Publish.Required {
+				return nil, codedErrorf(ErrPublishFailed, tr("error publicando: %v"), err)
+			}
+			log.Printf(tr("[WARN] Publicación incompleta: %v"), err)
+		}
+	}
+	return res, nil
+}
+
+// outputDirFor resuelve el directorio donde se escriben listas y
+// exportaciones: override (-out), app.output.dir o el recurso RTU. Las rutas
+// relativas de la configuración se toman desde la raíz del proyecto.
+func outputDirFor(absProjectPath, node, override string) string {
+	dir := override
+	if dir == "" && GlobalConfig.App.Output.Dir != "" {
+		dir = expandTemplate(GlobalConfig.App.Output.Dir, map[string]string{"project": absProjectPath, "node": node})
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(absProjectPath, dir)
+		}
+	}
+	if dir == "" {
+		return resourceDirFor(absProjectPath, node)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// listsPathFor devuelve la ruta del __lists.ini combinado de un nodo según
+// la configuración de salida.
+func listsPathFor(absProjectPath, node string) string {
+	return filepath.Join(outputDirFor(absProjectPath, node, ""), GlobalConfig.App.Output.fileName())
+}
+
+// expandTemplate sustituye las variables {clave} de una plantilla.
+func expandTemplate(tpl string, vars map[string]string) string {
+	pairs := make([]string, 0, 2*len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tpl)
 }
 
 func loadConfiguration() {
+	path, ok := findConfigFile()
+	if !ok {
+		log.Fatalf("[FATAL] %v", codedErrorf(ErrConfigNotFound, tr("No se encuentra %s"), ConfigFile))
+	}
+	if err := loadConfigFile(path); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+}
+
+// findConfigFile busca config.yaml junto al ejecutable y, si no, en el
+// directorio actual.
+func findConfigFile() (string, bool) {
 	exePath, _ := os.Executable()
 	configPathExe := filepath.Join(filepath.Dir(exePath), ConfigFile)
-	configPathCWD := ConfigFile
+	if _, errStat := os.Stat(configPathExe); errStat == nil {
+		return configPathExe, true
+	}
+	if _, errStat := os.Stat(ConfigFile); errStat == nil {
+		return ConfigFile, true
+	}
+	return "", false
+}
 
-	var f *os.File
-	var err error
+// renamedConfigKeys son claves que cambiaron de nombre: la decodificación
+// estricta las rechaza y el error dice cuál es la buena.
+var renamedConfigKeys = map[string]string{
+	"digital_output_patterns": "digital_output_regex",
+}
 
-	if _, errStat := os.Stat(configPathExe); errStat == nil {
-		f, err = os.Open(configPathExe)
-	} else if _, errStat := os.Stat(configPathCWD); errStat == nil {
-		f, err = os.Open(configPathCWD)
-	} else {
-		log.Fatalf("No se encuentra %s", ConfigFile)
+// decodeConfig decodifica config.yaml en cfg. Las claves desconocidas son un
+// error: una regla mal escrita no puede quedarse sin efecto en silencio.
+func decodeConfig(r io.Reader, cfg *Config) error {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	err := dec.Decode(cfg)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
 	}
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return err
+	}
+	// yaml nombra el tipo Go completo; basta con la línea y la clave.
+	msgs := make([]string, len(te.Errors))
+	for i, e := range te.Errors {
+		msgs[i] = e
+		m := unknownFieldRe.FindStringSubmatch(e)
+		if m == nil {
+			continue
+		}
+		msgs[i] = trf("línea %s: clave desconocida %s", m[1], m[2])
+		if key, ok := renamedConfigKeys[m[2]]; ok {
+			msgs[i] += trf(" (ahora se llama %s)", key)
+		}
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
 
+var unknownFieldRe = regexp.MustCompile(`^line (\d+): field (\S+) not found in type`)
+
+// loadConfigFile carga path en GlobalConfig (lo usa también bundle run con
+// el config.yaml del paquete).
+func loadConfigFile(path string) error {
+	f, err := os.Open(path)
 	if err != nil {
-		log.Fatalf("Error abriendo config: %v", err)
+		return codedErrorf(ErrConfigNotFound, tr("Error abriendo config: %v"), err)
 	}
 	defer f.Close()
+	configSource, _ = filepath.Abs(path)
 
-	if err := yaml.NewDecoder(f).Decode(&GlobalConfig); err != nil {
-		log.Fatalf("YAML malformado: %v", err)
+	if err := decodeConfig(f, &GlobalConfig); err != nil {
+		return codedErrorf(ErrConfigMalformed, tr("YAML malformado: %v"), err)
+	}
+	if err := validateTimezone(GlobalConfig.App.Timezone); err != nil {
+		return withCode(ErrConfigInvalid, err)
 	}
+	initLocale()
+	applyFirmware()
+	return nil
 }
 
-func runSigExt(exePath, flags, mwtPath, nodeName, sigPath string) error {
-	if _, err := os.Stat(exePath); os.IsNotExist(err) {
-		return fmt.Errorf("exe no encontrado")
+func runSigExt(exePath, flags, workDir, mwtPath, nodeName, sigPath string, transcript io.Writer) error {
+	wrapper := GlobalConfig.App.SigExtWrapper
+	if wrapper.Command == "" {
+		if _, err := os.Stat(exePath); os.IsNotExist(err) {
+			recordSigExt(transcript, exePath, nil, errSigExtNotFound)
+			return errSigExtNotFound
+		}
 	}
-	args := []string{}
-	if flags != "" {
-		args = append(args, strings.Fields(flags)...)
+	paths := GlobalConfig.App.SigExtPaths
+	mwtArg, sigArg, dir := paths.child(mwtPath), paths.child(sigPath), paths.mapped(workDir)
+	if isUNC(mwtArg) || isUNC(sigArg) {
+		log.Printf(tr("[WARN] SIGEXT recibe rutas UNC (%s); si falla, mapee una unidad en app.sigext_paths.map"), mwtArg)
 	}
-	args = append(args, mwtPath, nodeName, sigPath)
-	return exec.Command(exePath, args...).Run()
+	args := sigExtArgs(GlobalConfig.App.SigExtArgs, flags, wrapper.path(mwtArg), nodeName, wrapper.path(sigArg))
+	name := exePath
+	if wrapper.Command != "" {
+		// Con envoltorio (wine) la ruta del exe es la de Windows y no se
+		// comprueba en el sistema de ficheros local.
+		fields := strings.Fields(wrapper.Command)
+		name, args = fields[0], append(append(fields[1:], exePath), args...)
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	err := cmd.Run()
+	recordSigExt(transcript, cmd.String(), output.Bytes(), err)
+	return err
 }
 
-// --- NUEVA LÓGICA DE REGEX ---
-// isMatchRegex verifica si el nombre cumple con alguna de las expresiones regulares del YAML
-func isMatchRegex(name string, patterns []string) bool {
-	for _, p := range patterns {
-		// regexp.MatchString compila y verifica.
-		// Si el patrón es inválido, devolverá error (aquí lo ignoramos y asume false)
-		matched, _ := regexp.MatchString(p, name)
-		if matched {
-			return true
-		}
+// outputRule describe por qué una analógica o digital fue a su lista: la
+// regla de salida que cumplió o, si ninguna, su tipo.
+func outputRule(key string, i int, varType string) string {
+	if i >= 0 {
+		return fmt.Sprintf("%s[%d]", key, i)
 	}
-	return false
+	return "type:" + varType
+}
+
+// sigReport resume la lectura de un .SIG.
+type sigReport struct {
+	Signals     int
+	Format      SigFormat
+	Diagnostics []SigDiagnostic
+	Unknown     map[string]int    // señales sin lista, por tipo
+	Asymmetries []MirrorAsymmetry // primera divergencia de cada pareja espejo
+	vars        map[string]bool   // variables del .SIG, solo con sig_check.mwt
+	asymmetric  map[string]bool   // parejas que la configuración desequilibra a propósito
 }
 
-func processSigFile(path string) error {
+// processSigFile clasifica las señales del .SIG en las cuatro listas. Las
+// líneas ilegibles no abortan el proceso: se devuelven como diagnósticos.
+// progress, si no es nil, recibe cada señal leída con los bytes leídos del
+// total. Las señales se clasifican según se leen, sin guardar el .SIG, pero
+// las listas sí quedan enteras en memoria: espejos, reservas, orden,
+// hallazgos y exportaciones necesitan todos los puntos, así que la memoria
+// crece con el número de señales (ver BenchmarkClassify).
+func processSigFile(path string, progress func(signals int, read, size int64)) (*Lists, *sigReport, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer file.Close()
+	var size int64
+	if fi, err := file.Stat(); err == nil {
+		size = fi.Size()
+	}
+	in := &countingReader{r: file}
 
-	ListAO, ListAI, ListDO, ListDI = []string{}, []string{}, []string{}, []string{}
-	spares := GlobalConfig.App.Spares
+	l := &Lists{AI: []Point{}, AO: []Point{}, DI: []Point{}, DO: []Point{}}
 	rules := GlobalConfig.App.Classification
+	mirrors, err := compileMirrorRules(rules.Mirror, rules.MirrorRules)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	scanner := bufio.NewScanner(file)
-	re := regexp.MustCompile(`SIG=@GV\.([\w\d_]+)\s+TYPE=([A-Z]+)`)
+	// Los patrones se compilan una sola vez: con SIG de 100k señales,
+	// compilarlos por cada señal dominaba el tiempo total.
+	analogOut, _ := compileRules(rules.AnalogRegex, true)
+	digitalOut, _ := compileRules(rules.DigitalRegex, true)
+	subtypes, err := compileAnalogSubtypes(rules.AnalogSubtypes)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if !strings.HasPrefix(line, "SIG=") {
-			continue
+	extra, err := compileExtraLists(rules.ExtraLists)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.Extra = newCustomLists(rules.ExtraLists)
+	addStaticEntries(l, rules.ExtraLists)
+	aos, err := newAOSBuilder(rules.AOStatus, l.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	octets, err := compileStringRules(rules.Strings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allocators := map[string]*spareAllocator{}
+	for _, list := range []string{"AI", "AO", "DI", "DO"} {
+		if allocators[list], err = newSpareAllocator(list, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// --- LÓGICA ESPEJO ---
+	// place añade el punto a su lista y aplica la estrategia de espejo
+	// en la lista opuesta (por defecto, un spare con nombre para depurar).
+	var spareErr error
+	asymmetric := map[string]bool{} // parejas que la configuración desequilibra a propósito
+	place := func(point Point, isOutput bool, in, out *[]Point, inList, outList string) {
+		target, opposite, spares := in, out, allocators[outList]
+		if isOutput {
+			target, opposite, spares = out, in, allocators[inList]
+		}
+		*target = append(*target, point)
+		if aos != nil && isOutput && outList == "AO" {
+			status := aos.point(point)
+			status.Rule = "ao_status"
+			status.line = point.line
+			if aos.target < 0 {
+				*opposite = append(*opposite, status) // sustituye al spare de espejo
+				return
+			}
+			l.Extra[aos.target].Items = append(l.Extra[aos.target].Items, status)
+		}
+		addSpare := func() {
+			if spares.omit() {
+				asymmetric[mirrorPair(inList, outList)] = true
+				return
+			}
+			spare, err := spares.next(point.Var, point.Type)
+			if err != nil {
+				if spareErr == nil {
+					spareErr = err
+				}
+				return
+			}
+			spare.Rule = "mirror:spare"
+			spare.line = point.line
+			*opposite = append(*opposite, spare)
+		}
+		switch mirrors.strategyFor(point.Var) {
+		case MirrorSpare:
+			addSpare()
+		case MirrorSpareInput:
+			asymmetric[mirrorPair(inList, outList)] = true
+			if isOutput {
+				addSpare()
+			}
+		case MirrorSpareOutput:
+			asymmetric[mirrorPair(inList, outList)] = true
+			if !isOutput {
+				addSpare()
+			}
+		case MirrorBoth:
+			mirrored := point
+			mirrored.Rule = "mirror:both"
+			*opposite = append(*opposite, mirrored)
+		case MirrorNone:
+			asymmetric[mirrorPair(inList, outList)] = true
+		}
+	}
+
+	report := &sigReport{Unknown: map[string]int{}}
+	if GlobalConfig.App.SigCheck.Mwt {
+		report.vars = map[string]bool{}
+	}
+	report.Format, report.Diagnostics, err = ParseSIG(in, func(sig Signal) {
+		report.Signals++
+		if progress != nil {
+			progress(report.Signals, in.n, size)
+		}
+		if report.vars != nil {
+			report.vars[sig.Var] = true
 		}
+		varName, varType := sig.Var, sig.Type
+		point := Point{Name: "@GV." + varName, Var: varName, Type: varType, Desc: sig.Desc, line: sig.Line}
 
-		matches := re.FindStringSubmatch(line)
-		if matches != nil {
-			varName := matches[1]
-			varType := matches[2]
-			fullName := "@GV." + varName
+		if i := extra.route(varName, varType); i >= 0 {
+			routed := point
+			routed.Rule = "extra_lists:" + rules.ExtraLists[i].Name
+			l.Extra[i].Items = append(l.Extra[i].Items, routed)
+			if !rules.ExtraLists[i].Copy {
+				return
+			}
+		}
 
-			// Nota: La lógica de _SPAN ya está manejada por el regex _SP($|_) en el YAML
+		// Las cadenas de octetos no tienen espejo: solo existen como lectura.
+		if octets.match(varName, varType) {
+			point.Rule = "strings"
+			l.OS = append(l.OS, point)
+			return
+		}
 
-			// --- LÓGICA ESPEJO ---
+		// Nota: La lógica de _SPAN ya está manejada por el regex _SP($|_) en el YAML
 
-			// 1. ANALÓGICAS
-			if strings.Contains(varType, "AA") || strings.Contains(varType, "REAL") {
+		// 1. ANALÓGICAS
+		if isAnalogType(varType) {
 
-				// AHORA USAMOS REGEX
-				// Esto validará "LIT.*_H_H" -> Solo si tiene LIT y H_H es true.
-				// FT041_H_H -> Fallará el regex, por tanto isOutput = false (ENTRADA) -> Correcto.
-				isOutput := isMatchRegex(varName, rules.AnalogRegex)
+			// AHORA USAMOS REGEX
+			// Esto validará "LIT.*_H_H" -> Solo si tiene LIT y H_H es true.
+			// FT041_H_H -> Fallará el regex, por tanto isOutput = false (ENTRADA) -> Correcto.
+			// Un subtipo con output_regex propio (p.ej. AAR) usa los suyos.
+			matcher, key := analogOut, "analog_output_regex"
+			if m := subtypes[strings.ToUpper(varType)]; m != nil {
+				matcher, key = m, "analog_subtypes."+strings.ToUpper(varType)+".output_regex"
+			}
+			i := matcher.Match(varName)
+			point.Rule = outputRule(key, i, varType)
+			place(point, i >= 0, &l.AI, &l.AO, "AI", "AO")
 
-				if isOutput {
-					ListAO = append(ListAO, fullName)
-					// Spare en AI con nombre para depurar
-					ListAI = append(ListAI, fmt.Sprintf("%s(%s)", spares.AI, varName))
-				} else {
-					ListAI = append(ListAI, fullName)
-					// Spare en AO con nombre para depurar
-					ListAO = append(ListAO, fmt.Sprintf("%s(%s)", spares.AO, varName))
-				}
+			// 2. DIGITALES
+		} else if strings.Contains(varType, "LA") || strings.Contains(varType, "BOOL") {
 
-				// 2. DIGITALES
-			} else if strings.Contains(varType, "LA") || strings.Contains(varType, "BOOL") {
+			i := digitalOut.Match(varName)
+			point.Rule = outputRule("digital_output_regex", i, varType)
+			place(point, i >= 0, &l.DI, &l.DO, "DI", "DO")
 
-				isOutput := isMatchRegex(varName, rules.DigitalRegex)
+		} else if varType == "AO" {
+			point.Rule = "type:AO"
+			place(point, true, &l.AI, &l.AO, "AI", "AO")
+		} else if varType == "DO" {
+			point.Rule = "type:DO"
+			place(point, true, &l.DI, &l.DO, "DI", "DO")
+		} else {
+			report.Unknown[varType]++
+		}
+	})
+	if err == nil {
+		err = spareErr
+	}
+	report.Asymmetries = checkMirrorSymmetry(l, asymmetric)
+	report.asymmetric = asymmetric
+	return l, report, err
+}
 
-				if isOutput {
-					ListDO = append(ListDO, fullName)
-					ListDI = append(ListDI, fmt.Sprintf("%s(%s)", spares.DI, varName))
-				} else {
-					ListDI = append(ListDI, fullName)
-					ListDO = append(ListDO, fmt.Sprintf("%s(%s)", spares.DO, varName))
-				}
+// listSection describe una sección *LIST de __lists.ini.
+type listSection struct {
+	Name, Code, Title string
+	Items             []Point
+}
 
-			} else if varType == "AO" {
-				ListAO = append(ListAO, fullName)
-				ListAI = append(ListAI, fmt.Sprintf("%s(%s)", spares.AI, varName))
-			} else if varType == "DO" {
-				ListDO = append(ListDO, fullName)
-				ListDI = append(ListDI, fmt.Sprintf("%s(%s)", spares.DI, varName))
+func listSections(l *Lists) []listSection {
+	sections := []listSection{
+		{"AI", ListCodeAI, listTitle("AI", "ENTRADAS ANALOGICAS DNP"), l.AI},
+		{"AO", ListCodeAO, listTitle("AO", "SALIDAS ANALOGICAS DNP"), l.AO},
+		{"DI", ListCodeDI, listTitle("DI", "ENTRADAS DIGITALES DNP"), l.DI},
+		{"DO", ListCodeDO, listTitle("DO", "SALIDAS DIGITALES DNP"), l.DO},
+	}
+	if cfg := GlobalConfig.App.Classification.Strings; cfg.enabled() || len(l.OS) > 0 {
+		sections = append(sections, listSection{"OS", cfg.code(), listTitle("OS", stringTitle(cfg)), l.OS})
+	}
+	for _, e := range l.Extra {
+		sections = append(sections, listSection{e.Name, e.Code, e.Title, e.Items})
+	}
+	if len(l.disabled) > 0 {
+		enabled := sections[:0]
+		for _, s := range sections {
+			if !l.disabled[s.Name] {
+				enabled = append(enabled, s)
 			}
 		}
+		sections = enabled
 	}
-	return scanner.Err()
+	return orderSections(sections, GlobalConfig.App.Output.Order)
 }
 
-func generateListsFile() error {
-	file, err := os.Create(ListFile)
-	if err != nil {
-		return err
+// orderSections pone primero las listas de app.output.order, en ese orden;
+// las demás siguen en el orden por defecto.
+func orderSections(sections []listSection, order []string) []listSection {
+	if len(order) == 0 {
+		return sections
 	}
-	defer file.Close()
-	w := bufio.NewWriter(file)
+	out := make([]listSection, 0, len(sections))
+	used := make([]bool, len(sections))
+	for _, name := range order {
+		for i, s := range sections {
+			if !used[i] && (strings.EqualFold(s.Name, name) || s.Code == name) {
+				out, used[i] = append(out, s), true
+			}
+		}
+	}
+	for i, s := range sections {
+		if !used[i] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func renderSection(w *bytes.Buffer, s listSection) {
+	fmt.Fprintf(w, "*LIST %s   '%s'\n", s.Code, s.Title)
+	for _, item := range s.Items {
+		w.WriteString(item.Name)
+		w.WriteByte('\n')
+	}
+	w.WriteByte('\n')
+}
+
+// listHeaderPrefix identifica la cabecera opcional de los ficheros de listas.
+// readListsFile ignora todo lo anterior al primer *LIST.
+const listHeaderPrefix = "; dnpgen "
+
+func withListHeader(content []byte) []byte {
+	if !GlobalConfig.App.Output.Header {
+		return content
+	}
+	return append([]byte("; "+generatorLine()+"\n"), content...)
+}
+
+func stripListHeader(content []byte) []byte {
+	if bytes.HasPrefix(content, []byte(listHeaderPrefix)) {
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			return content[i+1:]
+		}
+	}
+	return content
+}
+
+// renderLists produce el contenido de __lists.ini.
+func renderLists(l *Lists) []byte {
+	var w bytes.Buffer
+	size := 0
+	for _, s := range listSections(l) {
+		size += 48
+		for _, item := range s.Items {
+			size += len(item.Name) + 1
+		}
+	}
+	w.Grow(size)
+	for _, s := range listSections(l) {
+		renderSection(&w, s)
+	}
+	return w.Bytes()
+}
+
+// OutputConfig controla dónde y con qué nombre se escriben las listas: el
+// __lists.ini combinado, un fichero por lista o ambos.
+type OutputConfig struct {
+	Dir        string            `yaml:"dir"`      // vacío = recurso RTU; admite {project} y {node}
+	File       string            `yaml:"file"`     // vacío = __lists.ini
+	Combined   *bool             `yaml:"combined"` // por defecto true
+	Split      bool              `yaml:"split"`
+	SplitFiles map[string]string `yaml:"split_files"` // AI/AO/DI/DO -> nombre
+	Order      []string          `yaml:"order"`       // orden de las secciones *LIST (nombre o código)
+	Header     bool              `yaml:"header"`      // línea "; dnpgen <versión>" al inicio
+	Manifest   bool              `yaml:"manifest"`    // <nodo>.manifest.json con versión y hashes
+	RoundTrip  bool              `yaml:"roundtrip"`   // releer __lists.ini tras escribirlo y compararlo
+	// EffectiveConfig escribe <nodo>.effective-config.yaml con la
+	// configuración aplicada en la ejecución.
+	EffectiveConfig bool `yaml:"effective_config"`
+}
+
+func (o OutputConfig) fileName() string {
+	if o.File != "" {
+		return o.File
+	}
+	return ListFile
+}
+
+func (o OutputConfig) combined() bool {
+	return o.Combined == nil || *o.Combined
+}
+
+// splitFileName devuelve el nombre del fichero de una lista; por defecto
+// __list_<LISTA>.ini.
+func (o OutputConfig) splitFileName(list string) string {
+	for k, v := range o.SplitFiles {
+		if strings.EqualFold(k, list) && v != "" {
+			return v
+		}
+	}
+	return "__list_" + list + ".ini"
+}
 
-	write := func(code, title string, items []string) {
-		fmt.Fprintf(w, "*LIST %s   '%s'\n", code, title)
-		for _, item := range items {
-			fmt.Fprintln(w, item)
+type listOutput struct {
+	path    string
+	content []byte
+	list    string // lista de un fichero por lista; vacío en el combinado
+}
+
+// listOutputs calcula los ficheros de listas a escribir en dir según la
+// configuración de salida. combined es el contenido ya renderizado de
+// __lists.ini.
+func listOutputs(dir string, l *Lists, combined []byte) []listOutput {
+	cfg := GlobalConfig.App.Output
+	var out []listOutput
+	if cfg.combined() {
+		out = append(out, listOutput{filepath.Join(dir, cfg.fileName()), combined, ""})
+	}
+	if cfg.Split {
+		for _, s := range listSections(l) {
+			var w bytes.Buffer
+			renderSection(&w, s)
+			out = append(out, listOutput{filepath.Join(dir, cfg.splitFileName(s.Name)), withListHeader(w.Bytes()), s.Name})
 		}
-		fmt.Fprintln(w, "")
 	}
+	return out
+}
 
-	write("32761", "ENTRADAS ANALOGICAS DNP", ListAI)
-	write("32762", "SALIDAS ANALOGICAS DNP", ListAO)
-	write("32763", "ENTRADAS DIGITALES DNP", ListDI)
-	write("32764", "SALIDAS DIGITALES DNP", ListDO)
+// readListsFile lee un __lists.ini ya generado y reconstruye sus listas.
+// Las secciones con códigos desconocidos se ignoran.
+func readListsFile(path string) (*Lists, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-	return w.Flush()
+	l := &Lists{AI: []Point{}, AO: []Point{}, DI: []Point{}, DO: []Point{}}
+	l.Extra = newCustomLists(GlobalConfig.App.Classification.ExtraLists)
+	var current *[]Point
+	// Las listas básicas sin sección son listas desactivadas.
+	l.disabled = map[string]bool{"AI": true, "AO": true, "DI": true, "DO": true}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "*LIST") {
+			current = nil
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			for name, code := range map[string]string{"AI": ListCodeAI, "AO": ListCodeAO, "DI": ListCodeDI, "DO": ListCodeDO} {
+				if fields[1] == code {
+					delete(l.disabled, name)
+				}
+			}
+			switch fields[1] {
+			case ListCodeAI:
+				current = &l.AI
+			case ListCodeAO:
+				current = &l.AO
+			case ListCodeDI:
+				current = &l.DI
+			case ListCodeDO:
+				current = &l.DO
+			case GlobalConfig.App.Classification.Strings.code():
+				current = &l.OS
+			default:
+				for i := range l.Extra {
+					if l.Extra[i].Code == fields[1] {
+						current = &l.Extra[i].Items
+					}
+				}
+			}
+			continue
+		}
+		if current != nil {
+			*current = append(*current, pointFromLine(line))
+		}
+	}
+	return l, scanner.Err()
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// --- MANIFIESTO DE EJECUCIÓN ---
+
+// runManifest registra qué binario generó qué ficheros y a partir de qué
+// .SIG, para poder rastrear un __lists.ini defectuoso hasta su origen.
+type runManifest struct {
+	Generator   BuildInfo      `json:"generator"`
+	Project     string         `json:"project"`
+	Node        string         `json:"node"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Sig         manifestFile   `json:"sig"`
+	SigVersion  string         `json:"sig_version"` // dialecto del .SIG (sigdialect.go)
+	Outputs     []manifestFile `json:"outputs"`
+	Counts      map[string]int `json:"counts"`
+	StaleSig    bool           `json:"stale_sig,omitempty"`
+	// RulesVersion y RulesHash identifican el conjunto de reglas
+	// (app.rules_version).
+	RulesVersion string          `json:"rules_version,omitempty"`
+	RulesHash    string          `json:"rules_hash"`
+	Signer       *manifestSigner `json:"signer,omitempty"`
+}
+
+type manifestFile struct {
+	Path    string    `json:"path"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+func describeFile(path string) manifestFile {
+	mf := manifestFile{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mf
+	}
+	sum := sha256.Sum256(data)
+	mf.SHA256, mf.Size = hex.EncodeToString(sum[:]), int64(len(data))
+	if st, err := os.Stat(path); err == nil {
+		mf.ModTime = st.ModTime().UTC()
+	}
+	return mf
+}
+
+// writeManifest escribe <nodo>.manifest.json en outDir y devuelve su ruta.
+// Con signer el manifiesto identifica la clave con la que se va a firmar.
+func writeManifest(absProjectPath, outDir, node string, res *GenerateResult, signer *manifestSigner) (string, error) {
+	m := runManifest{
+		Generator:   buildInfo(),
+		Project:     absProjectPath,
+		Node:        node,
+		GeneratedAt: stampNow(),
+		Sig:         describeFile(res.SigFile),
+		SigVersion:  res.SigVersion,
+		Counts:      map[string]int{"DI": res.DI, "DO": res.DO, "AI": res.AI, "AO": res.AO},
+		StaleSig:    res.StaleSig,
+		Signer:      signer,
+
+		RulesVersion: GlobalConfig.App.RulesVersion,
+		RulesHash:    rulesHash(),
+	}
+	if res.ListFile != "" {
+		m.Outputs = append(m.Outputs, describeFile(res.ListFile))
+	}
+	for _, a := range res.Artifacts {
+		m.Outputs = append(m.Outputs, describeFile(a))
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(outDir, node+".manifest.json")
+	return path, writeFileAtomic(path, append(data, '\n'))
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dnp3converter/golden"
+)
+
+// --- FUSIÓN A TRES BANDAS DE LAS LISTAS ---
+//
+// Si __lists.ini se editó a mano después de la última generación, regenerar
+// lo sobrescribe y se pierden las ediciones. Con app.merge cada generación
+// guarda lo que generó (<salida>/.dnpgen/<nodo>.base.ini) y la siguiente
+// fusiona, lista a lista, el fichero existente (editado a mano) con lo
+// nuevo, tomando esa copia como base común. Los cambios de un solo lado se
+// aplican sin más; solo hay conflicto si los dos lados cambian las mismas
+// entradas de forma distinta, y entonces se pregunta en consola o se
+// resuelve según app.merge.conflicts.
+
+// MergeConfig controla la fusión de las ediciones a mano.
+type MergeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Conflicts decide los conflictos: ask (preguntar en consola; sin
+	// consola falla la generación), ours (la edición a mano), theirs (lo
+	// generado), both (las dos) o fail.
+	Conflicts string `yaml:"conflicts"`
+}
+
+// Resoluciones de un conflicto de fusión.
+const (
+	MergeAsk    = "ask"
+	MergeOurs   = "ours"
+	MergeTheirs = "theirs"
+	MergeBoth   = "both" // la edición a mano y después lo generado que falte
+	MergeFail   = "fail"
+)
+
+func (c MergeConfig) validate() error {
+	switch c.Conflicts {
+	case "", MergeAsk, MergeOurs, MergeTheirs, MergeBoth, MergeFail:
+		return nil
+	}
+	return fmt.Errorf(tr("merge.conflicts desconocido %q (ask, ours, theirs, both o fail)"), c.Conflicts)
+}
+
+func (c MergeConfig) policy() string {
+	if c.Conflicts == "" {
+		return MergeAsk
+	}
+	return c.Conflicts
+}
+
+// MergeConflict es un tramo de una lista que cambiaron a la vez la edición a
+// mano y la generación.
+type MergeConflict struct {
+	List       string   `json:"list"`
+	Index      int      `json:"index"` // primera entrada del tramo en la base
+	Base       []string `json:"base"`
+	Ours       []string `json:"ours"`   // fichero editado a mano
+	Theirs     []string `json:"theirs"` // generado a partir del .SIG
+	Resolution string   `json:"resolution"`
+}
+
+// MergeReport resume la fusión de una generación.
+type MergeReport struct {
+	Lists     []string        `json:"lists"` // listas con ediciones a mano
+	Conflicts []MergeConflict `json:"conflicts,omitempty"`
+}
+
+func mergeBasePath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".base.ini")
+}
+
+// mergeResolver decide un conflicto (lo pone el modo consola).
+type mergeResolver func(c *MergeConflict) (string, error)
+
+// mergeHandEdits fusiona en l las ediciones a mano de los ficheros de listas
+// existentes. Devuelve el informe (nil si no había ediciones) y la nueva
+// base: lo generado, con las listas conservadas por -only tal como estaban
+// en la base anterior.
+func mergeHandEdits(l *Lists, outDir, node string, partial *partialRegen, resolve mergeResolver) (*MergeReport, []byte, error) {
+	prev, err := os.ReadFile(mergeBasePath(outDir, node))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, codedErrorf(ErrStateInvalid, tr("base de la fusión: %v"), err)
+	}
+	baseBlocks := listBlocks(prev)
+
+	var base bytes.Buffer
+	for _, s := range listSections(l) {
+		if block, ok := baseBlocks[s.Code]; ok && partial.kept(s.Name) {
+			base.Write(block)
+			continue
+		}
+		renderSection(&base, s)
+	}
+	if prev == nil {
+		return nil, base.Bytes(), nil
+	}
+
+	cfg := GlobalConfig.App.Output
+	files := map[string][]byte{}
+	existing := func(path string) []byte {
+		if content, ok := files[path]; ok {
+			return content
+		}
+		content, _ := os.ReadFile(path)
+		files[path] = content
+		return content
+	}
+
+	var rep *MergeReport
+	for _, s := range listSections(l) {
+		if partial.kept(s.Name) {
+			continue
+		}
+		path := filepath.Join(outDir, cfg.fileName())
+		if !cfg.combined() {
+			path = filepath.Join(outDir, cfg.splitFileName(s.Name))
+		}
+		oursBlock, ok := listBlocks(existing(path))[s.Code]
+		if !ok {
+			continue
+		}
+		baseBlock, ok := baseBlocks[s.Code]
+		if !ok {
+			continue
+		}
+		baseLines, ours := blockItems(baseBlock), blockItems(oursBlock)
+		if equalLines(baseLines, ours) {
+			continue
+		}
+		if rep == nil {
+			rep = &MergeReport{}
+		}
+		rep.Lists = append(rep.Lists, s.Name)
+		theirs := make([]string, len(s.Items))
+		for i, p := range s.Items {
+			theirs[i] = p.Name
+		}
+
+		var merged []string
+		for _, chunk := range merge3(s.Name, baseLines, ours, theirs) {
+			if chunk.conflict == nil {
+				merged = append(merged, chunk.lines...)
+				continue
+			}
+			c := chunk.conflict
+			if err := resolveConflict(c, resolve); err != nil {
+				return nil, nil, err
+			}
+			merged = append(merged, c.resolved()...)
+			rep.Conflicts = append(rep.Conflicts, *c)
+		}
+		l.setItems(s.Name, pointsForLines(merged, s.Items))
+	}
+	return rep, base.Bytes(), nil
+}
+
+// blockItems devuelve las entradas de un bloque *LIST (sin la cabecera ni
+// las líneas en blanco).
+func blockItems(block []byte) []string {
+	var out []string
+	for i, line := range strings.Split(string(block), "\n") {
+		if line = strings.TrimSpace(line); i > 0 && line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// pointsForLines reconstruye los puntos de una lista fusionada: las
+// entradas generadas conservan su punto (tipo, escalado, área...); las
+// añadidas a mano se interpretan como en readListsFile.
+func pointsForLines(lines []string, generated []Point) []Point {
+	byName := map[string][]Point{}
+	for _, p := range generated {
+		byName[p.Name] = append(byName[p.Name], p)
+	}
+	out := make([]Point, 0, len(lines))
+	for _, line := range lines {
+		if ps := byName[line]; len(ps) > 0 {
+			out, byName[line] = append(out, ps[0]), ps[1:]
+			continue
+		}
+		out = append(out, pointFromLine(line))
+	}
+	return out
+}
+
+func resolveConflict(c *MergeConflict, resolve mergeResolver) error {
+	policy := GlobalConfig.App.Merge.policy()
+	switch {
+	case policy == MergeOurs || policy == MergeTheirs || policy == MergeBoth:
+		c.Resolution = policy
+		return nil
+	case policy == MergeAsk && resolve != nil:
+		r, err := resolve(c)
+		if err != nil {
+			return err
+		}
+		c.Resolution = r
+		return nil
+	}
+	return codedErrorf(ErrMergeConflict, tr("%s, entrada %d: la edición a mano (%s) y la generación (%s) cambian las mismas líneas"),
+		c.List, c.Index, describeLines(c.Ours), describeLines(c.Theirs))
+}
+
+// resolved son las líneas que quedan en la lista según la resolución.
+func (c *MergeConflict) resolved() []string {
+	switch c.Resolution {
+	case MergeTheirs:
+		return c.Theirs
+	case MergeBoth:
+		out := append([]string(nil), c.Ours...)
+		for _, line := range c.Theirs {
+			if !containsLine(c.Ours, line) {
+				out = append(out, line)
+			}
+		}
+		return out
+	}
+	return c.Ours
+}
+
+func describeLines(lines []string) string {
+	if len(lines) == 0 {
+		return tr("(nada)")
+	}
+	return strings.Join(lines, " ")
+}
+
+// mergeChunk es un tramo de la fusión: líneas ya decididas o un conflicto.
+type mergeChunk struct {
+	lines    []string
+	conflict *MergeConflict
+}
+
+// merge3 fusiona ours y theirs tomando base como antecesor común (diff3 por
+// líneas): recorre los tramos estables, iguales en las tres versiones, y
+// entre ellos se queda con el lado que cambió; si cambiaron los dos, y no
+// igual, el tramo es un conflicto.
+func merge3(list string, base, ours, theirs []string) []mergeChunk {
+	mo, mt := golden.MatchLines(base, ours), golden.MatchLines(base, theirs)
+	var out []mergeChunk
+	add := func(lines []string) {
+		if len(lines) > 0 {
+			out = append(out, mergeChunk{lines: lines})
+		}
+	}
+	i, a, b := 0, 0, 0
+	for {
+		k := 0
+		for i+k < len(base) && mo[i+k] == a+k && mt[i+k] == b+k {
+			k++
+		}
+		if k > 0 {
+			add(base[i : i+k])
+			i, a, b = i+k, a+k, b+k
+			continue
+		}
+		// Siguiente línea de la base que conservan los dos lados.
+		j := i
+		for j < len(base) && (mo[j] < 0 || mt[j] < 0) {
+			j++
+		}
+		ea, eb := len(ours), len(theirs)
+		if j < len(base) {
+			ea, eb = mo[j], mt[j]
+		}
+		if i == j && a == ea && b == eb {
+			return out
+		}
+		o, t, orig := ours[a:ea], theirs[b:eb], base[i:j]
+		switch {
+		case equalLines(o, orig):
+			add(t)
+		case equalLines(t, orig), equalLines(o, t):
+			add(o)
+		default:
+			out = append(out, mergeChunk{conflict: &MergeConflict{List: list, Index: i, Base: orig, Ours: o, Theirs: t}})
+		}
+		i, a, b = j, ea, eb
+	}
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// writeMergeBase guarda lo generado como base de la próxima fusión.
+func writeMergeBase(outDir, node string, base []byte) error {
+	path := mergeBasePath(outDir, node)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, base)
+}
+
+// promptMergeConflict pregunta en consola cómo resolver un conflicto.
+func promptMergeConflict(c *MergeConflict) (string, error) {
+	fmt.Fprintln(os.Stderr, boldText(trf("\nConflicto en %s, entrada %d:", c.List, c.Index)))
+	show := func(title string, lines []string, color func(string) string) {
+		fmt.Fprintf(os.Stderr, "  %s\n", title)
+		if len(lines) == 0 {
+			fmt.Fprintf(os.Stderr, "      %s\n", tr("(nada)"))
+		}
+		for _, line := range lines {
+			fmt.Fprintf(os.Stderr, "      %s\n", color(line))
+		}
+	}
+	plain := func(s string) string { return s }
+	show(tr("base (última generación):"), c.Base, plain)
+	show(tr("editado a mano:"), c.Ours, warnText)
+	show(tr("generado del .SIG:"), c.Theirs, okText)
+	for {
+		switch strings.ToLower(promptLine(tr("¿Conservar [e]dición, [g]enerado o [a]mbos? "))) {
+		case "e":
+			return MergeOurs, nil
+		case "g":
+			return MergeTheirs, nil
+		case "a", "b":
+			return MergeBoth, nil
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- MÉTRICAS POR EJECUCIÓN ---
+
+// MetricsConfig activa un fichero de métricas por ejecución para los paneles
+// de ingeniería: textfile de Prometheus (node_exporter) o JSON.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Format  string `yaml:"format"` // prometheus (por defecto) o json
+	// Path admite {project}, {output} y {node}. Vacío = {output}/{node}.metrics.prom
+	// (o .metrics.json).
+	Path string `yaml:"path"`
+}
+
+// runMetrics es el contenido del fichero de métricas.
+type runMetrics struct {
+	Node      string           `json:"node"`
+	Timestamp time.Time        `json:"timestamp"`
+	Signals   int              `json:"signals"`
+	Lists     []listMetrics    `json:"lists"`
+	Unknown   map[string]int   `json:"unknown_types"`
+	Stages    map[string]int64 `json:"stage_ms"`
+	Drift     bool             `json:"drift"`
+	Issues    int              `json:"name_issues"`
+	SigErrors int              `json:"sig_diagnostics"`
+}
+
+type listMetrics struct {
+	List       string  `json:"list"`
+	Points     int     `json:"points"`
+	Spares     int     `json:"spares"`
+	SpareRatio float64 `json:"spare_ratio"`
+}
+
+func collectMetrics(node string, res *GenerateResult, rep *sigReport) runMetrics {
+	m := runMetrics{
+		Node:      node,
+		Timestamp: stampNow(),
+		Signals:   rep.Signals,
+		Unknown:   rep.Unknown,
+		Stages:    map[string]int64{},
+		Drift:     res.Drift,
+		Issues:    len(res.NameIssues),
+		SigErrors: len(res.SigDiagnostics),
+	}
+	for _, s := range listSections(res.lists) {
+		lm := listMetrics{List: s.Name, Points: len(s.Items)}
+		for _, p := range s.Items {
+			if p.Spare {
+				lm.Spares++
+			}
+		}
+		if lm.Points > 0 {
+			lm.SpareRatio = float64(lm.Spares) / float64(lm.Points)
+		}
+		m.Lists = append(m.Lists, lm)
+	}
+	for _, t := range res.Timings {
+		m.Stages[t.Stage] = t.Millis
+	}
+	return m
+}
+
+// writeRunMetrics escribe las métricas de la ejecución y devuelve la ruta.
+func writeRunMetrics(absProjectPath, outDir, node string, res *GenerateResult, rep *sigReport) (string, error) {
+	cfg := GlobalConfig.App.Metrics
+	format := strings.ToLower(cfg.Format)
+	if format == "" {
+		format = "prometheus"
+	}
+	ext := ".metrics.prom"
+	switch format {
+	case "prometheus":
+	case "json":
+		ext = ".metrics.json"
+	default:
+		return "", fmt.Errorf("formato de métricas desconocido %q", cfg.Format)
+	}
+	path := filepath.Join(outDir, node+ext)
+	if cfg.Path != "" {
+		path = layoutPath(absProjectPath, cfg.Path, map[string]string{"project": absProjectPath, "output": outDir, "node": node})
+	}
+
+	m := collectMetrics(node, res, rep)
+	var content []byte
+	if format == "json" {
+		var err error
+		if content, err = json.MarshalIndent(m, "", "  "); err != nil {
+			return "", err
+		}
+		content = append(content, '\n')
+	} else {
+		content = []byte(prometheusText(m))
+	}
+	// node_exporter lee el directorio en cualquier momento: escritura atómica.
+	return path, writeFileAtomic(path, content)
+}
+
+func prometheusText(m runMetrics) string {
+	var b strings.Builder
+	node := promLabel(m.Node)
+	metric := func(name, help, typ string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	metric("dnpgen_points", "Entradas por lista DNP3 (spares incluidos).", "gauge")
+	for _, l := range m.Lists {
+		fmt.Fprintf(&b, "dnpgen_points{node=%s,list=%q} %d\n", node, l.List, l.Points)
+	}
+	metric("dnpgen_spares", "Spares por lista DNP3.", "gauge")
+	for _, l := range m.Lists {
+		fmt.Fprintf(&b, "dnpgen_spares{node=%s,list=%q} %d\n", node, l.List, l.Spares)
+	}
+	metric("dnpgen_spare_ratio", "Proporción de spares por lista (0-1).", "gauge")
+	for _, l := range m.Lists {
+		fmt.Fprintf(&b, "dnpgen_spare_ratio{node=%s,list=%q} %g\n", node, l.List, l.SpareRatio)
+	}
+	metric("dnpgen_signals", "Señales leídas del .SIG.", "gauge")
+	fmt.Fprintf(&b, "dnpgen_signals{node=%s} %d\n", node, m.Signals)
+
+	metric("dnpgen_unknown_signals", "Señales del .SIG sin lista, por tipo.", "gauge")
+	types := make([]string, 0, len(m.Unknown))
+	for t := range m.Unknown {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Fprintf(&b, "dnpgen_unknown_signals{node=%s,type=%s} %d\n", node, promLabel(t), m.Unknown[t])
+	}
+
+	metric("dnpgen_stage_duration_seconds", "Duración de cada etapa del pipeline.", "gauge")
+	for _, s := range pipelineStages {
+		if ms, ok := m.Stages[s]; ok {
+			fmt.Fprintf(&b, "dnpgen_stage_duration_seconds{node=%s,stage=%q} %g\n", node, s, float64(ms)/1000)
+		}
+	}
+	metric("dnpgen_name_issues", "Nombres de punto fuera de norma.", "gauge")
+	fmt.Fprintf(&b, "dnpgen_name_issues{node=%s} %d\n", node, m.Issues)
+	metric("dnpgen_sig_diagnostics", "Líneas del .SIG ignoradas.", "gauge")
+	fmt.Fprintf(&b, "dnpgen_sig_diagnostics{node=%s} %d\n", node, m.SigErrors)
+	metric("dnpgen_drift", "1 si el fichero de listas previo no coincidía con el SIG.", "gauge")
+	drift := 0
+	if m.Drift {
+		drift = 1
+	}
+	fmt.Fprintf(&b, "dnpgen_drift{node=%s} %d\n", node, drift)
+	metric("dnpgen_last_run_timestamp_seconds", "Hora de la última generación.", "gauge")
+	fmt.Fprintf(&b, "dnpgen_last_run_timestamp_seconds{node=%s} %d\n", node, m.Timestamp.Unix())
+	return b.String()
+}
+
+// promLabel entrecomilla un valor de etiqueta según el formato de texto de
+// Prometheus.
+func promLabel(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
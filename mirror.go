@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// --- ESTRATEGIAS DE ESPEJO ---
+
+// MirrorStrategy indica qué se escribe en la lista opuesta (entrada/salida)
+// a la que recibe cada punto.
+type MirrorStrategy string
+
+const (
+	MirrorSpare       MirrorStrategy = "spare"        // spare en la lista opuesta (convención original)
+	MirrorBoth        MirrorStrategy = "mirror"       // el mismo punto en ambas listas
+	MirrorSpareInput  MirrorStrategy = "spare-input"  // spare solo en la lista de entradas (para salidas)
+	MirrorSpareOutput MirrorStrategy = "spare-output" // spare solo en la lista de salidas (para entradas)
+	MirrorNone        MirrorStrategy = "none"         // nada: las listas no quedan alineadas
+)
+
+// MirrorRule aplica una estrategia a las variables cuyo nombre cumple Pattern.
+type MirrorRule struct {
+	Pattern string         `yaml:"pattern"`
+	Mirror  MirrorStrategy `yaml:"mirror"`
+}
+
+type mirrorRules struct {
+	def        MirrorStrategy
+	matcher    *ruleMatcher
+	strategies []MirrorStrategy // por índice de regla
+}
+
+// mirrorStrategies son todas las estrategias válidas; el esquema de la
+// configuración sale de aquí.
+var mirrorStrategies = []MirrorStrategy{MirrorSpare, MirrorBoth, MirrorSpareInput, MirrorSpareOutput, MirrorNone}
+
+func validMirrorStrategy(s MirrorStrategy) bool {
+	return slices.Contains(mirrorStrategies, s)
+}
+
+// compileMirrorRules valida la configuración de espejo. Sin estrategia por
+// defecto se mantiene la convención original (spare).
+func compileMirrorRules(def MirrorStrategy, rules []MirrorRule) (*mirrorRules, error) {
+	if def == "" {
+		def = MirrorSpare
+	}
+	if !validMirrorStrategy(def) {
+		return nil, fmt.Errorf("estrategia de espejo desconocida %q", def)
+	}
+	m := &mirrorRules{def: def}
+	var patterns []string
+	for _, r := range rules {
+		if !validMirrorStrategy(r.Mirror) {
+			return nil, fmt.Errorf("estrategia de espejo desconocida %q (patrón %q)", r.Mirror, r.Pattern)
+		}
+		patterns = append(patterns, r.Pattern)
+		m.strategies = append(m.strategies, r.Mirror)
+	}
+	var err error
+	if m.matcher, err = compileRules(patterns, false); err != nil {
+		return nil, fmt.Errorf("espejo: %v", err)
+	}
+	return m, nil
+}
+
+// strategyFor devuelve la estrategia de la primera regla que cumple la
+// variable, o la estrategia por defecto.
+func (m *mirrorRules) strategyFor(varName string) MirrorStrategy {
+	if i := m.matcher.Match(varName); i >= 0 {
+		return m.strategies[i]
+	}
+	return m.def
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- MODELO DE PUNTOS (EXPORT-MODEL) ---
+
+// ModelSchema versiona el formato del modelo. Un cambio incompatible (campo
+// renombrado o con otro significado) sube la versión; añadir campos no.
+const ModelSchema = "dnpgen.model/v1"
+
+// PointModel es el modelo de puntos clasificado de un nodo, completo y
+// autocontenido: el formato de intercambio para otras herramientas.
+type PointModel struct {
+	Schema      string         `json:"schema" yaml:"schema"`
+	Generator   string         `json:"generator" yaml:"generator"`
+	GeneratedAt time.Time      `json:"generated_at" yaml:"generated_at"`
+	Project     string         `json:"project,omitempty" yaml:"project,omitempty"`
+	Node        string         `json:"node" yaml:"node"`
+	Sig         *manifestFile  `json:"sig,omitempty" yaml:"sig,omitempty"`
+	Counts      map[string]int `json:"counts" yaml:"counts"`
+	Lists       []ModelList    `json:"lists" yaml:"lists"`
+	// Anonymized marca los modelos exportados con -anonymize.
+	Anonymized bool `json:"anonymized,omitempty" yaml:"anonymized,omitempty"`
+}
+
+// ModelList es una sección *LIST con sus puntos en orden de índice.
+type ModelList struct {
+	Name   string       `json:"name" yaml:"name"`
+	Code   string       `json:"code" yaml:"code"`
+	Title  string       `json:"title" yaml:"title"`
+	Points []ModelPoint `json:"points" yaml:"points"`
+}
+
+// ModelPoint es un punto con su índice DNP3, sus metadatos y la regla que lo
+// llevó a la lista.
+type ModelPoint struct {
+	Index    int         `json:"index" yaml:"index"`
+	Name     string      `json:"name" yaml:"name"`
+	Var      string      `json:"var,omitempty" yaml:"var,omitempty"`
+	Type     string      `json:"type,omitempty" yaml:"type,omitempty"`
+	Desc     string      `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Spare    bool        `json:"spare,omitempty" yaml:"spare,omitempty"`
+	Reserved bool        `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+	System   bool        `json:"system,omitempty" yaml:"system,omitempty"`
+	SOE      bool        `json:"soe,omitempty" yaml:"soe,omitempty"`
+	AOS      bool        `json:"aos,omitempty" yaml:"aos,omitempty"`
+	Area     string      `json:"area,omitempty" yaml:"area,omitempty"`
+	Alarm    string      `json:"alarm,omitempty" yaml:"alarm,omitempty"`
+	Scaling  *Scaling    `json:"scaling,omitempty" yaml:"scaling,omitempty"`
+	Object   *DNP3Object `json:"object,omitempty" yaml:"object,omitempty"`
+	Rule     string      `json:"rule,omitempty" yaml:"rule,omitempty"`
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"model", ".model.json", func(w io.Writer, ctx ExportContext) error {
+		return writeModel(w, buildModel(ctx), "json")
+	}})
+}
+
+// pointRule es la procedencia de un punto; los que no vienen del .SIG
+// (huecos, reservas, puntos de sistema) se nombran por su origen.
+func pointRule(p Point) string {
+	switch {
+	case p.Rule != "":
+		return p.Rule
+	case p.System:
+		return "system_points"
+	case p.Reserved:
+		return "reserved"
+	case p.Spare:
+		return "spare"
+	}
+	return ""
+}
+
+func buildModel(ctx ExportContext) *PointModel {
+	m := &PointModel{
+		Schema:      ModelSchema,
+		Generator:   generatorLine(),
+		GeneratedAt: stampNow(),
+		Project:     ctx.Project,
+		Node:        ctx.Node,
+		Counts:      map[string]int{},
+		Anonymized:  ctx.Anonymized,
+	}
+	if ctx.Sig != "" && fileExists(ctx.Sig) {
+		sig := describeFile(ctx.Sig)
+		m.Sig = &sig
+	}
+	for _, s := range listSections(ctx.Lists) {
+		ml := ModelList{Name: s.Name, Code: s.Code, Title: s.Title, Points: make([]ModelPoint, 0, len(s.Items))}
+		for i, p := range s.Items {
+			ml.Points = append(ml.Points, ModelPoint{
+				Index: i, Name: p.Name, Var: p.Var, Type: p.Type, Desc: p.Desc,
+				Spare: p.Spare, Reserved: p.Reserved, System: p.System, SOE: p.SOE, AOS: p.AOS,
+				Area: p.Area, Alarm: p.Alarm, Scaling: p.Scaling, Object: p.Object, Rule: pointRule(p),
+			})
+		}
+		m.Counts[s.Name] = len(s.Items)
+		m.Lists = append(m.Lists, ml)
+	}
+	return m
+}
+
+// writeModel serializa el modelo en json o yaml.
+func writeModel(w io.Writer, m *PointModel, format string) error {
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "json", "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+	return fmt.Errorf(tr("formato %q no soportado (json, yaml)"), format)
+}
+
+// runExportModel implementa "dnpgen export-model": genera el modelo de un
+// nodo sin escribir listas ni exportaciones.
+func runExportModel(args []string) {
+	fs := flag.NewFlagSet("export-model", flag.ExitOnError)
+	fs.Usage = manUsage("export-model", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto o copia .zip"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo"))
+	skipExt := fs.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	format := fs.String("format", "", tr("Formato del modelo: json o yaml (por defecto, según la extensión de -o)"))
+	out := fs.String("o", "", tr("Fichero de salida (vacío = salida estándar)"))
+	anonymize := fs.Bool("anonymize", false, tr("Sustituir variables, descripciones, áreas y nodo por hashes (app.anonymize)"))
+	fs.Parse(args)
+
+	if *projectPath == "" || *nodeName == "" {
+		log.Fatal(tr("Uso: dnpgen.exe export-model -path \"C:\\Ruta\" -node \"NombreNodo\" [-o modelo.json|modelo.yaml] [-anonymize]"))
+	}
+	if *format == "" {
+		*format = "json"
+		if ext := strings.ToLower(filepath.Ext(*out)); ext == ".yaml" || ext == ".yml" {
+			*format = "yaml"
+		}
+	}
+
+	res, err := runGenerate(GenerateRequest{ProjectPath: *projectPath, NodeName: *nodeName, SkipExt: *skipExt, CheckOnly: true})
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	abs, _ := filepath.Abs(*projectPath)
+	ctx := ExportContext{Project: abs, Node: *nodeName, Lists: res.lists, Sig: res.SigFile}
+	if *anonymize {
+		if ctx, err = anonymizeContext(ctx, GlobalConfig.App.Anonymize.Salt); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	m := buildModel(ctx)
+
+	if *out == "" {
+		if err := writeModel(os.Stdout, m, *format); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		return
+	}
+	var b strings.Builder
+	if err := writeModel(&b, m, *format); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if err := writeFileAtomic(*out, []byte(b.String())); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	log.Printf(tr("Modelo de %s escrito en %s"), *nodeName, *out)
+}
+
+// --- RENDER DESDE UN MODELO ---
+//
+// "dnpgen render -model modelo.json" regenera exportaciones (y, con -write-lists,
+// los ficheros de listas) desde un modelo guardado con export-model, sin
+// SIGEXT ni .SIG: sirve para iterar sobre formatos y plantillas. Códigos y
+// títulos de AI/AO/DI/DO/OS salen de la configuración actual, como al
+// generar; los de las listas adicionales, del propio modelo.
+
+// readModel lee un modelo json o yaml (según la extensión) y comprueba su
+// esquema.
+func readModel(path string) (*PointModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m PointModel
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &m)
+	default:
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+	if m.Schema != ModelSchema {
+		return nil, fmt.Errorf(tr("%s: esquema %q no soportado (se espera %s)"), filepath.Base(path), m.Schema, ModelSchema)
+	}
+	if m.Node == "" {
+		return nil, fmt.Errorf(tr("%s: el modelo no indica el nodo"), filepath.Base(path))
+	}
+	return &m, nil
+}
+
+// modelLists reconstruye las listas de un modelo. Los índices de cada lista
+// deben ser consecutivos desde 0: un hueco desplazaría todos los siguientes.
+func modelLists(m *PointModel) (*Lists, error) {
+	l := &Lists{AI: []Point{}, AO: []Point{}, DI: []Point{}, DO: []Point{}}
+	// Las listas básicas que no están en el modelo estaban desactivadas.
+	l.disabled = map[string]bool{"AI": true, "AO": true, "DI": true, "DO": true}
+	for _, ml := range m.Lists {
+		delete(l.disabled, ml.Name)
+		points := append([]ModelPoint{}, ml.Points...)
+		sort.SliceStable(points, func(a, b int) bool { return points[a].Index < points[b].Index })
+		items := make([]Point, len(points))
+		for i, p := range points {
+			if p.Index != i {
+				return nil, fmt.Errorf(tr("lista %s: falta el índice %d"), ml.Name, i)
+			}
+			items[i] = Point{
+				Name: p.Name, Var: p.Var, Type: p.Type, Desc: p.Desc,
+				Spare: p.Spare, Reserved: p.Reserved, System: p.System, SOE: p.SOE, AOS: p.AOS,
+				Area: p.Area, Alarm: p.Alarm, Scaling: p.Scaling, Object: p.Object, Rule: p.Rule,
+			}
+		}
+		switch ml.Name {
+		case "AI":
+			l.AI = items
+		case "AO":
+			l.AO = items
+		case "DI":
+			l.DI = items
+		case "DO":
+			l.DO = items
+		case "OS":
+			l.OS = items
+		default:
+			l.Extra = append(l.Extra, CustomList{Name: ml.Name, Code: ml.Code, Title: ml.Title, Items: items})
+		}
+	}
+	return l, nil
+}
+
+// runRender implementa "dnpgen render".
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	fs.Usage = manUsage("render", fs)
+	modelPath := fs.String("model", "", tr("Modelo guardado con export-model (json o yaml)"))
+	outDir := fs.String("out", "", tr("Directorio de salida (por defecto, el del modelo)"))
+	exports := fs.String("exports", "", tr("Exportadores separados por comas (por defecto app.exports y los protocolos)"))
+	withLists := fs.Bool("write-lists", false, tr("Escribir también los ficheros de listas"))
+	anonymize := fs.Bool("anonymize", false, tr("Sustituir variables, descripciones, áreas y nodo por hashes (app.anonymize)"))
+	fs.Parse(args)
+
+	if *modelPath == "" {
+		log.Fatal(tr("Uso: dnpgen.exe render -model modelo.json [-out DIR] [-exports csv,html] [-write-lists] [-anonymize]"))
+	}
+	m, err := readModel(*modelPath)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	lists, err := modelLists(m)
+	if err != nil {
+		log.Fatalf("[FATAL] %s: %v", filepath.Base(*modelPath), err)
+	}
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Dir(*modelPath)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	ctx := ExportContext{Project: m.Project, Node: m.Node, Lists: lists, Anonymized: m.Anonymized}
+	if *anonymize && !m.Anonymized {
+		if ctx, err = anonymizeContext(ctx, GlobalConfig.App.Anonymize.Salt); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+
+	var written []string
+	if *withLists {
+		for _, o := range listOutputs(dir, ctx.Lists, withListHeader(renderLists(ctx.Lists))) {
+			log.Printf(tr("Generando %s..."), filepath.Base(o.path))
+			if err := os.WriteFile(o.path, o.content, 0o644); err != nil {
+				log.Fatalf(tr("[FATAL] Error escribiendo %s: %v"), o.path, err)
+			}
+			written = append(written, o.path)
+		}
+	}
+	var exported []string
+	if *exports == "" {
+		exported, err = writeExports(dir, ctx)
+	} else {
+		var names []string
+		for _, n := range strings.Split(*exports, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+		exported, err = writeNamedExports(dir, ctx, names)
+	}
+	if err != nil {
+		log.Fatalf(tr("[FATAL] Error exportando: %v"), err)
+	}
+	written = append(written, exported...)
+	log.Printf(tr("%s: %d fichero(s) generados desde %s"), m.Node, len(written), filepath.Base(*modelPath))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// --- VALIDACIÓN DE NOMBRES DE PUNTO ---
+
+// NameRules refleja las limitaciones del firmware de la RTU sobre los nombres
+// de variable: longitud máxima y juego de caracteres admitido.
+type NameRules struct {
+	MaxLength    int    `yaml:"max_length"`    // 0 = sin límite
+	AllowedChars string `yaml:"allowed_chars"` // clase de caracteres regex, p.ej. "A-Za-z0-9_"
+	// Action es warn (solo informa), error (aborta la generación) o sanitize
+	// (sustituye los caracteres no válidos y recorta al máximo). warn y error
+	// son la severidad por defecto de los hallazgos name_length y name_chars.
+	Action      string `yaml:"action"`
+	Replacement string `yaml:"replacement"` // por defecto "_"
+}
+
+// NameIssue es un nombre que no cumple las reglas.
+type NameIssue struct {
+	List      string `json:"list"`
+	Index     int    `json:"index"`
+	Var       string `json:"var"`
+	Problem   string `json:"problem"`
+	Sanitized string `json:"sanitized,omitempty"`
+
+	badChars, tooLong bool // tipos de hallazgo (name_chars, name_length)
+}
+
+func (i NameIssue) String() string {
+	s := fmt.Sprintf("%s[%d] %s: %s", i.List, i.Index, i.Var, i.Problem)
+	if i.Sanitized != "" {
+		s += " -> " + i.Sanitized
+	}
+	return s
+}
+
+// checkPointNames valida las variables reales (los spares no) de las listas.
+// Con action sanitize los puntos se renombran en l; el mismo nombre corregido
+// se usa en todas las listas donde aparece la variable.
+func checkPointNames(l *Lists, rules NameRules) ([]NameIssue, error) {
+	if rules.MaxLength <= 0 && rules.AllowedChars == "" {
+		return nil, nil
+	}
+	var invalid *regexp.Regexp
+	if rules.AllowedChars != "" {
+		var err error
+		if invalid, err = regexp.Compile("[^" + rules.AllowedChars + "]"); err != nil {
+			return nil, fmt.Errorf("allowed_chars inválido %q: %v", rules.AllowedChars, err)
+		}
+	}
+	action := strings.ToLower(rules.Action)
+	switch action {
+	case "":
+		action = "warn"
+	case "warn", "error", "sanitize":
+	default:
+		return nil, fmt.Errorf("acción de nombres desconocida %q", rules.Action)
+	}
+	repl := rules.Replacement
+	if repl == "" {
+		repl = "_"
+	}
+	if action == "sanitize" && invalid != nil && invalid.MatchString(repl) {
+		return nil, fmt.Errorf("el reemplazo %q no cumple allowed_chars", repl)
+	}
+
+	existing := map[string]bool{}
+	for _, section := range listSections(l) {
+		for _, p := range section.Items {
+			if !p.Spare {
+				existing[p.Var] = true
+			}
+		}
+	}
+
+	var issues []NameIssue
+	owner := map[string]string{} // corregido -> original
+	for _, section := range listSections(l) {
+		for idx := range section.Items {
+			p := &section.Items[idx]
+			if p.Spare {
+				continue
+			}
+			var problems []string
+			fixed := p.Var
+			if invalid != nil && invalid.MatchString(p.Var) {
+				problems = append(problems, tr("caracteres no permitidos"))
+				fixed = invalid.ReplaceAllString(fixed, repl)
+			}
+			if rules.MaxLength > 0 && len(p.Var) > rules.MaxLength {
+				problems = append(problems, fmt.Sprintf(tr("%d caracteres (máx. %d)"), len(p.Var), rules.MaxLength))
+				fixed = fixed[:min(len(fixed), rules.MaxLength)]
+			}
+			if len(problems) == 0 {
+				continue
+			}
+			issue := NameIssue{List: section.Name, Index: idx, Var: p.Var, Problem: strings.Join(problems, ", "),
+				badChars: invalid != nil && invalid.MatchString(p.Var),
+				tooLong:  rules.MaxLength > 0 && len(p.Var) > rules.MaxLength}
+			if action == "sanitize" {
+				if prev, ok := owner[fixed]; ok && prev != p.Var {
+					return issues, fmt.Errorf("%s y %s quedan como %s tras corregir", prev, p.Var, fixed)
+				}
+				if existing[fixed] {
+					return issues, fmt.Errorf("%s queda como %s, que ya existe", p.Var, fixed)
+				}
+				owner[fixed] = p.Var
+				issue.Sanitized = fixed
+				p.Name = strings.Replace(p.Name, p.Var, fixed, 1)
+				p.Var = fixed
+			}
+			issues = append(issues, issue)
+		}
+	}
+	// Con action error la generación falla al evaluar los hallazgos.
+	return issues, nil
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- CONVENCIONES DE NOMBRES ---
+//
+// La deriva de los nombres de punto entre proyectos es el mayor problema de
+// mantenimiento a largo plazo. app.naming define linters sobre las
+// variables (prefijos obligatorios, caracteres prohibidos, estructura por
+// segmentos como AREA_EQUIP_SIGNAL o una regex completa) que se evalúan en
+// cada generación como hallazgos "naming" y con "dnpgen rules names" para
+// todos los nodos de un proyecto. Los linters propios se registran en código
+// con RegisterNameLinter, como los validadores.
+
+// NameLinter comprueba el nombre de un punto real (sin spares ni puntos de
+// sistema). Cada problema devuelto es una infracción.
+type NameLinter interface {
+	Name() string
+	Lint(list string, p Point) []string
+}
+
+var nameLinterRegistry []NameLinter
+
+// RegisterNameLinter añade un linter de nombres; se evalúan en orden de
+// registro y antes que los de app.naming.
+func RegisterNameLinter(l NameLinter) {
+	for _, r := range nameLinterRegistry {
+		if strings.EqualFold(r.Name(), l.Name()) {
+			panic("linter de nombres duplicado: " + l.Name())
+		}
+	}
+	nameLinterRegistry = append(nameLinterRegistry, l)
+}
+
+// NamingRule es un linter de app.naming. Las comprobaciones vacías no se
+// aplican; una regla puede combinar varias.
+type NamingRule struct {
+	Name    string   `yaml:"name"`
+	Lists   []string `yaml:"lists"`   // vacío = todas
+	Exclude string   `yaml:"exclude"` // regex de variables exentas
+	// Prefixes son los prefijos admitidos: la variable tiene que empezar por
+	// alguno.
+	Prefixes  []string `yaml:"prefixes"`
+	Forbidden string   `yaml:"forbidden"` // caracteres prohibidos, p.ej. "-. "
+	Pattern   string   `yaml:"pattern"`   // regex que tiene que cumplir la variable
+	// Structure es la estructura por segmentos, p.ej. "AREA_EQUIP_SIGNAL":
+	// la variable necesita al menos tantas partes como segmentos (el último
+	// se queda con el resto) y cada parte cumple la regex de Segments.
+	Structure string            `yaml:"structure"`
+	Separator string            `yaml:"separator"` // "_" por defecto
+	Segments  map[string]string `yaml:"segments"`
+}
+
+// NamingConfig son los linters de app.naming.
+type NamingConfig struct {
+	Rules []NamingRule `yaml:"rules"`
+}
+
+// ruleLinter es una NamingRule compilada.
+type ruleLinter struct {
+	name             string
+	lists            map[string]bool
+	exclude, pattern *regexp.Regexp
+	prefixes         []string
+	forbidden        string
+	separator        string
+	segments         []string
+	segmentRes       []*regexp.Regexp // por segmento; nil = sin restricción
+}
+
+func (r *ruleLinter) Name() string { return r.name }
+
+func (r *ruleLinter) Lint(list string, p Point) []string {
+	if len(r.lists) > 0 && !r.lists[list] {
+		return nil
+	}
+	v := p.Var
+	if r.exclude != nil && r.exclude.MatchString(v) {
+		return nil
+	}
+	var problems []string
+	if len(r.prefixes) > 0 {
+		ok := false
+		for _, prefix := range r.prefixes {
+			ok = ok || strings.HasPrefix(v, prefix)
+		}
+		if !ok {
+			problems = append(problems, trf("no empieza por %s", strings.Join(r.prefixes, ", ")))
+		}
+	}
+	if i := strings.IndexAny(v, r.forbidden); r.forbidden != "" && i >= 0 {
+		problems = append(problems, trf("carácter prohibido %q", v[i]))
+	}
+	if r.pattern != nil && !r.pattern.MatchString(v) {
+		problems = append(problems, trf("no cumple %s", r.pattern))
+	}
+	if len(r.segments) > 0 {
+		parts := strings.SplitN(v, r.separator, len(r.segments))
+		if len(parts) < len(r.segments) {
+			problems = append(problems, trf("%d segmento(s) de los %d de %s", len(parts), len(r.segments), strings.Join(r.segments, r.separator)))
+		} else {
+			for i, re := range r.segmentRes {
+				if re != nil && !re.MatchString(parts[i]) {
+					problems = append(problems, trf("segmento %s %q no cumple %s", r.segments[i], parts[i], re))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// compileNaming prepara los linters de app.naming.
+func compileNaming(cfg NamingConfig) ([]NameLinter, error) {
+	var out []NameLinter
+	for i, rule := range cfg.Rules {
+		label := "naming.rules[" + strconv.Itoa(i) + "]"
+		r := &ruleLinter{name: rule.Name, prefixes: rule.Prefixes, forbidden: rule.Forbidden, separator: rule.Separator}
+		if r.name == "" {
+			r.name = label
+		}
+		if len(rule.Lists) > 0 {
+			r.lists = map[string]bool{}
+			for _, l := range rule.Lists {
+				r.lists[strings.ToUpper(l)] = true
+			}
+		}
+		var err error
+		for _, re := range []struct {
+			expr string
+			dst  **regexp.Regexp
+		}{{rule.Exclude, &r.exclude}, {rule.Pattern, &r.pattern}} {
+			if re.expr == "" {
+				continue
+			}
+			if *re.dst, err = regexp.Compile(re.expr); err != nil {
+				return nil, fmt.Errorf("%s: %v", label, err)
+			}
+		}
+		if rule.Structure != "" {
+			if r.separator == "" {
+				r.separator = "_"
+			}
+			r.segments = strings.Split(rule.Structure, r.separator)
+			for _, seg := range r.segments {
+				var re *regexp.Regexp
+				if expr := rule.Segments[seg]; expr != "" {
+					if re, err = regexp.Compile(expr); err != nil {
+						return nil, fmt.Errorf("%s.segments.%s: %v", label, seg, err)
+					}
+				}
+				r.segmentRes = append(r.segmentRes, re)
+			}
+			for seg := range rule.Segments {
+				if !strings.Contains(r.separator+rule.Structure+r.separator, r.separator+seg+r.separator) {
+					return nil, fmt.Errorf(tr("%s: el segmento %s no está en structure %q"), label, seg, rule.Structure)
+				}
+			}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// NameViolation es un nombre que incumple un linter.
+type NameViolation struct {
+	Linter  string `json:"linter"`
+	List    string `json:"list"`
+	Index   int    `json:"index"`
+	Var     string `json:"var"`
+	Problem string `json:"problem"`
+}
+
+func (v NameViolation) String() string {
+	return fmt.Sprintf("%s[%d] %s: %s (%s)", v.List, v.Index, v.Var, v.Problem, v.Linter)
+}
+
+// lintNames evalúa los linters registrados y los de app.naming sobre los
+// puntos reales. Cada variable se informa una vez por linter, en su primera
+// aparición.
+func lintNames(l *Lists) ([]NameViolation, error) {
+	configured, err := compileNaming(GlobalConfig.App.Naming)
+	if err != nil {
+		return nil, err
+	}
+	linters := append(append([]NameLinter{}, nameLinterRegistry...), configured...)
+	if len(linters) == 0 {
+		return nil, nil
+	}
+	var out []NameViolation
+	seen := map[string]bool{}
+	for _, sec := range listSections(l) {
+		for idx, p := range sec.Items {
+			if p.Spare || p.System {
+				continue
+			}
+			for _, linter := range linters {
+				key := linter.Name() + "\x00" + p.Var
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				for _, problem := range linter.Lint(sec.Name, p) {
+					out = append(out, NameViolation{Linter: linter.Name(), List: sec.Name, Index: idx, Var: p.Var, Problem: problem})
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// runRulesNames implementa "dnpgen rules names": informe de infracciones de
+// nombres por nodo, a partir del .SIG existente (sin SIGEXT).
+func runRulesNames(args []string) {
+	fs := flag.NewFlagSet("rules names", flag.ExitOnError)
+	fs.Usage = manUsage("rules names", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (vacío = todos los del proyecto)"))
+	format := fs.String("format", "text", tr("Formato del informe: text o csv"))
+	strict := fs.Bool("strict", false, tr("Salir con error si hay infracciones"))
+	fs.Parse(args)
+
+	if *projectPath == "" {
+		log.Fatal(tr("Uso: dnpgen.exe rules names -path \"C:\\Ruta\" [-node \"NombreNodo\"] [-format text|csv]"))
+	}
+	abs, err := filepath.Abs(*projectPath)
+	if err != nil {
+		log.Fatalf(tr("Error ruta absoluta: %v"), err)
+	}
+	nodes := []string{*nodeName}
+	if *nodeName == "" {
+		infos, err := listProjectNodes(abs)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		nodes = nodes[:0]
+		for _, n := range infos {
+			if n.Sig != "" {
+				nodes = append(nodes, n.Node)
+			}
+		}
+	}
+
+	byNode := map[string][]NameViolation{}
+	total := 0
+	for _, node := range nodes {
+		lists, _, err := processSigFile(nodePathsFor(abs, node).Sig, nil)
+		if err != nil {
+			log.Printf("[ERROR] %s: %v", node, err)
+			continue
+		}
+		v, err := lintNames(lists)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		byNode[node] = v
+		total += len(v)
+	}
+
+	switch *format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"node", "linter", "list", "index", "var", "problem"})
+		for _, node := range nodes {
+			for _, v := range byNode[node] {
+				w.Write([]string{node, v.Linter, v.List, strconv.Itoa(v.Index), v.Var, v.Problem})
+			}
+		}
+		w.Flush()
+	case "text":
+		printNameViolations(nodes, byNode)
+	default:
+		log.Fatalf(tr("formato %q no soportado (text, csv)"), *format)
+	}
+	if total > 0 && *strict {
+		os.Exit(1)
+	}
+}
+
+func printNameViolations(nodes []string, byNode map[string][]NameViolation) {
+	total := 0
+	for _, node := range nodes {
+		violations, ok := byNode[node]
+		if !ok {
+			continue
+		}
+		total += len(violations)
+		if len(violations) == 0 {
+			fmt.Println(okText(trf("%s: sin infracciones", node)))
+			continue
+		}
+		perLinter := map[string]int{}
+		for _, v := range violations {
+			perLinter[v.Linter]++
+		}
+		linters := make([]string, 0, len(perLinter))
+		for l := range perLinter {
+			linters = append(linters, l+"="+strconv.Itoa(perLinter[l]))
+		}
+		sort.Strings(linters)
+		fmt.Println(boldText(trf("\n%s: %d infracción(es) (%s)", node, len(violations), strings.Join(linters, ", "))))
+		var rows [][]string
+		for _, v := range violations {
+			rows = append(rows, []string{v.List, strconv.Itoa(v.Index), v.Var, v.Linter, v.Problem})
+		}
+		printTable(tr("LISTA\tÍNDICE\tVARIABLE\tLINTER\tPROBLEMA"), rows, nil)
+	}
+	if total > 0 {
+		fmt.Println(warnText(trf("\n%d infracción(es) de nombres en %d nodo(s)", total, len(byNode))))
+	}
+}
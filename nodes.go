@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// --- LISTADO DE NODOS ---
+
+// nodeInfo resume el estado de un nodo del proyecto.
+type nodeInfo struct {
+	Node    string     `json:"node"`
+	Mwt     string     `json:"mwt,omitempty"`
+	Sig     string     `json:"sig,omitempty"`
+	SigTime *time.Time `json:"sig_time,omitempty"`
+	Lists   string     `json:"lists,omitempty"`
+}
+
+func runNodes(args []string) {
+	fs := flag.NewFlagSet("nodes", flag.ExitOnError)
+	fs.Usage = manUsage("nodes", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	asJSON := fs.Bool("json", false, tr("Salida en JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" {
+		log.Fatal(tr("Uso: dnpgen.exe nodes -path \"C:\\Ruta\" [-json]"))
+	}
+	abs, err := filepath.Abs(*projectPath)
+	if err != nil {
+		log.Fatalf(tr("Error ruta absoluta: %v"), err)
+	}
+
+	nodes, err := listProjectNodes(abs)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(nodes)
+		return
+	}
+	if len(nodes) == 0 {
+		fmt.Println(tr("No se encontraron nodos (.mwt ni .SIG)"))
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("NODO\tMWT\tÚLTIMA EXTRACCIÓN SIG\tLISTAS"))
+	for _, n := range nodes {
+		mwt, sig, lists := tr("no"), "-", tr("no")
+		if n.Mwt != "" {
+			mwt = tr("sí")
+		}
+		if n.SigTime != nil {
+			sig = formatStamp(*n.SigTime)
+		}
+		if n.Lists != "" {
+			lists = tr("sí")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", n.Node, mwt, sig, lists)
+	}
+	tw.Flush()
+}
+
+// listProjectNodes reúne los nodos que tienen .mwt o .SIG según la
+// estructura configurada.
+func listProjectNodes(absProjectPath string) ([]nodeInfo, error) {
+	sources := []func(string) string{
+		func(node string) string { return nodePathsFor(absProjectPath, node).Sig },
+	}
+	if GlobalConfig.App.Layout.MwtFile != "" {
+		sources = append(sources, func(node string) string { return nodePathsFor(absProjectPath, node).Mwt })
+	} else {
+		sources = append(sources,
+			func(node string) string { return filepath.Join(absProjectPath, node+".mwt") },
+			func(node string) string { return filepath.Join(resourceDirFor(absProjectPath, node), node+".mwt") })
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	var firstErr error
+	readable := 0
+	for _, src := range sources {
+		found, err := matchNodeFiles(src)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		readable++
+		for _, n := range found {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	if readable == 0 {
+		return nil, firstErr
+	}
+	sort.Strings(names)
+
+	var out []nodeInfo
+	for _, n := range names {
+		p := nodePathsFor(absProjectPath, n)
+		info := nodeInfo{Node: n}
+		if _, err := os.Stat(p.Mwt); err == nil {
+			info.Mwt = p.Mwt
+		}
+		if st, err := os.Stat(p.Sig); err == nil {
+			t := st.ModTime()
+			info.Sig, info.SigTime = p.Sig, &t
+		}
+		if lists := listsPathFor(absProjectPath, n); fileExists(lists) {
+			info.Lists = lists
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// --- NOTIFICACIONES (WEBHOOK / TEAMS / SLACK) ---
+
+// NotifyConfig configura los avisos enviados al terminar cada generación.
+type NotifyConfig struct {
+	// ArtifactBaseURL es la URL pública del modo servidor; si está definida,
+	// los avisos incluyen el enlace a /artifacts/{id}.
+	ArtifactBaseURL string         `yaml:"artifact_base_url"`
+	Targets         []NotifyTarget `yaml:"targets"`
+}
+
+// NotifyTarget es un destino de notificación.
+type NotifyTarget struct {
+	Type string   `yaml:"type"` // webhook, teams, slack
+	URL  string   `yaml:"url"`  // admite ${VAR} para no dejar el token en config.yaml
+	On   []string `yaml:"on"`   // success, failure, alarm (vacío = todos)
+}
+
+func (t NotifyTarget) url() string {
+	return os.ExpandEnv(t.URL)
+}
+
+// NotifyEvent es el resultado de una generación, tal como se envía a los
+// webhooks genéricos.
+type NotifyEvent struct {
+	Project     string          `json:"project"`
+	Node        string          `json:"node"`
+	Success     bool            `json:"success"`
+	Error       string          `json:"error,omitempty"`
+	ErrorCode   string          `json:"error_code,omitempty"`
+	JobID       string          `json:"job_id,omitempty"`
+	ArtifactURL string          `json:"artifact_url,omitempty"`
+	Result      *GenerateResult `json:"result,omitempty"`
+	Time        time.Time       `json:"time"`
+}
+
+var notifyClient = &http.Client{Timeout: 10 * time.Second}
+
+// notifyResult envía el aviso de una generación a todos los destinos
+// configurados. Los fallos de envío solo se registran en el log.
+func notifyResult(req GenerateRequest, jobID string, res *GenerateResult, genErr error) {
+	cfg := GlobalConfig.App.Notifications
+	if len(cfg.Targets) == 0 {
+		return
+	}
+	ev := NotifyEvent{
+		Project: req.ProjectPath,
+		Node:    req.NodeName,
+		Success: genErr == nil,
+		JobID:   jobID,
+		Result:  res,
+		Time:    time.Now(),
+	}
+	if genErr != nil {
+		ev.Error, ev.ErrorCode = genErr.Error(), errorCode(genErr)
+	}
+	if jobID != "" && cfg.ArtifactBaseURL != "" && genErr == nil {
+		ev.ArtifactURL = strings.TrimRight(cfg.ArtifactBaseURL, "/") + "/artifacts/" + jobID
+	}
+
+	outcome := "failure"
+	if ev.Success {
+		outcome = "success"
+	}
+	alarm := res != nil && len(res.CountAlarms) > 0
+	for _, t := range cfg.Targets {
+		if len(t.On) > 0 && !containsFold(t.On, outcome) && !(alarm && containsFold(t.On, "alarm")) {
+			continue
+		}
+		if err := sendNotification(t, ev); err != nil {
+			// La URL de un webhook es en sí una credencial: no sale en el log.
+			log.Printf("[WARN] Notificación %s (%s): %v", t.Type, redactURL(t.url()), err)
+		}
+	}
+}
+
+func sendNotification(t NotifyTarget, ev NotifyEvent) error {
+	var payload any
+	switch strings.ToLower(t.Type) {
+	case "", "webhook":
+		payload = ev
+	case "slack":
+		payload = map[string]string{"text": notifyText(ev, "*", "<%s|Descargar artefacto>")}
+	case "teams":
+		color := "2EB886"
+		if !ev.Success {
+			color = "D00000"
+		}
+		payload = map[string]any{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    notifyTitle(ev),
+			"themeColor": color,
+			"title":      notifyTitle(ev),
+			"text":       notifyText(ev, "**", "[Descargar artefacto](%s)"),
+		}
+	default:
+		return fmt.Errorf("tipo desconocido %q", t.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := notifyClient.Post(t.url(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		// El error de net/http repite la URL completa.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			return ue.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("respuesta HTTP %s", resp.Status)
+	}
+	return nil
+}
+
+func notifyTitle(ev NotifyEvent) string {
+	if ev.Success {
+		return fmt.Sprintf("Generación OK: %s", ev.Node)
+	}
+	return fmt.Sprintf("Generación FALLIDA: %s", ev.Node)
+}
+
+// notifyText arma el cuerpo del mensaje de chat; bold y linkFmt adaptan el
+// marcado a cada plataforma.
+func notifyText(ev NotifyEvent, bold, linkFmt string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s\n", bold, notifyTitle(ev), bold)
+	fmt.Fprintf(&b, "Proyecto: %s\n", ev.Project)
+	if ev.Success && ev.Result != nil {
+		r := ev.Result
+		fmt.Fprintf(&b, "DI: %d | DO: %d | AI: %d | AO: %d\n", r.DI, r.DO, r.AI, r.AO)
+		if r.Drift {
+			b.WriteString("Deriva: el __lists.ini anterior no coincidía con el SIG\n")
+		}
+		if len(r.CountAlarms) > 0 {
+			fmt.Fprintf(&b, "%sCaída brusca de puntos%s: %s (¿.SIG incompleto?)\n", bold, bold, formatCountAlarms(r.CountAlarms))
+		}
+	}
+	if ev.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", ev.Error)
+	}
+	if ev.ArtifactURL != "" {
+		fmt.Fprintf(&b, linkFmt+"\n", ev.ArtifactURL)
+	}
+	return b.String()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendNotificationExpandsAndHidesURL(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}))
+	t.Setenv("DNPGEN_TEST_WEBHOOK", srv.URL+"/hook/secreto")
+	target := NotifyTarget{Type: "webhook", URL: "${DNPGEN_TEST_WEBHOOK}"}
+	if err := sendNotification(target, NotifyEvent{Node: "N1", Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/hook/secreto" {
+		t.Errorf("path = %q", path)
+	}
+
+	srv.Close()
+	err := sendNotification(target, NotifyEvent{Node: "N1"})
+	if err == nil || strings.Contains(err.Error(), "secreto") {
+		t.Errorf("error con el servidor caído: %v", err)
+	}
+	if s := redactURL(target.url()); strings.Contains(s, "secreto") {
+		t.Errorf("redactURL = %q", s)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- PUNTOS DE CADENA DE OCTETOS (OS) ---
+
+// ListCodeOS es el código *LIST por defecto de las cadenas de octetos
+// (grupo 110), que los firmwares recientes usan para textos de
+// identificación del equipo.
+const ListCodeOS = "32765"
+
+// StringConfig lleva a la lista OS las señales cuyo tipo contiene alguna de
+// las subcadenas de Types o cuyo nombre cumple Patterns. Sin ninguno de los
+// dos la lista no existe y __lists.ini no cambia.
+type StringConfig struct {
+	Types    []string `yaml:"types"`
+	Patterns []string `yaml:"patterns"`
+	Code     string   `yaml:"code"`   // por defecto 32765
+	Title    string   `yaml:"title"`  // por defecto CADENAS DE OCTETOS DNP
+	Length   int      `yaml:"length"` // longitud máxima declarada en las exportaciones
+}
+
+func (c StringConfig) enabled() bool {
+	return len(c.Types) > 0 || len(c.Patterns) > 0
+}
+
+func (c StringConfig) code() string {
+	if c.Code != "" {
+		return c.Code
+	}
+	return ListCodeOS
+}
+
+func (c StringConfig) length() int {
+	if c.Length > 0 {
+		return c.Length
+	}
+	return 255
+}
+
+// stringClassifier decide qué señales son cadenas de octetos.
+type stringClassifier struct {
+	types    []string
+	patterns *ruleMatcher
+}
+
+func compileStringRules(c StringConfig) (*stringClassifier, error) {
+	if !c.enabled() {
+		return nil, nil
+	}
+	if c.Length > 255 {
+		return nil, fmt.Errorf("strings: length %d fuera de rango (máximo 255)", c.Length)
+	}
+	m, err := compileRules(c.Patterns, false)
+	if err != nil {
+		return nil, fmt.Errorf("strings: %v", err)
+	}
+	return &stringClassifier{types: c.Types, patterns: m}, nil
+}
+
+func (s *stringClassifier) match(varName, varType string) bool {
+	if s == nil {
+		return false
+	}
+	for _, t := range s.types {
+		if strings.Contains(varType, t) {
+			return true
+		}
+	}
+	return s.patterns.Any(varName)
+}
+
+func stringTitle(c StringConfig) string {
+	if c.Title != "" {
+		return c.Title
+	}
+	return "CADENAS DE OCTETOS DNP"
+}
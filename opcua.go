@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// --- EXPORTACIÓN OPC UA (NodeSet2.xml) ---
+
+// OPCUAConfig configura la exportación NodeSet2.
+type OPCUAConfig struct {
+	NamespaceURI string `yaml:"namespace_uri"` // vacío = urn:dnpgen:<nodo>
+}
+
+// opcuaList describe la carpeta y el tipo de dato de cada lista.
+type opcuaList struct {
+	name, title, dataType string
+	writable              bool
+}
+
+var opcuaLists = []opcuaList{
+	{"DI", "Entradas digitales", "Boolean", false},
+	{"DO", "Salidas digitales", "Boolean", true},
+	{"AI", "Entradas analógicas", "Float", false},
+	{"AO", "Salidas analógicas", "Float", true},
+	{"OS", "Cadenas de octetos", "String", false},
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"opcua", ".NodeSet2.xml", writeNodeSet})
+}
+
+// writeNodeSet genera un NodeSet2 con una carpeta por lista y una variable
+// por punto real (los spares se omiten, el índice DNP3 va en la descripción).
+func writeNodeSet(w io.Writer, ctx ExportContext) error {
+	node, l := ctx.Node, ctx.Lists
+	uri := GlobalConfig.App.OPCUA.NamespaceURI
+	if uri == "" {
+		uri = "urn:dnpgen:" + node
+	}
+	byList := map[string][]Point{"DI": l.DI, "DO": l.DO, "AI": l.AI, "AO": l.AO, "OS": l.OS}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(xmlGeneratorComment())
+	b.WriteString(`<UANodeSet xmlns="http://opcfoundation.org/UA/2011/03/UANodeSet.xsd">` + "\n")
+	fmt.Fprintf(&b, "  <NamespaceUris>\n    <Uri>%s</Uri>\n  </NamespaceUris>\n", xmlEscape(uri))
+	b.WriteString(`  <Aliases>
+    <Alias Alias="Boolean">i=1</Alias>
+    <Alias Alias="Float">i=10</Alias>
+    <Alias Alias="String">i=12</Alias>
+    <Alias Alias="Organizes">i=35</Alias>
+    <Alias Alias="HasTypeDefinition">i=40</Alias>
+    <Alias Alias="HasComponent">i=47</Alias>
+  </Aliases>
+`)
+
+	root := "ns=1;s=" + node
+	writeFolder(&b, root, node, node, "i=85", nil)
+	for _, ol := range opcuaLists {
+		if len(byList[ol.name]) == 0 && ol.name == "OS" {
+			continue
+		}
+		folderID := root + "." + ol.name
+		var children []string
+		for idx, p := range byList[ol.name] {
+			if !p.Spare {
+				children = append(children, fmt.Sprintf("%s.%d", folderID, idx))
+			}
+		}
+		writeFolder(&b, folderID, ol.name, ol.title, root, children)
+		for idx, p := range byList[ol.name] {
+			if p.Spare {
+				continue
+			}
+			access := 1
+			if ol.writable {
+				access = 3
+			}
+			desc := fmt.Sprintf("DNP3 %s índice %d", ol.name, idx)
+			if p.Type != "" {
+				desc += " (" + p.Type + ")"
+			}
+			if p.Desc != "" {
+				desc = p.Desc + " - " + desc
+			}
+			fmt.Fprintf(&b, `  <UAVariable NodeId="%s.%d" BrowseName="1:%s" ParentNodeId="%s" DataType="%s" AccessLevel="%d" UserAccessLevel="%d">`+"\n",
+				folderID, idx, xmlEscape(p.Var), folderID, ol.dataType, access, access)
+			fmt.Fprintf(&b, "    <DisplayName>%s</DisplayName>\n", xmlEscape(p.Name))
+			fmt.Fprintf(&b, "    <Description>%s</Description>\n", xmlEscape(desc))
+			b.WriteString("    <References>\n")
+			b.WriteString(`      <Reference ReferenceType="HasTypeDefinition">i=63</Reference>` + "\n")
+			fmt.Fprintf(&b, `      <Reference ReferenceType="HasComponent" IsForward="false">%s</Reference>`+"\n", folderID)
+			b.WriteString("    </References>\n  </UAVariable>\n")
+		}
+	}
+	b.WriteString("</UANodeSet>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFolder escribe un objeto FolderType organizado bajo parent.
+func writeFolder(b *strings.Builder, id, browse, display, parent string, children []string) {
+	fmt.Fprintf(b, `  <UAObject NodeId="%s" BrowseName="1:%s">`+"\n", id, xmlEscape(browse))
+	fmt.Fprintf(b, "    <DisplayName>%s</DisplayName>\n", xmlEscape(display))
+	b.WriteString("    <References>\n")
+	b.WriteString(`      <Reference ReferenceType="HasTypeDefinition">i=61</Reference>` + "\n")
+	fmt.Fprintf(b, `      <Reference ReferenceType="Organizes" IsForward="false">%s</Reference>`+"\n", parent)
+	for _, c := range children {
+		fmt.Fprintf(b, `      <Reference ReferenceType="HasComponent">%s</Reference>`+"\n", c)
+	}
+	b.WriteString("    </References>\n  </UAObject>\n")
+}
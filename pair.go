@@ -0,0 +1,339 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// --- PAREJAS DE RTU REDUNDANTES ---
+//
+// Las dos CPU de una pareja principal/reserva ejecutan el mismo programa y
+// el SCADA las ve como una sola estación: el mapa DNP3 tiene que ser
+// idéntico. "dnpgen pair" genera las dos desde un único .SIG (el del
+// principal) aplicando las diferencias configuradas en app.redundancy:
+// variables propias de cada CPU (p.ej. diagnósticos del reserva), cuyo
+// índice ocupa un spare en la otra, y renombrados que no mueven el punto.
+// Antes de escribir nada se comprueba índice a índice que los dos mapas
+// visibles (longitudes, objeto DNP3, SOE, escalado y tipo) coinciden.
+
+// RedundancyConfig agrupa las parejas redundantes del proyecto.
+type RedundancyConfig struct {
+	Pairs []RedundancyPair `yaml:"pairs"`
+}
+
+// RedundancyPair es una pareja principal/reserva.
+type RedundancyPair struct {
+	Name    string `yaml:"name"` // vacío = el nodo principal
+	Primary string `yaml:"primary"`
+	Standby string `yaml:"standby"`
+	// PrimaryOnly y StandbyOnly son patrones (regex) de variables propias
+	// de una CPU: en la otra, su índice lo ocupa un spare.
+	PrimaryOnly []string `yaml:"primary_only"`
+	StandbyOnly []string `yaml:"standby_only"`
+	// Rename cambia en el reserva el nombre de las variables que casan, sin
+	// moverlas de índice.
+	Rename []PairRename `yaml:"rename"`
+}
+
+// PairRename sustituye un patrón en el nombre de variable ($1... admitidos).
+type PairRename struct {
+	Pattern string `yaml:"pattern"`
+	Replace string `yaml:"replace"`
+}
+
+func (p RedundancyPair) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Primary
+}
+
+// findPair busca una pareja por nombre o por cualquiera de sus nodos.
+func findPair(name string) (RedundancyPair, bool) {
+	for _, p := range GlobalConfig.App.Redundancy.Pairs {
+		if strings.EqualFold(p.name(), name) || strings.EqualFold(p.Primary, name) || strings.EqualFold(p.Standby, name) {
+			return p, true
+		}
+	}
+	return RedundancyPair{}, false
+}
+
+// pairMember indica a generate que el nodo es un miembro de una pareja: el
+// reserva lee el .SIG del principal.
+type pairMember struct {
+	pair    RedundancyPair
+	standby bool
+}
+
+// node es el nodo que se genera.
+func (m *pairMember) node() string {
+	if m.standby {
+		return m.pair.Standby
+	}
+	return m.pair.Primary
+}
+
+// apply convierte en spares las variables de la otra CPU y, en el reserva,
+// aplica los renombrados. Las posiciones no cambian.
+func (m *pairMember) apply(l *Lists) error {
+	other := m.pair.StandbyOnly
+	if m.standby {
+		other = m.pair.PrimaryOnly
+	}
+	foreign, err := compilePatterns(other)
+	if err != nil {
+		return fmt.Errorf("redundancy %s: %v", m.pair.name(), err)
+	}
+	type rename struct {
+		re      *regexp.Regexp
+		replace string
+	}
+	var renames []rename
+	if m.standby {
+		for _, r := range m.pair.Rename {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return fmt.Errorf("redundancy %s: rename %q: %v", m.pair.name(), r.Pattern, err)
+			}
+			renames = append(renames, rename{re, r.Replace})
+		}
+	}
+
+	for _, list := range []string{"AI", "AO", "DI", "DO"} {
+		items := listItems(l, list)
+		var spares *spareAllocator
+		for i, p := range items {
+			if !p.Spare && matchesAny(foreign, p.Var) {
+				if spares == nil {
+					if spares, err = newSpareAllocator(list, items); err != nil {
+						return err
+					}
+				}
+				spare, err := spares.next(p.Var, p.Type)
+				if err != nil {
+					return err
+				}
+				items[i] = spare
+				continue
+			}
+			for _, r := range renames {
+				if !r.re.MatchString(p.Var) {
+					continue
+				}
+				v := r.re.ReplaceAllString(p.Var, r.replace)
+				switch {
+				case p.Name == "@GV."+p.Var:
+					p.Name = "@GV." + v
+				case strings.HasSuffix(p.Name, "("+p.Var+")"):
+					p.Name = strings.TrimSuffix(p.Name, "("+p.Var+")") + "(" + v + ")"
+				}
+				p.Var = v
+				items[i] = p
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// listItems devuelve las entradas de una de las cuatro listas DNP3.
+func listItems(l *Lists, list string) []Point {
+	switch list {
+	case "AI":
+		return l.AI
+	case "AO":
+		return l.AO
+	case "DI":
+		return l.DI
+	}
+	return l.DO
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// pairMapDifferences compara índice a índice lo que ve el SCADA de los dos
+// nodos. Un spare frente a un punto real solo se compara en objeto DNP3: es
+// el hueco de una variable propia de la otra CPU.
+func pairMapDifferences(a, b *Lists) []string {
+	var diffs []string
+	sb := map[string]listSection{}
+	for _, s := range listSections(b) {
+		sb[s.Code] = s
+	}
+	for _, sa := range listSections(a) {
+		other, ok := sb[sa.Code]
+		if !ok {
+			diffs = append(diffs, trf("%s: solo en el principal", sa.Name))
+			continue
+		}
+		delete(sb, sa.Code)
+		if len(sa.Items) != len(other.Items) {
+			diffs = append(diffs, trf("%s: %d entradas ≠ %d", sa.Name, len(sa.Items), len(other.Items)))
+		}
+		for i := 0; i < min(len(sa.Items), len(other.Items)); i++ {
+			pa, pb := sa.Items[i], other.Items[i]
+			at := fmt.Sprintf("%s[%d]", sa.Name, i)
+			if pa.Spare == pb.Spare {
+				if pa.Type != pb.Type {
+					diffs = append(diffs, trf("%s: tipo %s ≠ %s", at, pa.Type, pb.Type))
+				}
+				if pa.SOE != pb.SOE {
+					diffs = append(diffs, trf("%s: SOE %t ≠ %t", at, pa.SOE, pb.SOE))
+				}
+				if !reflect.DeepEqual(pa.Scaling, pb.Scaling) {
+					diffs = append(diffs, trf("%s: escalado distinto", at))
+				}
+			}
+			if !reflect.DeepEqual(pa.Object, pb.Object) {
+				diffs = append(diffs, trf("%s: objeto DNP3 distinto", at))
+			}
+		}
+	}
+	for _, s := range listSections(b) {
+		if _, ok := sb[s.Code]; ok {
+			diffs = append(diffs, trf("%s: solo en el reserva", s.Name))
+		}
+	}
+	return diffs
+}
+
+// pairResult es el resultado de generar una pareja.
+type pairResult struct {
+	Pair        string          `json:"pair"`
+	Primary     *GenerateResult `json:"primary,omitempty"`
+	Standby     *GenerateResult `json:"standby,omitempty"`
+	Differences []string        `json:"differences,omitempty"`
+	Written     bool            `json:"written"`
+}
+
+// generatePair clasifica los dos nodos en memoria, compara sus mapas y, si
+// coinciden y no es solo comprobación, escribe los dos.
+func generatePair(projectPath string, p RedundancyPair, skipExt, checkOnly bool) (*pairResult, error) {
+	out := &pairResult{Pair: p.name()}
+	if p.Primary == "" || p.Standby == "" {
+		return out, codedErrorf(ErrConfigInvalid, tr("redundancy %s: faltan primary o standby"), p.name())
+	}
+	req := func(standby, check, skip bool) GenerateRequest {
+		m := &pairMember{pair: p, standby: standby}
+		return GenerateRequest{ProjectPath: projectPath, NodeName: m.node(), SkipExt: skip, CheckOnly: check, pair: m}
+	}
+	// SIGEXT solo una vez, para el principal: el reserva usa el mismo .SIG.
+	primary, err := runGenerate(req(false, true, skipExt))
+	if err != nil {
+		return out, fmt.Errorf("%s: %w", p.Primary, err)
+	}
+	standby, err := runGenerate(req(true, true, true))
+	if err != nil {
+		return out, fmt.Errorf("%s: %w", p.Standby, err)
+	}
+	out.Primary, out.Standby = primary, standby
+	if out.Differences = pairMapDifferences(primary.lists, standby.lists); len(out.Differences) > 0 {
+		return out, codedErrorf(ErrPairMismatch, tr("%s y %s no exponen el mismo mapa DNP3 (%d diferencia(s)); no se escribe ninguno"),
+			p.Primary, p.Standby, len(out.Differences))
+	}
+	if primary.ListFile != "" && primary.ListFile == standby.ListFile {
+		return out, codedErrorf(ErrConfigInvalid, tr("%s y %s escribirían el mismo %s: configure app.output.dir con {node}"), p.Primary, p.Standby, primary.ListFile)
+	}
+	if checkOnly {
+		return out, nil
+	}
+	if out.Primary, err = runGenerate(req(false, false, true)); err != nil {
+		return out, fmt.Errorf("%s: %w", p.Primary, err)
+	}
+	if out.Standby, err = runGenerate(req(true, false, true)); err != nil {
+		return out, fmt.Errorf("%s: %w", p.Standby, err)
+	}
+	out.Written = true
+	return out, nil
+}
+
+// runPair implementa "dnpgen pair -path RUTA [-pair NOMBRE] [-check]".
+func runPair(args []string) {
+	fs := flag.NewFlagSet("pair", flag.ExitOnError)
+	fs.Usage = manUsage("pair", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto o copia .zip"))
+	pairName := fs.String("pair", "", tr("Pareja de app.redundancy (nombre o nodo); vacío = todas"))
+	skipExt := fs.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	check := fs.Bool("check", false, tr("Solo comprobar que los dos mapas coinciden, sin escribir"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" {
+		log.Fatal(tr("Uso: dnpgen.exe pair -path \"C:\\Ruta\" [-pair NOMBRE] [-check] [-skip-ext]"))
+	}
+	pairs := GlobalConfig.App.Redundancy.Pairs
+	if *pairName != "" {
+		p, ok := findPair(*pairName)
+		if !ok {
+			log.Fatalf(tr("[FATAL] No hay ninguna pareja %q en app.redundancy.pairs"), *pairName)
+		}
+		pairs = []RedundancyPair{p}
+	}
+	if len(pairs) == 0 {
+		log.Fatal(tr("[FATAL] No hay parejas configuradas en app.redundancy.pairs"))
+	}
+
+	var results []*pairResult
+	failed := 0
+	for _, p := range pairs {
+		res, err := generatePair(*projectPath, p, *skipExt, *check)
+		results = append(results, res)
+		if *asJSON {
+			if err != nil {
+				failed++
+				log.Printf("[ERROR] %s: %v", p.name(), err)
+			}
+			continue
+		}
+		fmt.Println(boldText(trf("\n=== Pareja %s: %s / %s ===", p.name(), p.Primary, p.Standby)))
+		for _, d := range res.Differences {
+			fmt.Println("  " + errText(d))
+		}
+		if err != nil {
+			failed++
+			log.Printf("[ERROR] %v", err)
+			continue
+		}
+		switch {
+		case res.Written:
+			fmt.Println(okText(trf("Mapas idénticos; listas generadas: %s y %s", res.Primary.ListFile, res.Standby.ListFile)))
+		default:
+			fmt.Println(okText(trf("%s y %s exponen el mismo mapa DNP3", p.Primary, p.Standby)))
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
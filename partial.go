@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- REGENERACIÓN PARCIAL ---
+//
+// En las campañas de recalibración de analógicas no se pueden tocar las
+// listas digitales ya validadas. Con -only ai,ao solo se regeneran las
+// listas indicadas; las demás se copian tal cual del __lists.ini (o de sus
+// ficheros por lista) existente, byte a byte, y el modelo de la generación
+// (exportaciones, recuentos, mapa de índices) usa también su contenido.
+// Regenerar solo un lado de una pareja espejo (AI/AO, DI/DO) cuando el otro
+// también ha cambiado en el .SIG desalinea sus índices: lo avisa el hallazgo
+// mirror_split.
+
+// parseOnly interpreta -only: nombres de lista separados por comas.
+func parseOnly(s string) []string {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// onlySet valida la selección contra las listas de la generación y
+// devuelve los nombres seleccionados.
+func onlySet(only []string, l *Lists) (map[string]bool, error) {
+	sections := listSections(l)
+	set := map[string]bool{}
+	for _, name := range only {
+		found := false
+		for _, s := range sections {
+			if strings.EqualFold(s.Name, name) || s.Code == name {
+				set[s.Name], found = true, true
+			}
+		}
+		if !found {
+			names := make([]string, len(sections))
+			for i, s := range sections {
+				names[i] = s.Name
+			}
+			return nil, fmt.Errorf(tr("-only: lista %q desconocida (%s)"), name, strings.Join(names, ", "))
+		}
+	}
+	return set, nil
+}
+
+// listBlocks parte el contenido de un fichero de listas en sus secciones
+// *LIST, por código, tal como están escritas (líneas en blanco incluidas).
+func listBlocks(content []byte) map[string][]byte {
+	blocks := map[string][]byte{}
+	var code string
+	var cur bytes.Buffer
+	flush := func() {
+		if code != "" {
+			blocks[code] = append([]byte(nil), cur.Bytes()...)
+		}
+		cur.Reset()
+	}
+	sc := bufio.NewScanner(bytes.NewReader(stripListHeader(content)))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "*LIST") {
+			flush()
+			code = ""
+			if fields := strings.Fields(line); len(fields) >= 2 {
+				code = fields[1]
+			}
+		}
+		cur.WriteString(line)
+		cur.WriteByte('\n')
+	}
+	flush()
+	return blocks
+}
+
+// partialRegen son las listas conservadas de una regeneración parcial.
+type partialRegen struct {
+	only    map[string]bool
+	blocks  map[string][]byte // bloque existente por código de lista
+	changed map[string]bool   // listas conservadas que se habrían generado distintas
+}
+
+// keepExistingLists aplica -only a l: cada lista no seleccionada se
+// sustituye por la del fichero existente. Falla si no hay fichero del que
+// conservarla.
+func keepExistingLists(l *Lists, only []string, outDir string) (*partialRegen, error) {
+	set, err := onlySet(only, l)
+	if err != nil {
+		return nil, err
+	}
+	cfg := GlobalConfig.App.Output
+	p := &partialRegen{only: set, blocks: map[string][]byte{}, changed: map[string]bool{}}
+	for _, s := range listSections(l) {
+		if set[s.Name] {
+			continue
+		}
+		path := filepath.Join(outDir, cfg.fileName())
+		if !cfg.combined() {
+			path = filepath.Join(outDir, cfg.splitFileName(s.Name))
+		}
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf(tr("-only: no existe %s del que conservar %s"), path, s.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		block, ok := listBlocks(content)[s.Code]
+		if !ok {
+			return nil, fmt.Errorf(tr("-only: %s no contiene la lista %s (*LIST %s)"), filepath.Base(path), s.Name, s.Code)
+		}
+		p.blocks[s.Code] = block
+		prev, err := readListsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, ps := range listSections(prev) {
+			if ps.Code == s.Code {
+				p.changed[s.Name] = !samePointNames(s.Items, ps.Items)
+				l.setItems(s.Name, ps.Items)
+			}
+		}
+	}
+	return p, nil
+}
+
+// setItems sustituye las entradas de la lista name.
+func (l *Lists) setItems(name string, items []Point) {
+	switch name {
+	case "AI":
+		l.AI = items
+	case "AO":
+		l.AO = items
+	case "DI":
+		l.DI = items
+	case "DO":
+		l.DO = items
+	case "OS":
+		l.OS = items
+	default:
+		for i := range l.Extra {
+			if l.Extra[i].Name == name {
+				l.Extra[i].Items = items
+			}
+		}
+	}
+}
+
+func samePointNames(a, b []Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// mirrorSplits describe las parejas espejo de las que solo se regenera un
+// lado mientras el conservado habría cambiado: sus índices dejan de
+// corresponderse. No se comprueban las parejas que la configuración
+// desequilibra a propósito (asymmetric, ver checkMirrorSymmetry).
+func (p *partialRegen) mirrorSplits(asymmetric map[string]bool) []string {
+	if p == nil {
+		return nil
+	}
+	var out []string
+	for _, pair := range [][2]string{{"AI", "AO"}, {"DI", "DO"}} {
+		in, o := pair[0], pair[1]
+		if asymmetric[mirrorPair(in, o)] || p.kept(in) == p.kept(o) {
+			continue
+		}
+		regen, kept := in, o
+		if p.kept(in) {
+			regen, kept = o, in
+		}
+		if p.changed[kept] {
+			out = append(out, trf("-only regenera %s pero conserva %s, que también ha cambiado en el .SIG: sus índices espejo ya no se corresponden; regenere ambas (-only %s,%s)",
+				regen, kept, strings.ToLower(in), strings.ToLower(o)))
+		}
+	}
+	return out
+}
+
+// kept indica si la lista se conserva del fichero existente.
+func (p *partialRegen) kept(name string) bool {
+	return p != nil && !p.only[name]
+}
+
+// render produce el __lists.ini combinado: las listas seleccionadas se
+// generan y las demás se copian del fichero existente.
+func (p *partialRegen) render(l *Lists) []byte {
+	var w bytes.Buffer
+	for _, s := range listSections(l) {
+		if block, ok := p.blocks[s.Code]; ok && p.kept(s.Name) {
+			w.Write(block)
+			continue
+		}
+		renderSection(&w, s)
+	}
+	return w.Bytes()
+}
+
+// outputs quita los ficheros por lista de las listas conservadas: no se
+// reescriben.
+func (p *partialRegen) outputs(outputs []listOutput) []listOutput {
+	out := outputs[:0]
+	for _, o := range outputs {
+		if o.list == "" || !p.kept(o.list) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestPartialMirrorSplits(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		only       []string
+		changed    map[string]bool
+		asymmetric map[string]bool
+		want       int
+	}{
+		{"pareja completa", []string{"AI", "AO"}, map[string]bool{}, nil, 0},
+		{"lado conservado sin cambios", []string{"AI"}, map[string]bool{"AO": false}, nil, 0},
+		{"lado conservado con cambios", []string{"AI"}, map[string]bool{"AO": true}, nil, 1},
+		{"salida regenerada", []string{"DO"}, map[string]bool{"DI": true}, nil, 1},
+		{"pareja asimétrica a propósito", []string{"AI"}, map[string]bool{"AO": true}, map[string]bool{"AI/AO": true}, 0},
+		{"otra pareja", []string{"DI", "DO"}, map[string]bool{"AI": true, "AO": true}, nil, 0},
+	} {
+		p := &partialRegen{only: map[string]bool{}, changed: c.changed}
+		for _, name := range c.only {
+			p.only[name] = true
+		}
+		if got := p.mirrorSplits(c.asymmetric); len(got) != c.want {
+			t.Errorf("%s: %q", c.name, got)
+		}
+	}
+	var none *partialRegen
+	if got := none.mirrorSplits(nil); got != nil {
+		t.Errorf("sin -only: %q", got)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// --- RUTAS UNC Y LARGAS (WINDOWS) ---
+
+// SigExtPaths adapta las rutas que se pasan a SIGEXT. Go maneja por sí solo
+// las rutas UNC y las de más de MAX_PATH (añade \\?\ al abrir ficheros),
+// pero SIGEXT es un ejecutable Win32 antiguo que no las admite: las
+// unidades mapeadas y los nombres cortos 8.3 le dan rutas que sí entiende.
+type SigExtPaths struct {
+	// Map sustituye prefijos de ruta antes de llamar a SIGEXT, p.ej. de
+	// \\servidor\proyectos a la unidad P: mapeada en la sesión del usuario.
+	Map []PathMapping `yaml:"map"`
+	// ShortNames usa el nombre corto 8.3 de las rutas que superan MAX_PATH.
+	// Por defecto true; solo tiene efecto en Windows.
+	ShortNames *bool `yaml:"short_names"`
+}
+
+// PathMapping es un prefijo de ruta y su sustituto.
+type PathMapping struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// maxPath es el límite clásico de Windows (MAX_PATH, 260 con el nulo final).
+const maxPath = 259
+
+func (c SigExtPaths) shortNames() bool {
+	return c.ShortNames == nil || *c.ShortNames
+}
+
+// child devuelve la ruta tal como debe verla SIGEXT.
+func (c SigExtPaths) child(p string) string {
+	p = c.mapped(p)
+	if len(p) > maxPath && c.shortNames() {
+		p = shortPath(p)
+	}
+	return p
+}
+
+// mapped aplica el primer prefijo de Map que coincide (sin distinguir
+// mayúsculas ni el tipo de barra, como Windows).
+func (c SigExtPaths) mapped(p string) string {
+	norm := strings.ReplaceAll(p, "/", `\`)
+	for _, m := range c.Map {
+		from := strings.TrimRight(strings.ReplaceAll(m.From, "/", `\`), `\`)
+		if from == "" || len(norm) < len(from) || !strings.EqualFold(norm[:len(from)], from) {
+			continue
+		}
+		if rest := norm[len(from):]; rest == "" || rest[0] == '\\' {
+			return strings.TrimRight(m.To, `\/`) + rest
+		}
+	}
+	return p
+}
+
+// normalizeLongPath quita el prefijo \\?\ (o \\?\UNC\) de una ruta recibida
+// por línea de comandos o configuración. Las plantillas y filepath trabajan
+// sobre la forma normal; el prefijo lo vuelve a poner Go al abrir ficheros.
+func normalizeLongPath(p string) string {
+	switch {
+	case strings.HasPrefix(p, `\\?\UNC\`):
+		return `\\` + p[len(`\\?\UNC\`):]
+	case strings.HasPrefix(p, `\\?\`):
+		return p[len(`\\?\`):]
+	}
+	return p
+}
+
+// isUNC indica si la ruta es de red (\\servidor\recurso\...).
+func isUNC(p string) bool {
+	return strings.HasPrefix(p, `\\`) || (filepath.Separator == '\\' && strings.HasPrefix(p, "//"))
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// shortPath no hace nada fuera de Windows: no hay nombres 8.3.
+func shortPath(p string) string { return p }
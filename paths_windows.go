@@ -0,0 +1,38 @@
+//go:build windows
+
+package main
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// shortPath devuelve el nombre corto 8.3 de p. Si el fichero aún no existe
+// (el .SIG que va a escribir SIGEXT) se acorta su directorio. Si el volumen
+// no tiene nombres cortos se devuelve p sin cambios.
+func shortPath(p string) string {
+	if s, ok := getShortPathName(p); ok {
+		return s
+	}
+	if s, ok := getShortPathName(filepath.Dir(p)); ok {
+		return filepath.Join(s, filepath.Base(p))
+	}
+	return p
+}
+
+func getShortPathName(p string) (string, bool) {
+	long, err := windows.UTF16PtrFromString(`\\?\` + p)
+	if isUNC(p) {
+		long, err = windows.UTF16PtrFromString(`\\?\UNC\` + p[2:])
+	}
+	if err != nil {
+		return "", false
+	}
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	n, err := windows.GetShortPathName(long, &buf[0], uint32(len(buf)))
+	if err != nil || n == 0 || int(n) > len(buf) {
+		return "", false
+	}
+	return normalizeLongPath(windows.UTF16ToString(buf[:n])), true
+}
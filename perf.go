@@ -0,0 +1,403 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// --- HISTORIAL DE RENDIMIENTO ---
+//
+// Con app.perf cada generación completa guarda la duración de sus etapas
+// (sigext, parse, classify, write, export) en la base SQLite del proyecto
+// (app.pointdb.path, aunque pointdb esté desactivada), junto con el número
+// de señales y la versión del .SIG para poder explicar el cambio. Al
+// terminar, cada etapa se compara con la mediana de las window ejecuciones
+// anteriores del nodo: si tarda más de threshold veces la mediana (y al
+// menos min_ms más) se añade un hallazgo perf_regression. Es un aviso
+// temprano: no hace fallar la generación. "dnpgen perf report" muestra la
+// misma comparación para todos los nodos.
+
+// PerfConfig configura el historial de rendimiento.
+type PerfConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	Window    int     `yaml:"window"`    // ejecuciones anteriores de referencia; 10 por defecto
+	Threshold float64 `yaml:"threshold"` // factor sobre la mediana; 2 por defecto
+	MinMs     int64   `yaml:"min_ms"`    // diferencia mínima en ms; 50 por defecto
+}
+
+func (c PerfConfig) window() int {
+	if c.Window > 0 {
+		return c.Window
+	}
+	return 10
+}
+
+func (c PerfConfig) threshold() float64 {
+	if c.Threshold > 1 {
+		return c.Threshold
+	}
+	return 2
+}
+
+func (c PerfConfig) minMs() int64 {
+	if c.MinMs > 0 {
+		return c.MinMs
+	}
+	return 50
+}
+
+// perfMinSamples es el mínimo de ejecuciones anteriores para comparar: con
+// menos, la mediana es ruido.
+const perfMinSamples = 3
+
+const perfSchema = `
+CREATE TABLE IF NOT EXISTS perf_runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	node        TEXT    NOT NULL,
+	run_at      TEXT    NOT NULL,
+	version     TEXT    NOT NULL,
+	sig_version TEXT    NOT NULL DEFAULT '',
+	signals     INTEGER NOT NULL DEFAULT 0,
+	total_ms    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS perf_runs_node ON perf_runs (node, id);
+CREATE TABLE IF NOT EXISTS perf_stages (
+	run_id INTEGER NOT NULL REFERENCES perf_runs (id),
+	stage  TEXT    NOT NULL,
+	ms     INTEGER NOT NULL,
+	PRIMARY KEY (run_id, stage)
+);
+`
+
+func openPerfDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(perfSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// perfRun es una ejecución del historial.
+type perfRun struct {
+	ID         int64            `json:"id"`
+	RunAt      string           `json:"run_at"`
+	Version    string           `json:"version"`
+	SigVersion string           `json:"sig_version,omitempty"`
+	Signals    int              `json:"signals"`
+	TotalMs    int64            `json:"total_ms"`
+	Stages     map[string]int64 `json:"stages"`
+}
+
+// recordPerfRun guarda las etapas de una ejecución.
+func recordPerfRun(db *sql.DB, node string, run perfRun) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	r, err := tx.Exec(`INSERT INTO perf_runs (node, run_at, version, sig_version, signals, total_ms) VALUES (?, ?, ?, ?, ?, ?)`,
+		node, run.RunAt, run.Version, run.SigVersion, run.Signals, run.TotalMs)
+	if err != nil {
+		return err
+	}
+	id, err := r.LastInsertId()
+	if err != nil {
+		return err
+	}
+	for stage, ms := range run.Stages {
+		if _, err := tx.Exec(`INSERT INTO perf_stages (run_id, stage, ms) VALUES (?, ?, ?)`, id, stage, ms); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// recentPerfRuns devuelve las últimas n ejecuciones del nodo, de la más
+// antigua a la más reciente.
+func recentPerfRuns(db *sql.DB, node string, n int) ([]perfRun, error) {
+	rows, err := db.Query(`SELECT id, run_at, version, sig_version, signals, total_ms FROM perf_runs WHERE node = ? ORDER BY id DESC LIMIT ?`, node, n)
+	if err != nil {
+		return nil, err
+	}
+	var runs []perfRun
+	for rows.Next() {
+		r := perfRun{Stages: map[string]int64{}}
+		if err := rows.Scan(&r.ID, &r.RunAt, &r.Version, &r.SigVersion, &r.Signals, &r.TotalMs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		stages, err := db.Query(`SELECT stage, ms FROM perf_stages WHERE run_id = ?`, runs[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for stages.Next() {
+			var stage string
+			var ms int64
+			if err := stages.Scan(&stage, &ms); err != nil {
+				stages.Close()
+				return nil, err
+			}
+			runs[i].Stages[stage] = ms
+		}
+		stages.Close()
+	}
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	return runs, nil
+}
+
+func perfNodes(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT node FROM perf_runs ORDER BY node`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var nodes []string
+	for rows.Next() {
+		var n string
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// PerfStage compara una etapa de la última ejecución con la mediana de las
+// anteriores.
+type PerfStage struct {
+	Stage      string  `json:"stage"`
+	Ms         int64   `json:"ms"`
+	MedianMs   int64   `json:"median_ms"`
+	Ratio      float64 `json:"ratio"`
+	Samples    int     `json:"samples"`
+	Regression bool    `json:"regression"`
+}
+
+func (s PerfStage) String() string {
+	if s.MedianMs == 0 {
+		return trf("%s: %dms frente a 0ms de mediana (%d ejecuciones anteriores)", s.Stage, s.Ms, s.Samples)
+	}
+	return trf("%s: %dms frente a %dms de mediana (x%.1f, %d ejecuciones anteriores)", s.Stage, s.Ms, s.MedianMs, s.Ratio, s.Samples)
+}
+
+// comparePerf compara last con las ejecuciones previous; total cuenta como
+// una etapa más.
+func comparePerf(cfg PerfConfig, previous []perfRun, last perfRun) []PerfStage {
+	stages := map[string]int64{"total": last.TotalMs}
+	for s, ms := range last.Stages {
+		stages[s] = ms
+	}
+	var out []PerfStage
+	for stage, ms := range stages {
+		var samples []int64
+		for _, r := range previous {
+			v, ok := r.Stages[stage]
+			if stage == "total" {
+				v, ok = r.TotalMs, true
+			}
+			if ok {
+				samples = append(samples, v)
+			}
+		}
+		ps := PerfStage{Stage: stage, Ms: ms, Samples: len(samples)}
+		if len(samples) > 0 {
+			sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+			ps.MedianMs = samples[len(samples)/2]
+			if len(samples)%2 == 0 {
+				ps.MedianMs = (samples[len(samples)/2-1] + samples[len(samples)/2]) / 2
+			}
+			if ps.MedianMs > 0 {
+				ps.Ratio = float64(ms) / float64(ps.MedianMs)
+			}
+			ps.Regression = len(samples) >= perfMinSamples && ms-ps.MedianMs >= cfg.minMs() &&
+				float64(ms) > cfg.threshold()*float64(ps.MedianMs)
+		}
+		out = append(out, ps)
+	}
+	order := map[string]int{}
+	for i, t := range last.stageOrder() {
+		order[t] = i
+	}
+	sort.Slice(out, func(i, j int) bool { return order[out[i].Stage] < order[out[j].Stage] })
+	return out
+}
+
+// stageOrder es el orden del pipeline, con total al final.
+func (r perfRun) stageOrder() []string {
+	known := []string{"sigext", "parse", "classify", "write", "export"}
+	var out []string
+	for _, s := range known {
+		if _, ok := r.Stages[s]; ok {
+			out = append(out, s)
+		}
+	}
+	var other []string
+	for s := range r.Stages {
+		if !containsFold(known, s) {
+			other = append(other, s)
+		}
+	}
+	sort.Strings(other)
+	return append(append(out, other...), "total")
+}
+
+// trackPerf guarda la ejecución y devuelve las etapas que empeoran.
+func trackPerf(absProjectPath, node string, res *GenerateResult, signals int) ([]PerfStage, error) {
+	cfg := GlobalConfig.App.Perf
+	db, err := openPerfDB(pointDBPath(absProjectPath))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	previous, err := recentPerfRuns(db, node, cfg.window())
+	if err != nil {
+		return nil, err
+	}
+	run := perfRun{RunAt: formatStamp(time.Now()), Version: version, SigVersion: res.SigVersion, Signals: signals, Stages: map[string]int64{}}
+	for _, t := range res.Timings {
+		run.Stages[t.Stage] = t.Millis
+		run.TotalMs += t.Millis
+	}
+	if err := recordPerfRun(db, node, run); err != nil {
+		return nil, err
+	}
+	var regressions []PerfStage
+	for _, s := range comparePerf(cfg, previous, run) {
+		if s.Regression {
+			regressions = append(regressions, s)
+		}
+	}
+	return regressions, nil
+}
+
+// runPerf implementa "dnpgen perf report".
+func runPerf(args []string) {
+	usage := tr("Uso: dnpgen.exe perf report -path \"C:\\Ruta\" [-node Nodo1,Nodo2] [-last 10] [-threshold 2] [-json]")
+	if len(args) == 0 || args[0] != "report" {
+		log.Fatal(usage)
+	}
+	fs := flag.NewFlagSet("perf", flag.ExitOnError)
+	fs.Usage = manUsage("perf report", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos, separados por comas"))
+	last := fs.Int("last", 0, tr("Ejecuciones anteriores de referencia (por defecto app.perf.window)"))
+	threshold := fs.Float64("threshold", 0, tr("Factor sobre la mediana que se marca como regresión (por defecto app.perf.threshold)"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args[1:])
+	if *projectPath == "" {
+		log.Fatal(usage)
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	cfg := GlobalConfig.App.Perf
+	if *last > 0 {
+		cfg.Window = *last
+	}
+	if *threshold > 0 {
+		cfg.Threshold = *threshold
+	}
+
+	path := pointDBPath(abs)
+	if _, err := os.Stat(path); err != nil {
+		log.Fatalf(tr("[FATAL] No hay historial de rendimiento en %s: active app.perf y genere los nodos"), path)
+	}
+	db, err := openPerfDB(path)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer db.Close()
+	nodes := parseOnly(*nodeNames)
+	if len(nodes) == 0 {
+		if nodes, err = perfNodes(db); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+
+	type nodePerf struct {
+		Node   string      `json:"node"`
+		Last   perfRun     `json:"last"`
+		Stages []PerfStage `json:"stages"`
+	}
+	var results []nodePerf
+	for _, node := range nodes {
+		runs, err := recentPerfRuns(db, node, cfg.window()+1)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if len(runs) == 0 {
+			continue
+		}
+		lastRun := runs[len(runs)-1]
+		results = append(results, nodePerf{node, lastRun, comparePerf(cfg, runs[:len(runs)-1], lastRun)})
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println(tr("Sin historial de rendimiento: active app.perf y genere los nodos"))
+		return
+	}
+	var rows [][]string
+	var flagged []bool
+	regressions := 0
+	for _, r := range results {
+		for _, s := range r.Stages {
+			median, ratio, status := "-", "-", okText("OK")
+			if s.Samples > 0 {
+				median = strconv.FormatInt(s.MedianMs, 10)
+			}
+			if s.MedianMs > 0 {
+				ratio = fmt.Sprintf("x%.1f", s.Ratio)
+			}
+			switch {
+			case s.Regression:
+				status = tr("REGRESIÓN")
+				regressions++
+			case s.Samples < perfMinSamples:
+				status = tr("pocas muestras")
+			}
+			rows = append(rows, []string{r.Node, s.Stage, strconv.FormatInt(s.Ms, 10), median, ratio, strconv.Itoa(s.Samples),
+				strconv.Itoa(r.Last.Signals), r.Last.RunAt, status})
+			flagged = append(flagged, s.Regression)
+		}
+	}
+	printTable(tr("NODO\tETAPA\tÚLTIMA MS\tMEDIANA MS\tFACTOR\tMUESTRAS\tSEÑALES\tFECHA\tESTADO"), rows, func(i int) func(string) string {
+		if flagged[i] {
+			return warnText
+		}
+		return nil
+	})
+	if regressions > 0 {
+		fmt.Println(warnText(trf("%d etapa(s) más lentas que x%.1f la mediana", regressions, cfg.threshold())))
+		fmt.Println(tr("Compare la versión del programa, la del .SIG y las señales con \"perf report -json\"."))
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- LIBRO DE PUNTOS CONSOLIDADO ---
+//
+// El entregable que pide el cliente no es un fichero por nodo sino un único
+// libro del proyecto. Con app.point_book, la generación de todos los nodos
+// (-all o workspace) escribe <proyecto>.pointbook.xlsx y/o .html con un
+// resumen por nodo, el índice global de todas las variables @GV (nodo,
+// lista e índice DNP3) y una hoja por nodo con sus listas completas.
+
+// PointBookConfig controla el libro de puntos consolidado.
+type PointBookConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Formats []string `yaml:"formats"` // xlsx, html; vacío = los dos
+	// Dir es dónde se escribe, relativo a -out o a la raíz del proyecto.
+	Dir string `yaml:"dir"`
+}
+
+func (c PointBookConfig) formats() []string {
+	if len(c.Formats) > 0 {
+		return c.Formats
+	}
+	return []string{"xlsx", "html"}
+}
+
+// pointBookEntry es un punto real del índice global.
+type pointBookEntry struct {
+	Node, List string
+	Index      int
+	Point
+}
+
+// pointBookNode son las listas de un nodo del libro.
+type pointBookNode struct {
+	Node     string
+	Sections []listSection
+}
+
+// pointBook reúne los nodos generados, en orden alfabético.
+type pointBook struct {
+	Project string
+	Nodes   []pointBookNode
+	Index   []pointBookEntry
+}
+
+func newPointBook(project string, byNode map[string]*Lists) *pointBook {
+	b := &pointBook{Project: project}
+	names := make([]string, 0, len(byNode))
+	for node := range byNode {
+		names = append(names, node)
+	}
+	sort.Strings(names)
+	for _, node := range names {
+		sections := listSections(byNode[node])
+		b.Nodes = append(b.Nodes, pointBookNode{node, sections})
+		for _, s := range sections {
+			for i, p := range s.Items {
+				if !p.Spare {
+					b.Index = append(b.Index, pointBookEntry{node, s.Name, i, p})
+				}
+			}
+		}
+	}
+	sort.SliceStable(b.Index, func(i, j int) bool {
+		if b.Index[i].Var != b.Index[j].Var {
+			return b.Index[i].Var < b.Index[j].Var
+		}
+		return b.Index[i].Node < b.Index[j].Node
+	})
+	return b
+}
+
+// summaryRows es el número de posiciones de cada lista por nodo.
+func (b *pointBook) summaryRows() (header []string, rows [][]string) {
+	var lists []string
+	seen := map[string]bool{}
+	for _, n := range b.Nodes {
+		for _, s := range n.Sections {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				lists = append(lists, s.Name)
+			}
+		}
+	}
+	header = append(append([]string{"Nodo"}, lists...), "Reales", "Spares")
+	for _, n := range b.Nodes {
+		counts := map[string]int{}
+		real, spares := 0, 0
+		for _, s := range n.Sections {
+			counts[s.Name] = len(s.Items)
+			for _, p := range s.Items {
+				if p.Spare {
+					spares++
+				} else {
+					real++
+				}
+			}
+		}
+		row := []string{n.Node}
+		for _, l := range lists {
+			row = append(row, strconv.Itoa(counts[l]))
+		}
+		rows = append(rows, append(row, strconv.Itoa(real), strconv.Itoa(spares)))
+	}
+	return header, rows
+}
+
+func (b *pointBook) xlsx() ([]byte, error) {
+	header, rows := b.summaryRows()
+	sheets := []xlsxSheet{{Name: tr("Resumen"), Rows: append([][]string{header}, rows...)}}
+
+	index := xlsxSheet{Name: tr("Índice"), Rows: [][]string{{"Variable", "Nodo", "Lista", "Índice", "Punto", "Tipo", "Descripción", "Área", "Alarma"}}}
+	for _, e := range b.Index {
+		index.Rows = append(index.Rows, []string{e.Var, e.Node, e.List, strconv.Itoa(e.Index), e.Name, e.Type, e.Desc, e.Area, e.Alarm})
+	}
+	sheets = append(sheets, index)
+
+	for _, n := range b.Nodes {
+		sh := xlsxSheet{Name: n.Node, Rows: [][]string{{"Lista", "Índice", "Punto", "Variable", "Tipo", "Spare", "SOE", "Descripción", "Unidades", "Área", "Alarma"}}}
+		for _, s := range n.Sections {
+			for i, p := range s.Items {
+				spare, soe := "", ""
+				if p.Spare {
+					spare = "SI"
+				}
+				if p.SOE {
+					soe = "SI"
+				}
+				_, _, units := scalingColumns(p)
+				sh.Rows = append(sh.Rows, []string{s.Name, strconv.Itoa(i), p.Name, p.Var, p.Type, spare, soe, p.Desc, units, p.Area, p.Alarm})
+			}
+		}
+		sheets = append(sheets, sh)
+	}
+	var buf bytes.Buffer
+	if err := writeXLSX(&buf, sheets); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var htmlPointBookTemplate = template.Must(template.New("pointbook").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="generator" content="{{.Generator}}">
+<title>{{.Project}} - DNP3</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+th { background: #eee; }
+tr.spare td { color: #999; }
+</style>
+</head>
+<body>
+<h1>{{.Project}}</h1>
+<p>{{.Generated}}</p>
+<ul>
+<li><a href="#index">{{.IndexTitle}}</a> ({{len .Book.Index}})</li>
+{{range .Book.Nodes}}<li><a href="#node-{{.Node}}">{{.Node}}</a></li>
+{{end}}</ul>
+<table>
+<tr>{{range .SummaryHeader}}<th>{{.}}</th>{{end}}</tr>
+{{range .SummaryRows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+<h2 id="index">{{.IndexTitle}}</h2>
+<table>
+<tr><th>Variable</th><th>Nodo</th><th>Lista</th><th>#</th><th>Punto</th><th>Tipo</th><th>Descripción</th><th>Área</th><th>Alarma</th></tr>
+{{range .Book.Index}}<tr><td>{{.Var}}</td><td><a href="#node-{{.Node}}">{{.Node}}</a></td><td>{{.List}}</td><td>{{.Index}}</td><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Desc}}</td><td>{{.Area}}</td><td>{{.Alarm}}</td></tr>
+{{end}}</table>
+{{range .Book.Nodes}}<h2 id="node-{{.Node}}">{{.Node}}</h2>
+{{range .Sections}}<h3>{{.Name}} - {{.Title}} ({{len .Items}})</h3>
+<table>
+<tr><th>#</th><th>Punto</th><th>Variable</th><th>Tipo</th><th>Descripción</th><th>Área</th><th>Alarma</th></tr>
+{{range $i, $p := .Items}}<tr{{if $p.Spare}} class="spare"{{end}}><td>{{$i}}</td><td>{{$p.Name}}</td><td>{{$p.Var}}</td><td>{{$p.Type}}</td><td>{{$p.Desc}}</td><td>{{$p.Area}}</td><td>{{$p.Alarm}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`))
+
+func (b *pointBook) html() ([]byte, error) {
+	header, rows := b.summaryRows()
+	var buf bytes.Buffer
+	err := htmlPointBookTemplate.Execute(&buf, struct {
+		Lang, Project, Generator, Generated, IndexTitle string
+		SummaryHeader                                   []string
+		SummaryRows                                     [][]string
+		Book                                            *pointBook
+	}{locale, b.Project, generatorLine(), formatStamp(stampNow()), tr("Índice de variables"), header, rows, b})
+	return buf.Bytes(), err
+}
+
+// writePointBook escribe el libro en los formatos configurados y devuelve
+// las rutas. name es la ruta original del proyecto (carpeta o .zip), de la
+// que sale el nombre del libro; base, donde se escribe si point_book.dir no
+// es absoluto (-out o la raíz del proyecto).
+func writePointBook(name, base string, byNode map[string]*Lists) ([]string, error) {
+	cfg := GlobalConfig.App.PointBook
+	name = filepath.Base(filepath.Clean(name))
+	project := strings.TrimSuffix(name, filepath.Ext(name))
+	dir := cfg.Dir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(base, dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	book := newPointBook(project, byNode)
+	var written []string
+	for _, format := range cfg.formats() {
+		var data []byte
+		var err error
+		switch strings.ToLower(format) {
+		case "xlsx":
+			data, err = book.xlsx()
+		case "html":
+			data, err = book.html()
+		default:
+			return written, fmt.Errorf(tr("point_book: formato desconocido %q (xlsx o html)"), format)
+		}
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, project+".pointbook."+strings.ToLower(format))
+		log.Printf(tr("Escribiendo el libro de puntos %s..."), filepath.Base(path))
+		if err := writeFileAtomic(path, data); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
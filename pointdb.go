@@ -0,0 +1,187 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// --- BASE DE DATOS DE PUNTOS DEL PROYECTO (SQLITE) ---
+
+// PointDBConfig configura la base SQLite donde se acumulan los puntos de todos
+// los nodos de un proyecto.
+type PointDBConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // vacío = <proyecto>/dnpgen_points.db; admite {project}
+}
+
+const pointDBSchema = `
+CREATE TABLE IF NOT EXISTS points (
+	node         TEXT    NOT NULL,
+	list         TEXT    NOT NULL,
+	idx          INTEGER NOT NULL,
+	name         TEXT    NOT NULL,
+	var          TEXT    NOT NULL,
+	type         TEXT    NOT NULL DEFAULT '',
+	spare        INTEGER NOT NULL DEFAULT 0,
+	generated_at TEXT    NOT NULL,
+	PRIMARY KEY (node, list, idx)
+);
+CREATE INDEX IF NOT EXISTS points_var ON points (var);
+`
+
+// pointDBPath resuelve la ruta de la base para un proyecto.
+func pointDBPath(absProjectPath string) string {
+	p := GlobalConfig.App.PointDB.Path
+	if p == "" {
+		return filepath.Join(absProjectPath, "dnpgen_points.db")
+	}
+	return expandTemplate(p, map[string]string{"project": absProjectPath})
+}
+
+func openPointDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(pointDBSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// updatePointDB sustituye los puntos de un nodo por los recién generados.
+func updatePointDB(path, node string, l *Lists) error {
+	db, err := openPointDB(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM points WHERE node = ?`, node); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO points (node, list, idx, name, var, type, spare, generated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := formatStamp(time.Now())
+	for _, s := range listSections(l) {
+		for idx, p := range s.Items {
+			if _, err := stmt.Exec(node, s.Name, idx, p.Name, p.Var, p.Type, p.Spare, now); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func runQuery(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	fs.Usage = manUsage("query", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	dbFile := fs.String("db", "", tr("Ruta directa a la base de puntos (alternativa a -path)"))
+	name := fs.String("name", "", tr("Patrón de variable (* y ? como comodines)"))
+	list := fs.String("list", "", tr("Lista: DI, DO, AI, AO, OS o una de classification.extra_lists"))
+	node := fs.String("node", "", "Nodo")
+	from := fs.Int("from", -1, tr("Índice mínimo"))
+	to := fs.Int("to", -1, tr("Índice máximo"))
+	spares := fs.Bool("spares", false, tr("Incluir spares"))
+	fs.Parse(args)
+
+	path := *dbFile
+	if path == "" {
+		if *projectPath == "" {
+			log.Fatal(tr("Uso: dnpgen.exe query -path \"C:\\Ruta\" [-name \"LIT*\"] [-list DI] [-node N] [-from 0 -to 15]"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		path = pointDBPath(abs)
+	}
+	if _, err := os.Stat(path); err != nil {
+		log.Fatalf(tr("[FATAL] No existe la base de puntos %s (habilite app.pointdb y genere los nodos)"), path)
+	}
+
+	db, err := openPointDB(path)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer db.Close()
+
+	var where []string
+	var params []any
+	if *name != "" {
+		// Los comodines de shell se traducen a GLOB de SQLite sobre la variable
+		// y sobre la línea completa (para encontrar también "@GV.X").
+		where = append(where, "(var GLOB ? OR name GLOB ?)")
+		params = append(params, *name, *name)
+	}
+	if *list != "" {
+		// Las listas de extra_lists conservan su nombre tal cual se configuró.
+		where = append(where, "list = ? COLLATE NOCASE")
+		params = append(params, *list)
+	}
+	if *node != "" {
+		where = append(where, "node = ?")
+		params = append(params, *node)
+	}
+	if *from >= 0 {
+		where = append(where, "idx >= ?")
+		params = append(params, *from)
+	}
+	if *to >= 0 {
+		where = append(where, "idx <= ?")
+		params = append(params, *to)
+	}
+	if !*spares {
+		where = append(where, "spare = 0")
+	}
+	q := "SELECT node, list, idx, name, type FROM points"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY node, list, idx"
+
+	rows, err := db.Query(q, params...)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer rows.Close()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("NODO\tLISTA\tÍNDICE\tPUNTO\tTIPO"))
+	n := 0
+	for rows.Next() {
+		var nodeName, listName, pointName, typ string
+		var idx int
+		if err := rows.Scan(&nodeName, &listName, &idx, &pointName, &typ); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", nodeName, listName, idx, pointName, typ)
+		n++
+	}
+	tw.Flush()
+	if err := rows.Err(); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	fmt.Printf(tr("\n%d puntos\n"), n)
+}
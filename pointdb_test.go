@@ -0,0 +1,29 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdatePointDBStoresExtraLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "points.db")
+	l := &Lists{
+		AI:    []Point{{Name: "@GV.LIT101", Var: "@GV.LIT101"}},
+		Extra: []CustomList{{Name: "Contadores", Code: "CT", Items: []Point{{Name: "@GV.FQ1", Var: "@GV.FQ1"}, {Name: "@GV.FQ2", Var: "@GV.FQ2"}}}},
+	}
+	if err := updatePointDB(path, "N1", l); err != nil {
+		t.Fatal(err)
+	}
+	db, err := openPointDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM points WHERE node = ? AND list = ? COLLATE NOCASE`, "N1", "CONTADORES").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("%d puntos de la lista extra, se esperaban 2", n)
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- INTEGRACIÓN CON LA COMPILACIÓN DE CWAVE ---
+//
+// "dnpgen post-build" está pensado para la herramienta externa que el IDE de
+// ControlWave lanza al terminar de compilar: deduce el proyecto y el nodo de
+// lo que recibe (-path/-node, las rutas que pasa el IDE como argumentos, las
+// variables de entorno de app.post_build o el directorio actual), genera sin
+// escribir nada en la consola y deja el resultado en dos ficheros: uno breve
+// para que el IDE lo muestre (estado, conteos, avisos y error) y el log
+// completo. El código de salida es 0 si todo fue bien, 1 si falló y
+// app.post_build.warnings_exit si solo hubo avisos (0 por defecto, como OK).
+
+// PostBuildConfig configura dnpgen post-build.
+type PostBuildConfig struct {
+	// ProjectEnv y NodeEnv son las variables de entorno que se consultan, en
+	// orden, si el IDE no pasa el proyecto o el nodo como argumento. Vacío =
+	// DNPGEN_PROJECT y DNPGEN_NODE. El proyecto puede ser también la ruta del
+	// .mwt o del .SIG.
+	ProjectEnv []string `yaml:"project_env"`
+	NodeEnv    []string `yaml:"node_env"`
+	// Result y Log son los ficheros de resultado y de log; admiten {project},
+	// {output} y {node}. Vacío = {output}/{node}.postbuild.txt (o .json) y
+	// {output}/{node}.postbuild.log.
+	Result string `yaml:"result"`
+	Log    string `yaml:"log"`
+	Format string `yaml:"format"` // text (por defecto) o json
+	// WarningsExit es el código de salida cuando solo hay avisos; 0 = como
+	// OK.
+	WarningsExit int `yaml:"warnings_exit"`
+}
+
+func (c PostBuildConfig) projectEnv() []string {
+	if len(c.ProjectEnv) == 0 {
+		return []string{"DNPGEN_PROJECT"}
+	}
+	return c.ProjectEnv
+}
+
+func (c PostBuildConfig) nodeEnv() []string {
+	if len(c.NodeEnv) == 0 {
+		return []string{"DNPGEN_NODE"}
+	}
+	return c.NodeEnv
+}
+
+// Estados del resultado de post-build.
+const (
+	PostBuildOK      = "ok"
+	PostBuildWarning = "warning"
+	PostBuildError   = "error"
+)
+
+// postBuildResult es el fichero de resultado.
+type postBuildResult struct {
+	Status     string   `json:"status"`
+	Project    string   `json:"project,omitempty"`
+	Node       string   `json:"node,omitempty"`
+	ListFile   string   `json:"list_file,omitempty"`
+	DI         int      `json:"di"`
+	DO         int      `json:"do"`
+	AI         int      `json:"ai"`
+	AO         int      `json:"ao"`
+	Warnings   []string `json:"warnings,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	ErrorCode  string   `json:"error_code,omitempty"`
+	Log        string   `json:"log,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// postBuildWarningLines es el máximo de avisos en el resultado de texto; el
+// resto queda en el log.
+const postBuildWarningLines = 20
+
+// text es el resultado breve que muestra el IDE.
+func (r postBuildResult) text() string {
+	var b strings.Builder
+	node := r.Node
+	counts := fmt.Sprintf("DI %d, DO %d, AI %d, AO %d", r.DI, r.DO, r.AI, r.AO)
+	switch r.Status {
+	case PostBuildOK:
+		b.WriteString(trf("OK: %s generado (%s)\n", node, counts))
+	case PostBuildWarning:
+		b.WriteString(trf("AVISOS: %s generado con %d aviso(s) (%s)\n", node, len(r.Warnings), counts))
+	case PostBuildError:
+		if r.Node == "" {
+			b.WriteString(tr("ERROR: no se ha generado ningún nodo\n"))
+		} else {
+			b.WriteString(trf("ERROR: %s no se ha generado\n", node))
+		}
+	}
+	if r.Error != "" {
+		b.WriteString(r.Error + "\n")
+	}
+	if r.ListFile != "" {
+		b.WriteString(trf("Listas: %s\n", r.ListFile))
+	}
+	for i, w := range r.Warnings {
+		if i == postBuildWarningLines {
+			b.WriteString(trf("  ... y %d más (ver el log)\n", len(r.Warnings)-i))
+			break
+		}
+		b.WriteString("  - " + w + "\n")
+	}
+	if r.Log != "" {
+		b.WriteString(trf("Log: %s\n", r.Log))
+	}
+	return b.String()
+}
+
+// postBuildPath resuelve una plantilla de app.post_build.
+func postBuildPath(tpl, def, project, output, node string) string {
+	if tpl == "" {
+		tpl = def
+	}
+	p := expandTemplate(tpl, map[string]string{"project": project, "output": output, "node": node})
+	if !filepath.IsAbs(p) && project != "" {
+		p = filepath.Join(project, p)
+	}
+	return p
+}
+
+// postBuildProjectOf busca, subiendo desde el directorio de un .mwt o .SIG,
+// la raíz del proyecto cuyo layout da ese nodo.
+func postBuildProjectOf(file, node string) string {
+	dir := filepath.Dir(file)
+	for d := dir; ; {
+		p := nodePathsFor(d, node)
+		if fileExists(p.Sig) || p.Mwt == file {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// postBuildTarget deduce el proyecto y el nodo. Cada valor (argumento o
+// variable de entorno) puede ser el directorio del proyecto o la ruta de un
+// .mwt o .SIG, de la que salen ambos; lo que no existe se ignora.
+func postBuildTarget(pathArg, node string, args []string, cfg PostBuildConfig) (string, string, error) {
+	var project string
+	fromPath := func(v string) {
+		abs, err := filepath.Abs(normalizeLongPath(v))
+		if err != nil {
+			return
+		}
+		st, err := os.Stat(abs)
+		switch {
+		case err != nil:
+			log.Printf(tr("[WARN] post-build: se ignora %q, no es una ruta existente"), v)
+		case st.IsDir():
+			if project == "" {
+				project = abs
+			}
+		default:
+			ext := strings.ToLower(filepath.Ext(abs))
+			if ext != ".mwt" && ext != ".sig" {
+				log.Printf(tr("[WARN] post-build: se ignora %q, no es un .mwt ni un .SIG"), v)
+				return
+			}
+			n := strings.TrimSuffix(filepath.Base(abs), filepath.Ext(abs))
+			if node == "" {
+				node = n
+			}
+			if project == "" {
+				project = postBuildProjectOf(abs, n)
+			}
+		}
+	}
+	if pathArg != "" {
+		if fromPath(pathArg); project == "" {
+			return "", node, fmt.Errorf(tr("-path %s: no es el directorio del proyecto ni un .mwt o .SIG"), pathArg)
+		}
+	}
+	for _, a := range args {
+		fromPath(a)
+	}
+	for _, env := range cfg.projectEnv() {
+		if v := os.Getenv(env); v != "" && project == "" {
+			fromPath(v)
+		}
+	}
+	for _, env := range cfg.nodeEnv() {
+		if v := os.Getenv(env); v != "" && node == "" {
+			node = v
+		}
+	}
+	if project == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", "", err
+		}
+		project = wd
+	}
+	if node == "" {
+		nodes, err := discoverNodes(project)
+		if err != nil {
+			return project, "", err
+		}
+		if len(nodes) != 1 {
+			return project, "", fmt.Errorf(tr("%s tiene %d nodo(s): indique el nodo con -node, el .mwt o %s"), project, len(nodes), strings.Join(cfg.nodeEnv(), ", "))
+		}
+		node = nodes[0]
+	}
+	log.Printf(tr("post-build: proyecto %s, nodo %s"), project, node)
+	return project, node, nil
+}
+
+// runPostBuild implementa "dnpgen post-build".
+func runPostBuild(args []string) {
+	fs := flag.NewFlagSet("post-build", flag.ExitOnError)
+	fs.Usage = manUsage("post-build", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto (por defecto, la que pase el IDE)"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (por defecto, el que pase el IDE)"))
+	skipExt := fs.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	outDir := fs.String("out", "", tr("Directorio de salida, si no es el del recurso"))
+	resultFile := fs.String("result", "", tr("Fichero de resultado (por defecto app.post_build.result)"))
+	asJSON := fs.Bool("json", false, tr("Resultado en JSON"))
+	fs.Parse(args)
+
+	// Nada a la consola: el log se guarda entero en su fichero al terminar.
+	var logBuf bytes.Buffer
+	setLogOutput(&logBuf)
+	start := time.Now()
+	r := postBuildResult{Status: PostBuildError}
+	var project, output string
+
+	// finish escribe el log y el resultado y sale; no vuelve.
+	finish := func() {
+		r.DurationMs = time.Since(start).Milliseconds()
+		cfg := GlobalConfig.App.PostBuild
+		node := r.Node
+		if node == "" {
+			node = "dnpgen"
+		}
+		if output == "" {
+			output, _ = os.Getwd()
+		}
+		format := cfg.Format
+		if *asJSON {
+			format = "json"
+		}
+		if format != "json" && format != "text" && format != "" {
+			r.Warnings = append(r.Warnings, trf("app.post_build.format desconocido %q; se usa text", format))
+			format = "text"
+		}
+		ext := ".txt"
+		if format == "json" {
+			ext = ".json"
+		}
+		r.Log = postBuildPath(cfg.Log, "{output}/{node}.postbuild.log", project, output, node)
+		// Un log que no se puede escribir no cambia el resultado.
+		if os.MkdirAll(filepath.Dir(r.Log), 0o755) != nil || os.WriteFile(r.Log, logBuf.Bytes(), 0o644) != nil {
+			r.Log = ""
+		}
+		data := []byte(r.text())
+		if format == "json" {
+			data, _ = json.MarshalIndent(r, "", "  ")
+			data = append(data, '\n')
+		}
+		path := *resultFile
+		if path == "" {
+			path = postBuildPath(cfg.Result, "{output}/{node}.postbuild"+ext, project, output, node)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			os.Exit(1)
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			os.Exit(1)
+		}
+		switch r.Status {
+		case PostBuildOK:
+			os.Exit(0)
+		case PostBuildWarning:
+			os.Exit(cfg.WarningsExit)
+		}
+		os.Exit(1)
+	}
+	fail := func(err error) {
+		log.Printf("[ERROR] %v", err)
+		r.Error, r.ErrorCode = err.Error(), errorCode(err)
+		finish()
+	}
+
+	path, ok := findConfigFile()
+	if !ok {
+		fail(codedErrorf(ErrConfigNotFound, tr("No se encuentra %s"), ConfigFile))
+	}
+	if err := loadConfigFile(path); err != nil {
+		fail(err)
+	}
+	project, node, err := postBuildTarget(*projectPath, *nodeName, fs.Args(), GlobalConfig.App.PostBuild)
+	r.Project, r.Node = project, node
+	if project != "" && node != "" {
+		output = outputDirFor(project, node, *outDir)
+	}
+	if err != nil {
+		fail(withCode(ErrProjectInvalid, err))
+	}
+
+	req := GenerateRequest{ProjectPath: project, NodeName: node, SkipExt: *skipExt, OutDir: *outDir}
+	seen := map[string]bool{}
+	req.Events = func(e GenerateEvent) {
+		if e.Kind == EventWarning && !seen[e.Message] {
+			seen[e.Message] = true
+			r.Warnings = append(r.Warnings, e.Message)
+		}
+	}
+	res, err := runGenerate(req)
+	notifyResult(req, "", res, err)
+	if err != nil {
+		fail(err)
+	}
+	r.ListFile, r.DI, r.DO, r.AI, r.AO = res.ListFile, res.DI, res.DO, res.AI, res.AO
+	r.Status = PostBuildOK
+	if len(r.Warnings) > 0 {
+		r.Status = PostBuildWarning
+	}
+	log.Printf(tr("post-build: %s en %d ms"), r.Status, time.Since(start).Milliseconds())
+	finish()
+}
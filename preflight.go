@@ -0,0 +1,538 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- COMPROBACIONES PREVIAS ---
+//
+// Antes de lanzar SIGEXT se comprueba todo lo que haría fallar la generación
+// a mitad de camino: carpetas sin permiso de escritura, listas de solo
+// lectura o bloqueadas (ControlWave Designer o Excel abiertos) y un .SIG o
+// .mwt ilegible. Se informan todos los problemas juntos.
+
+// preflightProblems devuelve los problemas encontrados; vacío si todo está
+// en orden.
+func preflightProblems(req GenerateRequest, paths nodePaths, outDir string) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch sigSourceName(req) {
+	case SigSourceLocal:
+		if err := checkReadable(paths.Sig); err != nil && !os.IsNotExist(err) {
+			add(tr("no se puede leer %s: %v"), paths.Sig, err)
+		}
+	case SigSourceSigExt:
+		if GlobalConfig.App.SigExtRemote.enabled() || !fileExists(paths.Sig) {
+			// SIGEXT (o la descarga desde la máquina remota) crea el .SIG.
+			if err := checkWritableDir(filepath.Dir(paths.Sig)); err != nil {
+				add(tr("SIGEXT no podrá escribir en %s: %v"), filepath.Dir(paths.Sig), err)
+			}
+		} else if err := checkWritableFile(paths.Sig); err != nil {
+			add(tr("SIGEXT no podrá sobrescribir %s: %v"), paths.Sig, err)
+		}
+		if err := checkReadable(paths.Mwt); err != nil {
+			add(tr("no se puede leer %s: %v"), paths.Mwt, err)
+		}
+	default:
+		// El backend deja el .SIG junto al definitivo y lo renombra.
+		if err := checkWritableDir(filepath.Dir(paths.Sig)); err != nil {
+			add(tr("no se podrá escribir el .SIG en %s: %v"), filepath.Dir(paths.Sig), err)
+		} else if fileExists(paths.Sig) {
+			if err := checkWritableFile(paths.Sig); err != nil {
+				add(tr("no se podrá sobrescribir %s: %v"), paths.Sig, err)
+			}
+		}
+	}
+
+	if req.CheckOnly {
+		return problems
+	}
+	if cfg := GlobalConfig.App.Signing; cfg.Enabled {
+		if _, err := cfg.signingKey(); err != nil {
+			add(tr("clave de firma: %v"), err)
+		}
+	}
+	if err := checkWritableDir(outDir); err != nil {
+		add(tr("no se puede escribir en el directorio de salida %s: %v"), outDir, err)
+		return problems
+	}
+	for _, f := range preflightOutputs(outDir, req.NodeName) {
+		if err := checkWritableFile(f); err != nil {
+			add(tr("no se puede sobrescribir %s: %v"), f, err)
+		}
+	}
+	return problems
+}
+
+// preflightOutputs enumera los ficheros ya existentes que la generación va a
+// sobrescribir: listas y exportaciones.
+func preflightOutputs(outDir, node string) []string {
+	cfg := GlobalConfig.App.Output
+	var names []string
+	if cfg.combined() {
+		names = append(names, cfg.fileName())
+	}
+	if cfg.Split {
+		for _, list := range []string{"AI", "AO", "DI", "DO", "OS"} {
+			names = append(names, cfg.splitFileName(list))
+		}
+	}
+	protocols, _ := protocolExportNames(node) // un error aquí se informa al exportar
+	for _, name := range append(append([]string{}, GlobalConfig.App.Exports...), protocols...) {
+		if e, ok := exporterRegistry[strings.ToLower(name)]; ok {
+			names = append(names, e.FileName(node))
+		}
+	}
+	var out []string
+	for _, n := range names {
+		if p := filepath.Join(outDir, n); fileExists(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// checkWritableFile comprueba que un fichero existente se puede abrir para
+// escritura sin modificarlo. En Windows falla si es de solo lectura o si otro
+// programa lo tiene abierto sin compartir la escritura.
+func checkWritableFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0o200 == 0 {
+		return errors.New(tr("fichero de solo lectura"))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf(tr("bloqueado o sin permiso (¿abierto en otro programa?): %v"), unwrapPathError(err))
+	}
+	return f.Close()
+}
+
+// checkWritableDir crea y borra un fichero temporal en dir. Si dir no existe
+// se comprueba el primer directorio existente por encima, donde se creará.
+func checkWritableDir(dir string) error {
+	for !fileExists(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return errors.New(tr("no existe"))
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".dnpgen-preflight-*")
+	if err != nil {
+		return unwrapPathError(err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+func unwrapPathError(err error) error {
+	if pe, ok := err.(*os.PathError); ok {
+		return pe.Err
+	}
+	return err
+}
+
+// --- COMPROBACIÓN ANTES DE DESCARGAR AL CONTROLADOR ---
+//
+// "dnpgen preflight" reúne en una sola puerta todo lo que conviene revisar
+// antes de descargar las listas al controlador: que el .SIG sea legible y
+// esté al día, que las listas del disco sean las que generaría el .SIG y se
+// relean igual, su codificación, la capacidad y los límites del firmware, la
+// simetría de las listas espejo, la coherencia con __vardef.ini y el mapa de
+// índices. Genera en memoria (sin SIGEXT ni escrituras), imprime una lista
+// de comprobación y sale con código 1 si algo falla. -report escribe la misma
+// lista en HTML para imprimirla y firmarla.
+
+// Estados de una comprobación.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+	CheckSkip = "skip"
+)
+
+// PreflightCheck es una línea de la lista de comprobación.
+type PreflightCheck struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Status  string   `json:"status"`
+	Details []string `json:"details,omitempty"`
+}
+
+// PreflightNode es la lista de comprobación de un nodo.
+type PreflightNode struct {
+	Node   string           `json:"node"`
+	OK     bool             `json:"ok"`
+	Error  string           `json:"error,omitempty"`
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// preflightChecklist construye la lista; cada comprobación empieza en pass
+// y empeora con lo que se le añade.
+type preflightChecklist struct {
+	checks []PreflightCheck
+}
+
+func (c *preflightChecklist) check(id, title string) *PreflightCheck {
+	c.checks = append(c.checks, PreflightCheck{ID: id, Title: title, Status: CheckPass})
+	return &c.checks[len(c.checks)-1]
+}
+
+func (p *PreflightCheck) add(status, format string, args ...any) {
+	rank := map[string]int{CheckPass: 0, CheckSkip: 1, CheckWarn: 2, CheckFail: 3}
+	if rank[status] > rank[p.Status] {
+		p.Status = status
+	}
+	p.Details = append(p.Details, fmt.Sprintf(format, args...))
+}
+
+// addFindings pasa a la comprobación los hallazgos de los tipos codes: los
+// que harían fallar la generación según app.findings fallan aquí.
+func (p *PreflightCheck) addFindings(cfg FindingsConfig, list []Finding, codes ...string) {
+	for _, f := range list {
+		for _, code := range codes {
+			if f.Code != code {
+				continue
+			}
+			switch {
+			case cfg.fails(f.Severity):
+				p.add(CheckFail, "%s", f.Message)
+			case f.Severity == SeverityWarning:
+				p.add(CheckWarn, "%s", f.Message)
+			}
+		}
+	}
+}
+
+// preflightNode revisa un nodo.
+func preflightNode(abs, node, outOverride string) PreflightNode {
+	r := PreflightNode{Node: node}
+	// Los hallazgos se evalúan aquí: la generación no debe pararse en ellos.
+	cfg := GlobalConfig.App.Findings
+	GlobalConfig.App.Findings.FailOn = "never"
+	res, err := generate(GenerateRequest{ProjectPath: abs, NodeName: node, SkipExt: true, CheckOnly: true, OutDir: outOverride})
+	GlobalConfig.App.Findings = cfg
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	outDir := outputDirFor(abs, node, outOverride)
+	var c preflightChecklist
+
+	sig := c.check("sig", tr(".SIG legible y al día"))
+	if res.SigFormat != nil {
+		sig.add(CheckWarn, tr("formato normalizado al leer: %s"), res.SigFormat)
+	}
+	if res.StaleSig {
+		sig.add(CheckFail, "%s", tr("El .SIG es anterior al .mwt"))
+	}
+	sig.addFindings(cfg, res.Findings, FindingSigLine, FindingSigIncomplete)
+
+	outputs := listOutputs(outDir, res.lists, res.content)
+	lists := c.check("lists", tr("Listas del disco iguales a las del .SIG"))
+	var onDisk []listOutput
+	for _, o := range outputs {
+		data, err := os.ReadFile(o.path)
+		switch {
+		case os.IsNotExist(err):
+			lists.add(CheckFail, tr("no existe %s: genere el nodo"), o.path)
+		case err != nil:
+			lists.add(CheckFail, "%v", err)
+		case !bytes.Equal(stripListHeader(data), stripListHeader(o.content)):
+			lists.add(CheckFail, tr("%s no coincide con el .SIG actual: regenere el nodo"), filepath.Base(o.path))
+			for _, d := range goldenDiff(stripListHeader(data), stripListHeader(o.content)) {
+				lists.add(CheckFail, "%s", d)
+			}
+			onDisk = append(onDisk, listOutput{o.path, data, o.list})
+		default:
+			onDisk = append(onDisk, listOutput{o.path, data, o.list})
+		}
+	}
+	if lists.Status == CheckPass && res.ListFile != "" {
+		if problems, err := checkRoundTripFile(res.ListFile, res.lists); err != nil {
+			lists.add(CheckFail, tr("comprobación de ida y vuelta: %v"), err)
+		} else if len(problems) > 0 {
+			lists.add(CheckFail, tr("%s no se relee igual: %s"), filepath.Base(res.ListFile), strings.Join(problems, "; "))
+		}
+	}
+
+	enc := c.check("encoding", tr("Codificación de las listas"))
+	if len(onDisk) == 0 {
+		enc.add(CheckSkip, "%s", tr("sin listas en el disco"))
+	}
+	for _, o := range onDisk {
+		for _, problem := range listEncodingProblems(o.content) {
+			enc.add(CheckFail, "%s: %s", filepath.Base(o.path), problem)
+		}
+	}
+
+	capacity := c.check("capacity", tr("Capacidad de las listas y límites del firmware"))
+	capacity.addFindings(cfg, res.Findings, FindingCapacity, FindingFirmware)
+	if len(cfg.Capacity) == 0 && GlobalConfig.App.Firmware == "" {
+		capacity.add(CheckSkip, "%s", tr("sin findings.capacity ni firmware configurados"))
+	}
+
+	symmetry := c.check("symmetry", tr("Simetría de las listas espejo"))
+	symmetry.addFindings(cfg, res.Findings, FindingMirror)
+
+	vardef := c.check("vardef", tr("Coherencia con __vardef.ini"))
+	if GlobalConfig.App.Scaling.FromVarDef {
+		vardef.addFindings(cfg, res.Findings, FindingVarDefMismatch)
+	} else {
+		vardef.add(CheckSkip, "%s", tr("app.scaling.from_vardef desactivado"))
+	}
+
+	index := c.check("index_map", tr("Mapa de índices"))
+	if GlobalConfig.App.Lifecycle.Enabled {
+		for _, problem := range lifecycleProblems(outDir, node) {
+			index.add(CheckFail, "%s", problem)
+		}
+	} else {
+		index.add(CheckSkip, "%s", tr("app.lifecycle desactivado"))
+	}
+
+	rules := c.check("rules", tr("Versión de las reglas"))
+	rules.addFindings(cfg, res.Findings, FindingRulesVersion)
+
+	other := c.check("findings", tr("Otros hallazgos"))
+	other.addFindings(cfg, res.Findings, FindingNameLength, FindingNameChars, FindingNaming, FindingUnknownType, FindingValidator)
+
+	r.Checks, r.OK = c.checks, true
+	for _, ch := range c.checks {
+		if ch.Status == CheckFail {
+			r.OK = false
+		}
+	}
+	return r
+}
+
+// listEncodingProblems devuelve lo que el controlador puede leer mal en un
+// fichero de listas: BOM, bytes nulos o no ASCII y finales de línea mezclados.
+func listEncodingProblems(data []byte) []string {
+	var problems []string
+	if bytes.HasPrefix(data, []byte("\xef\xbb\xbf")) {
+		problems = append(problems, tr("empieza con BOM UTF-8"))
+		data = data[3:]
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		problems = append(problems, tr("contiene bytes nulos"))
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		for _, b := range line {
+			if b >= 0x80 {
+				problems = append(problems, trf("línea %d: caracteres no ASCII", i+1))
+				break
+			}
+		}
+	}
+	crlf := bytes.Count(data, []byte("\r\n"))
+	if lf := bytes.Count(data, []byte("\n")); crlf > 0 && crlf < lf {
+		problems = append(problems, trf("finales de línea mezclados (%d CRLF, %d LF)", crlf, lf-crlf))
+	}
+	return problems
+}
+
+// lifecycleProblems compara el mapa de índices guardado con el __lists.ini
+// del disco: cada entrada activa o retirada tiene que estar en su índice.
+func lifecycleProblems(outDir, node string) []string {
+	m, err := readLifecycle(outDir, node)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if len(m.Points) == 0 {
+		return []string{trf("no hay mapa de índices en %s: genere el nodo", lifecyclePath(outDir, node))}
+	}
+	if !GlobalConfig.App.Output.combined() {
+		return nil // sin __lists.ini combinado no hay con qué comparar
+	}
+	l, err := readListsFile(filepath.Join(outDir, GlobalConfig.App.Output.fileName()))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	items := map[string][]Point{}
+	for _, s := range listSections(l) {
+		items[s.Name] = s.Items
+	}
+	var problems []string
+	seen := map[string]string{}
+	for _, p := range m.Points {
+		slot := fmt.Sprintf("%s[%d]", p.List, p.Index)
+		if prev, dup := seen[slot]; dup {
+			problems = append(problems, trf("%s asignado a %s y a %s", slot, prev, p.Var))
+			continue
+		}
+		seen[slot] = p.Var
+		list := items[p.List]
+		switch {
+		case p.Index < 0 || p.Index >= len(list):
+			problems = append(problems, trf("%s %s: fuera de la lista (%d posiciones)", slot, p.Var, len(list)))
+		case p.State == LifecycleActive && (list[p.Index].Var != p.Var || list[p.Index].Spare != p.Spare):
+			problems = append(problems, trf("%s: el mapa tiene %s y la lista %s", slot, p.Var, list[p.Index].Name))
+		case p.State == LifecycleRetired && !list[p.Index].Spare:
+			problems = append(problems, trf("%s: índice retirado de %s ocupado por %s", slot, p.Var, list[p.Index].Name))
+		}
+	}
+	if len(problems) > 20 {
+		problems = append(problems[:20], trf("... y %d más", len(problems)-20))
+	}
+	return problems
+}
+
+// preflightMark es la marca de cada estado en la lista impresa.
+var preflightMark = map[string]string{CheckPass: "[OK]", CheckWarn: "[!!]", CheckFail: "[XX]", CheckSkip: "[--]"}
+
+func printPreflight(r PreflightNode) {
+	verdict := okText(tr("LISTO PARA DESCARGAR"))
+	if !r.OK {
+		verdict = errText(tr("NO DESCARGAR"))
+	}
+	fmt.Printf("\n%s  %s\n", boldText("=== "+r.Node+" ==="), verdict)
+	if r.Error != "" {
+		fmt.Println(errText("  " + r.Error))
+	}
+	for _, ch := range r.Checks {
+		color := map[string]func(string) string{CheckPass: okText, CheckWarn: warnText, CheckFail: errText, CheckSkip: func(s string) string { return s }}[ch.Status]
+		fmt.Println(color(fmt.Sprintf("  %s %s", preflightMark[ch.Status], ch.Title)))
+		for _, d := range ch.Details {
+			fmt.Println("       - " + d)
+		}
+	}
+}
+
+var htmlPreflightTemplate = template.Must(template.New("preflight").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="generator" content="{{.Generator}}">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; width: 100%; }
+th, td { border: 1px solid #999; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td.pass { color: #070; } td.warn { color: #a60; } td.fail { color: #b00; font-weight: bold; } td.skip { color: #777; }
+h2.fail { color: #b00; } h2.ok { color: #070; }
+.sign td { height: 3em; }
+@media print { h2 { page-break-before: auto; } table { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Project}}<br>{{.Generated}}</p>
+{{range .Nodes}}<h2 class="{{if .OK}}ok{{else}}fail{{end}}">{{.Node}}: {{if .OK}}{{$.Ready}}{{else}}{{$.NotReady}}{{end}}</h2>
+{{if .Error}}<p class="fail">{{.Error}}</p>{{end}}
+<table>
+<tr><th></th><th>{{$.CheckCol}}</th><th>{{$.DetailCol}}</th></tr>
+{{range .Checks}}<tr><td class="{{.Status}}">{{index $.Marks .Status}}</td><td>{{.Title}}</td><td>{{range .Details}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>
+{{end}}<table class="sign">
+<tr><th>{{.ReviewedBy}}</th><th>{{.Date}}</th><th>{{.Signature}}</th></tr>
+<tr><td></td><td></td><td></td></tr>
+</table>
+</body>
+</html>
+`))
+
+func writePreflightReport(path, project string, nodes []PreflightNode) error {
+	var buf bytes.Buffer
+	err := htmlPreflightTemplate.Execute(&buf, map[string]any{
+		"Lang": locale, "Generator": generatorLine(), "Generated": formatStamp(stampNow()), "Project": project,
+		"Title": tr("Comprobación antes de descargar al controlador"), "Nodes": nodes, "Marks": preflightMark,
+		"Ready": tr("LISTO PARA DESCARGAR"), "NotReady": tr("NO DESCARGAR"),
+		"CheckCol": tr("Comprobación"), "DetailCol": tr("Detalle"),
+		"ReviewedBy": tr("Revisado por"), "Date": tr("Fecha"), "Signature": tr("Firma"),
+	})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// runPreflight implementa "dnpgen preflight -path RUTA (-node A,B | -all)
+// [-out DIR] [-report FICHERO.html] [-json]".
+func runPreflight(args []string) {
+	fs := flag.NewFlagSet("preflight", flag.ExitOnError)
+	fs.Usage = manUsage("preflight", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos, separados por comas"))
+	all := fs.Bool("all", false, tr("Todos los nodos del proyecto"))
+	outDir := fs.String("out", "", tr("Directorio de salida, si no es el del recurso"))
+	report := fs.String("report", "", tr("Informe HTML imprimible de la comprobación"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(tr("Uso: dnpgen.exe preflight -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-report comprobacion.html] [-json]"))
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var nodes []string
+	if *all {
+		if nodes, err = discoverNodes(abs); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if len(nodes) == 0 {
+			log.Fatalf(tr("[FATAL] No hay nodos con .SIG en %s"), abs)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	var results []PreflightNode
+	failed := 0
+	for _, node := range nodes {
+		r := preflightNode(abs, node, *outDir)
+		if !r.OK {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		for _, r := range results {
+			printPreflight(r)
+		}
+	}
+	if *report != "" {
+		if err := writePreflightReport(*report, abs, results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		log.Printf(tr("Informe de comprobación: %s"), *report)
+	}
+	if failed > 0 {
+		log.Printf("[ERROR] %v", codedErrorf(ErrPreflightFailed, tr("%d de %d nodo(s) no están listos para descargar"), failed, len(results)))
+		os.Exit(1)
+	}
+}
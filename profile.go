@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// --- PERFIL DE DISPOSITIVO DNP3 (IEEE 1815 XML) ---
+
+func init() {
+	RegisterExporter(exporterFunc{"dnp3-profile", ".dnp3profile.xml", writeDeviceProfile})
+}
+
+// writeDeviceProfile escribe la sección DataPointsList del perfil de
+// dispositivo DNP3 en XML, que es la que importan las herramientas de
+// configuración del master. Los spares se incluyen para conservar los índices.
+func writeDeviceProfile(w io.Writer, ctx ExportContext) error {
+	l := ctx.Lists
+	sections := []struct {
+		group, element string
+		items          []Point
+	}{
+		{"BinaryInputPoints", "BinaryInput", l.DI},
+		{"BinaryOutputPoints", "BinaryOutput", l.DO},
+		{"AnalogInputPoints", "AnalogInput", l.AI},
+		{"AnalogOutputPoints", "AnalogOutput", l.AO},
+	}
+	if len(l.OS) > 0 {
+		sections = append(sections, struct {
+			group, element string
+			items          []Point
+		}{"OctetStringPoints", "OctetString", l.OS})
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(xmlGeneratorComment())
+	b.WriteString(`<DNP3DeviceProfileDocument xmlns="http://www.dnp3.org/DNP3/DeviceProfile/November2014" schemaVersion="2.11.00">` + "\n")
+	b.WriteString("  <ReferenceDevice>\n")
+	fmt.Fprintf(&b, "    <Configuration>\n      <DeviceConfig>\n        <deviceName><currentValue><value>%s</value></currentValue></deviceName>\n      </DeviceConfig>\n    </Configuration>\n", xmlEscape(ctx.Node))
+	b.WriteString("    <DataPointsList>\n")
+	for _, s := range sections {
+		fmt.Fprintf(&b, "      <%s>\n        <DataPoints>\n", s.group)
+		for idx, p := range s.items {
+			desc := p.Type
+			if p.Desc != "" {
+				desc = p.Desc
+			}
+			if p.Spare {
+				desc = "SPARE"
+			}
+			fmt.Fprintf(&b, "          <%s>\n            <index>%d</index>\n            <name>%s</name>\n            <description>%s</description>\n",
+				s.element, idx, xmlEscape(p.Name), xmlEscape(desc))
+			if scale, offset, units := scalingColumns(p); scale != "" {
+				fmt.Fprintf(&b, "            <scaleFactor>%s</scaleFactor>\n            <scaleOffset>%s</scaleOffset>\n", scale, offset)
+				if units != "" {
+					fmt.Fprintf(&b, "            <units>%s</units>\n", xmlEscape(units))
+				}
+			}
+			if o := p.Object; o != nil {
+				fmt.Fprintf(&b, "            <defaultStaticVariation>%d</defaultStaticVariation>\n", o.Variation)
+				if o.EventVariation > 0 {
+					fmt.Fprintf(&b, "            <defaultEventVariation>%d</defaultEventVariation>\n", o.EventVariation)
+				}
+			}
+			fmt.Fprintf(&b, "          </%s>\n", s.element)
+		}
+		fmt.Fprintf(&b, "        </DataPoints>\n      </%s>\n", s.group)
+	}
+	b.WriteString("    </DataPointsList>\n  </ReferenceDevice>\n</DNP3DeviceProfileDocument>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
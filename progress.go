@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- PROGRESO Y TIEMPOS POR ETAPA ---
+
+// pipelineStages son las etapas de runGenerate, en orden.
+var pipelineStages = []string{"sigext", "parse", "classify", "write", "export"}
+
+// StageTiming es la duración de una etapa del pipeline.
+type StageTiming struct {
+	Stage    string        `json:"stage"`
+	Duration time.Duration `json:"-"`
+	Millis   int64         `json:"ms"`
+}
+
+// stageTimer mide las etapas de una ejecución y reenvía cada cambio de etapa
+// al callback de progreso y a los eventos de la petición.
+type stageTimer struct {
+	req     GenerateRequest
+	current string
+	started time.Time
+	timings []StageTiming
+}
+
+// stage inicia una etapa y cierra la anterior. Llamarla de nuevo con la
+// etapa en curso solo actualiza el mensaje.
+func (t *stageTimer) stage(name, message string) {
+	if name != t.current {
+		t.end()
+		t.current, t.started = name, time.Now()
+		t.req.emit(GenerateEvent{Kind: EventStageStarted, Stage: name, Message: message})
+	}
+	t.req.progress(name, message)
+}
+
+func (t *stageTimer) end() {
+	if t.current == "" {
+		return
+	}
+	d := time.Since(t.started)
+	t.timings = append(t.timings, StageTiming{Stage: t.current, Duration: d, Millis: d.Milliseconds()})
+	t.req.emit(GenerateEvent{Kind: EventStageFinished, Stage: t.current, Millis: d.Milliseconds()})
+	t.current = ""
+}
+
+func (t *stageTimer) done() []StageTiming {
+	t.end()
+	return t.timings
+}
+
+func printTimings(timings []StageTiming) {
+	if len(timings) == 0 {
+		return
+	}
+	var parts []string
+	var total time.Duration
+	for _, t := range timings {
+		parts = append(parts, fmt.Sprintf("%s %s", t.Stage, t.Duration.Round(time.Millisecond)))
+		total += t.Duration
+	}
+	fmt.Printf(tr("Tiempos: %s (total %s)\n"), strings.Join(parts, " | "), total.Round(time.Millisecond))
+}
+
+// consoleProgress muestra la etapa en curso en la consola. Si la salida es
+// un terminal, mantiene un contador de tiempo para que las etapas largas
+// (SIGEXT en proyectos grandes) no parezcan colgadas.
+type consoleProgress struct {
+	mu      sync.Mutex
+	total   int
+	offset  int
+	tty     bool
+	stage   string
+	started time.Time
+	quit    chan struct{}
+}
+
+func newConsoleProgress(withSigExt bool) *consoleProgress {
+	p := &consoleProgress{total: len(pipelineStages), quit: make(chan struct{})}
+	if !withSigExt {
+		p.total--
+		p.offset = 1
+	}
+	if isTerminal(os.Stderr) {
+		p.tty = true
+		go p.tick()
+	}
+	return p
+}
+
+func (p *consoleProgress) update(stage, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if stage == p.stage {
+		return
+	}
+	p.clearLine()
+	p.stage, p.started = stage, time.Now()
+	n := 0
+	for i, s := range pipelineStages {
+		if s == stage {
+			n = i + 1 - p.offset
+		}
+	}
+	fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", n, p.total, message)
+}
+
+func (p *consoleProgress) tick() {
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-t.C:
+			p.mu.Lock()
+			if p.stage != "" && time.Since(p.started) >= time.Second {
+				fmt.Fprintf(os.Stderr, "\r      %s... %s", p.stage, time.Since(p.started).Round(time.Second))
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// clearLine borra el contador de tiempo antes de escribir otra línea.
+func (p *consoleProgress) clearLine() {
+	if p.tty && p.stage != "" && time.Since(p.started) >= time.Second {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// pause envuelve una pregunta de consola: el contador de tiempo no escribe
+// mientras se espera la respuesta.
+func (p *consoleProgress) pause(ask mergeResolver) mergeResolver {
+	return func(c *MergeConflict) (string, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.clearLine()
+		defer func() { p.started = time.Now() }()
+		return ask(c)
+	}
+}
+
+func (p *consoleProgress) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clearLine()
+	p.stage = ""
+	close(p.quit)
+}
+
+// --- EVENTOS DE LA GENERACIÓN ---
+//
+// GenerateRequest.Events recibe, para quien integra dnpgen como biblioteca
+// (interfaces gráficas, modo servidor), el avance de la generación sin tener
+// que interpretar el log: inicio y fin de cada etapa, los avisos y, mientras
+// se lee el .SIG, el número de señales cada ProgressEvery. El callback se
+// llama desde la goroutine de la generación y no debe bloquearla.
+
+// Tipos de GenerateEvent.
+const (
+	EventStageStarted  = "stage_started"
+	EventStageFinished = "stage_finished"
+	EventWarning       = "warning"
+	EventProgress      = "progress"
+)
+
+// defaultProgressEvery es cada cuántas señales se emite EventProgress.
+const defaultProgressEvery = 1000
+
+// GenerateEvent es un evento de la generación.
+type GenerateEvent struct {
+	Kind    string `json:"kind"`
+	Stage   string `json:"stage,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Code es el código del hallazgo de un aviso (vacío en los del log).
+	Code string `json:"code,omitempty"`
+	// Signals, Read y Size son el avance de la lectura del .SIG (progress):
+	// señales leídas y bytes leídos del total.
+	Signals int   `json:"signals,omitempty"`
+	Read    int64 `json:"read,omitempty"`
+	Size    int64 `json:"size,omitempty"`
+	// Millis es la duración de la etapa (stage_finished).
+	Millis int64     `json:"ms,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+func (r GenerateRequest) emit(e GenerateEvent) {
+	if r.Events != nil {
+		e.Time = stampNow()
+		r.Events(e)
+	}
+}
+
+func (r GenerateRequest) progressEvery() int {
+	if r.ProgressEvery > 0 {
+		return r.ProgressEvery
+	}
+	return defaultProgressEvery
+}
+
+// warnf escribe un aviso en el log y lo emite como EventWarning.
+func (r GenerateRequest) warnf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	log.Output(2, msg)
+	r.emit(GenerateEvent{Kind: EventWarning, Message: strings.TrimPrefix(msg, "[WARN] ")})
+}
+
+// sigProgress devuelve el callback de avance de la lectura del .SIG, o nil
+// si la petición no tiene Events.
+func (r GenerateRequest) sigProgress(stage string) func(signals int, read, size int64) {
+	if r.Events == nil {
+		return nil
+	}
+	every := r.progressEvery()
+	return func(signals int, read, size int64) {
+		if signals%every == 0 {
+			r.emit(GenerateEvent{Kind: EventProgress, Stage: stage, Signals: signals, Read: read, Size: size})
+		}
+	}
+}
+
+// countingReader cuenta los bytes leídos.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// --- PREGUNTAS INTERACTIVAS ---
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// isTerminal indica si f es una consola interactiva (no basta con
+// ModeCharDevice: /dev/null y NUL también lo son).
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+func promptLine(question string) string {
+	fmt.Fprint(os.Stderr, question)
+	line, _ := stdinReader.ReadString('\n')
+	return strings.Trim(strings.TrimSpace(line), `"`)
+}
+
+// promptNode ofrece los nodos encontrados en el proyecto para elegir uno por
+// número; también se puede escribir el nombre directamente.
+func promptNode(projectPath string) string {
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		log.Fatalf(tr("Error ruta absoluta: %v"), err)
+	}
+	nodes, _ := listProjectNodes(abs)
+	if len(nodes) == 0 {
+		fmt.Fprintln(os.Stderr, tr("No se encontraron nodos en el proyecto."))
+		if name := promptLine(tr("Nombre del nodo: ")); name != "" {
+			return name
+		}
+		log.Fatal(tr("[FATAL] No se indicó ningún nodo"))
+	}
+
+	fmt.Fprintln(os.Stderr, tr("Nodos del proyecto:"))
+	for i, n := range nodes {
+		sig := tr("sin .SIG")
+		if n.SigTime != nil {
+			sig = "SIG " + formatStamp(*n.SigTime)
+		}
+		fmt.Fprintf(os.Stderr, "  %2d) %-20s %s\n", i+1, n.Node, sig)
+	}
+	for {
+		answer := promptLine(fmt.Sprintf(tr("Nodo [1-%d o nombre]: "), len(nodes)))
+		if answer == "" {
+			continue
+		}
+		if i, err := strconv.Atoi(answer); err == nil {
+			if i >= 1 && i <= len(nodes) {
+				return nodes[i-1].Node
+			}
+			fmt.Fprintln(os.Stderr, tr("Número fuera de rango"))
+			continue
+		}
+		return answer
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// --- GENERACIÓN MULTIPROTOCOLO ---
+
+// ProtocolsConfig declara los protocolos que se generan para cada nodo a
+// partir de la misma clasificación. DNP3 (__lists.ini) se genera siempre;
+// iec104 y modbus añaden su mapa de puntos, con las direcciones derivadas
+// del índice DNP3 para que los tres protocolos describan el mismo modelo.
+type ProtocolsConfig struct {
+	Targets []string            `yaml:"targets"` // por defecto [dnp3]
+	Nodes   map[string][]string `yaml:"nodes"`   // sustituye a targets para un nodo
+	IEC104  IEC104Config        `yaml:"iec104"`
+	Modbus  ModbusConfig        `yaml:"modbus"`
+}
+
+// IEC104Config fija la dirección común (ASDU) y la primera IOA de cada lista.
+type IEC104Config struct {
+	CommonAddress int            `yaml:"common_address"`
+	IOABase       map[string]int `yaml:"ioa_base"` // DI/DO/AI/AO -> primera IOA
+}
+
+// ModbusConfig fija la unidad y la primera dirección de cada lista; AI y AO
+// ocupan dos registros por punto (float32).
+type ModbusConfig struct {
+	UnitID int            `yaml:"unit_id"`
+	Base   map[string]int `yaml:"base"`
+}
+
+// protocolExporters son los exportadores que aporta cada protocolo.
+var protocolExporters = map[string]string{
+	"iec104": "iec104",
+	"modbus": "modbus",
+}
+
+// targetsFor devuelve los protocolos del nodo, validados.
+func (c ProtocolsConfig) targetsFor(node string) ([]string, error) {
+	targets := c.Targets
+	for k, v := range c.Nodes {
+		if strings.EqualFold(k, node) {
+			targets = v
+		}
+	}
+	var out []string
+	for _, t := range targets {
+		t = strings.ToLower(t)
+		if _, ok := protocolExporters[t]; !ok && t != "dnp3" {
+			return nil, fmt.Errorf("protocolo desconocido %q (disponibles: dnp3, iec104, modbus)", t)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// protocolExportNames devuelve los exportadores de los protocolos del nodo
+// que no estén ya en app.exports.
+func protocolExportNames(node string) ([]string, error) {
+	targets, err := GlobalConfig.App.Protocols.targetsFor(node)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range targets {
+		name, ok := protocolExporters[t]
+		if !ok || containsFold(GlobalConfig.App.Exports, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"iec104", ".iec104.csv", writeIEC104})
+	RegisterExporter(exporterFunc{"modbus", ".modbus.csv", writeModbus})
+}
+
+// iec104Types son los tipos ASDU de cada lista: monitorización para las
+// entradas y comando para las salidas.
+var iec104Types = map[string]struct {
+	id   int
+	name string
+}{
+	"DI": {1, "M_SP_NA_1"},
+	"DO": {45, "C_SC_NA_1"},
+	"AI": {13, "M_ME_NC_1"},
+	"AO": {50, "C_SE_NC_1"},
+}
+
+var iec104DefaultBase = map[string]int{"DI": 1000, "DO": 2000, "AI": 3000, "AO": 4000}
+
+// writeIEC104 escribe el mapa IEC 60870-5-104: IOA = base de la lista +
+// índice DNP3. Los spares no tienen IOA pero conservan el hueco.
+func writeIEC104(out io.Writer, ctx ExportContext) error {
+	cfg := GlobalConfig.App.Protocols.IEC104
+	ca := cfg.CommonAddress
+	if ca == 0 {
+		ca = 1
+	}
+	w := csv.NewWriter(out)
+	w.Write([]string{"node", "common_address", "ioa", "type_id", "type", "list", "dnp3_index", "name", "var", "description"})
+	for _, list := range listSections(ctx.Lists) {
+		typ, ok := iec104Types[list.Name]
+		if !ok {
+			continue
+		}
+		base, ok := cfg.IOABase[list.Name]
+		if !ok {
+			base = iec104DefaultBase[list.Name]
+		}
+		for idx, p := range list.Items {
+			if p.Spare {
+				continue
+			}
+			w.Write([]string{ctx.Node, strconv.Itoa(ca), strconv.Itoa(base + idx), strconv.Itoa(typ.id), typ.name,
+				list.Name, strconv.Itoa(idx), p.Name, p.Var, p.Desc})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// modbusTables son la tabla Modbus y el número de registros por punto.
+var modbusTables = map[string]struct {
+	table string
+	width int
+}{
+	"DI": {"discrete_input", 1},
+	"DO": {"coil", 1},
+	"AI": {"input_register", 2},
+	"AO": {"holding_register", 2},
+}
+
+// writeModbus escribe el mapa Modbus: dirección = base + índice DNP3 *
+// registros por punto, con los huecos de los spares reservados.
+func writeModbus(out io.Writer, ctx ExportContext) error {
+	cfg := GlobalConfig.App.Protocols.Modbus
+	unit := cfg.UnitID
+	if unit == 0 {
+		unit = 1
+	}
+	w := csv.NewWriter(out)
+	w.Write([]string{"node", "unit_id", "table", "address", "data_type", "list", "dnp3_index", "name", "var", "description"})
+	for _, list := range listSections(ctx.Lists) {
+		t, ok := modbusTables[list.Name]
+		if !ok {
+			continue
+		}
+		dataType := "bool"
+		if t.width == 2 {
+			dataType = "float32"
+		}
+		for idx, p := range list.Items {
+			if p.Spare {
+				continue
+			}
+			w.Write([]string{ctx.Node, strconv.Itoa(unit), t.table, strconv.Itoa(cfg.Base[list.Name] + idx*t.width), dataType,
+				list.Name, strconv.Itoa(idx), p.Name, p.Var, p.Desc})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// --- PUBLICACIÓN DE ARTEFACTOS ---
+
+// PublishConfig copia las listas, exportaciones y manifiesto de cada
+// generación a uno o varios destinos (carpeta compartida, SFTP o un bucket
+// compatible con S3), con una ruta por nodo y fecha.
+type PublishConfig struct {
+	Targets []PublishTarget `yaml:"targets"`
+	// Required hace que un fallo de publicación falle la generación; por
+	// defecto solo se avisa y las listas quedan escritas en local.
+	Required bool `yaml:"required"`
+}
+
+// PublishTarget es un destino de publicación.
+type PublishTarget struct {
+	Type string `yaml:"type"` // dir, sftp, s3
+	// Path es la carpeta de destino (dir: ruta local o UNC; sftp: directorio
+	// remoto; s3: prefijo de la clave). Admite {project}, {node}, {date} y
+	// {time}; vacío = {node}/{date}.
+	Path string `yaml:"path"`
+
+	// SFTP
+	Host       string `yaml:"host"` // host[:puerto], 22 por defecto
+	User       string `yaml:"user"`
+	Password   string `yaml:"password"` // admite ${VAR} de entorno
+	KeyFile    string `yaml:"key_file"`
+	KnownHosts string `yaml:"known_hosts"` // obligatorio salvo insecure_ignore_host_key
+	// InsecureIgnoreHostKey acepta cualquier clave del host, como en
+	// sigext_remote; se avisa en cada conexión.
+	InsecureIgnoreHostKey bool `yaml:"insecure_ignore_host_key"`
+
+	// S3 / MinIO (direccionamiento por ruta: endpoint/bucket/clave)
+	Endpoint  string `yaml:"endpoint"` // vacío = https://s3.<region>.amazonaws.com
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region"`     // us-east-1 por defecto
+	AccessKey string `yaml:"access_key"` // admite ${VAR} de entorno
+	SecretKey string `yaml:"secret_key"` // admite ${VAR} de entorno
+
+	TimeoutSec int `yaml:"timeout_sec"`
+}
+
+func (t PublishTarget) timeout() time.Duration {
+	if t.TimeoutSec > 0 {
+		return time.Duration(t.TimeoutSec) * time.Second
+	}
+	return 60 * time.Second
+}
+
+// dest resuelve la ruta de destino para un nodo en el instante now.
+func (t PublishTarget) dest(absProjectPath, node string, now time.Time) string {
+	tpl := t.Path
+	if tpl == "" {
+		tpl = "{node}/{date}"
+	}
+	return expandTemplate(tpl, map[string]string{
+		"project": filepath.Base(absProjectPath),
+		"node":    node,
+		"date":    now.Format("2006-01-02"),
+		"time":    now.Format("150405"),
+	})
+}
+
+// describe es la forma corta del destino para los mensajes.
+func (t PublishTarget) describe(dest string) string {
+	switch strings.ToLower(t.Type) {
+	case "sftp":
+		return t.User + "@" + t.Host + ":" + dest
+	case "s3":
+		return "s3://" + t.Bucket + "/" + strings.Trim(dest, "/")
+	}
+	return dest
+}
+
+// publishFiles devuelve los ficheros de una generación a publicar: la lista
+// combinada y todos los artefactos (listas separadas, exportaciones,
+// métricas, configuración efectiva y manifiesto).
+func publishFiles(res *GenerateResult) []string {
+	var files []string
+	if res.ListFile != "" {
+		files = append(files, res.ListFile)
+	}
+	return append(files, res.Artifacts...)
+}
+
+// publishArtifacts copia los ficheros de res a cada destino configurado y
+// devuelve la descripción de los destinos publicados. Se intentan todos los
+// destinos aunque alguno falle.
+func publishArtifacts(absProjectPath, node string, res *GenerateResult) ([]string, error) {
+	files := publishFiles(res)
+	now := time.Now()
+	var done, failed []string
+	for _, t := range GlobalConfig.App.Publish.Targets {
+		dest := t.dest(absProjectPath, node, now)
+		where := t.describe(dest)
+		log.Printf(tr("Publicando %d fichero(s) en %s..."), len(files), where)
+		var err error
+		switch strings.ToLower(t.Type) {
+		case "dir", "":
+			err = publishDir(dest, files)
+		case "sftp":
+			err = publishSFTP(t, dest, files)
+		case "s3":
+			err = publishS3(t, dest, files)
+		default:
+			err = fmt.Errorf(tr("tipo de destino %q no soportado (dir, sftp, s3)"), t.Type)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", where, err))
+			continue
+		}
+		done = append(done, where)
+	}
+	if len(failed) > 0 {
+		return done, fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return done, nil
+}
+
+// publishDir copia a una carpeta local o compartida (\\servidor\recurso).
+func publishDir(dest string, files []string) error {
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(dest, filepath.Base(f)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func publishSFTP(t PublishTarget, dest string, files []string) error {
+	if t.Host == "" {
+		return fmt.Errorf("sftp: falta host")
+	}
+	remote := SigExtRemote{Host: t.Host, User: t.User, Password: t.Password, KeyFile: t.KeyFile,
+		KnownHosts: t.KnownHosts, InsecureIgnoreHostKey: t.InsecureIgnoreHostKey, TimeoutSec: int(t.timeout() / time.Second)}
+	cfg, err := remote.clientConfig("sftp")
+	if err != nil {
+		return err
+	}
+	addr := t.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return fmt.Errorf("ssh %s: %v", addr, err)
+	}
+	defer client.Close()
+	fs, err := sftp.NewClient(client)
+	if err != nil {
+		return fmt.Errorf("sftp: %v", err)
+	}
+	defer fs.Close()
+
+	dir := strings.ReplaceAll(dest, `\`, "/")
+	if err := fs.MkdirAll(dir); err != nil {
+		return fmt.Errorf("sftp mkdir %s: %v", dir, err)
+	}
+	for _, f := range files {
+		if err := sftpUpload(fs, f, path.Join(dir, filepath.Base(f))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishS3 sube cada fichero con un PUT firmado (AWS Signature V4), válido
+// para AWS S3, MinIO y demás servicios compatibles.
+func publishS3(t PublishTarget, dest string, files []string) error {
+	if t.Bucket == "" {
+		return fmt.Errorf("s3: falta bucket")
+	}
+	region := t.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := t.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	base, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return fmt.Errorf("s3: endpoint inválido %q", endpoint)
+	}
+	accessKey, secretKey := os.ExpandEnv(t.AccessKey), os.ExpandEnv(t.SecretKey)
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("s3: faltan access_key o secret_key")
+	}
+
+	client := &http.Client{Timeout: t.timeout()}
+	prefix := strings.Trim(strings.ReplaceAll(dest, `\`, "/"), "/")
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		key := path.Join(prefix, filepath.Base(f))
+		req, err := http.NewRequest(http.MethodPut, base.String()+s3Escape("/"+t.Bucket+"/"+key), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		signS3(req, data, region, accessKey, secretKey, time.Now().UTC())
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("s3 %s: %v", key, err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("s3 %s: %s %s", key, resp.Status, strings.TrimSpace(string(body)))
+		}
+	}
+	return nil
+}
+
+// signS3 añade las cabeceras de la firma V4 de AWS a una petición.
+func signS3(req *http.Request, payload []byte, region, accessKey, secretKey string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signed = "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{day, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3Escape codifica una ruta según RFC 3986 conservando las '/', como exige
+// la ruta canónica de la firma V4.
+func s3Escape(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// --- RENOMBRADO MASIVO DE VARIABLES ---
+//
+// Al renombrar variables en el proyecto de ControlWave el .SIG trae los
+// nombres nuevos, pero el SCADA sigue buscando los antiguos y, con
+// app.lifecycle, las variables viejas pasarían a retiradas y desplazarían
+// los índices. "dnpgen rename -map renames.csv" traslada cada entrada del
+// mapa de índices a su nombre nuevo, regenera las listas comprobando que
+// cada punto conserva su índice y escribe el informe de renombrado para el
+// SCADA (lista, índice, nombre anterior y nuevo).
+
+// variableRename es una fila del mapa de renombrado.
+type variableRename struct {
+	Old, New string
+}
+
+// readRenameMap lee un CSV "antiguo,nuevo" (sin "@GV."; admite cabecera
+// old,new y líneas # de comentario).
+func readRenameMap(path string) ([]variableRename, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	var out []variableRename
+	olds, news := map[string]bool{}, map[string]bool{}
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if line == 1 && strings.EqualFold(rec[0], "old") && strings.EqualFold(rec[1], "new") {
+			continue
+		}
+		rn := variableRename{Old: strings.TrimPrefix(strings.TrimSpace(rec[0]), "@GV."), New: strings.TrimPrefix(strings.TrimSpace(rec[1]), "@GV.")}
+		switch {
+		case rn.Old == "" || rn.New == "":
+			return nil, fmt.Errorf(tr("%s: fila %d incompleta"), path, line)
+		case rn.Old == rn.New:
+			continue
+		case olds[rn.Old]:
+			return nil, fmt.Errorf(tr("%s: %s aparece dos veces"), path, rn.Old)
+		case news[rn.New]:
+			return nil, fmt.Errorf(tr("%s: dos variables se renombran a %s"), path, rn.New)
+		}
+		olds[rn.Old], news[rn.New] = true, true
+		out = append(out, rn)
+	}
+	for _, rn := range out {
+		if olds[rn.New] {
+			return nil, fmt.Errorf(tr("%s: %s es a la vez nombre antiguo y nuevo (renombrados encadenados)"), path, rn.New)
+		}
+	}
+	return out, nil
+}
+
+// renameLifecycle traslada las entradas del mapa de índices a los nombres
+// nuevos. Falla si un nombre nuevo ya tiene entrada propia.
+func renameLifecycle(m *lifecycleMap, renames []variableRename) (int, error) {
+	byOld := map[string]string{}
+	for _, rn := range renames {
+		byOld[rn.Old] = rn.New
+	}
+	for _, p := range m.Points {
+		for _, rn := range renames {
+			if p.Var == rn.New && p.State != LifecycleReserved {
+				return 0, fmt.Errorf(tr("%s ya está en el mapa de índices (%s[%d])"), rn.New, p.List, p.Index)
+			}
+		}
+	}
+	n := 0
+	for i, p := range m.Points {
+		if v, ok := byOld[p.Var]; ok && p.State != LifecycleReserved {
+			m.Points[i].Var = v
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RenameRow es una fila del informe de renombrado: una posición de la
+// variable antes y después.
+type RenameRow struct {
+	List     string `json:"list"`
+	OldIndex int    `json:"old_index"` // -1 si no estaba
+	NewIndex int    `json:"new_index"` // -1 si ya no está
+	OldName  string `json:"old_name"`
+	NewName  string `json:"new_name"`
+	Status   string `json:"status"`
+}
+
+// Estados de una fila del informe.
+const (
+	RenameOK      = "ok"      // mismo índice con el nombre nuevo
+	RenameMoved   = "moved"   // el punto cambió de índice
+	RenameMissing = "missing" // el nombre nuevo no está en las listas generadas
+	RenameUnused  = "unused"  // el nombre antiguo no estaba en las listas anteriores
+)
+
+// positionsOf devuelve las entradas de v por lista, en orden.
+func positionsOf(l *Lists, v string) map[string][]int {
+	out := map[string][]int{}
+	if l == nil {
+		return out
+	}
+	for _, sec := range listSections(l) {
+		for idx, p := range sec.Items {
+			if p.Var == v && !p.Reserved && !p.System {
+				out[sec.Name] = append(out[sec.Name], idx)
+			}
+		}
+	}
+	return out
+}
+
+// renameReport compara las posiciones de cada variable antigua en las
+// listas anteriores con las de su nombre nuevo en las generadas.
+func renameReport(before, after *Lists, renames []variableRename) []RenameRow {
+	var rows []RenameRow
+	for _, rn := range renames {
+		was, is := positionsOf(before, rn.Old), positionsOf(after, rn.New)
+		if len(was) == 0 && len(is) == 0 {
+			rows = append(rows, RenameRow{OldIndex: -1, NewIndex: -1, OldName: rn.Old, NewName: rn.New, Status: RenameMissing})
+			continue
+		}
+		for _, sec := range listSections(after) {
+			w, i := was[sec.Name], is[sec.Name]
+			for k := 0; k < max(len(w), len(i)); k++ {
+				row := RenameRow{List: sec.Name, OldIndex: -1, NewIndex: -1, Status: RenameOK}
+				if k < len(w) {
+					row.OldIndex, row.OldName = w[k], before.pointAt(sec.Name, w[k]).Name
+				}
+				if k < len(i) {
+					row.NewIndex, row.NewName = i[k], after.pointAt(sec.Name, i[k]).Name
+				}
+				switch {
+				case row.OldIndex < 0:
+					row.Status = RenameUnused
+				case row.NewIndex < 0:
+					row.Status = RenameMissing
+				case row.OldIndex != row.NewIndex:
+					row.Status = RenameMoved
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// pointAt devuelve la entrada idx de la lista name.
+func (l *Lists) pointAt(name string, idx int) Point {
+	for _, sec := range listSections(l) {
+		if sec.Name == name && idx < len(sec.Items) {
+			return sec.Items[idx]
+		}
+	}
+	return Point{}
+}
+
+// reportIndex escribe un índice del informe; vacío si no hay posición.
+func reportIndex(i int) string {
+	if i < 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+func writeRenameCSV(out io.Writer, rows []RenameRow) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"list", "old_index", "new_index", "old_name", "new_name", "status"})
+	for _, r := range rows {
+		w.Write([]string{r.List, reportIndex(r.OldIndex), reportIndex(r.NewIndex), r.OldName, r.NewName, r.Status})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("csv: %v", err)
+	}
+	return nil
+}
+
+// runRename implementa "dnpgen rename".
+func runRename(args []string) {
+	fs := flag.NewFlagSet("rename", flag.ExitOnError)
+	fs.Usage = manUsage("rename", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo"))
+	mapPath := fs.String("map", "", tr("CSV de renombrado: nombre antiguo,nombre nuevo"))
+	skipExt := fs.Bool("skip-ext", false, tr("Saltar ejecución de SIGEXT"))
+	reportPath := fs.String("report", "", tr("Fichero CSV para el informe de renombrado (vacío = <salida>/<nodo>.renames.csv)"))
+	dryRun := fs.Bool("dry-run", false, tr("Solo comprobar: no modificar el mapa de índices ni las listas"))
+	force := fs.Bool("force", false, tr("Escribir aunque algún punto cambie de índice"))
+	fs.Parse(args)
+
+	if *projectPath == "" || *nodeName == "" || *mapPath == "" {
+		log.Fatal(tr("Uso: dnpgen.exe rename -path \"C:\\Ruta\" -node \"NombreNodo\" -map renames.csv [-dry-run] [-force]"))
+	}
+	abs, err := filepath.Abs(*projectPath)
+	if err != nil {
+		log.Fatalf(tr("Error ruta absoluta: %v"), err)
+	}
+	renames, err := readRenameMap(*mapPath)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if len(renames) == 0 {
+		log.Printf(tr("%s no contiene renombrados"), *mapPath)
+		return
+	}
+
+	outDir := outputDirFor(abs, *nodeName, "")
+	before, err := readListsFile(listsPathFor(abs, *nodeName))
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var lifecycle *lifecycleMap
+	if fileExists(lifecyclePath(outDir, *nodeName)) {
+		if lifecycle, err = readLifecycle(outDir, *nodeName); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		n, err := renameLifecycle(lifecycle, renames)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		log.Printf(tr("Mapa de índices: %d entrada(s) renombradas"), n)
+	}
+
+	req := GenerateRequest{ProjectPath: abs, NodeName: *nodeName, SkipExt: *skipExt, CheckOnly: true, lifecycle: lifecycle}
+	res, err := runGenerate(req)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	rows := renameReport(before, res.lists, renames)
+	moved := 0
+	var table [][]string
+	for _, r := range rows {
+		if r.Status == RenameMoved || r.Status == RenameMissing {
+			moved++
+		}
+		table = append(table, []string{r.List, reportIndex(r.OldIndex), reportIndex(r.NewIndex), r.OldName, r.NewName, r.Status})
+	}
+	printTable(tr("LISTA\tANTES\tDESPUÉS\tNOMBRE ANTERIOR\tNOMBRE NUEVO\tESTADO"), table, nil)
+
+	if *dryRun {
+		log.Println(tr("Simulación: no se modifica el mapa de índices ni las listas"))
+		if moved > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	if moved > 0 && !*force {
+		log.Fatalf(tr("[FATAL] %d punto(s) cambiarían de índice o no aparecen con el nombre nuevo; use -force para escribir igualmente"), moved)
+	}
+	if lifecycle != nil {
+		if err := writeLifecycle(outDir, *nodeName, lifecycle); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	// El .SIG ya está actualizado por la pasada de comprobación.
+	req.CheckOnly, req.SkipExt, req.lifecycle = false, true, nil
+	res, err = runGenerate(req)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if !reflect.DeepEqual(renameReport(before, res.lists, renames), rows) {
+		log.Println(tr("[WARN] Las listas escritas no coinciden con la comprobación previa"))
+	}
+
+	report := *reportPath
+	if report == "" {
+		report = filepath.Join(outDir, *nodeName+".renames.csv")
+	}
+	f, err := os.Create(report)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	defer f.Close()
+	if err := writeRenameCSV(f, rows); err != nil {
+		log.Fatalf(tr("[FATAL] Informe de renombrado: %v"), err)
+	}
+	printSummary(res)
+	log.Printf(tr("Informe de renombrado para el SCADA: %s"), report)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- RESERVA DE ÍNDICES ---
+
+// IndexReservation aparta un rango de índices de una lista (p.ej. DI 0-15
+// para diagnósticos internos). La asignación automática los salta y en su
+// lugar se escribe Name, una plantilla con {list}, {index} y {spare}.
+type IndexReservation struct {
+	List string `yaml:"list"`
+	From int    `yaml:"from"`
+	To   int    `yaml:"to"`
+	Name string `yaml:"name"` // vacío = "{spare}" (el siguiente spare de la lista, según su estrategia)
+
+	system  *SystemPoint // no nil si la reserva proviene de app.system_points
+	derived []Point      // no nil si la reserva es el rango de app.derived_points
+}
+
+// SystemPoint es un punto fijo de sistema o diagnóstico (estado de
+// comunicaciones, indicaciones IIN, watchdog...) que se inyecta en todos los
+// nodos en el índice indicado. Name admite {node} y {list}.
+type SystemPoint struct {
+	List  string `yaml:"list"`
+	Index int    `yaml:"index"`
+	Name  string `yaml:"name"`
+	Type  string `yaml:"type"`
+}
+
+// systemReservations convierte los puntos de sistema en reservas de un
+// índice, para que compartan la inserción y la detección de solapes.
+func systemReservations(points []SystemPoint) ([]IndexReservation, error) {
+	var out []IndexReservation
+	for i := range points {
+		p := &points[i]
+		if p.Name == "" {
+			return nil, fmt.Errorf(tr("system_points: falta name en %s %d"), p.List, p.Index)
+		}
+		out = append(out, IndexReservation{List: p.List, From: p.Index, To: p.Index, Name: p.Name, system: p})
+	}
+	return out, nil
+}
+
+// applyReservations inserta los marcadores de las reservas y los puntos de
+// sistema en las listas, desplazando los puntos generados a los índices
+// libres.
+func applyReservations(l *Lists, node string, reservations []IndexReservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	targets := map[string]*[]Point{"AI": &l.AI, "AO": &l.AO, "DI": &l.DI, "DO": &l.DO}
+
+	byList := map[string][]IndexReservation{}
+	for _, r := range reservations {
+		list := strings.ToUpper(r.List)
+		if _, ok := targets[list]; !ok {
+			return fmt.Errorf(tr("reserva: lista desconocida %q"), r.List)
+		}
+		if r.From < 0 || r.To < r.From {
+			return fmt.Errorf(tr("reserva %s: rango inválido %d-%d"), list, r.From, r.To)
+		}
+		byList[list] = append(byList[list], r)
+	}
+
+	for list, rs := range byList {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].From < rs[j].From })
+		for i := 1; i < len(rs); i++ {
+			if rs[i].From <= rs[i-1].To {
+				return fmt.Errorf(tr("reserva %s: los rangos %d-%d y %d-%d se solapan"), list, rs[i-1].From, rs[i-1].To, rs[i].From, rs[i].To)
+			}
+		}
+
+		items := targets[list]
+		spares, err := newSpareAllocator(list, *items)
+		if err != nil {
+			return err
+		}
+		generated := *items
+		out := make([]Point, 0, len(generated))
+		next := 0
+		for _, r := range rs {
+			// Los puntos generados ocupan los índices libres previos a la reserva.
+			for len(out) < r.From && next < len(generated) {
+				out = append(out, generated[next])
+				next++
+			}
+			// Si no hay puntos suficientes, el hueco hasta la reserva se rellena
+			// con spares para que los índices reservados sean los pedidos.
+			for len(out) < r.From {
+				spare, err := spares.next("", "")
+				if err != nil {
+					return fmt.Errorf(tr("reserva %s %d-%d: %v"), list, r.From, r.To, err)
+				}
+				out = append(out, spare)
+			}
+			tpl := r.Name
+			if tpl == "" {
+				tpl = "{spare}"
+			}
+			if r.system != nil {
+				name := expandTemplate(r.Name, map[string]string{"list": list, "node": node})
+				out = append(out, Point{Name: name, Var: strings.TrimPrefix(name, "@GV."), Type: r.system.Type, System: true})
+				continue
+			}
+			for idx := r.From; idx <= r.To; idx++ {
+				if r.derived != nil {
+					if k := idx - r.From; k < len(r.derived) {
+						out = append(out, r.derived[k])
+						continue
+					}
+				}
+				vars := map[string]string{"list": list, "index": strconv.Itoa(idx), "node": node}
+				if strings.Contains(tpl, "{spare}") {
+					spare, err := spares.next("", "")
+					if err != nil {
+						return fmt.Errorf(tr("reserva %s %d-%d: %v"), list, r.From, r.To, err)
+					}
+					vars["spare"] = spare.Name
+				}
+				name := expandTemplate(tpl, vars)
+				out = append(out, Point{Name: name, Var: strings.TrimPrefix(name, "@GV."), Spare: true, Reserved: true})
+			}
+		}
+		*items = append(out, generated[next:]...)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// --- COMPROBACIÓN DE IDA Y VUELTA ---
+//
+// El __lists.ini se vuelve a leer con el mismo lector que usan compact,
+// verify o simulate y se compara con el modelo del que salió: un error del
+// escritor (codificación, saltos de línea, secciones) se detecta aquí y no
+// en la RTU.
+
+// compareLists describe las diferencias entre dos modelos: número de
+// puntos, orden y nombres de cada sección.
+func compareLists(want, got *Lists) []string {
+	gotSections := map[string]listSection{}
+	for _, s := range listSections(got) {
+		gotSections[s.Name] = s
+	}
+	var problems []string
+	for _, w := range listSections(want) {
+		g, ok := gotSections[w.Name]
+		delete(gotSections, w.Name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf(tr("%s: la sección %s no se encontró al releer"), w.Name, w.Code))
+			continue
+		}
+		if len(w.Items) != len(g.Items) {
+			problems = append(problems, fmt.Sprintf(tr("%s: %d puntos generados, %d al releer"), w.Name, len(w.Items), len(g.Items)))
+		}
+		for i := 0; i < min(len(w.Items), len(g.Items)); i++ {
+			wp, gp := w.Items[i], g.Items[i]
+			if wp.Name != gp.Name || wp.Spare != gp.Spare {
+				problems = append(problems, fmt.Sprintf(tr("%s[%d]: generado %q, releído %q"), w.Name, i, wp.Name, gp.Name))
+			}
+		}
+	}
+	for name, g := range gotSections {
+		if len(g.Items) > 0 {
+			problems = append(problems, fmt.Sprintf(tr("%s: sección inesperada al releer (%d puntos)"), name, len(g.Items)))
+		}
+	}
+	return problems
+}
+
+// checkRoundTripFile relee path y lo compara con el modelo generado.
+func checkRoundTripFile(path string, want *Lists) ([]string, error) {
+	got, err := readListsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return compareLists(want, got), nil
+}
+
+// checkRoundTripContent escribe content en un temporal y lo relee.
+func checkRoundTripContent(content []byte, want *Lists) ([]string, error) {
+	f, err := os.CreateTemp("", "dnpgen-roundtrip-*.ini")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return checkRoundTripFile(f.Name(), want)
+}
+
+func runCheckRoundTrip(args []string) {
+	fs := flag.NewFlagSet("check-roundtrip", flag.ExitOnError)
+	fs.Usage = manUsage("check-roundtrip", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo"))
+	listsPath := fs.String("lists", "", tr("Comprobar un __lists.ini existente (releer y reescribir) en lugar de generar"))
+	sigext := fs.Bool("sigext", false, tr("Ejecutar SIGEXT antes de generar"))
+	fs.Parse(args)
+
+	var problems []string
+	if *listsPath != "" {
+		// Un fichero existente debe reescribirse byte a byte igual a sí mismo.
+		original, err := os.ReadFile(*listsPath)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		lists, err := readListsFile(*listsPath)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if !bytes.Equal(renderLists(lists), stripListHeader(original)) {
+			problems = append(problems, tr("el fichero releído no se reescribe igual (formato o espacios distintos)"))
+		}
+		more, err := checkRoundTripContent(renderLists(lists), lists)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		problems = append(problems, more...)
+	} else {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe check-roundtrip -path \"C:\\Ruta\" -node \"NombreNodo\" | -lists __lists.ini"))
+		}
+		res, err := runGenerate(GenerateRequest{ProjectPath: *projectPath, NodeName: *nodeName, SkipExt: !*sigext, CheckOnly: true})
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if problems, err = checkRoundTripContent(res.content, res.lists); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		*listsPath = filepath.Base(res.SigFile)
+	}
+
+	if len(problems) > 0 {
+		fmt.Println(errText(trf("Ida y vuelta de %s: %d diferencia(s)", *listsPath, len(problems))))
+		for _, p := range problems {
+			fmt.Println("  - " + p)
+		}
+		os.Exit(1)
+	}
+	fmt.Println(okText(trf("Ida y vuelta de %s: OK", *listsPath)))
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- IMPACTO DE UN CAMBIO DE REGLAS ---
+//
+// "dnpgen rules impact -old A.yaml -new B.yaml" clasifica el .SIG actual de
+// cada nodo con los dos juegos de reglas, sin SIGEXT y sin escribir nada, y
+// lista los puntos que cambian de lista o de índice, los que aparecen y los
+// que desaparecen. Cada fichero se aplica sobre config.yaml: puede ser una
+// configuración completa o solo las secciones que cambian (classification,
+// spares, reserved...). Sin -old la referencia es config.yaml tal cual. Es la
+// revisión previa a desplegar unas reglas en muchos nodos; migrate hace lo
+// mismo nodo a nodo contra lo ya aceptado.
+
+// ruleImpact es el impacto en un nodo.
+type ruleImpact struct {
+	Node  string      `json:"node"`
+	Moves []rulesMove `json:"moves"`
+	Error string      `json:"error,omitempty"`
+}
+
+// rulesConfig es config.yaml con overlay encima (vacío = nada encima).
+func rulesConfig(base, overlay string) (Config, error) {
+	var cfg Config
+	for _, path := range []string{base, overlay} {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		if err := decodeConfig(bytes.NewReader(data), &cfg); err != nil {
+			return cfg, fmt.Errorf(tr("%s: YAML malformado: %v"), path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// impactPositions clasifica un nodo con cfg y devuelve sus posiciones. Como
+// al migrar, las reglas se dan por aceptadas: el registro de reglas del nodo
+// no cuenta.
+func impactPositions(cfg Config, abs, node, out string) (map[string]string, error) {
+	saved := GlobalConfig
+	defer func() { GlobalConfig = saved }()
+	GlobalConfig = cfg
+	applyFirmware()
+	res, err := generate(GenerateRequest{ProjectPath: abs, NodeName: node, SkipExt: true, CheckOnly: true, OutDir: out, migrate: true})
+	if err != nil {
+		return nil, err
+	}
+	return listPositions(res.lists), nil
+}
+
+// moveKind resume un cambio de posición.
+func moveKind(m rulesMove) string {
+	from, _, _ := strings.Cut(m.From, "[")
+	to, _, _ := strings.Cut(m.To, "[")
+	switch {
+	case m.From == "":
+		return tr("alta")
+	case m.To == "":
+		return tr("baja")
+	case from != to:
+		return tr("lista")
+	}
+	return tr("índice")
+}
+
+// runRulesImpact implementa "dnpgen rules impact".
+func runRulesImpact(args []string) {
+	fs := flag.NewFlagSet("rules impact", flag.ExitOnError)
+	fs.Usage = manUsage("rules impact", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos, separados por comas"))
+	all := fs.Bool("all", false, tr("Todos los nodos del proyecto"))
+	outDir := fs.String("out", "", tr("Directorio de salida, si no es el del recurso"))
+	oldRules := fs.String("old", "", tr("Reglas actuales (vacío = config.yaml)"))
+	newRules := fs.String("new", "", tr("Reglas propuestas, completas o solo las secciones que cambian"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	strict := fs.Bool("strict", false, tr("Salir con error si algún punto cambia de posición"))
+	fs.Parse(args)
+
+	usage := tr("Uso: dnpgen.exe rules impact -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all -new reglas_b.yaml [-old reglas_a.yaml] [-json] [-strict]")
+	if *projectPath == "" || *newRules == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(usage)
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	before, err := rulesConfig(configSource, *oldRules)
+	if err != nil {
+		log.Fatalf("[FATAL] -old: %v", err)
+	}
+	after, err := rulesConfig(configSource, *newRules)
+	if err != nil {
+		log.Fatalf("[FATAL] -new: %v", err)
+	}
+	var nodes []string
+	if *all {
+		if nodes, err = discoverNodes(abs); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	var results []ruleImpact
+	failed, moved, total := false, 0, 0
+	for _, node := range nodes {
+		r := ruleImpact{Node: node, Moves: []rulesMove{}}
+		from, err := impactPositions(before, abs, node, *outDir)
+		if err != nil {
+			r.Error = trf("reglas actuales: %v", err)
+		} else if to, err := impactPositions(after, abs, node, *outDir); err != nil {
+			r.Error = trf("reglas propuestas: %v", err)
+		} else {
+			r.Moves = diffPositions(from, to)
+		}
+		failed = failed || r.Error != ""
+		if len(r.Moves) > 0 {
+			moved++
+			total += len(r.Moves)
+		}
+		results = append(results, r)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		for _, r := range results {
+			fmt.Println(boldText("\n=== " + r.Node + " ==="))
+			switch {
+			case r.Error != "":
+				fmt.Println(errText(r.Error))
+			case len(r.Moves) == 0:
+				fmt.Println(okText(tr("Ningún punto cambia de lista ni de índice")))
+			default:
+				var rows [][]string
+				for _, mv := range r.Moves {
+					rows = append(rows, []string{mv.Var, mv.From, mv.To, moveKind(mv)})
+				}
+				printTable(tr("VARIABLE\tANTES\tDESPUÉS\tCAMBIO"), rows, nil)
+			}
+		}
+		summary := trf("\n%d de %d nodo(s) cambian; %d punto(s) en total", moved, len(nodes), total)
+		if moved > 0 {
+			fmt.Println(warnText(summary))
+		} else {
+			fmt.Println(okText(summary))
+		}
+	}
+	if failed || (*strict && moved > 0) {
+		os.Exit(1)
+	}
+}
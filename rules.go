@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// --- MOTOR DE REGLAS ---
+//
+// Los patrones del YAML son expresiones regulares, pero la mayoría son
+// literales ("_CMD", "^ESD_GENERAL$", "_OUT$"). ruleMatcher los compila una
+// vez y resuelve los literales con búsquedas en mapas por longitud de
+// prefijo/sufijo, recurriendo a regexp solo para los patrones que lo
+// necesitan. El resultado es el mismo que evaluar los patrones en orden.
+
+type ruleMatcher struct {
+	exact    map[string]int
+	prefixes map[int]map[string]int // longitud -> literal -> índice
+	suffixes map[int]map[string]int
+	prefLens []int
+	sufLens  []int
+	contains []indexedLiteral
+	regexes  []indexedRegexp
+}
+
+type indexedLiteral struct {
+	index int
+	lit   string
+}
+
+type indexedRegexp struct {
+	index int
+	re    *regexp.Regexp
+}
+
+// compileRules prepara los patrones. Con lenient, los patrones inválidos se
+// ignoran (nunca coinciden), como en la clasificación original.
+func compileRules(patterns []string, lenient bool) (*ruleMatcher, error) {
+	m := &ruleMatcher{
+		exact:    map[string]int{},
+		prefixes: map[int]map[string]int{},
+		suffixes: map[int]map[string]int{},
+	}
+	addLen := func(byLen map[int]map[string]int, lens *[]int, lit string, idx int) {
+		if byLen[len(lit)] == nil {
+			byLen[len(lit)] = map[string]int{}
+			*lens = append(*lens, len(lit))
+		}
+		if _, dup := byLen[len(lit)][lit]; !dup {
+			byLen[len(lit)][lit] = idx
+		}
+	}
+
+	for idx, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			if lenient {
+				continue
+			}
+			return nil, fmt.Errorf("patrón inválido %q: %v", p, err)
+		}
+		kind, lit := literalKind(p)
+		switch kind {
+		case "exact":
+			if _, dup := m.exact[lit]; !dup {
+				m.exact[lit] = idx
+			}
+		case "prefix":
+			addLen(m.prefixes, &m.prefLens, lit, idx)
+		case "suffix":
+			addLen(m.suffixes, &m.sufLens, lit, idx)
+		case "contains":
+			m.contains = append(m.contains, indexedLiteral{idx, lit})
+		default:
+			m.regexes = append(m.regexes, indexedRegexp{idx, re})
+		}
+	}
+	sort.Ints(m.prefLens)
+	sort.Ints(m.sufLens)
+	return m, nil
+}
+
+// literalKind clasifica un patrón que es un literal, opcionalmente anclado.
+func literalKind(pattern string) (kind, lit string) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", ""
+	}
+	re = re.Simplify()
+	literal := func(r *syntax.Regexp) (string, bool) {
+		if r.Op != syntax.OpLiteral || r.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+		return string(r.Rune), true
+	}
+	if s, ok := literal(re); ok {
+		return "contains", s
+	}
+	if re.Op != syntax.OpConcat {
+		return "", ""
+	}
+	subs := re.Sub
+	begin := len(subs) > 0 && subs[0].Op == syntax.OpBeginText
+	end := len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText
+	if begin {
+		subs = subs[1:]
+	}
+	if end && len(subs) > 0 {
+		subs = subs[:len(subs)-1]
+	}
+	if len(subs) != 1 {
+		return "", ""
+	}
+	s, ok := literal(subs[0])
+	switch {
+	case !ok:
+		return "", ""
+	case begin && end:
+		return "exact", s
+	case begin:
+		return "prefix", s
+	case end:
+		return "suffix", s
+	}
+	return "", ""
+}
+
+// Match devuelve el índice del primer patrón (en el orden del YAML) que
+// cumple s, o -1.
+func (m *ruleMatcher) Match(s string) int {
+	if m == nil {
+		return -1
+	}
+	best := -1
+	better := func(idx int) {
+		if best < 0 || idx < best {
+			best = idx
+		}
+	}
+	if idx, ok := m.exact[s]; ok {
+		better(idx)
+	}
+	for _, n := range m.prefLens {
+		if n > len(s) {
+			break
+		}
+		if idx, ok := m.prefixes[n][s[:n]]; ok {
+			better(idx)
+		}
+	}
+	for _, n := range m.sufLens {
+		if n > len(s) {
+			break
+		}
+		if idx, ok := m.suffixes[n][s[len(s)-n:]]; ok {
+			better(idx)
+		}
+	}
+	for _, c := range m.contains {
+		if (best < 0 || c.index < best) && strings.Contains(s, c.lit) {
+			better(c.index)
+		}
+	}
+	for _, r := range m.regexes {
+		if (best < 0 || r.index < best) && r.re.MatchString(s) {
+			better(r.index)
+		}
+	}
+	return best
+}
+
+// Any indica si algún patrón cumple s.
+func (m *ruleMatcher) Any(s string) bool {
+	return m.Match(s) >= 0
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// --- VERSIÓN DEL CONJUNTO DE REGLAS ---
+//
+// Un cambio en las reglas de clasificación (o en reservas, puntos de
+// sistema, derivados o spares) mueve puntos de lista o de índice en todos
+// los nodos que se regeneren. app.rules_version nombra el conjunto de reglas
+// y la primera generación de cada nodo guarda en <salida>/.dnpgen/<nodo>.
+// rules.json la versión, una huella de las reglas y la posición de cada
+// punto. Si config.yaml trae otra versión, o las mismas reglas han cambiado
+// sin cambiar la versión, la generación lo avisa con un hallazgo
+// rules_version (error si cambia la versión, warning si solo cambia la
+// huella) y no actualiza el registro: "dnpgen migrate" muestra qué puntos
+// cambian de posición y, con -apply, regenera y acepta las reglas nuevas.
+
+// RulesStamp es el conjunto de reglas con que se generó un nodo.
+type RulesStamp struct {
+	Version   string    `json:"rules_version"`
+	Hash      string    `json:"rules_hash"`
+	Generator string    `json:"generator,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Positions es la posición LISTA[índice] de cada punto con esas reglas:
+	// la referencia de "dnpgen migrate" aunque se haya regenerado después.
+	Positions map[string]string `json:"positions,omitempty"`
+}
+
+// RulesChange describe el paso del conjunto de reglas guardado al actual.
+type RulesChange struct {
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	FromHash string    `json:"from_hash"`
+	ToHash   string    `json:"to_hash"`
+	Since    time.Time `json:"since"` // desde cuándo rige el anterior
+}
+
+func (c RulesChange) versionChanged() bool { return c.From != c.To }
+
+func (c RulesChange) String() string {
+	if c.versionChanged() {
+		return trf("el nodo se generó con las reglas %s y config.yaml tiene las %s: revise los cambios con dnpgen migrate", rulesLabel(c.From), rulesLabel(c.To))
+	}
+	return trf("las reglas han cambiado sin cambiar app.rules_version (%s, huella %s → %s): revise los cambios con dnpgen migrate", rulesLabel(c.To), c.FromHash, c.ToHash)
+}
+
+func rulesLabel(v string) string {
+	if v == "" {
+		return tr("(sin versión)")
+	}
+	return strconv.Quote(v)
+}
+
+// rulesHash es la huella de la configuración que decide la lista y el índice
+// de cada punto.
+func rulesHash() string {
+	app := GlobalConfig.App
+	// comm_health solo cuenta activado, y sorting y disabled_lists solo con
+	// alguna lista, para no cambiar la huella de los nodos que no los usan.
+	var comm *CommHealthConfig
+	if app.CommHealth.Enabled {
+		comm = &app.CommHealth
+	}
+	var disabled *DisabledListsConfig
+	if len(app.DisabledLists.Default) > 0 || len(app.DisabledLists.Nodes) > 0 {
+		disabled = &app.DisabledLists
+	}
+	data, _ := json.Marshal(struct {
+		Classification any
+		Spares         SparesConfig
+		Reserved       []IndexReservation
+		SystemPoints   []SystemPoint
+		DerivedPoints  []DerivedPoint
+		CommHealth     *CommHealthConfig `json:",omitempty"`
+		Firmware       string
+		Order          []string
+		Sorting        map[string]ListSort  `json:",omitempty"`
+		DisabledLists  *DisabledListsConfig `json:",omitempty"`
+	}{app.Classification, app.Spares, app.Reserved, app.SystemPoints, app.DerivedPoints, comm, app.Firmware, app.Output.Order, app.Sorting, disabled})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// currentRules es el registro de las reglas actuales; lists, si no es nil,
+// aporta las posiciones.
+func currentRules(lists *Lists) RulesStamp {
+	s := RulesStamp{Version: GlobalConfig.App.RulesVersion, Hash: rulesHash(), Generator: generatorLine(), UpdatedAt: stampNow()}
+	if lists != nil {
+		s.Positions = listPositions(lists)
+	}
+	return s
+}
+
+func rulesStampPath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".rules.json")
+}
+
+// readRulesStamp lee el registro del nodo; nil si no lo hay (nodo nuevo o
+// generado antes de esta versión).
+func readRulesStamp(outDir, node string) (*RulesStamp, error) {
+	path := rulesStampPath(outDir, node)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s RulesStamp
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &s, nil
+}
+
+func writeRulesStamp(outDir, node string, s RulesStamp) error {
+	path := rulesStampPath(outDir, node)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// checkRules compara el registro del nodo con las reglas actuales. Devuelve
+// el registro (nil si no lo hay) y el cambio (nil si coinciden).
+func checkRules(outDir, node string) (*RulesStamp, *RulesChange, error) {
+	prev, err := readRulesStamp(outDir, node)
+	if err != nil || prev == nil {
+		return prev, nil, err
+	}
+	cur := currentRules(nil)
+	if prev.Version == cur.Version && prev.Hash == cur.Hash {
+		return prev, nil, nil
+	}
+	return prev, &RulesChange{From: prev.Version, To: cur.Version, FromHash: prev.Hash, ToHash: cur.Hash, Since: prev.UpdatedAt}, nil
+}
+
+// warnRulesChange avisa del cambio de reglas de forma visible y lo añade a
+// los hallazgos.
+func warnRulesChange(s *findingSet, c *RulesChange) {
+	severity := SeverityWarning
+	if c.versionChanged() {
+		severity = SeverityError
+	}
+	log.Printf("[WARN] **************************************************")
+	log.Printf("[WARN] %s", c)
+	log.Print(tr("[WARN] Los puntos pueden cambiar de lista o de índice respecto a lo descargado"))
+	log.Printf("[WARN] **************************************************")
+	s.add(FindingRulesVersion, severity, "%s", c)
+}
+
+// rulesMove es un punto que cambia de posición con las reglas nuevas.
+type rulesMove struct {
+	Var  string `json:"var"`
+	From string `json:"from,omitempty"` // LISTA[índice]; vacío si es nuevo
+	To   string `json:"to,omitempty"`   // vacío si desaparece
+}
+
+// rulesMigration es el resultado de migrar un nodo.
+type rulesMigration struct {
+	Node    string       `json:"node"`
+	Change  *RulesChange `json:"change,omitempty"`
+	Moves   []rulesMove  `json:"moves"`
+	Applied bool         `json:"applied,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// listPositions da la posición LISTA[índice] de cada punto real.
+func listPositions(l *Lists) map[string]string {
+	pos := map[string]string{}
+	for _, s := range listSections(l) {
+		for i, p := range s.Items {
+			if !p.Spare && p.Var != "" {
+				pos[p.Var] = fmt.Sprintf("%s[%d]", s.Name, i)
+			}
+		}
+	}
+	return pos
+}
+
+// diffPositions compara las posiciones aceptadas con las nuevas.
+func diffPositions(before, after map[string]string) []rulesMove {
+	var moves []rulesMove
+	for v, to := range after {
+		if from := before[v]; from != to {
+			moves = append(moves, rulesMove{v, from, to})
+		}
+	}
+	for v, from := range before {
+		if _, ok := after[v]; !ok {
+			moves = append(moves, rulesMove{v, from, ""})
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].Var < moves[j].Var })
+	return moves
+}
+
+// migrateNode calcula (y con apply ejecuta) la migración de un nodo.
+func migrateNode(abs, node, out string, apply bool) rulesMigration {
+	m := rulesMigration{Node: node, Moves: []rulesMove{}}
+	outDir := outputDirFor(abs, node, out)
+	prev, change, err := checkRules(outDir, node)
+	if err != nil {
+		m.Error = err.Error()
+		return m
+	}
+	m.Change = change
+	res, err := generate(GenerateRequest{ProjectPath: abs, NodeName: node, SkipExt: true, CheckOnly: true, OutDir: out, migrate: true})
+	if err != nil {
+		m.Error = err.Error()
+		return m
+	}
+	// Sin posiciones en el registro (nodo nuevo o registro antiguo) la
+	// referencia son las listas del disco.
+	var before map[string]string
+	if prev != nil && prev.Positions != nil {
+		before = prev.Positions
+	} else if old, err := readListsFile(filepath.Join(outDir, GlobalConfig.App.Output.fileName())); err == nil {
+		before = listPositions(old)
+	} else if !os.IsNotExist(err) {
+		m.Error = err.Error()
+		return m
+	}
+	m.Moves = diffPositions(before, listPositions(res.lists))
+	if apply {
+		if _, err := runGenerate(GenerateRequest{ProjectPath: abs, NodeName: node, SkipExt: true, OutDir: out, migrate: true}); err != nil {
+			m.Error = err.Error()
+			return m
+		}
+		m.Applied = true
+	}
+	return m
+}
+
+// runMigrate implementa "dnpgen migrate -path RUTA (-node A,B | -all) [-out
+// DIR] [-apply] [-json]".
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = manUsage("migrate", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos, separados por comas"))
+	all := fs.Bool("all", false, tr("Todos los nodos del proyecto"))
+	outDir := fs.String("out", "", tr("Directorio de salida, si no es el del recurso"))
+	apply := fs.Bool("apply", false, tr("Regenerar con las reglas nuevas y aceptarlas (sin -apply solo se muestran los cambios)"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(tr("Uso: dnpgen.exe migrate -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-apply] [-json]"))
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var nodes []string
+	if *all {
+		if nodes, err = discoverNodes(abs); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	var results []rulesMigration
+	failed := false
+	for _, node := range nodes {
+		m := migrateNode(abs, node, *outDir, *apply)
+		failed = failed || m.Error != ""
+		results = append(results, m)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		for _, m := range results {
+			fmt.Println(boldText("\n=== " + m.Node + " ==="))
+			switch {
+			case m.Error != "":
+				fmt.Println(errText(m.Error))
+				continue
+			case m.Change == nil:
+				fmt.Println(okText(trf("Reglas %s: sin cambios", rulesLabel(GlobalConfig.App.RulesVersion))))
+			default:
+				fmt.Println(warnText(m.Change.String()))
+			}
+			if len(m.Moves) == 0 {
+				fmt.Println(tr("Ningún punto cambia de lista ni de índice"))
+			} else {
+				var rows [][]string
+				for _, mv := range m.Moves {
+					rows = append(rows, []string{mv.Var, mv.From, mv.To})
+				}
+				printTable(tr("VARIABLE\tANTES\tDESPUÉS"), rows, nil)
+			}
+			if m.Applied {
+				fmt.Println(okText(trf("Migrado: %d punto(s) cambian de posición; reglas %s aceptadas", len(m.Moves), rulesLabel(GlobalConfig.App.RulesVersion))))
+			}
+		}
+		if !*apply && !failed {
+			fmt.Println(tr("\nEjecute con -apply para regenerar los nodos con las reglas nuevas y aceptarlas."))
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- PLANTILLAS DE PROYECTO (SCAFFOLD) ---
+//
+// "dnpgen scaffold -type pumping-station" escribe un config.yaml inicial para
+// uno de los tipos de estación estándar (reglas de salida, SOE, spares,
+// puntos de sistema y reservas, preset de firmware), de modo que un proyecto
+// nuevo parta de una base validada y no de una copia del último proyecto.
+// Las secciones que no aparecen toman los valores por defecto; el
+// config.yaml completo documenta todas.
+
+// stationArchetype son los valores de un tipo de estación.
+type stationArchetype struct {
+	Description  string
+	Firmware     string
+	AnalogOut    []string
+	DigitalOut   []string
+	SOE          []string
+	Strings      []string // tipos que van a la lista de cadenas de octetos
+	SystemPoints []SystemPoint
+	Reserved     []IndexReservation
+	Derived      []DerivedPoint
+	Objects      map[string]DNP3Object
+}
+
+// commonSystemPoints son los puntos de sistema de todas las estaciones.
+var commonSystemPoints = []SystemPoint{
+	{List: "DI", Index: 0, Name: "@GV.SYS_COMM_OK", Type: "BOOL"},
+	{List: "DI", Index: 1, Name: "@GV.SYS_IIN_DEVICE_TROUBLE", Type: "BOOL"},
+	{List: "AI", Index: 0, Name: "@GV.{node}_WATCHDOG", Type: "INT"},
+}
+
+var stationArchetypes = map[string]stationArchetype{
+	"pumping-station": {
+		Description:  "estación de bombeo",
+		Firmware:     "cwm-06",
+		AnalogOut:    []string{"_SP($|_)", "_SPEED_REF$"},
+		DigitalOut:   []string{"_CMD", "_START$", "_STOP$", "_RESET", "_MANUAL"},
+		SOE:          []string{"_TRIP$", "_FAULT$"},
+		SystemPoints: commonSystemPoints,
+		Reserved:     []IndexReservation{{List: "DI", From: 2, To: 15, Name: "@GV.DNP_DIAG_{index}"}},
+		Derived: []DerivedPoint{
+			{Pattern: "_RUN$", From: "DI", List: "AI", Name: "@GV.{var}_HOURS", Type: "REAL", Desc: "Horas de marcha de {var}"},
+		},
+		Objects: map[string]DNP3Object{"AI": {Variation: 5, EventVariation: 7}},
+	},
+	"tank-farm": {
+		Description:  "parque de tanques",
+		Firmware:     "cwm-06",
+		AnalogOut:    []string{"LIT.*_H_H", "LIT.*_L_L", "_SP($|_)"},
+		DigitalOut:   []string{"_CMD", "_OPEN", "_CLOSE", "_RESET"},
+		SOE:          []string{"_HH_ALM$", "_LL_ALM$"},
+		SystemPoints: commonSystemPoints,
+		Reserved:     []IndexReservation{{List: "DI", From: 2, To: 15, Name: "@GV.DNP_DIAG_{index}"}},
+		Objects:      map[string]DNP3Object{"AI": {Variation: 5, EventVariation: 7}},
+	},
+	"valve-station": {
+		Description:  "estación de válvulas",
+		Firmware:     "cwm-05",
+		AnalogOut:    []string{"_POS_SP$"},
+		DigitalOut:   []string{"_OPEN$", "_CLOSE$", "_STOP$", "_RESET"},
+		SOE:          []string{"_OPENED$", "_CLOSED$", "_TRIP$"},
+		SystemPoints: commonSystemPoints,
+		Reserved:     []IndexReservation{{List: "DI", From: 2, To: 7, Name: "@GV.DNP_DIAG_{index}"}},
+		Objects:      map[string]DNP3Object{"DI": {Variation: 2, EventVariation: 2}},
+	},
+	"metering-station": {
+		Description:  "estación de medida",
+		Firmware:     "cwm-06",
+		AnalogOut:    []string{"_SP($|_)"},
+		DigitalOut:   []string{"_CMD", "_RESET"},
+		SOE:          []string{"_ALM$"},
+		Strings:      []string{"STRING"},
+		SystemPoints: commonSystemPoints,
+		Reserved:     []IndexReservation{{List: "AI", From: 1, To: 15, Name: "@GV.DNP_METER_{index}"}},
+		Objects:      map[string]DNP3Object{"AI": {Variation: 5, EventVariation: 7}},
+	},
+}
+
+func archetypeNames() []string {
+	names := make([]string, 0, len(stationArchetypes))
+	for name := range stationArchetypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// yamlStrings escribe una lista YAML en línea con las cadenas entre comillas.
+func yamlStrings(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var scaffoldTemplate = template.Must(template.New("scaffold").Funcs(template.FuncMap{
+	"list":  yamlStrings,
+	"quote": strconv.Quote,
+}).Parse(`# config.yaml inicial de dnpgen: {{.A.Description}} ({{.Type}}).
+# Generado por {{.Generator}} con "dnpgen scaffold -type {{.Type}}".
+# Las secciones que faltan toman sus valores por defecto; el config.yaml de
+# la distribución las documenta todas.
+app:
+  sigext_path: ''
+  sigext_flags: "-b -61131"
+
+  # Preset de firmware de la RTU: capacidad por lista, códigos *LIST, tipos
+  # admitidos y reglas de nombres (ver firmware_presets).
+  firmware: {{.A.Firmware}}
+
+  classification:
+    analog_output_regex: {{list .A.AnalogOut}}
+    digital_output_regex: {{list .A.DigitalOut}}
+    mirror: spare
+    soe:
+      patterns: {{list .A.SOE}}
+      event_class: 1
+{{- if .A.Strings}}
+    strings:
+      types: {{list .A.Strings}}
+{{- end}}
+
+  spares:
+    do: "@GV.DNP_DO_SPARE"
+    di: "@GV.DNP_DI_SPARE"
+    ao: "@GV.DNP_AO_SPARE"
+    ai: "@GV.DNP_AI_SPARE"
+
+  # Puntos de sistema en el mismo índice de todos los nodos.
+  system_points:
+{{- range .A.SystemPoints}}
+    - {list: {{.List}}, index: {{.Index}}, name: {{quote .Name}}, type: {{.Type}}}
+{{- end}}
+
+  # Índices reservados para ampliaciones sin renumerar.
+  reserved:
+{{- range .A.Reserved}}
+    - {list: {{.List}}, from: {{.From}}, to: {{.To}}, name: {{quote .Name}}}
+{{- end}}
+{{- if .A.Derived}}
+
+  derived_points:
+{{- range .A.Derived}}
+    - pattern: {{quote .Pattern}}
+      from: {{.From}}
+      list: {{.List}}
+      name: {{quote .Name}}
+      type: {{.Type}}
+      desc: {{quote .Desc}}
+{{- end}}
+{{- end}}
+
+  dnp3_objects:
+    defaults:
+{{- range $list, $o := .A.Objects}}
+      {{$list}}: {variation: {{$o.Variation}}{{if $o.EventVariation}}, event_variation: {{$o.EventVariation}}{{end}}}
+{{- end}}
+
+  names:
+    action: warn
+
+  findings:
+    fail_on: error
+
+  output:
+    combined: true
+    manifest: true
+
+  exports: [csv, dnp3-profile]
+`))
+
+// scaffoldConfig genera el config.yaml de un tipo de estación y comprueba que
+// se carga: YAML sin campos desconocidos, patrones válidos, preset de
+// firmware existente y reservas sin solapes.
+func scaffoldConfig(kind string) ([]byte, error) {
+	a, ok := stationArchetypes[kind]
+	if !ok {
+		return nil, fmt.Errorf(tr("tipo de estación desconocido %q (disponibles: %s)"), kind, strings.Join(archetypeNames(), ", "))
+	}
+	var b bytes.Buffer
+	data := struct {
+		Type, Generator string
+		A               stationArchetype
+	}{kind, generatorLine(), a}
+	if err := scaffoldTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(b.Bytes()))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf(tr("plantilla %s: %v"), kind, err)
+	}
+	rules := cfg.App.Classification
+	for _, patterns := range [][]string{rules.AnalogRegex, rules.DigitalRegex, rules.SOE.Patterns} {
+		if _, err := compileRules(patterns, false); err != nil {
+			return nil, fmt.Errorf(tr("plantilla %s: %v"), kind, err)
+		}
+	}
+	if _, ok := firmwarePresets[cfg.App.Firmware]; !ok {
+		return nil, fmt.Errorf(tr("plantilla %s: firmware desconocido %q"), kind, cfg.App.Firmware)
+	}
+	system, err := systemReservations(cfg.App.SystemPoints)
+	if err != nil {
+		return nil, fmt.Errorf(tr("plantilla %s: %v"), kind, err)
+	}
+	empty := &Lists{AI: []Point{}, AO: []Point{}, DI: []Point{}, DO: []Point{}}
+	if err := applyReservations(empty, "NODO", append(system, cfg.App.Reserved...)); err != nil {
+		return nil, fmt.Errorf(tr("plantilla %s: %v"), kind, err)
+	}
+	if err := assignObjects(empty, cfg.App.DNP3Objects); err != nil {
+		return nil, fmt.Errorf(tr("plantilla %s: %v"), kind, err)
+	}
+	return b.Bytes(), nil
+}
+
+// runScaffold implementa "dnpgen scaffold".
+func runScaffold(args []string) {
+	fs := flag.NewFlagSet("scaffold", flag.ExitOnError)
+	fs.Usage = manUsage("scaffold", fs)
+	kind := fs.String("type", "", tr("Tipo de estación: ")+strings.Join(archetypeNames(), ", "))
+	out := fs.String("o", ConfigFile, tr("Fichero de configuración a escribir (- = salida estándar)"))
+	force := fs.Bool("force", false, tr("Sobrescribir el fichero si ya existe"))
+	types := fs.Bool("types", false, tr("Mostrar los tipos de estación disponibles"))
+	fs.Parse(args)
+
+	if *types {
+		for _, name := range archetypeNames() {
+			a := stationArchetypes[name]
+			fmt.Printf("%-18s %s (firmware %s)\n", name, a.Description, a.Firmware)
+		}
+		return
+	}
+	if *kind == "" {
+		log.Fatal(tr("Uso: dnpgen.exe scaffold -type pumping-station [-o config.yaml] [-force] | -types"))
+	}
+	content, err := scaffoldConfig(*kind)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if *out == "-" {
+		os.Stdout.Write(content)
+		return
+	}
+	if fileExists(*out) && !*force {
+		log.Fatalf(tr("[FATAL] %s ya existe (use -force para sobrescribirlo)"), *out)
+	}
+	if err := writeFileAtomic(*out, content); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	log.Printf(tr("Configuración inicial de %s escrita en %s"), *kind, *out)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// --- ESCALADO DE ANALÓGICAS ---
+
+// ScalingConfig define el escalado bruto↔ingeniería de las analógicas para
+// que las exportaciones lleven factor y offset en lugar de retipearlos en el
+// SCADA. Las reglas tienen prioridad; con from_vardef, las variables sin
+// regla toman el rango de ingeniería de __vardef.ini y el rango bruto por
+// defecto.
+type ScalingConfig struct {
+	Rules      []ScalingRule `yaml:"rules"`
+	FromVarDef bool          `yaml:"from_vardef"`
+	RawMin     *float64      `yaml:"raw_min"` // rango bruto por defecto; 0..32767
+	RawMax     *float64      `yaml:"raw_max"`
+}
+
+// ScalingRule asigna un escalado a las variables que cumplen Pattern.
+type ScalingRule struct {
+	Pattern string   `yaml:"pattern"`
+	RawMin  *float64 `yaml:"raw_min"` // vacío = rango bruto por defecto
+	RawMax  *float64 `yaml:"raw_max"`
+	EngMin  float64  `yaml:"eng_min"`
+	EngMax  float64  `yaml:"eng_max"`
+	Units   string   `yaml:"units"`
+}
+
+// Scaling es el escalado resuelto de un punto: eng = raw*Scale + Offset.
+type Scaling struct {
+	RawMin float64 `json:"raw_min"`
+	RawMax float64 `json:"raw_max"`
+	EngMin float64 `json:"eng_min"`
+	EngMax float64 `json:"eng_max"`
+	Scale  float64 `json:"scale"`
+	Offset float64 `json:"offset"`
+	Units  string  `json:"units,omitempty"`
+}
+
+func newScaling(rawMin, rawMax, engMin, engMax float64, units string) (*Scaling, error) {
+	if rawMax == rawMin {
+		return nil, fmt.Errorf(tr("rango bruto vacío (%g..%g)"), rawMin, rawMax)
+	}
+	scale := (engMax - engMin) / (rawMax - rawMin)
+	return &Scaling{
+		RawMin: rawMin, RawMax: rawMax, EngMin: engMin, EngMax: engMax,
+		Scale: scale, Offset: engMin - rawMin*scale, Units: units,
+	}, nil
+}
+
+func (c ScalingConfig) enabled() bool {
+	return len(c.Rules) > 0 || c.FromVarDef
+}
+
+func (c ScalingConfig) rawRange() (float64, float64) {
+	lo, hi := 0.0, 32767.0
+	if c.RawMin != nil {
+		lo = *c.RawMin
+	}
+	if c.RawMax != nil {
+		hi = *c.RawMax
+	}
+	return lo, hi
+}
+
+// applyScaling resuelve el escalado de los puntos reales de AI y AO.
+// Devuelve cuántos puntos quedaron escalados.
+func applyScaling(l *Lists, resourceDir string, cfg ScalingConfig) (int, error) {
+	if !cfg.enabled() {
+		return 0, nil
+	}
+	var patterns []string
+	for _, r := range cfg.Rules {
+		patterns = append(patterns, r.Pattern)
+	}
+	rules, err := compileRules(patterns, false)
+	if err != nil {
+		return 0, fmt.Errorf(tr("scaling: %v"), err)
+	}
+	var vardef map[string]varDefRange
+	if cfg.FromVarDef {
+		if vardef, err = readVarDef(filepath.Join(resourceDir, VarDefFile)); err != nil {
+			return 0, fmt.Errorf(tr("scaling: %v"), err)
+		}
+	}
+
+	rawMin, rawMax := cfg.rawRange()
+	n := 0
+	for _, list := range []*[]Point{&l.AI, &l.AO} {
+		for i := range *list {
+			p := &(*list)[i]
+			if p.Spare {
+				continue
+			}
+			var s *Scaling
+			if idx := rules.Match(p.Var); idx >= 0 {
+				r := cfg.Rules[idx]
+				lo, hi := rawMin, rawMax
+				if r.RawMin != nil {
+					lo = *r.RawMin
+				}
+				if r.RawMax != nil {
+					hi = *r.RawMax
+				}
+				s, err = newScaling(lo, hi, r.EngMin, r.EngMax, r.Units)
+			} else if v, ok := vardef[p.Var]; ok {
+				s, err = newScaling(rawMin, rawMax, v.min, v.max, v.units)
+			}
+			if err != nil {
+				return n, fmt.Errorf(tr("scaling %s: %v"), p.Var, err)
+			}
+			if s != nil {
+				p.Scaling = s
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+// varDefRange es el rango de ingeniería de una variable en __vardef.ini.
+type varDefRange struct {
+	min, max float64
+	units    string
+}
+
+// readVarDef lee los rangos de __vardef.ini: líneas "@GV.VAR" seguidas de
+// pares CLAVE=valor, de los que se usan MIN, MAX y UNITS (o EU). Las
+// variables sin MIN y MAX se ignoran; un fichero inexistente no es error.
+func readVarDef(path string) (map[string]varDefRange, error) {
+	out := map[string]varDefRange{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "@GV.") {
+			continue
+		}
+		var r varDefRange
+		hasMin, hasMax := false, false
+		for _, f := range fields[1:] {
+			key, value, ok := strings.Cut(f, "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"'`)
+			switch strings.ToUpper(key) {
+			case "MIN":
+				r.min, err = strconv.ParseFloat(value, 64)
+				hasMin = err == nil
+			case "MAX":
+				r.max, err = strconv.ParseFloat(value, 64)
+				hasMax = err == nil
+			case "UNITS", "EU":
+				r.units = value
+			}
+		}
+		if hasMin && hasMax {
+			out[strings.TrimPrefix(fields[0], "@GV.")] = r
+		}
+	}
+	return out, scanner.Err()
+}
+
+// scalingColumns devuelve factor, offset y unidades como texto para las
+// exportaciones tabulares; vacíos si el punto no tiene escalado.
+func scalingColumns(p Point) (string, string, string) {
+	if p.Scaling == nil {
+		return "", "", ""
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	return f(p.Scaling.Scale), f(p.Scaling.Offset), p.Scaling.Units
+}
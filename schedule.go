@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- REGENERACIÓN PROGRAMADA ---
+
+// ScheduleEntry programa la regeneración periódica de los nodos de un
+// proyecto en modo servidor. Cron usa el formato clásico de cinco campos
+// (minuto hora día-del-mes mes día-de-la-semana).
+type ScheduleEntry struct {
+	Cron      string   `yaml:"cron"`
+	Project   string   `yaml:"project"`
+	Nodes     []string `yaml:"nodes"`      // vacío = todos los .SIG del recurso
+	CheckOnly bool     `yaml:"check_only"` // solo informa deriva, no sobrescribe
+	SkipExt   bool     `yaml:"skip_ext"`
+}
+
+// cronSpec es una expresión cron ya analizada: un conjunto de valores
+// permitidos por campo.
+type cronSpec struct {
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+func parseCron(expr string) (*cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(tr("cron %q: se esperan 5 campos"), expr)
+	}
+	spec := &cronSpec{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	parse := func(field string, min, max int) map[int]bool {
+		if err != nil {
+			return nil
+		}
+		var set map[int]bool
+		set, err = parseCronField(field, min, max)
+		if err != nil {
+			err = fmt.Errorf("cron %q: %v", expr, err)
+		}
+		return set
+	}
+	spec.minute = parse(fields[0], 0, 59)
+	spec.hour = parse(fields[1], 0, 23)
+	spec.dom = parse(fields[2], 1, 31)
+	spec.month = parse(fields[3], 1, 12)
+	spec.dow = parse(fields[4], 0, 7)
+	if err != nil {
+		return nil, err
+	}
+	if spec.dow[7] {
+		spec.dow[0] = true
+	}
+	return spec, nil
+}
+
+// parseCronField admite "*", valores, rangos "a-b", listas "a,b" y pasos "/n".
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf(tr("paso inválido en %q"), part)
+			}
+			step, part = n, part[:i]
+		}
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf(tr("valor inválido %q"), part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf(tr("valor inválido %q"), part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf(tr("fuera de rango %q (%d-%d)"), part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// next devuelve el primer instante posterior a t que cumple la expresión.
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return limit
+}
+
+// dayMatches aplica la regla de cron: si ambos campos de día están
+// restringidos basta con que coincida uno de ellos.
+func (c *cronSpec) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// startScheduler lanza una gorutina por entrada programada que encola los
+// trabajos correspondientes en cada disparo.
+func startScheduler(entries []ScheduleEntry, store *jobStore) error {
+	for _, e := range entries {
+		spec, err := parseCron(e.Cron)
+		if err != nil {
+			return err
+		}
+		go func(e ScheduleEntry, spec *cronSpec) {
+			for {
+				at := spec.next(time.Now())
+				log.Printf(tr("[SCHEDULE] %s (%s): próxima ejecución %s"), e.Project, e.Cron, formatStamp(at))
+				time.Sleep(time.Until(at))
+				runScheduled(e, store)
+			}
+		}(e, spec)
+	}
+	return nil
+}
+
+func runScheduled(e ScheduleEntry, store *jobStore) {
+	nodes := e.Nodes
+	if len(nodes) == 0 {
+		var err error
+		if nodes, err = discoverNodes(e.Project); err != nil {
+			log.Printf(tr("[SCHEDULE] [ERROR] %s: %v"), e.Project, err)
+			return
+		}
+	}
+	for _, node := range nodes {
+		j, err := store.enqueue(GenerateRequest{
+			ProjectPath: e.Project,
+			NodeName:    node,
+			SkipExt:     e.SkipExt,
+			CheckOnly:   e.CheckOnly,
+		})
+		if err != nil {
+			log.Printf(tr("[SCHEDULE] [ERROR] %s/%s: %v"), e.Project, node, err)
+			continue
+		}
+		log.Printf(tr("[SCHEDULE] Encolado %s/%s como trabajo %s"), e.Project, node, j.ID)
+	}
+}
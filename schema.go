@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- ESQUEMA JSON DE CONFIG.YAML ---
+//
+// "dnpgen config schema" escribe un JSON Schema de config.yaml generado por
+// reflexión de Config, de modo que nunca se queda atrás al añadir
+// secciones. Las descripciones salen de los comentarios del config.yaml
+// documentado (el que se cargaría, o -from): cada clave toma el bloque de
+// comentario que la precede. Con la extensión YAML de VS Code basta con
+// "# yaml-language-server: $schema=config.schema.json" en la primera línea.
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaEnums son los valores admitidos de los tipos con constantes.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(MirrorStrategy("")): enumOf(mirrorStrategies),
+	reflect.TypeOf(SpareStrategy("")):  {"", string(SpareFixed), string(SparePool), string(SpareReserved), string(SpareOmit)},
+}
+
+// enumOf da los valores de un enum más "" (sin valor = por defecto).
+func enumOf[T ~string](values []T) []string {
+	out := []string{""}
+	for _, v := range values {
+		out = append(out, string(v))
+	}
+	return out
+}
+
+// schemaPathEnums son los valores admitidos de campos string sin tipo
+// propio, por ruta YAML.
+var schemaPathEnums = map[string][]string{
+	"app.stale_sig":        {"", StaleSigWarn, StaleSigError, StaleSigIgnore},
+	"app.merge.conflicts":  {"", "ask", "ours", "theirs", "both", "fail"},
+	"app.names.action":     {"", "warn", "error", "sanitize"},
+	"app.findings.fail_on": {"", SeverityError, SeverityWarning, "never"},
+	"app.timezone":         {"", "local", "utc"},
+}
+
+// schemaBuilder recorre los tipos de Config.
+type schemaBuilder struct {
+	docs     map[string]string // ruta YAML → descripción
+	visiting map[reflect.Type]bool
+}
+
+func (b *schemaBuilder) schema(t reflect.Type, path string) map[string]any {
+	s := b.typeSchema(t, path)
+	if d := b.docs[path]; d != "" {
+		s["description"] = d
+	}
+	if enum, ok := schemaPathEnums[path]; ok {
+		s["enum"] = enum
+	}
+	return s
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type, path string) map[string]any {
+	if enum, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": enum}
+	}
+	// SpareSpec admite también la forma corta: solo el nombre.
+	if t == reflect.TypeOf(SpareSpec{}) {
+		obj := b.structSchema(t, path)
+		return map[string]any{"anyOf": []any{map[string]any{"type": "string"}, obj}}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typeSchema(t.Elem(), path)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem(), path+"[]")}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.Elem(), path+".*")}
+	case reflect.Struct:
+		return b.structSchema(t, path)
+	}
+	return map[string]any{}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type, path string) map[string]any {
+	if b.visiting[t] {
+		return map[string]any{"type": "object"}
+	}
+	b.visiting[t] = true
+	defer delete(b.visiting, t)
+
+	props := map[string]any{}
+	b.addFields(t, path, props)
+	return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+}
+
+// addFields añade a props los campos de t con su nombre YAML; los
+// ",inline" se aplanan como hace yaml.v3.
+func (b *schemaBuilder) addFields(t reflect.Type, path string, props map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, path, props)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		key := name
+		if path != "" {
+			key = path + "." + name
+		}
+		props[name] = b.schema(f.Type, key)
+	}
+}
+
+// configDocs lee los comentarios de un config.yaml documentado: el bloque
+// que precede a cada clave y el comentario de su línea.
+func configDocs(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	docs := map[string]string{}
+	var walk func(n *yaml.Node, path string)
+	walk = func(n *yaml.Node, path string) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(c, path)
+			}
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				walk(c, path+"[]")
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k, v := n.Content[i], n.Content[i+1]
+				key := k.Value
+				if path != "" {
+					key = path + "." + k.Value
+				}
+				var parts []string
+				for _, c := range []string{k.HeadComment, k.LineComment, v.LineComment} {
+					if c = commentText(c); c != "" {
+						parts = append(parts, c)
+					}
+				}
+				if len(parts) > 0 && docs[key] == "" {
+					docs[key] = strings.Join(parts, "\n")
+				}
+				walk(v, key)
+			}
+		}
+	}
+	walk(&root, "")
+	return docs, nil
+}
+
+// commentText quita las almohadillas de un comentario YAML.
+func commentText(c string) string {
+	var out []string
+	for _, line := range strings.Split(c, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// configSchema construye el esquema de config.yaml con las descripciones
+// de docs (puede ser nil).
+func configSchema(docs map[string]string) map[string]any {
+	b := &schemaBuilder{docs: docs, visiting: map[reflect.Type]bool{}}
+	s := b.schema(reflect.TypeOf(Config{}), "")
+	s["$schema"] = schemaDialect
+	s["title"] = "dnpgen config.yaml"
+	s["description"] = generatorLine()
+	return s
+}
+
+// runConfig implementa "dnpgen config schema [-o FICHERO] [-from
+// config.yaml]".
+func runConfig(args []string) {
+	usage := tr("Uso: dnpgen.exe config schema [-o config.schema.json] [-from config.yaml]")
+	if len(args) == 0 || args[0] != "schema" {
+		log.Fatal(usage)
+	}
+	fs := flag.NewFlagSet("config schema", flag.ExitOnError)
+	fs.Usage = manUsage("config", fs)
+	out := fs.String("o", "", tr("Fichero de salida (por defecto, la salida estándar)"))
+	from := fs.String("from", "", tr("config.yaml documentado del que tomar las descripciones (por defecto, el que se cargaría)"))
+	fs.Parse(args[1:])
+
+	src := *from
+	if src == "" {
+		src, _ = findConfigFile()
+	}
+	var docs map[string]string
+	if src != "" {
+		var err error
+		if docs, err = configDocs(src); err != nil {
+			if *from != "" {
+				log.Fatalf("[FATAL] %v", err)
+			}
+			log.Printf(tr("[WARN] Esquema sin descripciones: %v"), err)
+		}
+	}
+
+	data, err := json.MarshalIndent(configSchema(docs), "", "  ")
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := writeFileAtomic(*out, data); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	log.Printf(tr("Esquema escrito en %s (%d claves documentadas)"), *out, len(docs))
+}
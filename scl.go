@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- EXPORTACIÓN IEC 61850 (SCL / ICD) ---
+
+// SCLConfig configura la exportación SCL. Por defecto cada lista va a un LN
+// GGIO propio (prefijos DI, DO, AI, AO); las reglas permiten llevar grupos de
+// variables a otros nodos lógicos según su nombre, mezclando listas si hace
+// falta (Ind, SPCSO, AnIn y AnOut conviven en el mismo LN).
+type SCLConfig struct {
+	IEDName      string    `yaml:"ied_name"` // vacío = nombre del nodo
+	Manufacturer string    `yaml:"manufacturer"`
+	LDInst       string    `yaml:"ld_inst"`
+	Rules        []SCLRule `yaml:"rules"`
+}
+
+// SCLRule asigna las variables cuyo nombre cumple Pattern a un LN concreto.
+type SCLRule struct {
+	Pattern string `yaml:"pattern"`
+	LNClass string `yaml:"ln_class"` // GGIO si se omite
+	Prefix  string `yaml:"prefix"`
+	Inst    int    `yaml:"inst"`
+}
+
+// sclKind describe cómo se modela cada lista en 61850.
+type sclKind struct {
+	list, doName, doType string
+}
+
+var sclKinds = []sclKind{
+	{"DI", "Ind", "SPS_T"},
+	{"DO", "SPCSO", "SPC_T"},
+	{"AI", "AnIn", "MV_T"},
+	{"AO", "AnOut", "APC_T"},
+	{"OS", "VSS", "VSS_T"},
+}
+
+type sclLN struct {
+	Prefix  string   `xml:"prefix,attr,omitempty"`
+	LNClass string   `xml:"lnClass,attr"`
+	Inst    string   `xml:"inst,attr"`
+	LNType  string   `xml:"lnType,attr"`
+	DOIs    []sclDOI `xml:"DOI"`
+}
+
+type sclDOI struct {
+	Name string `xml:"name,attr"`
+	Desc string `xml:"desc,attr"`
+}
+
+type sclDO struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type sclLNodeType struct {
+	ID      string  `xml:"id,attr"`
+	LNClass string  `xml:"lnClass,attr"`
+	DOs     []sclDO `xml:"DO"`
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"scl", ".icd", writeSCL})
+}
+
+// writeSCL genera un ICD con los puntos reales (sin spares) del nodo.
+func writeSCL(w io.Writer, ctx ExportContext) error {
+	node, l := ctx.Node, ctx.Lists
+	cfg := GlobalConfig.App.SCL
+	ied := cfg.IEDName
+	if ied == "" {
+		ied = sclName(node)
+	}
+	ldInst := cfg.LDInst
+	if ldInst == "" {
+		ldInst = "LD0"
+	}
+	manufacturer := cfg.Manufacturer
+	if manufacturer == "" {
+		manufacturer = "Emerson"
+	}
+
+	var patterns []string
+	for _, r := range cfg.Rules {
+		patterns = append(patterns, r.Pattern)
+	}
+	rules, err := compileRules(patterns, false)
+	if err != nil {
+		return fmt.Errorf("scl: %v", err)
+	}
+
+	byList := map[string][]Point{"DI": l.DI, "DO": l.DO, "AI": l.AI, "AO": l.AO, "OS": l.OS}
+	var lns []*sclLN
+	lnIndex := map[string]*sclLN{}
+	doCount := map[string]int{}
+	var types []sclLNodeType
+
+	for _, k := range sclKinds {
+		for idx, p := range byList[k.list] {
+			if p.Spare {
+				continue
+			}
+			prefix, class, inst := k.list, "GGIO", 1
+			if i := rules.Match(p.Var); i >= 0 {
+				rule := cfg.Rules[i]
+				prefix, inst = rule.Prefix, rule.Inst
+				if rule.LNClass != "" {
+					class = rule.LNClass
+				}
+				if inst == 0 {
+					inst = 1
+				}
+			}
+			key := prefix + class + strconv.Itoa(inst)
+			ln := lnIndex[key]
+			if ln == nil {
+				ln = &sclLN{Prefix: prefix, LNClass: class, Inst: strconv.Itoa(inst), LNType: key + "_T"}
+				lnIndex[key] = ln
+				lns = append(lns, ln)
+				types = append(types, sclLNodeType{ID: ln.LNType, LNClass: class})
+			}
+			doCount[key+k.doName]++
+			n := doCount[key+k.doName]
+			ln.DOIs = append(ln.DOIs, sclDOI{
+				Name: fmt.Sprintf("%s%d", k.doName, n),
+				Desc: fmt.Sprintf("%s (DNP %s %d)", p.Name, k.list, idx),
+			})
+			for i := range types {
+				if types[i].ID == ln.LNType {
+					types[i].DOs = append(types[i].DOs, sclDO{Name: fmt.Sprintf("%s%d", k.doName, n), Type: k.doType})
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(xmlGeneratorComment())
+	fmt.Fprintf(&b, `<SCL xmlns="http://www.iec.ch/61850/2003/SCL" version="2007" revision="B">`+"\n")
+	fmt.Fprintf(&b, `  <Header id="%s" toolID="dnpgen" nameStructure="IEDName"/>`+"\n", xmlEscape(ied))
+	fmt.Fprintf(&b, `  <IED name="%s" manufacturer="%s" type="ControlWave">`+"\n", xmlEscape(ied), xmlEscape(manufacturer))
+	b.WriteString("    <AccessPoint name=\"AP1\">\n      <Server>\n        <Authentication/>\n")
+	fmt.Fprintf(&b, "        <LDevice inst=\"%s\">\n", xmlEscape(ldInst))
+	b.WriteString("          <LN0 lnClass=\"LLN0\" inst=\"\" lnType=\"LLN0_T\"/>\n")
+	b.WriteString("          <LN lnClass=\"LPHD\" inst=\"1\" lnType=\"LPHD_T\"/>\n")
+	for _, ln := range lns {
+		out, err := xml.MarshalIndent(struct {
+			XMLName xml.Name `xml:"LN"`
+			*sclLN
+		}{sclLN: ln}, "          ", "  ")
+		if err != nil {
+			return err
+		}
+		b.Write(out)
+		b.WriteString("\n")
+	}
+	b.WriteString("        </LDevice>\n      </Server>\n    </AccessPoint>\n  </IED>\n")
+	b.WriteString("  <DataTypeTemplates>\n")
+	b.WriteString(sclBaseLNodeTypes)
+	for _, t := range types {
+		out, err := xml.MarshalIndent(struct {
+			XMLName xml.Name `xml:"LNodeType"`
+			sclLNodeType
+		}{sclLNodeType: t}, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		b.Write(out)
+		b.WriteString("\n")
+	}
+	b.WriteString(sclBaseTypes)
+	b.WriteString("  </DataTypeTemplates>\n</SCL>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// sclName adapta un nombre de nodo a un nombre de IED válido.
+func sclName(s string) string {
+	out := regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(s, "_")
+	if out == "" || (out[0] >= '0' && out[0] <= '9') {
+		out = "IED_" + out
+	}
+	return out
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const sclBaseLNodeTypes = `    <LNodeType id="LLN0_T" lnClass="LLN0">
+      <DO name="Beh" type="ENS_T"/>
+    </LNodeType>
+    <LNodeType id="LPHD_T" lnClass="LPHD">
+      <DO name="PhyHealth" type="ENS_T"/>
+    </LNodeType>
+`
+
+const sclBaseTypes = `    <DOType id="ENS_T" cdc="ENS">
+      <DA name="stVal" bType="Enum" type="BehModeKind" fc="ST"/>
+      <DA name="q" bType="Quality" fc="ST"/>
+      <DA name="t" bType="Timestamp" fc="ST"/>
+    </DOType>
+    <DOType id="SPS_T" cdc="SPS">
+      <DA name="stVal" bType="BOOLEAN" fc="ST" dchg="true"/>
+      <DA name="q" bType="Quality" fc="ST" qchg="true"/>
+      <DA name="t" bType="Timestamp" fc="ST"/>
+    </DOType>
+    <DOType id="SPC_T" cdc="SPC">
+      <DA name="stVal" bType="BOOLEAN" fc="ST" dchg="true"/>
+      <DA name="q" bType="Quality" fc="ST" qchg="true"/>
+      <DA name="t" bType="Timestamp" fc="ST"/>
+      <DA name="Oper" bType="Struct" type="SPC_Oper_T" fc="CO"/>
+      <DA name="ctlModel" bType="Enum" type="CtlModelKind" fc="CF"/>
+    </DOType>
+    <DOType id="MV_T" cdc="MV">
+      <DA name="mag" bType="Struct" type="AV_T" fc="MX" dchg="true"/>
+      <DA name="q" bType="Quality" fc="MX" qchg="true"/>
+      <DA name="t" bType="Timestamp" fc="MX"/>
+    </DOType>
+    <DOType id="APC_T" cdc="APC">
+      <DA name="mxVal" bType="Struct" type="AV_T" fc="MX" dchg="true"/>
+      <DA name="q" bType="Quality" fc="MX" qchg="true"/>
+      <DA name="t" bType="Timestamp" fc="MX"/>
+      <DA name="Oper" bType="Struct" type="APC_Oper_T" fc="CO"/>
+      <DA name="ctlModel" bType="Enum" type="CtlModelKind" fc="CF"/>
+    </DOType>
+    <DOType id="VSS_T" cdc="VSS">
+      <DA name="stVal" bType="VisString255" fc="ST" dchg="true"/>
+      <DA name="q" bType="Quality" fc="ST" qchg="true"/>
+      <DA name="t" bType="Timestamp" fc="ST"/>
+    </DOType>
+    <DAType id="AV_T">
+      <BDA name="f" bType="FLOAT32"/>
+    </DAType>
+    <DAType id="SPC_Oper_T">
+      <BDA name="ctlVal" bType="BOOLEAN"/>
+      <BDA name="ctlNum" bType="INT8U"/>
+      <BDA name="T" bType="Timestamp"/>
+      <BDA name="Test" bType="BOOLEAN"/>
+      <BDA name="Check" bType="Check"/>
+    </DAType>
+    <DAType id="APC_Oper_T">
+      <BDA name="ctlVal" bType="Struct" type="AV_T"/>
+      <BDA name="ctlNum" bType="INT8U"/>
+      <BDA name="T" bType="Timestamp"/>
+      <BDA name="Test" bType="BOOLEAN"/>
+      <BDA name="Check" bType="Check"/>
+    </DAType>
+    <EnumType id="BehModeKind">
+      <EnumVal ord="1">on</EnumVal>
+      <EnumVal ord="2">blocked</EnumVal>
+      <EnumVal ord="3">test</EnumVal>
+      <EnumVal ord="4">test/blocked</EnumVal>
+      <EnumVal ord="5">off</EnumVal>
+    </EnumType>
+    <EnumType id="CtlModelKind">
+      <EnumVal ord="0">status-only</EnumVal>
+      <EnumVal ord="1">direct-with-normal-security</EnumVal>
+      <EnumVal ord="2">sbo-with-normal-security</EnumVal>
+      <EnumVal ord="3">direct-with-enhanced-security</EnumVal>
+      <EnumVal ord="4">sbo-with-enhanced-security</EnumVal>
+    </EnumType>
+`
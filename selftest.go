@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dnp3converter/golden"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- AUTOCOMPROBACIÓN (selftest) ---
+//
+// Antes de tocar un proyecto de cliente, "dnpgen selftest" confirma que el
+// ejecutable y el config.yaml instalados funcionan. Escribe en un directorio
+// temporal un proyecto en miniatura incluido en el binario (.SIG y
+// __vardef.ini) y hace dos pasadas:
+//   - binary: genera con la configuración de referencia incluida y compara
+//     byte a byte __lists.ini y las exportaciones csv y soe con la salida
+//     esperada;
+//   - config: genera la misma muestra con el config.yaml instalado, sin
+//     escribir nada, y comprueba que el pipeline completo termina.
+// Sale con código 1 si algo falla.
+
+// selftestNode es el nodo de la muestra.
+const selftestNode = "SELFTEST"
+
+// selftestExports son las exportaciones que se comparan con la referencia.
+var selftestExports = []string{"csv", "soe"}
+
+// SelftestStep es el resultado de una pasada.
+type SelftestStep struct {
+	Name    string        `json:"name"`
+	Status  string        `json:"status"` // pass, fail o skip
+	Detail  string        `json:"detail,omitempty"`
+	Files   []golden.File `json:"files,omitempty"`
+	Elapsed string        `json:"elapsed,omitempty"`
+}
+
+// writeSelftestProject escribe la muestra en dir y devuelve la raíz del
+// proyecto.
+func writeSelftestProject(dir string) (string, error) {
+	resource := filepath.Join(dir, RelativePathToResource)
+	if err := os.MkdirAll(resource, 0o755); err != nil {
+		return "", err
+	}
+	files := map[string]string{
+		selftestNode + ".SIG": strings.ReplaceAll(selftestSIG, "\n", "\r\n"),
+		VarDefFile:            selftestVarDef,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(resource, name), []byte(content), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// selftestBinary genera la muestra con la configuración de referencia y la
+// compara con la salida esperada.
+func selftestBinary(project string) SelftestStep {
+	step := SelftestStep{Name: "binary", Status: "pass"}
+	var cfg Config
+	dec := yaml.NewDecoder(strings.NewReader(selftestConfig))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		step.Status, step.Detail = "fail", trf("configuración de referencia: %v", err)
+		return step
+	}
+	saved := GlobalConfig
+	GlobalConfig = cfg
+	got, err := goldenOutputs(project, selftestNode, selftestExports)
+	GlobalConfig = saved
+	if err != nil {
+		step.Status, step.Detail = "fail", err.Error()
+		return step
+	}
+
+	names := make([]string, 0, len(selftestExpected))
+	for name := range selftestExpected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := []byte(selftestExpected[name])
+		f := golden.File{File: name, Status: golden.StatusOK}
+		switch data, ok := got[name]; {
+		case !ok:
+			f.Status = golden.StatusMissing
+		case string(data) != string(want):
+			f.Status, f.Diff = golden.StatusChanged, goldenDiff(want, data)
+		}
+		if f.Status != golden.StatusOK {
+			step.Status = "fail"
+		}
+		step.Files = append(step.Files, f)
+	}
+	return step
+}
+
+// selftestConfigStep genera la muestra con el config.yaml instalado. Los
+// hallazgos no la paran: dependen de las reglas del equipo, no de la
+// instalación.
+func selftestConfigStep(project string, loaded error) SelftestStep {
+	step := SelftestStep{Name: "config", Status: "pass"}
+	if loaded != nil {
+		step.Status, step.Detail = "fail", loaded.Error()
+		return step
+	}
+	if configSource == "" {
+		step.Status, step.Detail = "skip", trf("No se encuentra %s", ConfigFile)
+		return step
+	}
+	findings := GlobalConfig.App.Findings
+	GlobalConfig.App.Findings.FailOn = "never"
+	res, err := generate(GenerateRequest{ProjectPath: project, NodeName: selftestNode, SkipExt: true, CheckOnly: true})
+	GlobalConfig.App.Findings = findings
+	if err != nil {
+		step.Status, step.Detail = "fail", err.Error()
+		return step
+	}
+	step.Detail = trf("%s: DI %d, DO %d, AI %d, AO %d; %d hallazgo(s)", configSource, res.DI, res.DO, res.AI, res.AO, len(res.Findings))
+	return step
+}
+
+// runSelftest implementa "dnpgen selftest [-keep] [-json]".
+func runSelftest(args []string) {
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	fs.Usage = manUsage("selftest", fs)
+	keep := fs.Bool("keep", false, tr("Conservar el proyecto de muestra y mostrar dónde está"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	// Sin config.yaml la pasada binary sigue siendo útil.
+	var loaded error
+	if path, ok := findConfigFile(); ok {
+		loaded = loadConfigFile(path)
+	}
+
+	dir, err := os.MkdirTemp("", "dnpgen-selftest-")
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	project, err := writeSelftestProject(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	var steps []SelftestStep
+	for _, run := range []func() SelftestStep{
+		func() SelftestStep { return selftestBinary(project) },
+		func() SelftestStep { return selftestConfigStep(project, loaded) },
+	} {
+		start := time.Now()
+		step := run()
+		step.Elapsed = time.Since(start).Round(time.Millisecond).String()
+		steps = append(steps, step)
+	}
+	failed := false
+	for _, s := range steps {
+		failed = failed || s.Status == "fail"
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"generator": generatorLine(), "project": project, "steps": steps}); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	} else {
+		fmt.Println(boldText(generatorLine()))
+		for _, s := range steps {
+			line := fmt.Sprintf("%-7s %s", s.Name, strings.ToUpper(s.Status))
+			switch s.Status {
+			case "pass":
+				fmt.Println(okText(line))
+			case "skip":
+				fmt.Println(warnText(line))
+			default:
+				fmt.Println(errText(line))
+			}
+			if s.Detail != "" {
+				fmt.Println("  " + s.Detail)
+			}
+			for _, f := range s.Files {
+				if f.Status == golden.StatusOK {
+					fmt.Println(okText("  = " + f.File))
+					continue
+				}
+				fmt.Println(errText(fmt.Sprintf("  ! %s (%s)", f.File, f.Status)))
+				for _, d := range f.Diff {
+					fmt.Println("      " + d)
+				}
+			}
+		}
+		if *keep {
+			fmt.Println(trf("Proyecto de muestra: %s", project))
+		}
+		if failed {
+			fmt.Println(errText(tr("La instalación no supera la autocomprobación")))
+		} else {
+			fmt.Println(okText(tr("Instalación correcta")))
+		}
+	}
+	if !*keep {
+		os.RemoveAll(dir)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// selftestConfig es la configuración de referencia: fija todo lo que cambia
+// la salida (títulos, spares, cabecera) para que no dependa del config.yaml
+// instalado ni del idioma.
+const selftestConfig = `app:
+  stale_sig: ignore
+  classification:
+    analog_output_regex: ["_SP$"]
+    digital_output_regex: ["_CMD$"]
+    soe:
+      patterns: ["_TRIP$"]
+    strings:
+      types: ["STRING"]
+  spares:
+    do: "@GV.DNP_DO_SPARE"
+    di: "@GV.DNP_DI_SPARE"
+    ao: "@GV.DNP_AO_SPARE"
+    ai: {strategy: pool, name: "@GV.AI_SPARE_{n}", start: 1, digits: 2}
+  scaling:
+    from_vardef: true
+    raw_min: 0
+    raw_max: 32767
+  reserved:
+    - {list: DI, from: 4, to: 5, name: "@GV.DNP_DIAG_{index}"}
+  system_points:
+    - {list: DI, index: 0, name: "@GV.SYS_COMM_OK", type: BOOL}
+  list_titles:
+    AI: "ENTRADAS ANALOGICAS DNP"
+    AO: "SALIDAS ANALOGICAS DNP"
+    DI: "ENTRADAS DIGITALES DNP"
+    DO: "SALIDAS DIGITALES DNP"
+    OS: "CADENAS DE OCTETOS DNP"
+  output:
+    header: false
+`
+
+// selftestSIG es el .SIG de la muestra, con LF; se escribe con CRLF como los
+// de SIGEXT.
+const selftestSIG = `SIG=@GV.FT101_PV TYPE=AA DESC="Caudal de entrada"
+SIG=@GV.PT102_PV TYPE=AAR DESC="Presion del colector"
+SIG=@GV.TK1_LVL_SP TYPE=REAL DESC="Consigna de nivel"
+SIG=@GV.P101_RUN TYPE=LA DESC="Bomba 1 en marcha"
+SIG=@GV.P101_CMD TYPE=BOOL DESC="Orden bomba 1"
+SIG=@GV.BRK1_TRIP TYPE=LA DESC="Disparo interruptor 1"
+SIG=@GV.V1_OPEN_CMD TYPE=DO
+SIG=@GV.AO_SPEED TYPE=AO
+SIG=@GV.RTU_ID TYPE=STRING
+SIG=@GV.TMR_1 TYPE=TMR
+`
+
+const selftestVarDef = `@GV.FT101_PV MIN=0 MAX=250 UNITS="m3/h"
+@GV.PT102_PV MIN=0 MAX=10 UNITS="bar"
+@GV.TK1_LVL_SP MIN=0 MAX=100 UNITS="%"
+`
+
+// selftestExpected es la salida esperada con selftestConfig, byte a byte.
+var selftestExpected = map[string]string{
+	"__lists.ini": `*LIST 32761   'ENTRADAS ANALOGICAS DNP'
+@GV.FT101_PV
+@GV.PT102_PV
+@GV.AI_SPARE_01
+@GV.AI_SPARE_02
+
+*LIST 32762   'SALIDAS ANALOGICAS DNP'
+@GV.DNP_AO_SPARE(FT101_PV)
+@GV.DNP_AO_SPARE(PT102_PV)
+@GV.TK1_LVL_SP
+@GV.AO_SPEED
+
+*LIST 32763   'ENTRADAS DIGITALES DNP'
+@GV.SYS_COMM_OK
+@GV.P101_RUN
+@GV.DNP_DI_SPARE(P101_CMD)
+@GV.BRK1_TRIP
+@GV.DNP_DIAG_4
+@GV.DNP_DIAG_5
+@GV.DNP_DI_SPARE(V1_OPEN_CMD)
+
+*LIST 32764   'SALIDAS DIGITALES DNP'
+@GV.DNP_DO_SPARE(P101_RUN)
+@GV.P101_CMD
+@GV.DNP_DO_SPARE(BRK1_TRIP)
+@GV.V1_OPEN_CMD
+
+*LIST 32765   'CADENAS DE OCTETOS DNP'
+@GV.RTU_ID
+
+`,
+	"SELFTEST.points.csv": `node,list,index,name,var,type,spare,soe,description,scale,offset,units,area,group,variation,event_variation,alarm_priority,trip_var,close_var
+SELFTEST,AI,0,@GV.FT101_PV,FT101_PV,AA,false,false,Caudal de entrada,0.007629627368999298,0,m3/h,,,,,,,
+SELFTEST,AI,1,@GV.PT102_PV,PT102_PV,AAR,false,false,Presion del colector,0.0003051850947599719,0,bar,,,,,,,
+SELFTEST,AI,2,@GV.AI_SPARE_01,TK1_LVL_SP,REAL,true,false,,,,,,,,,,,
+SELFTEST,AI,3,@GV.AI_SPARE_02,AO_SPEED,AO,true,false,,,,,,,,,,,
+SELFTEST,AO,0,@GV.DNP_AO_SPARE(FT101_PV),FT101_PV,AA,true,false,,,,,,,,,,,
+SELFTEST,AO,1,@GV.DNP_AO_SPARE(PT102_PV),PT102_PV,AAR,true,false,,,,,,,,,,,
+SELFTEST,AO,2,@GV.TK1_LVL_SP,TK1_LVL_SP,REAL,false,false,Consigna de nivel,0.0030518509475997192,0,%,,,,,,,
+SELFTEST,AO,3,@GV.AO_SPEED,AO_SPEED,AO,false,false,,,,,,,,,,,
+SELFTEST,DI,0,@GV.SYS_COMM_OK,SYS_COMM_OK,BOOL,false,false,,,,,,,,,,,
+SELFTEST,DI,1,@GV.P101_RUN,P101_RUN,LA,false,false,Bomba 1 en marcha,,,,,,,,,,
+SELFTEST,DI,2,@GV.DNP_DI_SPARE(P101_CMD),P101_CMD,BOOL,true,false,,,,,,,,,,,
+SELFTEST,DI,3,@GV.BRK1_TRIP,BRK1_TRIP,LA,false,true,Disparo interruptor 1,,,,,,,,,,
+SELFTEST,DI,4,@GV.DNP_DIAG_4,DNP_DIAG_4,,true,false,,,,,,,,,,,
+SELFTEST,DI,5,@GV.DNP_DIAG_5,DNP_DIAG_5,,true,false,,,,,,,,,,,
+SELFTEST,DI,6,@GV.DNP_DI_SPARE(V1_OPEN_CMD),V1_OPEN_CMD,DO,true,false,,,,,,,,,,,
+SELFTEST,DO,0,@GV.DNP_DO_SPARE(P101_RUN),P101_RUN,LA,true,false,,,,,,,,,,,
+SELFTEST,DO,1,@GV.P101_CMD,P101_CMD,BOOL,false,false,Orden bomba 1,,,,,,,,,,
+SELFTEST,DO,2,@GV.DNP_DO_SPARE(BRK1_TRIP),BRK1_TRIP,LA,true,false,,,,,,,,,,,
+SELFTEST,DO,3,@GV.V1_OPEN_CMD,V1_OPEN_CMD,DO,false,false,,,,,,,,,,,
+SELFTEST,OS,0,@GV.RTU_ID,RTU_ID,STRING,false,false,,,,,,,,,,,
+`,
+	"SELFTEST.soe.csv": `node,index,name,var,event_class
+SELFTEST,3,@GV.BRK1_TRIP,BRK1_TRIP,1
+`,
+}
@@ -0,0 +1,617 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// --- MODO SERVIDOR HTTP ---
+
+// ServerConfig configura el acceso al modo servidor (HTTP y gRPC).
+type ServerConfig struct {
+	// Token es el secreto que deben presentar los clientes (cabecera
+	// "Authorization: Bearer ..." o el metadato authorization en gRPC); admite
+	// ${VAR}. Obligatorio si alguna dirección de escucha no es de loopback.
+	Token string `yaml:"token"`
+	// Roots son los directorios bajo los que deben quedar el proyecto y el
+	// directorio de salida de cada trabajo recibido. Vacío = sin restricción,
+	// solo admitido escuchando en loopback.
+	Roots []string `yaml:"roots"`
+}
+
+func (c ServerConfig) token() string {
+	return os.ExpandEnv(c.Token)
+}
+
+// isLoopbackAddr indica si una dirección de escucha solo acepta conexiones
+// locales. ":8080" escucha en todas las interfaces.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkExposure exige token y raíces si alguna dirección no es de loopback.
+func (c ServerConfig) checkExposure(addrs ...string) error {
+	for _, addr := range addrs {
+		if addr == "" || isLoopbackAddr(addr) {
+			continue
+		}
+		if c.token() == "" {
+			return fmt.Errorf(tr("%s no es una dirección de loopback: configure app.server.token"), addr)
+		}
+		if len(c.Roots) == 0 {
+			return fmt.Errorf(tr("%s no es una dirección de loopback: configure app.server.roots"), addr)
+		}
+	}
+	return nil
+}
+
+// authorized compara la credencial recibida ("Bearer ...") con el token.
+func (c ServerConfig) authorized(header string) bool {
+	token := c.token()
+	if token == "" {
+		return true
+	}
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// errOutsideRoots indica un trabajo fuera de app.server.roots.
+var errOutsideRoots = errors.New("fuera de app.server.roots")
+
+// checkRequest comprueba que el proyecto y el directorio de salida (el de
+// out_dir o el que resulte de app.output.dir) queden bajo alguna raíz.
+func (c ServerConfig) checkRequest(req GenerateRequest) error {
+	if req.NodeName == "." || req.NodeName == ".." || strings.ContainsAny(req.NodeName, `/\`) {
+		return fmt.Errorf(tr("nodo %q no válido"), req.NodeName)
+	}
+	if len(c.Roots) == 0 {
+		return nil
+	}
+	project, err := filepath.Abs(normalizeLongPath(req.ProjectPath))
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{project, outputDirFor(project, req.NodeName, req.OutDir)} {
+		if !c.withinRoots(p) {
+			return fmt.Errorf("%s: %w", p, errOutsideRoots)
+		}
+	}
+	return nil
+}
+
+func (c ServerConfig) withinRoots(path string) bool {
+	for _, root := range c.Roots {
+		root, err := filepath.Abs(os.ExpandEnv(root))
+		if err != nil {
+			continue
+		}
+		// Windows no distingue mayúsculas.
+		rel, err := filepath.Rel(strings.ToLower(root), strings.ToLower(filepath.Clean(path)))
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Estados posibles de un trabajo de generación.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// Job es un trabajo de generación encolado en el servidor.
+type Job struct {
+	ID        string          `json:"id"`
+	Request   GenerateRequest `json:"request"`
+	State     string          `json:"state"`
+	Error     string          `json:"error,omitempty"`
+	ErrorCode string          `json:"error_code,omitempty"`
+	Result    *GenerateResult `json:"result,omitempty"`
+	Created   time.Time       `json:"created"`
+	Started   *time.Time      `json:"started,omitempty"`
+	Finished  *time.Time      `json:"finished,omitempty"`
+	Events    []JobEvent      `json:"events"`
+
+	artifact []byte
+	changed  chan struct{}
+}
+
+// JobEvent registra el inicio de una etapa de un trabajo.
+type JobEvent struct {
+	Stage   string    `json:"stage"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+	// Kind y los demás campos son los de GenerateEvent en los eventos de la
+	// generación que no son un cambio de etapa (avisos, avance de la lectura
+	// y fin de etapa).
+	Kind    string `json:"kind,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Signals int    `json:"signals,omitempty"`
+	Read    int64  `json:"read,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+	Millis  int64  `json:"ms,omitempty"`
+}
+
+func (j *Job) finished() bool {
+	return j.State == JobDone || j.State == JobFailed
+}
+
+// jobStore guarda los trabajos en memoria y los reparte entre un número
+// acotado de workers. Dos trabajos del mismo proyecto nunca se ejecutan a la
+// vez (comparten recurso RTU, SIGEXT y base de puntos); los de proyectos
+// distintos sí, hasta el límite de workers. Dentro de cada proyecto se
+// respeta el orden de llegada.
+// Si dir no está vacío, cada trabajo (y su artefacto) se persiste allí para
+// que la cola sobreviva a un reinicio del servicio.
+type jobStore struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    map[string]*Job
+	pending []*Job          // en espera, por orden de llegada
+	busy    map[string]*Job // proyecto -> trabajo en curso
+	workers int
+	limit   int // máximo de trabajos en espera
+	dir     string
+}
+
+// errQueueFull indica que la cola alcanzó su límite de trabajos en espera.
+var errQueueFull = codedErrorf(ErrQueueFull, "cola de trabajos llena")
+
+func newJobStore(dir string, workers, limit int) (*jobStore, error) {
+	s := &jobStore{jobs: map[string]*Job{}, busy: map[string]*Job{}, workers: max(workers, 1), limit: limit, dir: dir}
+	s.cond = sync.NewCond(&s.mu)
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	pending, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		log.Printf(tr("Recuperados %d trabajos pendientes de %s"), len(pending), dir)
+		s.pending = pending
+	}
+	return s, nil
+}
+
+// load lee los trabajos persistidos y devuelve, en orden de creación, los que
+// no llegaron a terminar. Los que estaban en curso vuelven a la cola.
+func (s *jobStore) load() ([]*Job, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Job
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		j := &Job{}
+		if err := json.Unmarshal(data, j); err != nil {
+			log.Printf(tr("[WARN] Trabajo ilegible %s: %v"), filepath.Base(f), err)
+			continue
+		}
+		j.changed = make(chan struct{})
+		if j.State == JobDone {
+			j.artifact, _ = os.ReadFile(filepath.Join(s.dir, j.ID+".ini"))
+		}
+		if !j.finished() {
+			j.State = JobQueued
+			pending = append(pending, j)
+		}
+		s.jobs[j.ID] = j
+	}
+	sort.Slice(pending, func(a, b int) bool { return pending[a].Created.Before(pending[b].Created) })
+	return pending, nil
+}
+
+// persist guarda el estado del trabajo en disco. Requiere s.mu.
+func (s *jobStore) persist(j *Job) {
+	if s.dir == "" {
+		return
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(filepath.Join(s.dir, j.ID+".json"), data)
+	}
+	if err == nil && j.State == JobDone {
+		err = os.WriteFile(filepath.Join(s.dir, j.ID+".ini"), j.artifact, 0o644)
+	}
+	if err != nil {
+		log.Printf(tr("[WARN] No se pudo persistir el trabajo %s: %v"), j.ID, err)
+	}
+}
+
+// writeFileAtomic escribe en un temporal y renombra, para no dejar ficheros a
+// medias si el proceso se detiene durante la escritura.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *j, true
+}
+
+// watch devuelve los eventos del trabajo a partir de from, si ya terminó, y un
+// canal que se cierra en el próximo cambio. Debe llamarse sin el lock tomado.
+func (s *jobStore) watch(id string, from int) (events []JobEvent, done bool, changed <-chan struct{}, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, false, nil, false
+	}
+	if from < len(j.Events) {
+		events = append(events, j.Events[from:]...)
+	}
+	return events, j.finished(), j.changed, true
+}
+
+// addEvent añade un evento y despierta a los observadores. Requiere s.mu.
+func (s *jobStore) addEvent(j *Job, stage, message string) {
+	s.addJobEvent(j, JobEvent{Stage: stage, Message: message, Time: time.Now()})
+}
+
+func (s *jobStore) addJobEvent(j *Job, e JobEvent) {
+	j.Events = append(j.Events, e)
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// projectKey identifica el proyecto de un trabajo para serializarlo.
+func projectKey(req GenerateRequest) string {
+	p := req.ProjectPath
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	return strings.ToLower(filepath.Clean(p)) // Windows no distingue mayúsculas
+}
+
+func (s *jobStore) enqueue(req GenerateRequest) (*Job, error) {
+	j := &Job{ID: newJobID(), Request: req, State: JobQueued, Created: time.Now(), changed: make(chan struct{})}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit > 0 && len(s.pending) >= s.limit {
+		return nil, errQueueFull
+	}
+	s.jobs[j.ID] = j
+	s.addEvent(j, JobQueued, "")
+	s.persist(j)
+	s.pending = append(s.pending, j)
+	s.cond.Signal()
+	return j, nil
+}
+
+// next espera al primer trabajo en espera cuyo proyecto esté libre, lo marca
+// en curso y lo devuelve. Requiere s.mu.
+func (s *jobStore) next() *Job {
+	for {
+		for i, j := range s.pending {
+			key := projectKey(j.Request)
+			if s.busy[key] != nil {
+				continue
+			}
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			s.busy[key] = j
+			return j
+		}
+		s.cond.Wait()
+	}
+}
+
+// start lanza los workers.
+func (s *jobStore) start() {
+	for i := 0; i < s.workers; i++ {
+		go s.worker()
+	}
+}
+
+func (s *jobStore) worker() {
+	for {
+		s.mu.Lock()
+		j := s.next()
+		now := time.Now()
+		j.State = JobRunning
+		j.Started = &now
+		req := j.Request
+		s.addEvent(j, JobRunning, "")
+		s.persist(j)
+		s.mu.Unlock()
+
+		req.Progress = func(stage, message string) {
+			s.mu.Lock()
+			s.addEvent(j, stage, message)
+			s.mu.Unlock()
+		}
+		// Los cambios de etapa ya llegan por Progress.
+		req.Events = func(e GenerateEvent) {
+			if e.Kind == EventStageStarted {
+				return
+			}
+			s.mu.Lock()
+			s.addJobEvent(j, JobEvent{Stage: e.Stage, Message: e.Message, Time: e.Time, Kind: e.Kind,
+				Code: e.Code, Signals: e.Signals, Read: e.Read, Size: e.Size, Millis: e.Millis})
+			s.mu.Unlock()
+		}
+
+		log.Printf(tr("[JOB %s] Generando nodo %s (%s)"), j.ID, req.NodeName, req.ProjectPath)
+		res, err := runGenerate(req)
+		if err == nil && res.Drift {
+			log.Printf(tr("[JOB %s] [DRIFT] %s/%s: %s no coincide con el SIG actual"), j.ID, req.ProjectPath, req.NodeName, ListFile)
+		}
+		if err == nil && len(res.CountAlarms) > 0 {
+			log.Printf(tr("[JOB %s] [ALARM] %s/%s: caída brusca de puntos (%s)"), j.ID, req.ProjectPath, req.NodeName, formatCountAlarms(res.CountAlarms))
+		}
+
+		s.mu.Lock()
+		now = time.Now()
+		j.Finished = &now
+		if err != nil {
+			j.State = JobFailed
+			j.Error, j.ErrorCode = err.Error(), errorCode(err)
+			log.Printf(tr("[JOB %s] [ERROR] %v"), j.ID, err)
+			s.addEvent(j, JobFailed, j.Error)
+		} else {
+			j.State = JobDone
+			j.Result = res
+			j.artifact = res.content
+			s.addEvent(j, JobDone, "")
+		}
+		s.persist(j)
+		delete(s.busy, projectKey(j.Request))
+		s.cond.Broadcast()
+		s.mu.Unlock()
+
+		notifyResult(req, j.ID, res, err)
+	}
+}
+
+// QueueEntry describe un trabajo en la vista de la cola.
+type QueueEntry struct {
+	ID      string     `json:"id"`
+	Project string     `json:"project"`
+	Node    string     `json:"node"`
+	Created time.Time  `json:"created"`
+	Started *time.Time `json:"started,omitempty"`
+	// Blocked indica que espera a que termine otro trabajo del mismo proyecto.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// QueueStatus es la respuesta de GET /queue.
+type QueueStatus struct {
+	Workers int          `json:"workers"`
+	Limit   int          `json:"limit"`
+	Running []QueueEntry `json:"running"`
+	Queued  []QueueEntry `json:"queued"`
+}
+
+// status devuelve una foto de los trabajos en curso y en espera.
+func (s *jobStore) status() QueueStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := QueueStatus{Workers: s.workers, Limit: s.limit, Running: []QueueEntry{}, Queued: []QueueEntry{}}
+	entry := func(j *Job) QueueEntry {
+		return QueueEntry{ID: j.ID, Project: j.Request.ProjectPath, Node: j.Request.NodeName, Created: j.Created, Started: j.Started}
+	}
+	for _, j := range s.busy {
+		st.Running = append(st.Running, entry(j))
+	}
+	sort.Slice(st.Running, func(a, b int) bool { return st.Running[a].Started.Before(*st.Running[b].Started) })
+	for _, j := range s.pending {
+		e := entry(j)
+		e.Blocked = s.busy[projectKey(j.Request)] != nil
+		st.Queued = append(st.Queued, e)
+	}
+	return st
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+// serverShutdownTimeout es lo que se espera a las peticiones en curso al
+// detener el servidor.
+const serverShutdownTimeout = 10 * time.Second
+
+// runServer ejecuta el modo servidor hasta Ctrl+C o SIGTERM.
+func runServer(args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := serveUntil(ctx, args, flag.ExitOnError); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+}
+
+// serveUntil arranca los servidores HTTP y gRPC y, al cancelarse ctx, los
+// detiene esperando a las peticiones en curso. Devuelve el error que impida
+// arrancar o que detenga alguno de ellos (el otro se para con él); nil tras
+// una parada pedida. El servicio de Windows lo llama con ContinueOnError
+// para informar de un flag erróneo al SCM en lugar de salir.
+func serveUntil(ctx context.Context, args []string, onFlagError flag.ErrorHandling) error {
+	fs := flag.NewFlagSet("server", onFlagError)
+	fs.Usage = manUsage("server", fs)
+	addr := fs.String("addr", "127.0.0.1:8080", tr("Dirección de escucha HTTP (fuera de loopback exige app.server.token y app.server.roots)"))
+	grpcAddr := fs.String("grpc-addr", "", tr("Dirección de escucha gRPC (vacío = deshabilitado)"))
+	queueDir := fs.String("queue-dir", "", tr("Directorio para persistir la cola de trabajos (vacío = solo memoria)"))
+	logFile := fs.String("log", "", tr("Fichero de log (útil al ejecutarse como servicio)"))
+	workers := fs.Int("workers", 4, tr("Trabajos simultáneos (nunca dos del mismo proyecto)"))
+	queueLimit := fs.Int("queue-limit", 100, tr("Máximo de trabajos en espera (0 = sin límite)"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf(tr("Error abriendo log: %v"), err)
+		}
+		setLogOutput(f)
+	}
+
+	cfg := GlobalConfig.App.Server
+	if err := cfg.checkExposure(*addr, *grpcAddr); err != nil {
+		return err
+	}
+
+	store, err := newJobStore(*queueDir, *workers, *queueLimit)
+	if err != nil {
+		return fmt.Errorf(tr("Error inicializando cola de trabajos: %v"), err)
+	}
+	store.start()
+
+	if err := startScheduler(GlobalConfig.App.Schedule, store); err != nil {
+		return fmt.Errorf(tr("Error en schedule: %v"), err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /generate", func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": tr("JSON inválido: ") + err.Error()})
+			return
+		}
+		if req.ProjectPath == "" || req.NodeName == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": tr("se requieren 'project' y 'node'")})
+			return
+		}
+		if err := cfg.checkRequest(req); err != nil {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+			return
+		}
+		j, err := store.enqueue(req)
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error(), "error_code": errorCode(err)})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": j.ID, "state": j.State})
+	})
+	mux.HandleFunc("GET /queue", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, store.status())
+	})
+	mux.HandleFunc("GET /status/{id}", func(w http.ResponseWriter, r *http.Request) {
+		j, ok := store.get(r.PathValue("id"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": tr("trabajo no encontrado")})
+			return
+		}
+		writeJSON(w, http.StatusOK, j)
+	})
+	mux.HandleFunc("GET /artifacts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		j, ok := store.get(r.PathValue("id"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": tr("trabajo no encontrado")})
+			return
+		}
+		if j.State != JobDone {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": tr("trabajo no finalizado"), "state": j.State})
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+ListFile+`"`)
+		w.Write(j.artifact)
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errc := make(chan error, 2)
+	servers := 1
+	if *grpcAddr != "" {
+		servers++
+		go func() { errc <- serveGRPC(ctx, *grpcAddr, store, cfg) }()
+	}
+	log.Printf(tr("Servidor escuchando en %s"), *addr)
+	go func() { errc <- serveHTTP(ctx, &http.Server{Addr: *addr, Handler: requireToken(cfg, mux)}) }()
+
+	var first error
+	for range servers {
+		if err := <-errc; err != nil && first == nil {
+			first = err
+		}
+		cancel()
+	}
+	return first
+}
+
+// serveHTTP atiende srv hasta que se cancela ctx y espera entonces a las
+// peticiones en curso (como mucho serverShutdownTimeout).
+func serveHTTP(ctx context.Context, srv *http.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		if srv.Shutdown(sctx) != nil {
+			srv.Close()
+		}
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-stopped
+	return nil
+}
+
+// requireToken rechaza las peticiones sin el token de app.server.token.
+func requireToken(cfg ServerConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.authorized(r.Header.Get("Authorization")) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": tr("token no válido")})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServerExposureRequiresTokenAndRoots(t *testing.T) {
+	for _, c := range []struct {
+		addr string
+		cfg  ServerConfig
+		ok   bool
+	}{
+		{"127.0.0.1:8080", ServerConfig{}, true},
+		{"localhost:8080", ServerConfig{}, true},
+		{"[::1]:8080", ServerConfig{}, true},
+		{":8080", ServerConfig{}, false},
+		{"0.0.0.0:8080", ServerConfig{Token: "x"}, false},
+		{"0.0.0.0:8080", ServerConfig{Roots: []string{"/srv"}}, false},
+		{"0.0.0.0:8080", ServerConfig{Token: "x", Roots: []string{"/srv"}}, true},
+	} {
+		if err := c.cfg.checkExposure(c.addr); (err == nil) != c.ok {
+			t.Errorf("checkExposure(%q, %+v) = %v", c.addr, c.cfg, err)
+		}
+	}
+}
+
+func TestServerCheckRequestRestrictsToRoots(t *testing.T) {
+	root := t.TempDir()
+	cfg := ServerConfig{Roots: []string{root}}
+	project := filepath.Join(root, "Planta")
+	for _, c := range []struct {
+		name string
+		req  GenerateRequest
+		ok   bool
+	}{
+		{"proyecto bajo la raíz", GenerateRequest{ProjectPath: project, NodeName: "RTU01"}, true},
+		{"salida bajo la raíz", GenerateRequest{ProjectPath: project, NodeName: "RTU01", OutDir: filepath.Join(root, "salida")}, true},
+		{"proyecto fuera", GenerateRequest{ProjectPath: filepath.Dir(root), NodeName: "RTU01"}, false},
+		{"proyecto que escapa con ..", GenerateRequest{ProjectPath: filepath.Join(root, "..", "otro"), NodeName: "RTU01"}, false},
+		{"prefijo sin separador", GenerateRequest{ProjectPath: root + "-otro", NodeName: "RTU01"}, false},
+		{"salida fuera", GenerateRequest{ProjectPath: project, NodeName: "RTU01", OutDir: filepath.Dir(root)}, false},
+		{"nodo con ruta", GenerateRequest{ProjectPath: project, NodeName: "../../x"}, false},
+	} {
+		err := cfg.checkRequest(c.req)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: checkRequest = %v", c.name, err)
+		}
+		if err != nil && c.name != "nodo con ruta" && !errors.Is(err, errOutsideRoots) {
+			t.Errorf("%s: error %v no es errOutsideRoots", c.name, err)
+		}
+	}
+}
+
+func TestServerRequireToken(t *testing.T) {
+	h := requireToken(ServerConfig{Token: "s3creto"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for header, want := range map[string]int{
+		"":               http.StatusUnauthorized,
+		"Bearer otro":    http.StatusUnauthorized,
+		"s3creto":        http.StatusUnauthorized,
+		"Bearer s3creto": http.StatusOK,
+	} {
+		r := httptest.NewRequest("GET", "/queue", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("Authorization %q: %d, se esperaba %d", header, w.Code, want)
+		}
+	}
+}
+
+func TestServeUntilReturnsErrorsAndStops(t *testing.T) {
+	if err := serveUntil(context.Background(), []string{"-addr", "0.0.0.0:0"}, flag.ContinueOnError); err == nil {
+		t.Fatal("sin token fuera de loopback: se esperaba un error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveUntil(ctx, []string{"-addr", "127.0.0.1:0", "-grpc-addr", "127.0.0.1:0"}, flag.ContinueOnError)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("parada pedida: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("el servidor no se detuvo al cancelar el contexto")
+	}
+}
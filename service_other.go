@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "log"
+
+// runServiceCommand solo está disponible en Windows. En Linux el modo servidor
+// se ejecuta con systemd (ver deploy/dnpgen.service).
+func runServiceCommand(args []string) {
+	log.Fatal("El subcomando service solo existe en Windows; en Linux use deploy/dnpgen.service con systemd")
+}
@@ -0,0 +1,129 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// --- SERVICIO DE WINDOWS ---
+
+const serviceName = "dnpgen"
+
+// runServiceCommand registra, elimina o ejecuta el modo servidor como servicio
+// de Windows. Los argumentos tras "install" se pasan tal cual a "server".
+func runServiceCommand(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Uso: dnpgen.exe service install|uninstall|run [flags de server]")
+	}
+	var err error
+	switch args[0] {
+	case "install":
+		err = installService(args[1:])
+	case "uninstall":
+		err = uninstallService()
+	case "run":
+		loadConfiguration()
+		err = svc.Run(serviceName, &serviceHandler{args: args[1:]})
+	default:
+		err = fmt.Errorf("acción desconocida: %s", args[0])
+	}
+	if err != nil {
+		log.Fatalf("[FATAL] Servicio: %v", err)
+	}
+}
+
+func installService(serverArgs []string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(serviceName); err == nil {
+		s.Close()
+		return fmt.Errorf("el servicio %s ya existe", serviceName)
+	}
+	s, err := m.CreateService(serviceName, exePath, mgr.Config{
+		DisplayName: "Generador DNP3",
+		Description: "Genera __lists.ini bajo demanda (API HTTP/gRPC)",
+		StartType:   mgr.StartAutomatic,
+	}, append([]string{"service", "run"}, serverArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	log.Printf("Servicio %s instalado: %s service run %s", serviceName, exePath, strings.Join(serverArgs, " "))
+	return nil
+}
+
+func uninstallService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("el servicio %s no está instalado", serviceName)
+	}
+	defer s.Close()
+	if err := s.Delete(); err != nil {
+		return err
+	}
+	log.Printf("Servicio %s eliminado", serviceName)
+	return nil
+}
+
+type serviceHandler struct {
+	args []string
+}
+
+// Execute arranca el servidor y lo detiene de forma ordenada con Stop o
+// Shutdown. Si el servidor no arranca o cae, se informa al SCM con un código
+// propio para que registre el fallo (y aplique la recuperación configurada).
+func (h *serviceHandler) Execute(_ []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- serveUntil(ctx, h.args, flag.ContinueOnError) }()
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case err := <-done:
+			return serviceExit(err)
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+				return serviceExit(<-done)
+			}
+		}
+	}
+}
+
+// serviceExit traduce el final del servidor al resultado de Execute.
+func serviceExit(err error) (bool, uint32) {
+	if err == nil {
+		return false, 0
+	}
+	log.Printf("[FATAL] Servicio: %v", err)
+	return true, 1
+}
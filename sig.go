@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// --- LECTURA DEL .SIG ---
+//
+// Los .SIG editados a mano en campo traen de todo: líneas cortadas, bytes
+// binarios, finales de línea mezclados o líneas de megas. ParseSIG nunca
+// aborta por el contenido: cada línea problemática queda como diagnóstico y
+// se sigue con la siguiente. El BOM UTF-8 y los finales CR o CRLF de los
+// ficheros que han pasado por Windows y Linux se normalizan antes de leer
+// las líneas, y SigFormat cuenta lo que se encontró junto con la versión
+// del formato.
+
+const (
+	maxSigLine        = 64 * 1024 // las líneas más largas se descartan
+	maxSigDiagnostics = 200       // a partir de aquí solo se cuentan
+)
+
+// Signal es una línea SIG= válida.
+type Signal struct {
+	Var  string // sin "@GV."
+	Type string
+	Desc string // DESC=/DESCRIPTION= de los .SIG recientes; vacío si no hay
+	Line int
+}
+
+// SigDiagnostic describe una línea del .SIG que se ignoró.
+type SigDiagnostic struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (d SigDiagnostic) String() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	return fmt.Sprintf(tr("línea %d: %s"), d.Line, d.Message)
+}
+
+// SigFormat describe la versión del formato, la codificación y los finales
+// de línea del .SIG.
+type SigFormat struct {
+	Version string `json:"version"`       // dialecto detectado (sigdialect.go)
+	BOM     bool   `json:"bom,omitempty"` // BOM UTF-8 al principio
+	CRLF    int    `json:"crlf,omitempty"`
+	LF      int    `json:"lf,omitempty"`
+	CR      int    `json:"cr,omitempty"` // CR solo (Mac clásico o edición a medias)
+}
+
+// normalized indica si hubo que normalizar algo digno de mención: el BOM,
+// finales CR o una mezcla de tipos. Un fichero todo CRLF o todo LF es
+// normal.
+func (f SigFormat) normalized() bool {
+	return f.BOM || f.CR > 0 || (f.CRLF > 0 && f.LF > 0)
+}
+
+func (f SigFormat) String() string {
+	var parts []string
+	if f.BOM {
+		parts = append(parts, tr("BOM UTF-8 eliminado"))
+	}
+	var kinds []string
+	for _, k := range []struct {
+		name string
+		n    int
+	}{{"CRLF", f.CRLF}, {"LF", f.LF}, {"CR", f.CR}} {
+		if k.n > 0 {
+			kinds = append(kinds, fmt.Sprintf("%s %d", k.name, k.n))
+		}
+	}
+	switch {
+	case len(kinds) > 1:
+		parts = append(parts, trf("finales de línea mezclados normalizados (%s)", strings.Join(kinds, ", ")))
+	case f.CR > 0:
+		parts = append(parts, trf("finales de línea CR normalizados (%d)", f.CR))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// errSigUTF16 es un .SIG guardado como "Unicode" desde el Bloc de notas: sin
+// convertir, no se reconoce ninguna línea.
+var errSigUTF16 = errors.New("el .SIG está en UTF-16: guárdelo como UTF-8 o ANSI, o regenérelo con SIGEXT")
+
+// eolReader convierte CRLF y CR en LF al vuelo, contando cada tipo. Solo
+// elimina bytes, así que transforma en el propio buffer de lectura.
+type eolReader struct {
+	r         io.Reader
+	f         *SigFormat
+	pendingCR bool
+}
+
+func (e *eolReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	w := 0
+	for _, c := range p[:n] {
+		if e.pendingCR {
+			e.pendingCR = false
+			if c == '\n' {
+				e.f.CRLF++
+				continue // el LF ya se emitió por el CR
+			}
+			e.f.CR++
+		}
+		switch c {
+		case '\r':
+			c, e.pendingCR = '\n', true
+		case '\n':
+			e.f.LF++
+		}
+		p[w] = c
+		w++
+	}
+	if err != nil && e.pendingCR {
+		e.pendingCR = false
+		e.f.CR++
+	}
+	return w, err
+}
+
+// ParseSIG recorre el .SIG y llama a emit con cada señal válida, en orden,
+// con el dialecto de su versión (sigdialect.go). Solo devuelve error si
+// falla la lectura en sí, el fichero está en UTF-16 o declara una versión
+// del formato que no se conoce.
+func ParseSIG(r io.Reader, emit func(Signal)) (SigFormat, []SigDiagnostic, error) {
+	var format SigFormat
+	head := bufio.NewReaderSize(r, 64*1024)
+	if start, _ := head.Peek(len(bomUTF8)); bytes.HasPrefix(start, bomUTF8) {
+		format.BOM = true
+		head.Discard(len(bomUTF8))
+	} else if bytes.HasPrefix(start, bomUTF16LE) || bytes.HasPrefix(start, bomUTF16BE) {
+		return format, nil, errSigUTF16
+	}
+	br := bufio.NewReaderSize(&eolReader{r: head, f: &format}, 64*1024)
+	start, _ := br.Peek(4 * 1024)
+	dialect, err := sniffSigDialect(start)
+	if err != nil {
+		return format, nil, err
+	}
+	format.Version = dialect.Version
+	var diags []SigDiagnostic
+	dropped := 0
+	diag := func(line int, format string, args ...any) {
+		if len(diags) >= maxSigDiagnostics {
+			dropped++
+			return
+		}
+		diags = append(diags, SigDiagnostic{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for lineNo := 1; ; lineNo++ {
+		raw, tooLong, err := readSigLine(br)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return format, diags, err
+		}
+		if raw == nil && errors.Is(err, io.EOF) {
+			break
+		}
+
+		line := bytes.TrimSpace(raw)
+		switch {
+		case tooLong:
+			diag(lineNo, tr("línea de más de %d bytes"), maxSigLine)
+		case bytes.IndexByte(line, 0) >= 0 || !utf8.Valid(line):
+			if bytes.Contains(line, []byte("SIG=")) {
+				diag(lineNo, "%s", tr("contenido binario o codificación inválida"))
+			}
+		case !bytes.HasPrefix(line, []byte("SIG=")):
+			// Un SIG= a media línea es un registro pegado a otro o cortado.
+			if bytes.Contains(line, []byte("SIG=")) {
+				diag(lineNo, tr("línea SIG no reconocida: %.80q"), line)
+			}
+		default:
+			sig, ok := dialect.parse(line)
+			if !ok {
+				diag(lineNo, tr("línea SIG no reconocida: %.80q"), line)
+				break
+			}
+			sig.Line = lineNo
+			emit(sig)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+	if dropped > 0 {
+		diags = append(diags, SigDiagnostic{Message: fmt.Sprintf(tr("... y %d diagnósticos más"), dropped)})
+	}
+	return format, diags, nil
+}
+
+// readSigLine lee hasta el siguiente '\n' sin acumular más de maxSigLine
+// bytes; el resto de una línea demasiado larga se descarta. Devuelve io.EOF
+// junto con la última línea si el fichero no termina en salto de línea.
+func readSigLine(br *bufio.Reader) (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(chunk) > maxSigLine {
+				tooLong, line = true, []byte{}
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil && len(line) == 0 && !tooLong {
+			return nil, false, err
+		}
+		return line, tooLong, err
+	}
+}
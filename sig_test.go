@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sigParseBudget es el tiempo máximo de ParseSIG con una entrada del fuzz.
+const sigParseBudget = 5 * time.Second
+
+func FuzzParseSIG(f *testing.F) {
+	for _, seed := range []string{
+		"SIG=@GV.LIT101 TYPE=AA\nSIG=@GV.P101_RUN TYPE=LA\n",
+		"SIG=@GV.LIT101 TYPE=AA\r\nSIG=@GV.P101_RUN TYPE=LA\r\n",
+		"SIG=@GV.A TYPE=AA\rSIG=@GV.B TYPE=LA\r\nSIG=@GV.C TYPE=REAL\n",
+		"\xef\xbb\xbfSIG=@GV.A TYPE=AA\n",
+		"[HEADER]\nVERSION=2\n[SIGNALS]\nSIG=@GV.A;TYPE=AA;DESC=\"Nivel\"\n",
+		"SIG=@GV.A TYPE=AA\nSIG=@GV.B TY",
+		"SIG=@GV.A TYPE=AA\nSIG=",
+		"SIG=\n",
+		"SIG=@GV.A TYPE=AA SIG=@GV.B TYPE=LA\n",
+		"basuraSIG=@GV.A TYPE=AA\n",
+		"SIG=@GV.A\xff\xfe TYPE=AA\n",
+		"SIG=@GV.A\x00 TYPE=AA\n",
+		"\xc3\x28SIG=@GV.B TYPE=LA\n",
+		"VERSION=9\nSIG=@GV.A TYPE=AA\n",
+		"\xff\xfeS\x00I\x00G\x00=\x00",
+		"SIG=@GV." + strings.Repeat("X", maxSigLine+10) + " TYPE=AA\nSIG=@GV.B TYPE=LA\n",
+		strings.Repeat("SIG=@GV.A TYPE=AA\n", 2000),
+		strings.Repeat("\r", 100) + "SIG=@GV.A TYPE=AA",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		type parsed struct {
+			format SigFormat
+			diags  []SigDiagnostic
+			err    error
+			lines  []int
+		}
+		done := make(chan parsed, 1)
+		go func() {
+			var p parsed
+			p.format, p.diags, p.err = ParseSIG(bytes.NewReader(data), func(s Signal) { p.lines = append(p.lines, s.Line) })
+			done <- p
+		}()
+		var p parsed
+		select {
+		case p = <-done:
+		case <-time.After(sigParseBudget):
+			t.Fatalf("ParseSIG tarda más de %s con %d bytes", sigParseBudget, len(data))
+		}
+		if p.err != nil {
+			return // UTF-16 o versión desconocida: error explícito, no un salto silencioso
+		}
+
+		for i := 1; i < len(p.lines); i++ {
+			if p.lines[i] <= p.lines[i-1] {
+				t.Fatalf("señales fuera de orden: línea %d tras %d", p.lines[i], p.lines[i-1])
+			}
+		}
+		emitted := map[int]bool{}
+		for _, l := range p.lines {
+			emitted[l] = true
+		}
+		diagnosed := map[int]bool{}
+		truncated := false
+		for _, d := range p.diags {
+			if d.Line == 0 {
+				truncated = true // "... y N diagnósticos más"
+			}
+			diagnosed[d.Line] = true
+		}
+
+		// Cada línea con SIG= da una señal o un diagnóstico, con las mismas
+		// líneas que ve ParseSIG (sin BOM y con CR/CRLF como LF).
+		text := bytes.TrimPrefix(data, bomUTF8)
+		text = bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n"))
+		text = bytes.ReplaceAll(text, []byte("\r"), []byte("\n"))
+		for i, line := range bytes.Split(text, []byte("\n")) {
+			n := i + 1
+			if !bytes.Contains(line, []byte("SIG=")) || emitted[n] || diagnosed[n] || truncated {
+				continue
+			}
+			t.Fatalf("línea %d con SIG= sin señal ni diagnóstico: %.80q", n, line)
+		}
+	})
+}
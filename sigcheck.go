@@ -0,0 +1,112 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- .SIG INCOMPLETO ---
+//
+// Un SIGEXT cortado a medias deja un .SIG válido pero con menos señales, y la
+// generación sigue como si nada. Se compara el número de señales con un
+// mínimo configurado y/o con las variables que referencia el .mwt.
+
+// SigCheckConfig configura la comprobación; todo desactivado por defecto.
+type SigCheckConfig struct {
+	MinSignals int `yaml:"min_signals"` // 0 = sin mínimo
+	// Mwt busca en el .mwt las referencias @GV.<variable> y exige que el
+	// .SIG contenga al menos MinRatio de ellas.
+	Mwt      bool    `yaml:"mwt"`
+	MinRatio float64 `yaml:"min_ratio"` // 0.9 por defecto
+}
+
+func (c SigCheckConfig) minRatio() float64 {
+	if c.MinRatio > 0 {
+		return c.MinRatio
+	}
+	return 0.9
+}
+
+var mwtVarRe = regexp.MustCompile(`@GV\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// mwtVariables devuelve las variables referenciadas como @GV.<nombre> en el
+// .mwt. El formato del proyecto no está documentado: se buscan las
+// referencias como texto (ASCII y UTF-16) en el fichero o, si es un zip, en
+// cada una de sus entradas.
+func mwtVariables(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]bool{}
+	scan := func(b []byte) {
+		if bytes.Contains(b, []byte("@\x00G\x00V\x00")) {
+			b = bytes.ReplaceAll(b, []byte{0}, nil) // UTF-16LE
+		}
+		for _, m := range mwtVarRe.FindAllSubmatch(b, -1) {
+			vars[string(m[1])] = true
+		}
+	}
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		scan(data)
+		return vars, nil
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		scan(data)
+		return vars, nil
+	}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err == nil {
+			scan(b)
+		}
+	}
+	return vars, nil
+}
+
+// checkSigCompleteness añade un hallazgo sig_incomplete si el .SIG tiene
+// sospechosamente pocas señales.
+func checkSigCompleteness(s *findingSet, rep *sigReport, mwtPath string) {
+	cfg := GlobalConfig.App.SigCheck
+	if cfg.MinSignals > 0 && rep.Signals < cfg.MinSignals {
+		s.add(FindingSigIncomplete, SeverityWarning, tr(".SIG con %d señales (mínimo %d): ¿SIGEXT incompleto?"), rep.Signals, cfg.MinSignals)
+	}
+	if !cfg.Mwt || !fileExists(mwtPath) {
+		return
+	}
+	vars, err := mwtVariables(mwtPath)
+	if err != nil {
+		log.Printf(tr("[WARN] No se puede comprobar el .SIG contra el .mwt: %v"), err)
+		return
+	}
+	if len(vars) == 0 {
+		log.Printf(tr("[WARN] No se encontraron variables @GV en %s: no se comprueba el .SIG"), mwtPath)
+		return
+	}
+	var missing []string
+	for v := range vars {
+		if !rep.vars[v] {
+			missing = append(missing, v)
+		}
+	}
+	found := len(vars) - len(missing)
+	if float64(found) >= cfg.minRatio()*float64(len(vars)) {
+		return
+	}
+	sort.Strings(missing)
+	examples := missing[:min(len(missing), 5)]
+	s.add(FindingSigIncomplete, SeverityWarning, tr(".SIG con %d de las %d variables del .mwt (%.0f%%, mínimo %.0f%%): ¿SIGEXT incompleto? Faltan, p.ej.: %s"),
+		found, len(vars), 100*float64(found)/float64(len(vars)), 100*cfg.minRatio(), strings.Join(examples, ", "))
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- VERSIONES DEL FORMATO .SIG ---
+//
+// Las versiones de CWave cambian la cabecera y la forma de los atributos del
+// .SIG. Cada dialecto se registra por versión con su detector y su lector de
+// líneas SIG=; ParseSIG mira el principio del fichero, elige el dialecto y
+// apunta la versión en SigFormat.Version, que pasa al resultado y al
+// manifiesto. Un fichero con una versión declarada que no conocemos no se
+// lee a ciegas: es un error con las versiones soportadas.
+//
+//   - 1 (clásico): sin cabecera; SIG=@GV.<var> TYPE=<tipo> [DESC=...],
+//     separados por espacios y en ese orden.
+//   - 2: cabecera [HEADER] con VERSION=2 (o 2.x) antes de las señales;
+//     SIG=@GV.<var>;TYPE=<tipo>;DESC="...";... con los atributos separados
+//     por ';' en cualquier orden.
+
+// sigDialect lee las líneas SIG= de una versión del formato.
+type sigDialect struct {
+	Version string
+	// detect reconoce el dialecto sin versión declarada por la primera
+	// línea SIG= (nil si el fichero no tiene ninguna en lo leído).
+	detect func(first []byte) bool
+	// parse interpreta una línea que empieza por SIG=; ok = false si no
+	// tiene la forma del dialecto.
+	parse func(line []byte) (sig Signal, ok bool)
+}
+
+var sigDialects = map[string]sigDialect{}
+
+func registerSigDialect(d sigDialect) {
+	if _, dup := sigDialects[d.Version]; dup {
+		panic("dialecto .SIG duplicado: " + d.Version)
+	}
+	sigDialects[d.Version] = d
+}
+
+func init() {
+	registerSigDialect(sigDialect{Version: "1", parse: parseSigLineV1})
+	registerSigDialect(sigDialect{Version: "2", detect: func(first []byte) bool {
+		return first != nil && bytes.Contains(first, []byte(";TYPE="))
+	}, parse: parseSigLineV2})
+}
+
+// sigVersions devuelve las versiones registradas, ordenadas.
+func sigVersions() []string {
+	out := make([]string, 0, len(sigDialects))
+	for v := range sigDialects {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// sigVersionRe es la versión declarada en la cabecera: VERSION=2,
+// FORMAT_VERSION="2.1"...; cuenta la versión mayor.
+var sigVersionRe = regexp.MustCompile(`(?i)^(?:FORMAT_)?VERSION\s*=\s*["']?(\d+)(?:\.\d+)*`)
+
+// sniffSigDialect elige el dialecto por el principio del fichero (ya sin
+// BOM y con los finales de línea normalizados): la versión declarada en las
+// líneas anteriores a la primera SIG= o, sin versión, el primer detector
+// que reconoce esa línea; si ninguno, el clásico.
+func sniffSigDialect(head []byte) (sigDialect, error) {
+	var first []byte
+	declared := ""
+	for _, raw := range bytes.Split(head, []byte("\n")) {
+		line := bytes.TrimSpace(raw)
+		if bytes.HasPrefix(line, []byte("SIG=")) {
+			first = line
+			break
+		}
+		if m := sigVersionRe.FindSubmatch(line); m != nil && declared == "" {
+			declared = string(m[1])
+		}
+	}
+	if declared != "" {
+		d, ok := sigDialects[declared]
+		if !ok {
+			return sigDialect{}, fmt.Errorf(tr("versión de formato .SIG %s no soportada (soportadas: %s)"), declared, strings.Join(sigVersions(), ", "))
+		}
+		return d, nil
+	}
+	for _, v := range sigVersions() {
+		if d := sigDialects[v]; d.detect != nil && d.detect(first) {
+			return d, nil
+		}
+	}
+	return sigDialects["1"], nil
+}
+
+var sigLineRe = regexp.MustCompile(`SIG=@GV\.([\w\d_]+)\s+TYPE=([A-Z]+)`)
+
+// sigDescRe captura la descripción opcional de los .SIG recientes, entre
+// comillas dobles o simples, o una sola palabra sin comillas.
+var sigDescRe = regexp.MustCompile(`\bDESC(?:RIPTION)?=(?:"([^"]*)"|'([^']*)'|(\S+))`)
+
+func parseSigLineV1(line []byte) (Signal, bool) {
+	m := sigLineRe.FindSubmatch(line)
+	if m == nil {
+		return Signal{}, false
+	}
+	sig := Signal{Var: string(m[1]), Type: string(m[2])}
+	if d := sigDescRe.FindSubmatch(line[len(m[0]):]); d != nil {
+		sig.Desc = string(bytes.TrimSpace(bytes.Join(d[1:], nil)))
+	}
+	return sig, true
+}
+
+var sigVarRe = regexp.MustCompile(`^@GV\.([\w\d_]+)$`)
+
+func parseSigLineV2(line []byte) (Signal, bool) {
+	fields := splitSigAttrs(string(line[len("SIG="):]))
+	if len(fields) == 0 {
+		return Signal{}, false
+	}
+	m := sigVarRe.FindStringSubmatch(strings.TrimSpace(fields[0]))
+	if m == nil {
+		return Signal{}, false
+	}
+	sig := Signal{Var: m[1]}
+	for _, f := range fields[1:] {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "TYPE":
+			sig.Type = strings.ToUpper(value)
+		case "DESC", "DESCRIPTION":
+			sig.Desc = strings.TrimSpace(unquoteSigValue(value))
+		}
+	}
+	if sig.Type == "" || strings.Trim(sig.Type, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return Signal{}, false
+	}
+	return sig, true
+}
+
+// splitSigAttrs separa por ';' sin partir los valores entre comillas.
+func splitSigAttrs(s string) []string {
+	var out []string
+	var quote rune
+	start := 0
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ';':
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	if rest := s[start:]; strings.TrimSpace(rest) != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+func unquoteSigValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- EJECUCIÓN DE SIGEXT ---
+
+// SigExtRetry define los reintentos de SIGEXT. El fallo intermitente más
+// habitual es el .mwt bloqueado unos segundos por el IDE.
+type SigExtRetry struct {
+	Attempts     int `yaml:"attempts"`      // total de intentos; 0 o 1 = sin reintentos
+	InitialDelay int `yaml:"initial_delay"` // milisegundos antes del segundo intento
+	MaxDelay     int `yaml:"max_delay"`     // tope en milisegundos del retardo (se duplica en cada intento)
+}
+
+// SigExtWrapper ejecuta SIGEXT a través de otro programa, normalmente wine
+// en contenedores Linux. Con TranslatePaths las rutas del .mwt y del .SIG se
+// pasan al estilo Windows sobre la unidad Drive (wine mapea Z: a /).
+type SigExtWrapper struct {
+	Command        string `yaml:"command"` // p.ej. "wine" o "wine64"; vacío = sin envoltorio
+	TranslatePaths bool   `yaml:"translate_paths"`
+	Drive          string `yaml:"drive"` // por defecto Z:
+}
+
+// path traduce una ruta local absoluta al estilo Windows si procede.
+func (w SigExtWrapper) path(p string) string {
+	if !w.TranslatePaths || !strings.HasPrefix(p, "/") {
+		return p
+	}
+	drive := w.Drive
+	if drive == "" {
+		drive = "Z:"
+	}
+	return strings.TrimSuffix(drive, `\`) + strings.ReplaceAll(p, "/", `\`)
+}
+
+var errSigExtNotFound = errors.New("exe no encontrado")
+
+// defaultSigExtArgs es el orden de argumentos de las versiones de SIGEXT
+// conocidas.
+const defaultSigExtArgs = "{flags} {mwt} {node} {sig}"
+
+// sigExtArgs construye los argumentos de SIGEXT con la plantilla
+// app.sigext_args. Cada palabra de la plantilla es un argumento y admite
+// {flags}, {mwt}, {node} y {sig}; {flags} suelto se sustituye por las
+// palabras de sigext_flags (ninguna si está vacío).
+func sigExtArgs(tpl, flags, mwt, node, sig string) []string {
+	if strings.TrimSpace(tpl) == "" {
+		tpl = defaultSigExtArgs
+	}
+	vars := map[string]string{"flags": flags, "mwt": mwt, "node": node, "sig": sig}
+	var args []string
+	for _, word := range strings.Fields(tpl) {
+		if word == "{flags}" {
+			args = append(args, strings.Fields(flags)...)
+			continue
+		}
+		args = append(args, expandTemplate(word, vars))
+	}
+	return args
+}
+
+// runSigExtRetry ejecuta SIGEXT con reintentos y retardo exponencial.
+// Devuelve el intento que tuvo éxito (0 si ninguno) y el último error.
+// La salida de cada intento se añade a transcript.
+func runSigExtRetry(workDir, mwtPath, nodeName, sigPath string, transcript io.Writer) (int, error) {
+	cfg := GlobalConfig.App.SigExtRetry
+	attempts := max(cfg.Attempts, 1)
+	delay := time.Duration(cfg.InitialDelay) * time.Millisecond
+	if delay <= 0 {
+		delay = time.Second
+	}
+	maxDelay := time.Duration(cfg.MaxDelay) * time.Millisecond
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		fmt.Fprintf(transcript, "# %s\n", fmt.Sprintf(tr("intento %d/%d, %s"), attempt, attempts, formatStamp(time.Now())))
+		if remote := GlobalConfig.App.SigExtRemote; remote.enabled() {
+			err = runSigExtRemote(remote, GlobalConfig.App.SigExtFlags, mwtPath, nodeName, sigPath, transcript)
+		} else {
+			err = runSigExt(sigExtExe(), GlobalConfig.App.SigExtFlags, workDir, mwtPath, nodeName, sigPath, transcript)
+		}
+		if err != nil {
+			fmt.Fprintf(transcript, "= %v\n\n", err)
+		} else {
+			fmt.Fprint(transcript, "= OK\n\n")
+		}
+		if err == nil {
+			if attempts > 1 {
+				log.Printf(tr("SIGEXT correcto en el intento %d/%d"), attempt, attempts)
+			}
+			return attempt, nil
+		}
+		if errors.Is(err, errSigExtNotFound) || attempt == attempts {
+			break
+		}
+		log.Printf(tr("[WARN] SIGEXT intento %d/%d: %v (reintento en %s)"), attempt, attempts, err, delay)
+		time.Sleep(delay)
+		if delay *= 2; maxDelay > 0 && delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+	return 0, err
+}
+
+// Política ante un .SIG más antiguo que el .mwt (app.stale_sig).
+const (
+	StaleSigWarn   = "warn"
+	StaleSigError  = "error"
+	StaleSigIgnore = "ignore"
+)
+
+// checkStaleSig compara las fechas del .SIG y del .mwt cuando no se ha podido
+// regenerar el .SIG. Si el SIG es anterior, la extracción no refleja el
+// proyecto actual: según la política se avisa o se aborta. Con -skip-ext el
+// uso del SIG existente es deliberado y solo se avisa. Devuelve true si el
+// SIG está desactualizado.
+func checkStaleSig(sigPath, mwtPath string, skipped bool) (bool, error) {
+	policy := GlobalConfig.App.StaleSig
+	if policy == "" {
+		policy = StaleSigWarn
+	}
+	switch policy {
+	case StaleSigWarn, StaleSigError, StaleSigIgnore:
+	default:
+		return false, fmt.Errorf("stale_sig desconocido %q", policy)
+	}
+	if policy == StaleSigIgnore {
+		return false, nil
+	}
+	sig, err := os.Stat(sigPath)
+	if err != nil {
+		return false, nil
+	}
+	mwt, err := os.Stat(mwtPath)
+	if err != nil || !sig.ModTime().Before(mwt.ModTime()) {
+		return false, nil
+	}
+
+	msg := fmt.Sprintf(tr(".SIG desactualizado: %s (%s) es anterior a %s (%s)"),
+		sig.Name(), formatStamp(sig.ModTime()), mwt.Name(), formatStamp(mwt.ModTime()))
+	if policy == StaleSigError && !skipped {
+		return true, errors.New(msg)
+	}
+	log.Printf("[WARN] **************************************************")
+	log.Printf("[WARN] %s", msg)
+	log.Print(tr("[WARN] Las listas NO reflejan el proyecto actual"))
+	log.Printf("[WARN] **************************************************")
+	return true, nil
+}
+
+// writeSigExtLog guarda la transcripción de SIGEXT en <nodo>.sigext.log,
+// también cuando SIGEXT termina bien: si el .SIG sale mal sin que SIGEXT
+// falle, su salida es la única pista.
+func writeSigExtLog(outDir, node string, transcript []byte) (string, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(outDir, node+".sigext.log")
+	content := append([]byte("; "+generatorLine()+"\n"), transcript...)
+	return path, writeFileAtomic(path, content)
+}
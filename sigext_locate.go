@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// --- LOCALIZACIÓN DE SIGEXT ---
+//
+// Con sigext_path vacío (o apuntando a un fichero que no existe, típico de
+// un portátil recién instalado en otra unidad) se busca SIGEXT.exe en la
+// instalación de OpenBSI: primero las claves de registro del instalador y
+// después las rutas de instalación estándar.
+
+// defaultSigExtPath es la ruta de una instalación estándar de OpenBSI.
+const defaultSigExtPath = `C:\Program Files (x86)\Bristol\OpenBSI\SIGEXT.exe`
+
+const sigExtExeName = "SIGEXT.exe"
+
+var (
+	sigExtOnce     sync.Once
+	sigExtResolved string
+)
+
+// sigExtExe devuelve la ruta de SIGEXT que se va a ejecutar. La búsqueda se
+// hace una sola vez por proceso.
+func sigExtExe() string {
+	sigExtOnce.Do(func() { sigExtResolved = resolveSigExt(GlobalConfig.App.SigExtPath) })
+	return sigExtResolved
+}
+
+// resolveSigExt aplica la configuración y, si no sirve, la búsqueda. Con
+// envoltorio (wine) la ruta es la de Windows y se usa tal cual.
+func resolveSigExt(configured string) string {
+	if GlobalConfig.App.SigExtWrapper.Command != "" {
+		if configured == "" {
+			return defaultSigExtPath
+		}
+		return configured
+	}
+	if configured != "" && fileExists(configured) {
+		return configured
+	}
+	found := locateSigExt()
+	switch {
+	case found == "" && configured == "":
+		return defaultSigExtPath // runSigExt informa de que no existe
+	case found == "":
+		return configured
+	case configured != "":
+		log.Printf(tr("[WARN] No existe sigext_path %s; se usa %s"), configured, found)
+	default:
+		log.Printf(tr("SIGEXT encontrado en %s"), found)
+	}
+	return found
+}
+
+// locateSigExt busca SIGEXT.exe en los directorios de instalación conocidos;
+// vacío si no está en ninguno.
+func locateSigExt() string {
+	for _, dir := range append(registryInstallDirs(), standardInstallDirs()...) {
+		for _, p := range []string{filepath.Join(dir, sigExtExeName), filepath.Join(dir, "bin", sigExtExeName)} {
+			if fileExists(p) {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// standardInstallDirs son las carpetas donde suelen instalarse OpenBSI y
+// ControlWave Designer.
+func standardInstallDirs() []string {
+	var roots []string
+	for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles", "ProgramW6432"} {
+		if v := os.Getenv(env); v != "" {
+			roots = append(roots, v)
+		}
+	}
+	if len(roots) == 0 {
+		roots = []string{`C:\Program Files (x86)`, `C:\Program Files`}
+	}
+	var dirs []string
+	for _, root := range roots {
+		for _, sub := range []string{`Bristol\OpenBSI`, `Emerson\OpenBSI`, `OpenBSI`, `Bristol\ControlWave Designer`} {
+			dirs = append(dirs, filepath.Join(root, sub))
+		}
+	}
+	return append(dirs, `C:\OpenBSI`, `C:\Bristol\OpenBSI`)
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// registryInstallDirs no hace nada fuera de Windows: no hay registro.
+func registryInstallDirs() []string { return nil }
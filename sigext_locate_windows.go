@@ -0,0 +1,73 @@
+//go:build windows
+
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// sigExtRegistryKeys son las claves (bajo HKLM, en las vistas de 32 y 64
+// bits) donde el instalador de OpenBSI deja su carpeta.
+var sigExtRegistryKeys = []string{
+	`SOFTWARE\Bristol Babcock\OpenBSI`,
+	`SOFTWARE\Bristol\OpenBSI`,
+	`SOFTWARE\Emerson\OpenBSI`,
+}
+
+var sigExtRegistryValues = []string{"InstallDir", "InstallPath", "Path", "Directory"}
+
+// registryInstallDirs devuelve las carpetas de instalación de OpenBSI que
+// constan en el registro: las claves del producto y, si no, las entradas de
+// "Desinstalar programas" cuyo nombre menciona OpenBSI o ControlWave.
+func registryInstallDirs() []string {
+	var dirs []string
+	views := []uint32{registry.WOW64_32KEY, registry.WOW64_64KEY}
+	for _, view := range views {
+		for _, path := range sigExtRegistryKeys {
+			k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE|view)
+			if err != nil {
+				continue
+			}
+			for _, name := range sigExtRegistryValues {
+				if v, _, err := k.GetStringValue(name); err == nil && v != "" {
+					dirs = append(dirs, v)
+				}
+			}
+			k.Close()
+		}
+	}
+	for _, view := range views {
+		dirs = append(dirs, uninstallDirs(view)...)
+	}
+	return dirs
+}
+
+func uninstallDirs(view uint32) []string {
+	const uninstall = `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`
+	root, err := registry.OpenKey(registry.LOCAL_MACHINE, uninstall, registry.ENUMERATE_SUB_KEYS|view)
+	if err != nil {
+		return nil
+	}
+	defer root.Close()
+	names, err := root.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, name := range names {
+		k, err := registry.OpenKey(root, name, registry.QUERY_VALUE|view)
+		if err != nil {
+			continue
+		}
+		display, _, _ := k.GetStringValue("DisplayName")
+		location, _, _ := k.GetStringValue("InstallLocation")
+		k.Close()
+		lower := strings.ToLower(display)
+		if location != "" && (strings.Contains(lower, "openbsi") || strings.Contains(lower, "controlwave")) {
+			dirs = append(dirs, location)
+		}
+	}
+	return dirs
+}
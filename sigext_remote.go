@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// --- SIGEXT REMOTO (SSH) ---
+
+// SigExtRemote configura la ejecución de SIGEXT en otra máquina (la VM
+// Windows con licencia de OpenBSI) a través de OpenSSH: se sube el .mwt por
+// SFTP, se ejecuta SIGEXT allí y se descarga el .SIG resultante.
+type SigExtRemote struct {
+	Type       string `yaml:"type"` // "" (local) o "ssh"
+	Host       string `yaml:"host"` // host[:puerto], 22 por defecto
+	User       string `yaml:"user"`
+	Password   string `yaml:"password"`    // admite ${VAR} de entorno
+	KeyFile    string `yaml:"key_file"`    // clave privada (alternativa a password)
+	KnownHosts string `yaml:"known_hosts"` // obligatorio salvo insecure_ignore_host_key
+	WorkDir    string `yaml:"work_dir"`    // directorio remoto de trabajo
+	SigExtPath string `yaml:"sigext_path"` // vacío = app.sigext_path
+	TimeoutSec int    `yaml:"timeout_sec"`
+	// InsecureIgnoreHostKey acepta cualquier clave del host sin known_hosts:
+	// un intermediario recibiría la contraseña y el .mwt. Se avisa en cada
+	// conexión.
+	InsecureIgnoreHostKey bool `yaml:"insecure_ignore_host_key"`
+}
+
+func (r SigExtRemote) enabled() bool {
+	return r.Type != ""
+}
+
+// clientConfig prepara la conexión SSH; section nombra la configuración en
+// los errores y avisos.
+func (r SigExtRemote) clientConfig(section string) (*ssh.ClientConfig, error) {
+	var auth []ssh.AuthMethod
+	if r.KeyFile != "" {
+		key, err := os.ReadFile(r.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf(tr("clave ssh: %v"), err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf(tr("clave ssh: %v"), err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if r.Password != "" {
+		auth = append(auth, ssh.Password(os.ExpandEnv(r.Password)))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf(tr("%s: falta password o key_file"), section)
+	}
+
+	var hostKey ssh.HostKeyCallback
+	switch {
+	case r.KnownHosts != "":
+		cb, err := knownhosts.New(os.ExpandEnv(r.KnownHosts))
+		if err != nil {
+			return nil, fmt.Errorf("%s.known_hosts: %v", section, err)
+		}
+		hostKey = cb
+	case r.InsecureIgnoreHostKey:
+		log.Printf(tr("[WARN] %s: no se verifica la clave del host %s (insecure_ignore_host_key)"), section, r.Host)
+		hostKey = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, fmt.Errorf(tr("%s: falta known_hosts; sin él no se verifica la clave del host (use insecure_ignore_host_key: true para aceptarlo)"), section)
+	}
+	timeout := time.Duration(r.TimeoutSec) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return &ssh.ClientConfig{User: r.User, Auth: auth, HostKeyCallback: hostKey, Timeout: timeout}, nil
+}
+
+// runSigExtRemote hace lo mismo que runSigExt pero en la máquina remota.
+func runSigExtRemote(r SigExtRemote, flags, mwtPath, nodeName, sigPath string, transcript io.Writer) error {
+	if strings.ToLower(r.Type) != "ssh" {
+		return fmt.Errorf(tr("sigext_remote: tipo %q no soportado (solo ssh)"), r.Type)
+	}
+	if r.Host == "" || r.WorkDir == "" {
+		return errors.New(tr("sigext_remote: se requieren host y work_dir"))
+	}
+	exe := r.SigExtPath
+	if exe == "" {
+		exe = GlobalConfig.App.SigExtPath
+	}
+	if exe == "" {
+		exe = defaultSigExtPath
+	}
+	cfg, err := r.clientConfig("sigext_remote")
+	if err != nil {
+		return err
+	}
+	addr := r.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return fmt.Errorf("ssh %s: %v", addr, err)
+	}
+	defer client.Close()
+	fs, err := sftp.NewClient(client)
+	if err != nil {
+		return fmt.Errorf("sftp: %v", err)
+	}
+	defer fs.Close()
+
+	// SFTP usa '/' también contra OpenSSH de Windows (C:/dir/...).
+	workDir := strings.ReplaceAll(r.WorkDir, `\`, "/")
+	remoteMwt := path.Join(workDir, nodeName+".mwt")
+	remoteSig := path.Join(workDir, nodeName+".SIG")
+	if err := fs.MkdirAll(workDir); err != nil {
+		return fmt.Errorf("sftp mkdir %s: %v", workDir, err)
+	}
+
+	log.Printf(tr("Subiendo %s a %s:%s..."), nodeName+".mwt", r.Host, remoteMwt)
+	if err := sftpUpload(fs, mwtPath, remoteMwt); err != nil {
+		return err
+	}
+	fs.Remove(remoteSig)
+
+	win := func(p string) string { return strings.ReplaceAll(p, "/", `\`) }
+	args := sigExtArgs(GlobalConfig.App.SigExtArgs, flags, win(remoteMwt), nodeName, win(remoteSig))
+	for i, a := range args {
+		args[i] = cmdQuote(a)
+	}
+	cmd := fmt.Sprintf(`cd /d "%s" && "%s" %s`, win(workDir), exe, strings.Join(args, " "))
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("ssh session: %v", err)
+	}
+	var output bytes.Buffer
+	session.Stdout, session.Stderr = &output, &output
+	err = session.Run(cmd)
+	session.Close()
+	recordSigExt(transcript, r.User+"@"+r.Host+": "+cmd, output.Bytes(), err)
+	if err != nil {
+		return fmt.Errorf(tr("SIGEXT remoto: %v: %s"), err, strings.TrimSpace(output.String()))
+	}
+
+	log.Printf(tr("Descargando %s..."), nodeName+".SIG")
+	return sftpDownload(fs, remoteSig, sigPath)
+}
+
+func sftpUpload(fs *sftp.Client, local, remote string) error {
+	in, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := fs.Create(remote)
+	if err != nil {
+		return fmt.Errorf("sftp %s: %v", remote, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("sftp %s: %v", remote, err)
+	}
+	return out.Close()
+}
+
+// sftpDownload descarga a un temporal y lo renombra, para no dejar un .SIG a
+// medias si la conexión se corta.
+func sftpDownload(fs *sftp.Client, remote, local string) error {
+	in, err := fs.Open(remote)
+	if err != nil {
+		return fmt.Errorf("sftp %s: %v", remote, err)
+	}
+	defer in.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, in); err != nil {
+		return fmt.Errorf("sftp %s: %v", remote, err)
+	}
+	return writeFileAtomic(local, buf.Bytes())
+}
+
+// cmdQuote entrecomilla un argumento para cmd.exe si lleva espacios o
+// caracteres especiales.
+func cmdQuote(a string) string {
+	if a == "" || strings.ContainsAny(a, " \t&|<>^()") {
+		return `"` + a + `"`
+	}
+	return a
+}
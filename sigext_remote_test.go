@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSigExtRemoteRequiresHostKeyCheck(t *testing.T) {
+	base := SigExtRemote{Host: "vm:22", User: "u", Password: "p"}
+	if _, err := base.clientConfig("sigext_remote"); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+		t.Fatalf("sin known_hosts: %v", err)
+	}
+
+	insecure := base
+	insecure.InsecureIgnoreHostKey = true
+	if _, err := insecure.clientConfig("sigext_remote"); err != nil {
+		t.Fatalf("insecure_ignore_host_key: %v", err)
+	}
+
+	known := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(known, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checked := base
+	checked.KnownHosts = known
+	cfg, err := checked.clientConfig("sigext_remote")
+	if err != nil || cfg.HostKeyCallback == nil {
+		t.Fatalf("known_hosts: %v", err)
+	}
+}
+
+func TestPublishSFTPRequiresKnownHosts(t *testing.T) {
+	target := PublishTarget{Type: "sftp", Host: "127.0.0.1:1", User: "u", Password: "p"}
+	if err := publishSFTP(target, "/srv", nil); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+		t.Fatalf("sftp sin known_hosts: %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- FIRMA DE ARTEFACTOS ---
+//
+// La política de ciberseguridad exige poder demostrar el origen de los
+// ficheros de configuración que se descargan a una RTU. Con app.signing
+// cada generación firma su manifiesto (<nodo>.manifest.json, que ya lleva
+// el SHA-256 de cada fichero escrito) con una clave Ed25519 y escribe la
+// firma en <nodo>.manifest.json.sig. "dnpgen verify-artifacts" comprueba la
+// firma con la clave pública y los hashes de los ficheros antes de la
+// descarga.
+
+// SigningConfig controla la firma del manifiesto.
+type SigningConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PrivateKey es la clave Ed25519 (semilla de 32 bytes o clave de 64 en
+	// base64 o hex, o PEM PKCS#8 de openssl); admite ${VAR}. Mejor
+	// PrivateKeyFile o una variable de entorno que la clave en el YAML.
+	PrivateKey     string `yaml:"private_key"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	// PublicKey es la clave con la que verify-artifacts comprueba la firma
+	// (base64, hex o PEM).
+	PublicKey string `yaml:"public_key"`
+	KeyID     string `yaml:"key_id"` // identificador de la clave en el manifiesto
+}
+
+// manifestSigner identifica en el manifiesto la clave que lo firmó. Es
+// informativo: la verificación usa siempre la clave pública configurada.
+type manifestSigner struct {
+	KeyID     string `json:"key_id,omitempty"`
+	PublicKey string `json:"public_key"`
+}
+
+// signer devuelve la clave de firma y su identificación para el
+// manifiesto; nil si la firma está desactivada.
+func (c SigningConfig) signer() (ed25519.PrivateKey, *manifestSigner, error) {
+	if !c.Enabled {
+		return nil, nil, nil
+	}
+	key, err := c.signingKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	return key, &manifestSigner{KeyID: c.KeyID, PublicKey: base64.StdEncoding.EncodeToString(pub)}, nil
+}
+
+// signaturePath es el fichero de firma de un manifiesto.
+func signaturePath(manifest string) string {
+	return manifest + ".sig"
+}
+
+// signingKey carga la clave privada configurada.
+func (c SigningConfig) signingKey() (ed25519.PrivateKey, error) {
+	raw := os.ExpandEnv(c.PrivateKey)
+	if c.PrivateKeyFile != "" {
+		data, err := os.ReadFile(os.ExpandEnv(c.PrivateKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		raw = string(data)
+	}
+	if strings.TrimSpace(raw) == "" {
+		return nil, errors.New(tr("falta app.signing.private_key o private_key_file"))
+	}
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New(tr("la clave PEM no es Ed25519"))
+		}
+		return priv, nil
+	}
+	b, err := decodeKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	}
+	return nil, fmt.Errorf(tr("clave privada de %d bytes (se esperan %d o %d)"), len(b), ed25519.SeedSize, ed25519.PrivateKeySize)
+}
+
+// parsePublicKey interpreta una clave pública Ed25519 en base64, hex o PEM.
+func parsePublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New(tr("la clave PEM no es Ed25519"))
+		}
+		return pub, nil
+	}
+	b, err := decodeKey(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf(tr("clave pública de %d bytes (se esperan %d)"), len(b), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// signManifest firma el manifiesto ya escrito y devuelve la ruta de la firma.
+func signManifest(manifest string, key ed25519.PrivateKey) (string, error) {
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return "", err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	path := signaturePath(manifest)
+	return path, writeFileAtomic(path, []byte(sig+"\n"))
+}
+
+// Estados de un fichero en verify-artifacts.
+const (
+	ArtifactOK       = "ok"
+	ArtifactModified = "modified"
+	ArtifactMissing  = "missing"
+)
+
+// ArtifactCheck es el resultado de comprobar un fichero del manifiesto.
+type ArtifactCheck struct {
+	Path   string `json:"path"`
+	Found  string `json:"found,omitempty"` // ruta donde se encontró, si no es Path
+	Status string `json:"status"`
+}
+
+// ArtifactVerification es el resultado de verify-artifacts.
+type ArtifactVerification struct {
+	Manifest  string          `json:"manifest"`
+	Signed    bool            `json:"signed"`
+	Signature string          `json:"signature"` // valid, invalid, unchecked o missing
+	KeyID     string          `json:"key_id,omitempty"`
+	Files     []ArtifactCheck `json:"files"`
+	OK        bool            `json:"ok"`
+}
+
+// locateArtifact busca un fichero del manifiesto junto al manifiesto (la
+// copia publicada o recibida) y, si no está ahí, en su ruta original. Se
+// prefiere la copia: es la que se quiere verificar, y la ruta original puede
+// ser otro fichero que ya no corresponde al manifiesto.
+func locateArtifact(manifestDir, path string) string {
+	// El manifiesto puede venir de Windows: separar también por "\".
+	base := path[strings.LastIndexAny(path, `/\`)+1:]
+	if alt := filepath.Join(manifestDir, base); fileExists(alt) {
+		if a, err := os.Stat(alt); err == nil {
+			if p, err := os.Stat(path); err == nil && os.SameFile(a, p) {
+				return path // la copia es el propio original
+			}
+		}
+		return alt
+	}
+	if fileExists(path) {
+		return path
+	}
+	return ""
+}
+
+// verifyArtifacts comprueba la firma del manifiesto con pub (nil = no se
+// comprueba) y el hash de cada fichero que lista.
+func verifyArtifacts(manifestPath string, pub ed25519.PublicKey) (*ArtifactVerification, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var m runManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", manifestPath, err)
+	}
+	v := &ArtifactVerification{Manifest: manifestPath, Signature: "missing", OK: true}
+	if m.Signer != nil {
+		v.KeyID = m.Signer.KeyID
+	}
+	if sig, err := os.ReadFile(signaturePath(manifestPath)); err == nil {
+		v.Signed, v.Signature = true, "unchecked"
+		if pub != nil {
+			raw, err := decodeKey(string(sig))
+			if err == nil && ed25519.Verify(pub, data, raw) {
+				v.Signature = "valid"
+			} else {
+				v.Signature, v.OK = "invalid", false
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	dir := filepath.Dir(manifestPath)
+	for _, out := range m.Outputs {
+		c := ArtifactCheck{Path: out.Path, Status: ArtifactOK}
+		found := locateArtifact(dir, out.Path)
+		switch {
+		case found == "":
+			c.Status = ArtifactMissing
+		default:
+			if found != out.Path {
+				c.Found = found
+			}
+			content, err := os.ReadFile(found)
+			if err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256(content)
+			if !strings.EqualFold(hex.EncodeToString(sum[:]), out.SHA256) {
+				c.Status = ArtifactModified
+			}
+		}
+		if c.Status != ArtifactOK {
+			v.OK = false
+		}
+		v.Files = append(v.Files, c)
+	}
+	return v, nil
+}
+
+// runVerifyArtifacts implementa "dnpgen verify-artifacts".
+func runVerifyArtifacts(args []string) {
+	cfg := GlobalConfig.App.Signing
+	fs := flag.NewFlagSet("verify-artifacts", flag.ExitOnError)
+	fs.Usage = manUsage("verify-artifacts", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (usa su <nodo>.manifest.json)"))
+	manifestPath := fs.String("manifest", "", tr("Ruta directa a un manifiesto (alternativa a -path/-node)"))
+	publicKey := fs.String("public-key", cfg.PublicKey, tr("Clave pública Ed25519 (anula app.signing.public_key)"))
+	allowUnsigned := fs.Bool("allow-unsigned", false, tr("Aceptar manifiestos sin firma (solo se comprueban los hashes)"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	keygen := fs.Bool("keygen", false, tr("Generar un par de claves Ed25519 y salir"))
+	fs.Parse(args)
+
+	if *keygen {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		fmt.Printf("private_key: %s\n", base64.StdEncoding.EncodeToString(priv.Seed()))
+		fmt.Printf("public_key: %s\n", base64.StdEncoding.EncodeToString(pub))
+		return
+	}
+
+	path := *manifestPath
+	if path == "" {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe verify-artifacts -path \"C:\\Ruta\" -node \"NombreNodo\" | -manifest FICHERO [-public-key CLAVE]"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		path = filepath.Join(outputDirFor(abs, *nodeName, ""), *nodeName+".manifest.json")
+	}
+	var pub ed25519.PublicKey
+	if *publicKey != "" {
+		var err error
+		if pub, err = parsePublicKey(*publicKey); err != nil {
+			log.Fatalf("[FATAL] public_key: %v", err)
+		}
+	} else if !*allowUnsigned {
+		log.Fatal(tr("[FATAL] Falta la clave pública (-public-key o app.signing.public_key); use -allow-unsigned para comprobar solo los hashes"))
+	}
+
+	v, err := verifyArtifacts(path, pub)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if !v.Signed && !*allowUnsigned {
+		v.OK = false
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(v)
+	} else {
+		printArtifactVerification(v)
+	}
+	if !v.OK {
+		os.Exit(1)
+	}
+}
+
+func printArtifactVerification(v *ArtifactVerification) {
+	signature := map[string]string{
+		"valid":     okText(tr("válida")),
+		"invalid":   errText(tr("NO VÁLIDA")),
+		"unchecked": warnText(tr("sin comprobar")),
+		"missing":   warnText(tr("sin firma")),
+	}[v.Signature]
+	if v.KeyID != "" {
+		signature += " (" + v.KeyID + ")"
+	}
+	fmt.Printf(tr("Manifiesto: %s\nFirma: %s\n\n"), v.Manifest, signature)
+
+	var rows [][]string
+	for _, f := range v.Files {
+		where := f.Path
+		if f.Found != "" {
+			where = f.Found
+		}
+		rows = append(rows, []string{where, f.Status})
+	}
+	printTable(tr("FICHERO\tESTADO"), rows, func(i int) func(string) string {
+		if v.Files[i].Status != ArtifactOK {
+			return errText
+		}
+		return nil
+	})
+	if v.OK {
+		fmt.Println(okText(tr("\nArtefactos verificados")))
+	} else {
+		fmt.Println(errText(tr("\nLa verificación de los artefactos ha fallado")))
+	}
+}
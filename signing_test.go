@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateArtifactPrefersManifestCopy(t *testing.T) {
+	orig := filepath.Join(t.TempDir(), "RTU01.ini")
+	pub := t.TempDir()
+	copied := filepath.Join(pub, "RTU01.ini")
+	for _, p := range []string{orig, copied} {
+		if err := os.WriteFile(p, []byte(p), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := locateArtifact(pub, orig); got != copied {
+		t.Errorf("con copia junto al manifiesto: %q, se esperaba %q", got, copied)
+	}
+	if got := locateArtifact(filepath.Dir(orig), orig); got != orig {
+		t.Errorf("manifiesto junto al original: %q, se esperaba %q", got, orig)
+	}
+	if err := os.Remove(copied); err != nil {
+		t.Fatal(err)
+	}
+	if got := locateArtifact(pub, orig); got != orig {
+		t.Errorf("sin copia: %q, se esperaba %q", got, orig)
+	}
+	if got := locateArtifact(pub, filepath.Join(pub, "otro.ini")); got != "" {
+		t.Errorf("sin fichero: %q", got)
+	}
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- ORIGEN DEL .SIG ---
+//
+// La generación parte del .SIG del nodo y app.sig_source decide de dónde
+// sale. Hay dos orígenes incluidos: "sigext" (por defecto) ejecuta SIGEXT
+// sobre el .mwt, en local o por ssh y con reintentos, y "local" usa el .SIG
+// que ya hay en el recurso (lo mismo que -skip-ext). Los backends con nombre
+// de sig_source.backends traen el .SIG de otro sitio: http (un repositorio de
+// artefactos), archive (un .zip o .tar.gz de entrega) o command (un programa
+// propio que lo escribe). Cada tipo se registra con RegisterSigSourceType
+// desde init(), como los exportadores. El .SIG traído se escribe primero
+// junto al definitivo y solo lo sustituye si la descarga termina bien; si
+// falla, se sigue con el .SIG existente igual que cuando falla SIGEXT.
+
+// Orígenes incluidos.
+const (
+	SigSourceSigExt = "sigext"
+	SigSourceLocal  = "local"
+)
+
+// SigSourceConfig elige el origen del .SIG de cada nodo.
+type SigSourceConfig struct {
+	Backend  string                      `yaml:"backend"` // vacío = sigext
+	Nodes    map[string]string           `yaml:"nodes"`   // nodo → backend, sustituye a Backend
+	Backends map[string]SigBackendConfig `yaml:"backends"`
+}
+
+// SigBackendConfig es un backend con nombre. Las plantillas admiten {node},
+// {project} y {resource}; command además {sig} (donde escribir) y {mwt}.
+type SigBackendConfig struct {
+	Type string `yaml:"type"` // http, archive o command
+
+	// http: GET de URL; la respuesta 200 es el .SIG.
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"` // admiten ${VAR} de entorno (tokens)
+
+	// archive: Member (vacío = {node}.SIG, sin distinguir mayúsculas y en
+	// cualquier carpeta) dentro del .zip, .tar.gz o .tgz de Path.
+	Path   string `yaml:"path"`
+	Member string `yaml:"member"`
+
+	// command: Command con Args; tiene que dejar el .SIG en {sig}.
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+
+	TimeoutSec int `yaml:"timeout_sec"` // 60 por defecto
+}
+
+func (c SigBackendConfig) timeout() time.Duration {
+	if c.TimeoutSec > 0 {
+		return time.Duration(c.TimeoutSec) * time.Second
+	}
+	return 60 * time.Second
+}
+
+// SigFetch es lo que recibe un backend para traer el .SIG de un nodo.
+type SigFetch struct {
+	Project  string
+	Resource string
+	Mwt      string
+	Node     string
+	Sig      string // destino; el temporal junto al .SIG definitivo
+}
+
+func (f SigFetch) vars() map[string]string {
+	return map[string]string{"node": f.Node, "project": f.Project, "resource": f.Resource, "sig": f.Sig, "mwt": f.Mwt}
+}
+
+// SigSource trae el .SIG de un nodo a f.Sig. Lo que haga (peticiones,
+// comandos) se anota en transcript, que se guarda en <nodo>.sigext.log.
+type SigSource interface {
+	Fetch(f SigFetch, transcript io.Writer) error
+}
+
+var sigSourceTypes = map[string]func(SigBackendConfig) (SigSource, error){}
+
+// RegisterSigSourceType añade un tipo de backend. Registrar dos veces el
+// mismo tipo es un error de programación.
+func RegisterSigSourceType(name string, build func(SigBackendConfig) (SigSource, error)) {
+	name = strings.ToLower(name)
+	if _, dup := sigSourceTypes[name]; dup {
+		panic("tipo de origen del .SIG duplicado: " + name)
+	}
+	sigSourceTypes[name] = build
+}
+
+func init() {
+	RegisterSigSourceType("http", newHTTPSigSource)
+	RegisterSigSourceType("archive", newArchiveSigSource)
+	RegisterSigSourceType("command", newCommandSigSource)
+}
+
+// sigSourceName es el origen del .SIG de la petición: local con -skip-ext;
+// si no, el del nodo en sig_source.nodes o sig_source.backend.
+func sigSourceName(req GenerateRequest) string {
+	if req.SkipExt {
+		return SigSourceLocal
+	}
+	cfg := GlobalConfig.App.SigSource
+	if name, ok := cfg.Nodes[req.NodeName]; ok && name != "" {
+		return name
+	}
+	if cfg.Backend != "" {
+		return cfg.Backend
+	}
+	return SigSourceSigExt
+}
+
+// sigSourceFor construye el backend con nombre de sig_source.backends.
+func sigSourceFor(name string) (SigSource, error) {
+	cfg, ok := GlobalConfig.App.SigSource.Backends[name]
+	if !ok {
+		names := []string{SigSourceSigExt, SigSourceLocal}
+		for n := range GlobalConfig.App.SigSource.Backends {
+			names = append(names, n)
+		}
+		sort.Strings(names[2:])
+		return nil, fmt.Errorf(tr("sig_source: origen desconocido %q (disponibles: %s)"), name, strings.Join(names, ", "))
+	}
+	build, ok := sigSourceTypes[strings.ToLower(cfg.Type)]
+	if !ok {
+		types := make([]string, 0, len(sigSourceTypes))
+		for t := range sigSourceTypes {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		return nil, fmt.Errorf(tr("sig_source.backends.%s: tipo desconocido %q (disponibles: %s)"), name, cfg.Type, strings.Join(types, ", "))
+	}
+	src, err := build(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("sig_source.backends.%s: %v", name, err)
+	}
+	return src, nil
+}
+
+// fetchSig trae el .SIG con el backend a un temporal y, si llega entero,
+// sustituye el .SIG del nodo.
+func fetchSig(src SigSource, f SigFetch, transcript io.Writer) error {
+	final := f.Sig
+	f.Sig = final + ".part"
+	defer os.Remove(f.Sig)
+	fmt.Fprintf(transcript, "# %s\n", formatStamp(time.Now()))
+	if err := src.Fetch(f, transcript); err != nil {
+		fmt.Fprintf(transcript, "= %v\n\n", err)
+		return err
+	}
+	info, err := os.Stat(f.Sig)
+	if err == nil && info.Size() == 0 {
+		err = errors.New(tr("el .SIG recibido está vacío"))
+	}
+	if err == nil {
+		err = os.Rename(f.Sig, final)
+	}
+	if err != nil {
+		fmt.Fprintf(transcript, "= %v\n\n", err)
+		return err
+	}
+	fmt.Fprintf(transcript, "= OK (%d bytes)\n\n", info.Size())
+	return nil
+}
+
+// --- http ---
+
+type httpSigSource struct {
+	cfg    SigBackendConfig
+	client *http.Client
+}
+
+func newHTTPSigSource(cfg SigBackendConfig) (SigSource, error) {
+	if cfg.URL == "" {
+		return nil, errors.New(tr("falta url"))
+	}
+	return httpSigSource{cfg, &http.Client{Timeout: cfg.timeout()}}, nil
+}
+
+func (s httpSigSource) Fetch(f SigFetch, transcript io.Writer) error {
+	url := expandTemplate(s.cfg.URL, f.vars())
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	// Las cabeceras llevan el token: en la transcripción solo la URL.
+	fmt.Fprintf(transcript, "> GET %s\n", url)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.Sig, data, 0o644)
+}
+
+// --- archive ---
+
+type archiveSigSource struct{ cfg SigBackendConfig }
+
+func newArchiveSigSource(cfg SigBackendConfig) (SigSource, error) {
+	if cfg.Path == "" {
+		return nil, errors.New(tr("falta path"))
+	}
+	return archiveSigSource{cfg}, nil
+}
+
+func (s archiveSigSource) Fetch(f SigFetch, transcript io.Writer) error {
+	file := expandTemplate(s.cfg.Path, f.vars())
+	member := s.cfg.Member
+	if member == "" {
+		member = "{node}.SIG"
+	}
+	member = expandTemplate(member, f.vars())
+	fmt.Fprintf(transcript, "> %s: %s\n", file, member)
+
+	var data []byte
+	var err error
+	lower := strings.ToLower(file)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		data, err = tarGzMember(file, member)
+	} else {
+		data, err = zipMember(file, member)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.Sig, data, 0o644)
+}
+
+// memberMatches compara un nombre del paquete con member: la ruta completa o,
+// si member no lleva carpeta, el nombre del fichero; sin distinguir
+// mayúsculas.
+func memberMatches(name, member string) bool {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.EqualFold(name, member) {
+		return true
+	}
+	return !strings.ContainsAny(member, `/\`) && strings.EqualFold(path.Base(name), member)
+}
+
+func zipMember(file, member string) ([]byte, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	for _, zf := range r.File {
+		if zf.FileInfo().IsDir() || !memberMatches(zf.Name, member) {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf(tr("%s no contiene %s"), file, member)
+}
+
+func tarGzMember(file, member string) ([]byte, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	gz, err := gzip.NewReader(fh)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	r := tar.NewReader(gz)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if h.Typeflag == tar.TypeReg && memberMatches(h.Name, member) {
+			return io.ReadAll(r)
+		}
+	}
+	return nil, fmt.Errorf(tr("%s no contiene %s"), file, member)
+}
+
+// --- command ---
+
+type commandSigSource struct{ cfg SigBackendConfig }
+
+func newCommandSigSource(cfg SigBackendConfig) (SigSource, error) {
+	if cfg.Command == "" {
+		return nil, errors.New(tr("falta command"))
+	}
+	return commandSigSource{cfg}, nil
+}
+
+func (s commandSigSource) Fetch(f SigFetch, transcript io.Writer) error {
+	vars := f.vars()
+	args := make([]string, len(s.cfg.Args))
+	for i, a := range s.cfg.Args {
+		args[i] = expandTemplate(a, vars)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.timeout())
+	defer cancel()
+	cmd := exec.CommandContext(ctx, expandTemplate(s.cfg.Command, vars), args...)
+	cmd.Dir = f.Resource
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	err := cmd.Run()
+	fmt.Fprintf(transcript, "> %s\n", cmd.String())
+	transcript.Write(output.Bytes())
+	if output.Len() > 0 && !bytes.HasSuffix(output.Bytes(), []byte("\n")) {
+		fmt.Fprintln(transcript)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf(tr("%s: sin terminar tras %s"), s.cfg.Command, s.cfg.timeout())
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(f.Sig); err != nil {
+		return fmt.Errorf(tr("%s terminó sin escribir {sig}"), s.cfg.Command)
+	}
+	return nil
+}
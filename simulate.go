@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"path/filepath"
+)
+
+// --- SIMULADOR DE OUTSTATION DNP3 ---
+
+// simPointDB es la base de puntos del simulador, con un punto por línea de
+// cada lista (los spares incluidos, para respetar los índices).
+type simPointDB struct {
+	bi, bo, ai, ao int
+	os, osLen      int // cadenas de octetos (g110, la variación es la longitud)
+}
+
+func runSimulate(args []string) {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	fs.Usage = manUsage("simulate", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (usa su __lists.ini)"))
+	listsPath := fs.String("lists", "", tr("Ruta directa a un __lists.ini (alternativa a -path/-node)"))
+	addr := fs.String("addr", "0.0.0.0:20000", tr("Dirección TCP de escucha"))
+	address := fs.Uint("address", 10, tr("Dirección DNP3 de la outstation"))
+	fs.Parse(args)
+
+	file := *listsPath
+	if file == "" {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe simulate -path \"C:\\Ruta\" -node \"NombreNodo\" [-addr 0.0.0.0:20000]"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		file = listsPathFor(abs, *nodeName)
+	}
+
+	lists, err := readListsFile(file)
+	if err != nil {
+		log.Fatalf(tr("[FATAL] Error leyendo %s: %v"), file, err)
+	}
+	db := simPointDB{bi: len(lists.DI), bo: len(lists.DO), ai: len(lists.AI), ao: len(lists.AO),
+		os: len(lists.OS), osLen: GlobalConfig.App.Classification.Strings.length()}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	log.Printf(tr("Outstation %d escuchando en %s (DI: %d | DO: %d | AI: %d | AO: %d)"), *address, *addr, db.bi, db.bo, db.ai, db.ao)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Printf(tr("[ERROR] accept: %v"), err)
+			continue
+		}
+		go func() {
+			defer conn.Close()
+			log.Printf(tr("Master conectado desde %s"), conn.RemoteAddr())
+			err := serveOutstation(newDNPConn(conn, uint16(*address), 0, false), db)
+			log.Printf(tr("Master %s desconectado: %v"), conn.RemoteAddr(), err)
+		}()
+	}
+}
+
+func serveOutstation(c *dnpConn, db simPointDB) error {
+	iin1 := byte(iin1DeviceRestart)
+	for {
+		frag, err := c.readFragment()
+		if err != nil {
+			return err
+		}
+		seq, fc := frag[0]&0x0F, frag[1]
+		objs, parseErr := parseObjects(frag[2:])
+
+		var iin2 byte
+		var body []byte
+		switch fc {
+		case fcConfirm:
+			continue
+		case fcRead:
+			if parseErr != nil {
+				iin2 |= iin2ObjectUnknown
+				break
+			}
+			frags := db.readResponse(objs)
+			if err := c.sendResponses(seq, iin1, frags); err != nil {
+				return err
+			}
+			continue
+		case fcWrite:
+			// La escritura habitual es g80v1 índice 7 = 0 (borrar DEVICE_RESTART).
+			iin1 &^= iin1DeviceRestart
+		case fcSelect, fcOperate, fcDirectOperate, fcDirectOperateNR:
+			if parseErr != nil {
+				iin2 |= iin2ObjectUnknown
+				break
+			}
+			logControls(fc, objs)
+			if fc == fcDirectOperateNR {
+				continue
+			}
+			body = frag[2:] // eco de los comandos con estado 0 (éxito)
+		case fcEnableUnsol, fcDisableUnsol:
+		case fcDelayMeasure:
+			body = []byte{52, 2, 0x07, 1, 0, 0}
+		default:
+			iin2 |= iin2NoFuncSupport
+		}
+		resp := append([]byte{appFir | appFin | seq, fcResponse, iin1, iin2}, body...)
+		if err := c.sendFragment(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// readResponse construye los objetos estáticos pedidos (clase 0 o lectura
+// por grupo) repartidos en fragmentos de tamaño máximo maxAppFragment.
+func (db simPointDB) readResponse(objs []objectHeader) [][]byte {
+	want := map[byte]bool{}
+	for _, o := range objs {
+		if o.Group == 60 && o.Variation == 1 {
+			want[1], want[10], want[30], want[40], want[110] = true, true, true, true, true
+		} else if o.Qualifier == 0x06 {
+			want[o.Group] = true
+		}
+	}
+
+	type block struct {
+		group, variation byte
+		count, size      int
+		point            []byte
+	}
+	blocks := []block{
+		{1, 2, db.bi, 1, []byte{0x01}},
+		{10, 2, db.bo, 1, []byte{0x01}},
+		{30, 1, db.ai, 5, []byte{0x01, 0, 0, 0, 0}},
+		{40, 1, db.ao, 5, []byte{0x01, 0, 0, 0, 0}},
+		{110, byte(db.osLen), db.os, db.osLen, make([]byte, db.osLen)},
+	}
+
+	const headerLen = 4 + 7 // cabecera de respuesta + cabecera de objeto
+	var frags [][]byte
+	cur := []byte{}
+	for _, b := range blocks {
+		if !want[b.group] || b.count == 0 {
+			continue
+		}
+		for start := 0; start < b.count; {
+			room := (maxAppFragment - headerLen - len(cur)) / b.size
+			if room <= 0 {
+				frags = append(frags, cur)
+				cur = []byte{}
+				continue
+			}
+			stop := min(start+room, b.count) - 1
+			cur = appendRangeHeader(cur, b.group, b.variation, start, stop)
+			for i := start; i <= stop; i++ {
+				cur = append(cur, b.point...)
+			}
+			start = stop + 1
+		}
+	}
+	return append(frags, cur)
+}
+
+// sendResponses envía una respuesta multi-fragmento, esperando la confirmación
+// del master entre fragmentos.
+func (c *dnpConn) sendResponses(seq, iin1 byte, frags [][]byte) error {
+	for i, objs := range frags {
+		ac := seq & 0x0F
+		if i == 0 {
+			ac |= appFir
+		}
+		if i == len(frags)-1 {
+			ac |= appFin
+		} else {
+			ac |= appCon
+		}
+		if err := c.sendFragment(append([]byte{ac, fcResponse, iin1, 0}, objs...)); err != nil {
+			return err
+		}
+		if i < len(frags)-1 {
+			confirm, err := c.readFragment()
+			if err != nil {
+				return err
+			}
+			if confirm[1] != fcConfirm {
+				return fmt.Errorf(tr("se esperaba CONFIRM, recibido FC 0x%02X"), confirm[1])
+			}
+		}
+		seq = (seq + 1) & 0x0F
+	}
+	return nil
+}
+
+func logControls(fc byte, objs []objectHeader) {
+	for _, o := range objs {
+		size := staticObjectSize(o.Group, o.Variation)
+		prefix := 0
+		switch o.Qualifier {
+		case 0x17:
+			prefix = 1
+		case 0x28:
+			prefix = 2
+		}
+		for i := 0; i < o.Count && size > 0; i++ {
+			item := o.Data[i*(size+prefix):]
+			index := o.Start + i
+			if prefix > 0 {
+				index = readUint(item[:prefix])
+			}
+			item = item[prefix:]
+			switch o.Group {
+			case 12:
+				log.Printf(tr("[CONTROL] FC 0x%02X CROB índice %d código 0x%02X"), fc, index, item[0])
+			case 41:
+				var value any
+				switch o.Variation {
+				case 1:
+					value = int32(binary.LittleEndian.Uint32(item))
+				case 2:
+					value = int16(binary.LittleEndian.Uint16(item))
+				default:
+					value = fmt.Sprintf("% X", item[:size-1])
+				}
+				log.Printf(tr("[CONTROL] FC 0x%02X salida analógica índice %d valor %v"), fc, index, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// --- PUNTOS SOE (SECUENCIA DE EVENTOS) ---
+
+// SOEConfig marca como SOE las entradas digitales cuyo nombre cumple alguno
+// de los patrones. El master configura su buffer de eventos de otra forma
+// que para el resto de entradas binarias.
+type SOEConfig struct {
+	Patterns   []string `yaml:"patterns"`
+	EventClass int      `yaml:"event_class"` // clase de evento DNP3 (por defecto 1)
+	// List, si no está vacío, es una lista de extra_lists donde se copian
+	// además los puntos SOE.
+	List string `yaml:"list"`
+}
+
+func (c SOEConfig) eventClass() int {
+	if c.EventClass == 0 {
+		return 1
+	}
+	return c.EventClass
+}
+
+// markSOE marca los puntos SOE de la lista DI y los copia a la lista
+// dedicada si está configurada. Devuelve cuántos se marcaron.
+func markSOE(l *Lists, cfg SOEConfig) (int, error) {
+	if len(cfg.Patterns) == 0 {
+		return 0, nil
+	}
+	rules, err := compileRules(cfg.Patterns, false)
+	if err != nil {
+		return 0, fmt.Errorf("soe: %v", err)
+	}
+	var dedicated *CustomList
+	if cfg.List != "" {
+		for i := range l.Extra {
+			if l.Extra[i].Name == strings.ToUpper(cfg.List) {
+				dedicated = &l.Extra[i]
+			}
+		}
+		if dedicated == nil {
+			return 0, fmt.Errorf("soe: la lista %q no está en extra_lists", cfg.List)
+		}
+	}
+
+	n := 0
+	for i := range l.DI {
+		p := &l.DI[i]
+		if p.Spare || !rules.Any(p.Var) {
+			continue
+		}
+		p.SOE = true
+		n++
+		if dedicated != nil {
+			dedicated.Items = append(dedicated.Items, *p)
+		}
+	}
+	return n, nil
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"soe", ".soe.csv", writeSOECSV})
+}
+
+// writeSOECSV lista los puntos SOE con su índice DI y la clase de evento,
+// para configurar el buffer de eventos del master.
+func writeSOECSV(out io.Writer, ctx ExportContext) error {
+	class := strconv.Itoa(GlobalConfig.App.Classification.SOE.eventClass())
+	w := csv.NewWriter(out)
+	w.Write([]string{"node", "index", "name", "var", "event_class"})
+	for idx, p := range ctx.Lists.DI {
+		if p.SOE {
+			w.Write([]string{ctx.Node, strconv.Itoa(idx), p.Name, p.Var, class})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
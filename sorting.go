@@ -0,0 +1,393 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- ORDEN DE LAS LISTAS ---
+//
+// Por defecto cada lista sigue el orden del .SIG. app.sorting da a una lista
+// su propio orden: primero por grupo (group, con el orden de groups y los
+// demás grupos después, alfabéticamente; sin grupo al final) y dentro del
+// grupo por las claves de by; el empate se resuelve por el orden del .SIG.
+// Las claves son expresiones cortas: un campo y, detrás de '|', funciones
+// que lo transforman; un '-' delante invierte el orden.
+//
+//	var | name | desc | type | area | line     campos del punto
+//	prefix[:SEP]   texto hasta el primer SEP ("_" por defecto)
+//	suffix[:SEP]   texto tras el último SEP
+//	upper, lower   mayúsculas / minúsculas
+//	natural        compara los números por su valor (P2 antes que P10)
+//	re:PATRÓN      primer grupo (o la coincidencia) de la regex; la última
+//
+// p.ej. group: "area|prefix:_" y by: ["var|natural"]. Solo se reordenan los
+// puntos del .SIG, en los índices que ocupan: entradas fijas, reservas y
+// puntos generados no se mueven. En las listas espejo (DI/DO, AI/AO) se
+// ordenan las filas, punto y espejo juntos, con la entrada de cualquiera de
+// las dos. Con app.lifecycle los puntos del mapa de índices conservan su
+// orden y solo los nuevos se ordenan, detrás; "dnpgen migrate" reordena la
+// lista completa.
+
+// ListSort es el orden de una lista.
+type ListSort struct {
+	Group  string   `yaml:"group" json:"group,omitempty"`
+	Groups []string `yaml:"groups" json:"groups,omitempty"` // orden explícito de los grupos
+	By     []string `yaml:"by" json:"by,omitempty"`
+}
+
+// sortExpr es una clave de orden compilada.
+type sortExpr struct {
+	field   string
+	steps   []func(string) string
+	desc    bool
+	natural bool
+}
+
+var sortFields = []string{"var", "name", "desc", "type", "area", "line"}
+
+func compileSortExpr(expr string) (*sortExpr, error) {
+	s := strings.TrimSpace(expr)
+	e := &sortExpr{}
+	if strings.HasPrefix(s, "-") {
+		e.desc, s = true, strings.TrimSpace(s[1:])
+	}
+	parts := strings.Split(s, "|")
+	e.field = strings.ToLower(strings.TrimSpace(parts[0]))
+	if !containsFold(sortFields, e.field) {
+		return nil, fmt.Errorf(tr("%q: campo desconocido %q (disponibles: %s)"), expr, parts[0], strings.Join(sortFields, ", "))
+	}
+	e.natural = e.field == "line"
+	for i := 1; i < len(parts); i++ {
+		name, arg, _ := strings.Cut(strings.TrimSpace(parts[i]), ":")
+		switch strings.ToLower(name) {
+		case "prefix", "suffix":
+			sep := arg
+			if sep == "" {
+				sep = "_"
+			}
+			if strings.EqualFold(name, "prefix") {
+				e.steps = append(e.steps, func(v string) string { before, _, _ := strings.Cut(v, sep); return before })
+			} else {
+				e.steps = append(e.steps, func(v string) string {
+					if i := strings.LastIndex(v, sep); i >= 0 {
+						return v[i+len(sep):]
+					}
+					return v
+				})
+			}
+		case "upper":
+			e.steps = append(e.steps, strings.ToUpper)
+		case "lower":
+			e.steps = append(e.steps, strings.ToLower)
+		case "natural":
+			e.natural = true
+		case "re":
+			// El patrón puede llevar '|': es el resto de la expresión.
+			_, pattern, _ := strings.Cut(strings.Join(parts[i:], "|"), ":")
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", expr, err)
+			}
+			e.steps = append(e.steps, func(v string) string {
+				m := re.FindStringSubmatch(v)
+				switch {
+				case m == nil:
+					return ""
+				case len(m) > 1:
+					return m[1]
+				}
+				return m[0]
+			})
+			i = len(parts)
+		default:
+			return nil, fmt.Errorf(tr("%q: función desconocida %q"), expr, name)
+		}
+	}
+	return e, nil
+}
+
+func (e *sortExpr) eval(p Point, area func(string) string) string {
+	var v string
+	switch e.field {
+	case "var":
+		v = p.Var
+	case "name":
+		v = p.Name
+	case "desc":
+		v = p.Desc
+	case "type":
+		v = p.Type
+	case "area":
+		v = area(p.Var)
+	case "line":
+		v = strconv.Itoa(p.line)
+	}
+	for _, f := range e.steps {
+		v = f(v)
+	}
+	return v
+}
+
+// compare devuelve <0, 0 o >0 según el orden de a y b.
+func (e *sortExpr) compare(a, b string) int {
+	c := strings.Compare(a, b)
+	if e.natural {
+		c = naturalCompare(a, b)
+	}
+	if e.desc {
+		return -c
+	}
+	return c
+}
+
+// naturalCompare compara los tramos de dígitos por su valor.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		da, db := digitRun(a), digitRun(b)
+		if da > 0 && db > 0 {
+			na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = a[da:], b[db:]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func digitRun(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// listSorter es un ListSort compilado.
+type listSorter struct {
+	group  *sortExpr
+	groups map[string]int
+	by     []*sortExpr
+	area   func(string) string
+}
+
+func compileListSort(list string, cfg ListSort, area func(string) string) (*listSorter, error) {
+	s := &listSorter{groups: map[string]int{}, area: area}
+	var err error
+	if cfg.Group != "" {
+		if s.group, err = compileSortExpr(cfg.Group); err != nil {
+			return nil, fmt.Errorf("sorting.%s.group: %v", list, err)
+		}
+	} else if len(cfg.Groups) > 0 {
+		return nil, fmt.Errorf(tr("sorting.%s: groups sin group"), list)
+	}
+	for i, g := range cfg.Groups {
+		s.groups[strings.ToUpper(g)] = i
+	}
+	for i, expr := range cfg.By {
+		e, err := compileSortExpr(expr)
+		if err != nil {
+			return nil, fmt.Errorf("sorting.%s.by[%d]: %v", list, i, err)
+		}
+		s.by = append(s.by, e)
+	}
+	return s, nil
+}
+
+// groupRank ordena los grupos: los de groups en su orden, después el resto
+// y al final los puntos sin grupo.
+func (s *listSorter) groupRank(g string) int {
+	if i, ok := s.groups[strings.ToUpper(g)]; ok {
+		return i
+	}
+	if g == "" {
+		return len(s.groups) + 1
+	}
+	return len(s.groups)
+}
+
+func (s *listSorter) less(a, b Point) bool {
+	if s.group != nil {
+		ga, gb := s.group.eval(a, s.area), s.group.eval(b, s.area)
+		if ra, rb := s.groupRank(ga), s.groupRank(gb); ra != rb {
+			return ra < rb
+		}
+		if c := s.group.compare(ga, gb); c != 0 {
+			return c < 0
+		}
+	}
+	for _, e := range s.by {
+		if c := e.compare(e.eval(a, s.area), e.eval(b, s.area)); c != 0 {
+			return c < 0
+		}
+	}
+	return a.line < b.line
+}
+
+// sortRow es una fila de una lista, o de dos espejo: el punto que da las
+// claves y su índice en el mapa anterior.
+type sortRow struct {
+	key  Point
+	prev int // índice en el mapa anterior; -1 si es nueva
+}
+
+// mirroredRows indica si a y b están alineadas índice a índice: cada fila
+// procede de la misma línea del .SIG en las dos listas.
+func mirroredRows(a, b []Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].line != b[i].line {
+			return false
+		}
+	}
+	return true
+}
+
+// sortLists aplica app.sorting a las listas recién clasificadas, antes de
+// las reservas. prev es el mapa de índices (nil sin app.lifecycle o al
+// migrar): sus puntos conservan el orden.
+func sortLists(l *Lists, cfg map[string]ListSort, prev *lifecycleMap) error {
+	if len(cfg) == 0 {
+		return nil
+	}
+	area := func(string) string { return "" }
+	if GlobalConfig.App.Areas.enabled() {
+		t, err := compileAreas(GlobalConfig.App.Areas)
+		if err != nil {
+			return err
+		}
+		area = t.area
+	}
+	lists := map[string]*[]Point{"AI": &l.AI, "AO": &l.AO, "DI": &l.DI, "DO": &l.DO, "OS": &l.OS}
+	for i := range l.Extra {
+		lists[l.Extra[i].Name] = &l.Extra[i].Items
+	}
+	sorters := map[string]*listSorter{}
+	configs := map[string]ListSort{}
+	var names []string
+	for name, c := range cfg {
+		list := strings.ToUpper(name)
+		if _, ok := lists[list]; !ok {
+			return fmt.Errorf(tr("sorting: lista desconocida %q"), name)
+		}
+		s, err := compileListSort(list, c, area)
+		if err != nil {
+			return err
+		}
+		sorters[list], configs[list] = s, c
+		names = append(names, list)
+	}
+	sort.Strings(names)
+
+	previous := map[string]int{}
+	if prev != nil {
+		for _, p := range prev.Points {
+			if p.State == LifecycleActive {
+				previous[lifecycleKey(p.List, p.Var, p.Spare)] = p.Index
+			}
+		}
+	}
+	prevIndex := func(list string, p Point) int {
+		if i, ok := previous[lifecycleKey(list, p.Var, p.Spare)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	done := map[string]bool{}
+	for _, pair := range [][2]string{{"AI", "AO"}, {"DI", "DO"}} {
+		a, b := pair[0], pair[1]
+		sa, sb := sorters[a], sorters[b]
+		if (sa == nil && sb == nil) || !mirroredRows(*lists[a], *lists[b]) {
+			continue
+		}
+		if sa != nil && sb != nil && !reflect.DeepEqual(configs[a], configs[b]) {
+			return fmt.Errorf(tr("sorting: %s y %s son listas espejo y se ordenan juntas: configure solo una (o la misma en las dos)"), a, b)
+		}
+		s := sa
+		if s == nil {
+			s = sb
+		}
+		// La clave de la fila es el punto real; si las dos entradas lo son
+		// (mirror), la de la primera lista.
+		rows := make([]sortRow, len(*lists[a]))
+		for i := range rows {
+			pa, pb := (*lists[a])[i], (*lists[b])[i]
+			rows[i] = sortRow{key: pa, prev: prevIndex(a, pa)}
+			if pa.Spare && !pb.Spare {
+				rows[i] = sortRow{key: pb, prev: prevIndex(b, pb)}
+			}
+		}
+		perm := sortPermutation(rows, s, a+"/"+b)
+		applyPermutation(lists[a], perm)
+		applyPermutation(lists[b], perm)
+		done[a], done[b] = true, true
+	}
+	for _, list := range names {
+		if done[list] {
+			continue
+		}
+		items := lists[list]
+		rows := make([]sortRow, len(*items))
+		for i, p := range *items {
+			rows[i] = sortRow{key: p, prev: prevIndex(list, p)}
+		}
+		applyPermutation(items, sortPermutation(rows, sorters[list], list))
+	}
+	return nil
+}
+
+// sortPermutation devuelve, para cada índice, el índice de origen. Solo se
+// mueven las filas del .SIG, entre los índices que ya ocupan; las del mapa
+// anterior van primero y en su orden.
+func sortPermutation(rows []sortRow, s *listSorter, label string) []int {
+	var slots, old, fresh []int
+	for i, r := range rows {
+		if r.key.line == 0 {
+			continue
+		}
+		slots = append(slots, i)
+		if r.prev >= 0 {
+			old = append(old, i)
+		} else {
+			fresh = append(fresh, i)
+		}
+	}
+	sort.SliceStable(old, func(i, j int) bool { return rows[old[i]].prev < rows[old[j]].prev })
+	sort.SliceStable(fresh, func(i, j int) bool { return s.less(rows[fresh[i]].key, rows[fresh[j]].key) })
+	if len(old) > 0 && len(fresh) > 0 {
+		log.Printf(tr("Orden de %s: %d punto(s) nuevo(s) ordenados detrás de los %d existentes para no mover sus índices"), label, len(fresh), len(old))
+	}
+	perm := make([]int, len(rows))
+	for i := range perm {
+		perm[i] = i
+	}
+	for i, src := range append(old, fresh...) {
+		perm[slots[i]] = src
+	}
+	return perm
+}
+
+func applyPermutation(items *[]Point, perm []int) {
+	out := make([]Point, len(perm))
+	for i, src := range perm {
+		out[i] = (*items)[src]
+	}
+	*items = out
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileSortExprEval(t *testing.T) {
+	p := Point{Var: "ST1_P10_RUN", Name: "@GV.ST1_P10_RUN", Desc: "", Type: "LA", line: 42}
+	area := func(v string) string { return "AREA-" + v[:3] }
+	for _, c := range []struct {
+		expr, want string
+		desc       bool
+		natural    bool
+	}{
+		{"var", "ST1_P10_RUN", false, false},
+		{" name ", "@GV.ST1_P10_RUN", false, false},
+		{"type|lower", "la", false, false},
+		{"line", "42", false, true},
+		{"area", "AREA-ST1", false, false},
+		{"var|prefix", "ST1", false, false},
+		{"var|prefix:--", "ST1_P10_RUN", false, false},
+		{"var|suffix", "RUN", false, false},
+		{"var|suffix:_P", "10_RUN", false, false},
+		{"var|suffix:--", "ST1_P10_RUN", false, false},
+		{"desc|suffix", "", false, false},
+		{"desc|suffix:--", "", false, false},
+		{"desc|prefix", "", false, false},
+		{"var|suffix|lower", "run", false, false},
+		{"-var|natural", "ST1_P10_RUN", true, true},
+		{"var|re:P([0-9]+)", "10", false, false},
+		{"var|re:RUN|STOP", "RUN", false, false},
+		{"var|re:X([0-9]+)", "", false, false},
+		{"var|upper|re:st", "", false, false},
+	} {
+		e, err := compileSortExpr(c.expr)
+		if err != nil {
+			t.Errorf("%q: %v", c.expr, err)
+			continue
+		}
+		if got := e.eval(p, area); got != c.want || e.desc != c.desc || e.natural != c.natural {
+			t.Errorf("%q = %q (desc %v, natural %v), se esperaba %q (desc %v, natural %v)", c.expr, got, e.desc, e.natural, c.want, c.desc, c.natural)
+		}
+	}
+}
+
+func TestCompileSortExprErrors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"":              "campo desconocido",
+		"valor":         "campo desconocido",
+		"var|reverse":   "función desconocida",
+		"var|re:([0-9]": "missing closing )",
+	} {
+		if _, err := compileSortExpr(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error %v, se esperaba %q", expr, err, want)
+		}
+	}
+}
+
+func TestSortExprCompare(t *testing.T) {
+	natural, _ := compileSortExpr("var|natural")
+	plain, _ := compileSortExpr("var")
+	desc, _ := compileSortExpr("-var|natural")
+	if natural.compare("P2", "P10") >= 0 || plain.compare("P2", "P10") <= 0 || desc.compare("P2", "P10") <= 0 {
+		t.Error("natural debe poner P2 antes que P10, el orden de texto al revés y -natural invertirlo")
+	}
+	if natural.compare("P007", "P7") != 0 {
+		t.Error("los ceros a la izquierda no cuentan en natural")
+	}
+}
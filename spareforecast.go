@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// --- CONSUMO DE SPARES Y PREVISIÓN DE AGOTAMIENTO ---
+//
+// Con app.spare_forecast cada generación añade a <salida>/.dnpgen/<nodo>.
+// spares.jsonl el tamaño, los puntos reales y los spares de cada lista. La
+// previsión ajusta una recta al tamaño de cada lista en los últimos
+// window_days y calcula cuándo alcanzará su capacidad (findings.capacity o
+// la del preset de firmware). El resumen de la generación avisa de las
+// listas que se agotan antes de warn_days; "dnpgen spares" muestra la tabla
+// completa sin generar.
+
+// SpareForecastConfig controla el historial y la previsión.
+type SpareForecastConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	WindowDays int  `yaml:"window_days"` // historial usado para el ritmo; 90 por defecto
+	WarnDays   int  `yaml:"warn_days"`   // avisar si se agota antes; 90 por defecto
+}
+
+func (c SpareForecastConfig) windowDays() int {
+	if c.WindowDays > 0 {
+		return c.WindowDays
+	}
+	return 90
+}
+
+func (c SpareForecastConfig) warnDays() int {
+	if c.WarnDays > 0 {
+		return c.WarnDays
+	}
+	return 90
+}
+
+// spareHistoryMax es el número de muestras que se conservan por nodo.
+const spareHistoryMax = 1000
+
+// spareSample es una línea del historial.
+type spareSample struct {
+	Time  time.Time                  `json:"time"`
+	Lists map[string]spareListSample `json:"lists"`
+}
+
+type spareListSample struct {
+	Points int `json:"points"`
+	Real   int `json:"real"`
+	Spares int `json:"spares"`
+}
+
+func spareHistoryPath(outDir, node string) string {
+	return filepath.Join(snapshotDir(outDir), node+".spares.jsonl")
+}
+
+// takeSpareSample cuenta las listas de una generación.
+func takeSpareSample(l *Lists) spareSample {
+	s := spareSample{Time: stampNow(), Lists: map[string]spareListSample{}}
+	for _, sec := range listSections(l) {
+		ls := spareListSample{Points: len(sec.Items)}
+		for _, p := range sec.Items {
+			if p.Spare {
+				ls.Spares++
+			}
+		}
+		ls.Real = ls.Points - ls.Spares
+		s.Lists[sec.Name] = ls
+	}
+	return s
+}
+
+// readSpareHistory lee el historial del nodo; sin fichero está vacío.
+func readSpareHistory(outDir, node string) ([]spareSample, error) {
+	path := spareHistoryPath(outDir, node)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []spareSample
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var s spareSample
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		out = append(out, s)
+	}
+	return out, sc.Err()
+}
+
+// writeSpareHistory añade la muestra al historial, conservando las últimas
+// spareHistoryMax.
+func writeSpareHistory(outDir, node string, history []spareSample, cur spareSample) error {
+	history = append(history, cur)
+	if len(history) > spareHistoryMax {
+		history = history[len(history)-spareHistoryMax:]
+	}
+	var b bytes.Buffer
+	for _, s := range history {
+		line, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		b.Write(append(line, '\n'))
+	}
+	path := spareHistoryPath(outDir, node)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b.Bytes())
+}
+
+// SpareForecast es el consumo y la previsión de una lista.
+type SpareForecast struct {
+	List     string `json:"list"`
+	Points   int    `json:"points"`
+	Real     int    `json:"real"`
+	Spares   int    `json:"spares"`
+	Capacity int    `json:"capacity,omitempty"`
+	Free     int    `json:"free,omitempty"` // posiciones hasta la capacidad
+	// Samples y Since son las muestras de la ventana y la más antigua.
+	Samples int       `json:"samples"`
+	Since   time.Time `json:"since"`
+	// Growth es el ritmo de crecimiento de la lista en posiciones cada 30
+	// días; SparesConsumed, los spares que se han ocupado en la ventana
+	// (negativo si se han liberado).
+	Growth         float64 `json:"growth_per_30d"`
+	SparesConsumed int     `json:"spares_consumed"`
+	// ExhaustDays y ExhaustAt son la previsión de agotamiento; vacíos sin
+	// capacidad o si la lista no crece.
+	ExhaustDays *float64   `json:"exhaust_days,omitempty"`
+	ExhaustAt   *time.Time `json:"exhaust_at,omitempty"`
+}
+
+// warn indica si la lista se agota antes de warn_days.
+func (f SpareForecast) warn() bool {
+	return f.ExhaustDays != nil && *f.ExhaustDays <= float64(GlobalConfig.App.SpareForecast.warnDays())
+}
+
+func (f SpareForecast) String() string {
+	if f.ExhaustDays == nil {
+		return ""
+	}
+	if *f.ExhaustDays <= 0 {
+		return trf("%s: capacidad agotada (%d/%d)", f.List, f.Points, f.Capacity)
+	}
+	return trf("%s: capacidad agotada en ~%.0f días (%s) al ritmo de %+.1f posiciones/30 días; quedan %d de %d",
+		f.List, *f.ExhaustDays, f.ExhaustAt.Format(time.DateOnly), f.Growth, f.Free, f.Capacity)
+}
+
+// forecastSpares calcula la previsión de cada lista con las muestras de la
+// ventana que acaba en la última.
+func forecastSpares(history []spareSample) []SpareForecast {
+	if len(history) == 0 {
+		return nil
+	}
+	cfg := GlobalConfig.App.SpareForecast
+	last := history[len(history)-1]
+	from := last.Time.AddDate(0, 0, -cfg.windowDays())
+	var window []spareSample
+	for _, s := range history {
+		if !s.Time.Before(from) {
+			window = append(window, s)
+		}
+	}
+
+	names := make([]string, 0, len(last.Lists))
+	for name := range last.Lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out []SpareForecast
+	for _, name := range names {
+		cur := last.Lists[name]
+		f := SpareForecast{List: name, Points: cur.Points, Real: cur.Real, Spares: cur.Spares, Since: window[0].Time}
+		var xs, ys []float64
+		for _, s := range window {
+			if ls, ok := s.Lists[name]; ok {
+				if len(xs) == 0 {
+					f.Since, f.SparesConsumed = s.Time, ls.Spares-cur.Spares
+				}
+				xs = append(xs, s.Time.Sub(last.Time).Hours()/24)
+				ys = append(ys, float64(ls.Points))
+			}
+		}
+		f.Samples = len(xs)
+		perDay := linearSlope(xs, ys)
+		f.Growth = perDay * 30
+		if f.Capacity = GlobalConfig.App.Findings.Capacity[name]; f.Capacity > 0 {
+			f.Free = max(f.Capacity-f.Points, 0)
+			var days float64
+			switch {
+			case f.Free == 0:
+			case perDay > 0:
+				days = float64(f.Free) / perDay
+			default:
+				out = append(out, f)
+				continue
+			}
+			at := stampTime(last.Time.Add(time.Duration(days * 24 * float64(time.Hour))))
+			f.ExhaustDays, f.ExhaustAt = &days, &at
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// linearSlope es la pendiente por mínimos cuadrados; 0 con menos de dos
+// instantes distintos.
+func linearSlope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - mx) * (ys[i] - my)
+		den += (xs[i] - mx) * (xs[i] - mx)
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+// printSpareWarnings muestra en el resumen las listas que se agotan pronto.
+func printSpareWarnings(forecast []SpareForecast) {
+	for _, f := range forecast {
+		if f.warn() {
+			fmt.Println(warnText(f.String()))
+		}
+	}
+}
+
+// runSpares implementa "dnpgen spares -path RUTA (-node A,B | -all)
+// [-json]": la previsión a partir del historial, sin generar.
+func runSpares(args []string) {
+	fs := flag.NewFlagSet("spares", flag.ExitOnError)
+	fs.Usage = manUsage("spares", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeNames := fs.String("node", "", tr("Nodos, separados por comas"))
+	all := fs.Bool("all", false, tr("Todos los nodos del proyecto"))
+	outDir := fs.String("out", "", tr("Directorio de salida, si no es el del recurso"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	if *projectPath == "" || (*nodeNames == "" && !*all) {
+		log.Fatal(tr("Uso: dnpgen.exe spares -path \"C:\\Ruta\" -node \"Nodo1,Nodo2\" | -all [-json]"))
+	}
+	abs, err := filepath.Abs(normalizeLongPath(*projectPath))
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	var nodes []string
+	if *all {
+		infos, err := listProjectNodes(abs)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		for _, n := range infos {
+			nodes = append(nodes, n.Node)
+		}
+	} else {
+		nodes = parseOnly(*nodeNames)
+	}
+
+	type nodeForecast struct {
+		Node     string          `json:"node"`
+		Forecast []SpareForecast `json:"forecast"`
+	}
+	var results []nodeForecast
+	for _, node := range nodes {
+		history, err := readSpareHistory(outputDirFor(abs, node, *outDir), node)
+		if err != nil {
+			log.Fatalf("[FATAL] %v", codedErrorf(ErrStateInvalid, "%v", err))
+		}
+		if len(history) > 0 {
+			results = append(results, nodeForecast{node, forecastSpares(history)})
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println(tr("Sin historial de spares: active app.spare_forecast y genere los nodos"))
+		return
+	}
+	var rows [][]string
+	var warn []bool
+	for _, r := range results {
+		for _, f := range r.Forecast {
+			capacity, free, exhaust := "-", "-", "-"
+			if f.Capacity > 0 {
+				capacity, free = strconv.Itoa(f.Capacity), strconv.Itoa(f.Free)
+			}
+			if f.ExhaustAt != nil {
+				exhaust = f.ExhaustAt.Format(time.DateOnly)
+			}
+			rows = append(rows, []string{r.Node, f.List, strconv.Itoa(f.Points), strconv.Itoa(f.Spares), capacity, free,
+				fmt.Sprintf("%+.1f", f.Growth), strconv.Itoa(f.SparesConsumed), strconv.Itoa(f.Samples), exhaust})
+			warn = append(warn, f.warn())
+		}
+	}
+	printTable(tr("NODO\tLISTA\tPOSICIONES\tSPARES\tCAPACIDAD\tLIBRES\tCRECIMIENTO/30D\tSPARES OCUPADOS\tMUESTRAS\tAGOTAMIENTO"), rows, func(i int) func(string) string {
+		if warn[i] {
+			return warnText
+		}
+		return nil
+	})
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- ESTRATEGIAS DE SPARE ---
+
+// SpareStrategy indica cómo se rellenan las posiciones sin punto real de
+// una lista (espejos y huecos de reservas).
+type SpareStrategy string
+
+const (
+	SpareFixed    SpareStrategy = "fixed"    // nombre fijo; en los espejos, NOMBRE(VAR) (convención original)
+	SparePool     SpareStrategy = "pool"     // nombres numerados: name con {n}
+	SpareReserved SpareStrategy = "reserved" // etiquetas reservadas de tags, en orden
+	SpareOmit     SpareStrategy = "omit"     // sin spare: la posición no se escribe
+)
+
+// SpareSpec configura los spares de una lista. En config.yaml admite la
+// forma corta de siempre (solo el nombre, estrategia fixed).
+type SpareSpec struct {
+	Strategy SpareStrategy `yaml:"strategy"`
+	Name     string        `yaml:"name"`   // fixed: nombre; pool: plantilla con {n}
+	Start    int           `yaml:"start"`  // pool: primer número (por defecto 1)
+	Digits   int           `yaml:"digits"` // pool: ancho con ceros a la izquierda
+	Tags     []string      `yaml:"tags"`   // reserved: etiquetas disponibles
+}
+
+func (s *SpareSpec) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*s = SpareSpec{Strategy: SpareFixed, Name: n.Value}
+		return nil
+	}
+	type plain SpareSpec
+	return n.Decode((*plain)(s))
+}
+
+func (s SpareSpec) strategy() SpareStrategy {
+	if s.Strategy == "" {
+		return SpareFixed
+	}
+	return s.Strategy
+}
+
+// SparesConfig agrupa la configuración de spares de las cuatro listas.
+type SparesConfig struct {
+	DO SpareSpec `yaml:"do"`
+	DI SpareSpec `yaml:"di"`
+	AO SpareSpec `yaml:"ao"`
+	AI SpareSpec `yaml:"ai"`
+	// Duplicates es lo que acepta el master de un mismo spare repetido:
+	// allow (por defecto), warn o unique-required. Solo valida, no mueve
+	// puntos: queda fuera de la huella de reglas.
+	Duplicates string `yaml:"duplicates" json:"-"`
+}
+
+// Políticas de spares repetidos (spares.duplicates).
+const (
+	SpareDupAllow  = "allow"
+	SpareDupWarn   = "warn"
+	SpareDupUnique = "unique-required"
+)
+
+// duplicateSeverity es la severidad del hallazgo duplicate_spare según
+// spares.duplicates.
+func (c SparesConfig) duplicateSeverity() (string, error) {
+	switch c.Duplicates {
+	case "", SpareDupAllow:
+		return SeverityOff, nil
+	case SpareDupWarn:
+		return SeverityWarning, nil
+	case SpareDupUnique:
+		return SeverityError, nil
+	}
+	return "", fmt.Errorf(tr("spares.duplicates desconocido %q (allow, warn, unique-required)"), c.Duplicates)
+}
+
+// checkDuplicateSpares informa los spares cuya línea se repite en el nodo,
+// en cualquiera de sus listas: hay masters que rechazan la misma etiqueta
+// dos veces.
+func checkDuplicateSpares(s *findingSet, l *Lists) {
+	fallback, err := GlobalConfig.App.Spares.duplicateSeverity()
+	if err != nil || fallback == SeverityOff {
+		return
+	}
+	type seen struct {
+		count int
+		where []string
+	}
+	byName := map[string]*seen{}
+	var order []string
+	for _, sec := range listSections(l) {
+		for i, p := range sec.Items {
+			if !p.Spare {
+				continue
+			}
+			e := byName[p.Name]
+			if e == nil {
+				e = &seen{}
+				byName[p.Name] = e
+				order = append(order, p.Name)
+			}
+			e.count++
+			if len(e.where) < 5 {
+				e.where = append(e.where, fmt.Sprintf("%s[%d]", sec.Name, i))
+			}
+		}
+	}
+	for _, name := range order {
+		if e := byName[name]; e.count > 1 {
+			more := ""
+			if e.count > len(e.where) {
+				more = ", ..."
+			}
+			s.add(FindingDuplicateSpare, fallback, tr("%s aparece %d veces (%s%s)"), name, e.count, strings.Join(e.where, ", "), more)
+		}
+	}
+}
+
+func (c SparesConfig) forList(list string) SpareSpec {
+	switch list {
+	case "DO":
+		return c.DO
+	case "DI":
+		return c.DI
+	case "AO":
+		return c.AO
+	}
+	return c.AI
+}
+
+// spareAllocator reparte los nombres de spare de una lista durante una
+// ejecución. Los nombres ya presentes en la lista no se repiten, de modo que
+// las reservas pueden seguir asignando sobre una lista ya clasificada.
+type spareAllocator struct {
+	list string
+	spec SpareSpec
+	used map[string]bool
+	n    int // siguiente número (pool) o etiqueta (reserved)
+}
+
+func newSpareAllocator(list string, existing []Point) (*spareAllocator, error) {
+	spec := GlobalConfig.App.Spares.forList(list)
+	a := &spareAllocator{list: list, spec: spec, used: map[string]bool{}}
+	switch spec.strategy() {
+	case SpareFixed, SpareOmit:
+	case SparePool:
+		if !strings.Contains(spec.Name, "{n}") {
+			return nil, fmt.Errorf("spares %s: la estrategia pool necesita {n} en name", list)
+		}
+		a.n = spec.Start
+		if a.n == 0 {
+			a.n = 1
+		}
+	case SpareReserved:
+		if len(spec.Tags) == 0 {
+			return nil, fmt.Errorf("spares %s: la estrategia reserved necesita tags", list)
+		}
+	default:
+		return nil, fmt.Errorf("spares %s: estrategia desconocida %q", list, spec.Strategy)
+	}
+	for _, p := range existing {
+		a.used[p.Name] = true
+	}
+	return a, nil
+}
+
+// omit indica que la lista no lleva spares.
+func (a *spareAllocator) omit() bool {
+	return a.spec.strategy() == SpareOmit
+}
+
+// next devuelve el siguiente spare. mirrored es la variable que lo origina
+// (vacía en los huecos de reservas).
+func (a *spareAllocator) next(mirrored, typ string) (Point, error) {
+	p := Point{Var: mirrored, Type: typ, Spare: true}
+	switch a.spec.strategy() {
+	case SpareOmit:
+		return p, fmt.Errorf("spares %s: la estrategia omit no admite huecos", a.list)
+	case SpareFixed:
+		p.Name = a.spec.Name
+		if mirrored != "" {
+			p.Name += "(" + mirrored + ")"
+		}
+		return p, nil
+	case SparePool:
+		for {
+			num := strconv.Itoa(a.n)
+			if len(num) < a.spec.Digits {
+				num = strings.Repeat("0", a.spec.Digits-len(num)) + num
+			}
+			a.n++
+			if name := strings.ReplaceAll(a.spec.Name, "{n}", num); !a.used[name] {
+				p.Name = name
+				break
+			}
+		}
+	case SpareReserved:
+		for ; a.n < len(a.spec.Tags) && a.used[a.spec.Tags[a.n]]; a.n++ {
+		}
+		if a.n >= len(a.spec.Tags) {
+			return p, fmt.Errorf("spares %s: se agotaron las %d etiquetas reservadas", a.list, len(a.spec.Tags))
+		}
+		p.Name = a.spec.Tags[a.n]
+		a.n++
+	}
+	a.used[p.Name] = true
+	if mirrored == "" {
+		p.Var = strings.TrimPrefix(p.Name, "@GV.")
+	}
+	return p, nil
+}
+
+// spareMatcher reconoce en un __lists.ini ya escrito las líneas que son
+// spares según la estrategia configurada de cada lista.
+func spareMatcher(list string) func(name string) bool {
+	spec := GlobalConfig.App.Spares.forList(list)
+	switch spec.strategy() {
+	case SparePool:
+		parts := strings.Split(spec.Name, "{n}")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		re := regexp.MustCompile("^" + strings.Join(parts, `\d+`) + "$")
+		return re.MatchString
+	case SpareReserved:
+		tags := map[string]bool{}
+		for _, t := range spec.Tags {
+			tags[t] = true
+		}
+		return func(name string) bool { return tags[name] }
+	case SpareFixed:
+		return func(name string) bool { return spec.Name != "" && name == spec.Name }
+	}
+	return func(string) bool { return false }
+}
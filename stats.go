@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- ESTADÍSTICAS DE USO LOCALES ---
+//
+// Con app.stats cada generación (y cada comprobación de deriva) añade una
+// línea JSON al fichero de estadísticas local: tipo de ejecución, proyecto,
+// duración y código de error. No se envía nada a ningún sitio ni se guardan
+// usuario, equipo ni rutas completas; con hash_projects tampoco el nombre
+// del proyecto. "dnpgen stats" resume uno o varios ficheros (p.ej. los de
+// varias estaciones de ingeniería) por proyecto y por código de fallo.
+
+// UsageStatsConfig controla el fichero de estadísticas de uso.
+type UsageStatsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Path es el fichero de estadísticas. Vacío = dnpgen/usage.jsonl en el
+	// directorio de configuración del usuario (%AppData% en Windows).
+	Path string `yaml:"path"`
+	// HashProjects guarda un resumen SHA-256 del nombre del proyecto en
+	// lugar del nombre.
+	HashProjects bool `yaml:"hash_projects"`
+}
+
+func (c UsageStatsConfig) path() (string, error) {
+	if c.Path != "" {
+		return c.Path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dnpgen", "usage.jsonl"), nil
+}
+
+// Tipos de ejecución registrados.
+const (
+	UsageGenerate = "generate"
+	UsageCheck    = "check"
+)
+
+// usageRecord es una línea del fichero de estadísticas.
+type usageRecord struct {
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Project    string    `json:"project"`
+	DurationMs int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	ErrorCode  string    `json:"error_code,omitempty"`
+	Points     int       `json:"points,omitempty"`
+	Version    string    `json:"version"`
+}
+
+// usageMu serializa las escrituras de los trabajos concurrentes del
+// servidor.
+var usageMu sync.Mutex
+
+// usageProject es el nombre del proyecto tal como se registra.
+func usageProject(projectPath string) string {
+	name := filepath.Base(filepath.Clean(projectPath))
+	if strings.EqualFold(filepath.Ext(name), ".zip") {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if GlobalConfig.App.Stats.HashProjects {
+		sum := sha256.Sum256([]byte(strings.ToLower(name)))
+		return hex.EncodeToString(sum[:6])
+	}
+	return name
+}
+
+// recordUsage añade la ejecución al fichero de estadísticas. Un fallo al
+// escribir solo se avisa: las estadísticas nunca hacen fallar la generación.
+func recordUsage(req GenerateRequest, elapsed time.Duration, res *GenerateResult, genErr error) {
+	cfg := GlobalConfig.App.Stats
+	if !cfg.Enabled {
+		return
+	}
+	rec := usageRecord{
+		Time:       stampNow(),
+		Kind:       UsageGenerate,
+		Project:    usageProject(req.ProjectPath),
+		DurationMs: elapsed.Milliseconds(),
+		Success:    genErr == nil,
+		ErrorCode:  errorCode(genErr),
+		Version:    version,
+	}
+	if req.CheckOnly {
+		rec.Kind = UsageCheck
+	}
+	if res != nil && res.lists != nil {
+		for _, sec := range listSections(res.lists) {
+			rec.Points += len(sec.Items)
+		}
+	}
+	if err := appendUsage(cfg, rec); err != nil {
+		log.Printf(tr("[WARN] No se pudieron guardar las estadísticas de uso: %v"), err)
+	}
+}
+
+func appendUsage(cfg UsageStatsConfig, rec usageRecord) error {
+	path, err := cfg.path()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readUsage lee un fichero de estadísticas. Las líneas dañadas (p.ej. una
+// escritura cortada) se saltan.
+func readUsage(path string) ([]usageRecord, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	var out []usageRecord
+	bad := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var rec usageRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			bad++
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, bad, sc.Err()
+}
+
+// usageSummary agrega las ejecuciones de un proyecto (o del total).
+type usageSummary struct {
+	Project  string         `json:"project"`
+	Runs     int            `json:"runs"`
+	Checks   int            `json:"checks"`
+	Failures int            `json:"failures"`
+	TotalMs  int64          `json:"total_ms"`
+	AvgMs    int64          `json:"avg_ms"`
+	MaxMs    int64          `json:"max_ms"`
+	Last     time.Time      `json:"last"`
+	Codes    map[string]int `json:"failure_codes,omitempty"`
+}
+
+func (s *usageSummary) add(rec usageRecord) {
+	if rec.Kind == UsageCheck {
+		s.Checks++
+	} else {
+		s.Runs++
+	}
+	s.TotalMs += rec.DurationMs
+	s.MaxMs = max(s.MaxMs, rec.DurationMs)
+	if rec.Time.After(s.Last) {
+		s.Last = rec.Time
+	}
+	if !rec.Success {
+		s.Failures++
+		if s.Codes == nil {
+			s.Codes = map[string]int{}
+		}
+		s.Codes[rec.ErrorCode]++
+	}
+	s.AvgMs = s.TotalMs / int64(s.Runs+s.Checks)
+}
+
+// summarizeUsage agrupa por proyecto (ordenados por tiempo total) y
+// devuelve también el total.
+func summarizeUsage(records []usageRecord) ([]*usageSummary, *usageSummary) {
+	byProject := map[string]*usageSummary{}
+	total := &usageSummary{Project: "TOTAL"}
+	for _, rec := range records {
+		s := byProject[rec.Project]
+		if s == nil {
+			s = &usageSummary{Project: rec.Project}
+			byProject[rec.Project] = s
+		}
+		s.add(rec)
+		total.add(rec)
+	}
+	out := make([]*usageSummary, 0, len(byProject))
+	for _, s := range byProject {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalMs != out[j].TotalMs {
+			return out[i].TotalMs > out[j].TotalMs
+		}
+		return out[i].Project < out[j].Project
+	})
+	return out, total
+}
+
+// parseSince interpreta -since: días ("30d"), una duración de Go ("12h") o
+// una fecha (2006-01-02).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		if days, err := strconv.Atoi(n); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf(tr("-since %q no válido (30d, 12h o 2006-01-02)"), s)
+}
+
+// formatMillis muestra una duración en ms con la unidad adecuada.
+func formatMillis(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d >= time.Second {
+		d = d.Round(100 * time.Millisecond)
+	}
+	return d.String()
+}
+
+// runStats implementa "dnpgen stats [FICHERO...]".
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.Usage = manUsage("stats", fs)
+	since := fs.String("since", "", tr("Solo ejecuciones recientes: 30d, 12h o una fecha 2006-01-02"))
+	project := fs.String("project", "", tr("Solo este proyecto"))
+	asJSON := fs.Bool("json", false, tr("Salida JSON"))
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		path, err := GlobalConfig.App.Stats.path()
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		files = []string{path}
+	}
+	var from time.Time
+	if *since != "" {
+		var err error
+		if from, err = parseSince(*since, time.Now()); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+
+	var records []usageRecord
+	for _, file := range files {
+		recs, bad, err := readUsage(file)
+		if os.IsNotExist(err) && len(files) == 1 {
+			log.Printf(tr("No hay estadísticas en %s (active app.stats.enabled)"), file)
+			return
+		}
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if bad > 0 {
+			log.Printf(tr("[WARN] %s: %d línea(s) dañadas ignoradas"), file, bad)
+		}
+		for _, rec := range recs {
+			if rec.Time.Before(from) || (*project != "" && !strings.EqualFold(rec.Project, *project)) {
+				continue
+			}
+			records = append(records, rec)
+		}
+	}
+	projects, total := summarizeUsage(records)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"projects": projects, "total": total}); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		return
+	}
+	if len(records) == 0 {
+		log.Println(tr("No hay ejecuciones registradas en el periodo"))
+		return
+	}
+	var rows [][]string
+	for _, s := range append(projects, total) {
+		rows = append(rows, []string{s.Project, strconv.Itoa(s.Runs), strconv.Itoa(s.Checks), strconv.Itoa(s.Failures),
+			formatMillis(s.TotalMs), formatMillis(s.AvgMs), formatMillis(s.MaxMs), formatStamp(s.Last)})
+	}
+	printTable(tr("PROYECTO\tGENERACIONES\tCOMPROBACIONES\tFALLOS\tTIEMPO TOTAL\tMEDIA\tMÁXIMO\tÚLTIMA"), rows, nil)
+
+	if len(total.Codes) > 0 {
+		codes := make([]string, 0, len(total.Codes))
+		for code := range total.Codes {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool {
+			if total.Codes[codes[i]] != total.Codes[codes[j]] {
+				return total.Codes[codes[i]] > total.Codes[codes[j]]
+			}
+			return codes[i] < codes[j]
+		})
+		fmt.Println(boldText(tr("\nFallos por código")))
+		rows = rows[:0]
+		for _, code := range codes {
+			rows = append(rows, []string{code, errorCatalog[code].Name, strconv.Itoa(total.Codes[code])})
+		}
+		printTable(tr("CÓDIGO\tNOMBRE\tFALLOS"), rows, nil)
+	}
+}
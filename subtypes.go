@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- SUBTIPOS ANALÓGICOS (AA, AAR...) ---
+//
+// El .SIG distingue las analógicas normales (AA) de las retenidas (AAR), pero
+// la clasificación las trata igual: las dos pasan por analog_output_regex. Los
+// firmwares recientes informan los eventos de las retenidas de otra forma,
+// así que classification.analog_subtypes permite, por tipo SIG exacto, sus
+// propios patrones de salida, una clase de evento y una lista de extra_lists
+// donde copiarlas. El exportador "subtypes" las lista con su índice y clase.
+
+// AnalogSubtype es el tratamiento propio de un tipo analógico del .SIG.
+type AnalogSubtype struct {
+	// OutputRegex sustituye a analog_output_regex para este tipo; vacío, se
+	// usan los generales.
+	OutputRegex []string `yaml:"output_regex"`
+	// EventClass es la clase de evento DNP3 (0 a 3; 0 = solo estático);
+	// omitida, la del master.
+	EventClass *int `yaml:"event_class"`
+	// List, si no está vacío, es una lista de extra_lists donde se copian
+	// además los puntos de este tipo.
+	List string `yaml:"list"`
+}
+
+// isAnalogType indica si el tipo SIG entra en las listas analógicas por
+// nombre, como en processSigFile.
+func isAnalogType(t string) bool {
+	return strings.Contains(t, "AA") || strings.Contains(t, "REAL")
+}
+
+// compileAnalogSubtypes valida classification.analog_subtypes y compila sus
+// patrones de salida, por tipo en mayúsculas; nil si el tipo usa
+// analog_output_regex.
+func compileAnalogSubtypes(cfg map[string]AnalogSubtype) (map[string]*ruleMatcher, error) {
+	out := map[string]*ruleMatcher{}
+	for name, st := range cfg {
+		key := strings.ToUpper(name)
+		label := "analog_subtypes." + name
+		if !isAnalogType(key) {
+			return nil, fmt.Errorf(tr("%s: %q no es un tipo analógico del .SIG"), label, name)
+		}
+		if _, dup := out[key]; dup {
+			return nil, fmt.Errorf(tr("%s: tipo repetido"), label)
+		}
+		if c := st.EventClass; c != nil && (*c < 0 || *c > 3) {
+			return nil, fmt.Errorf(tr("%s: event_class %d fuera de 0..3"), label, *c)
+		}
+		var m *ruleMatcher
+		if len(st.OutputRegex) > 0 {
+			var err error
+			if m, err = compileRules(st.OutputRegex, false); err != nil {
+				return nil, fmt.Errorf("%s.output_regex: %v", label, err)
+			}
+		}
+		out[key] = m
+	}
+	return out, nil
+}
+
+// analogSubtypeFor devuelve la configuración del tipo, si la tiene.
+func analogSubtypeFor(varType string) (AnalogSubtype, bool) {
+	for name, st := range GlobalConfig.App.Classification.AnalogSubtypes {
+		if strings.EqualFold(name, varType) {
+			return st, true
+		}
+	}
+	return AnalogSubtype{}, false
+}
+
+// copyAnalogSubtypes copia los puntos de cada subtipo con list a su lista
+// de extra_lists, en el orden de AI y después AO. Devuelve cuántos copió.
+func copyAnalogSubtypes(l *Lists, cfg map[string]AnalogSubtype) (int, error) {
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	n := 0
+	for _, name := range names {
+		st := cfg[name]
+		if st.List == "" {
+			continue
+		}
+		var dedicated *CustomList
+		for i := range l.Extra {
+			if l.Extra[i].Name == strings.ToUpper(st.List) {
+				dedicated = &l.Extra[i]
+			}
+		}
+		if dedicated == nil {
+			return n, fmt.Errorf(tr("analog_subtypes.%s: la lista %q no está en extra_lists"), name, st.List)
+		}
+		for _, list := range [][]Point{l.AI, l.AO} {
+			for _, p := range list {
+				if !p.Spare && strings.EqualFold(p.Type, name) {
+					dedicated.Items = append(dedicated.Items, p)
+					n++
+				}
+			}
+		}
+	}
+	return n, nil
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"subtypes", ".subtypes.csv", writeSubtypesCSV})
+}
+
+// writeSubtypesCSV lista los puntos de los subtipos configurados con su
+// lista, índice y clase de evento, para configurar el master.
+func writeSubtypesCSV(out io.Writer, ctx ExportContext) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"node", "list", "index", "name", "var", "type", "event_class"})
+	for _, s := range []struct {
+		name  string
+		items []Point
+	}{{"AI", ctx.Lists.AI}, {"AO", ctx.Lists.AO}} {
+		for idx, p := range s.items {
+			if p.Spare {
+				continue
+			}
+			st, ok := analogSubtypeFor(p.Type)
+			if !ok {
+				continue
+			}
+			class := ""
+			if st.EventClass != nil {
+				class = strconv.Itoa(*st.EventClass)
+			}
+			w.Write([]string{ctx.Node, s.name, strconv.Itoa(idx), p.Name, p.Var, p.Type, class})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
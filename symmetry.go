@@ -0,0 +1,84 @@
+package main
+
+import "fmt"
+
+// --- SIMETRÍA DE LAS LISTAS ESPEJO ---
+//
+// Con las estrategias spare y mirror cada señal ocupa el mismo índice en su
+// lista y en la opuesta, de modo que DI/DO y AI/AO crecen a la par. Si una
+// inserción rompe esa correspondencia, todos los índices posteriores de una
+// de las listas quedan desplazados respecto a la otra. Tras clasificar se
+// comprueba índice a índice que ambas listas proceden de las mismas líneas
+// del .SIG; si no, el hallazgo mirror_asymmetry (error) detiene la
+// generación antes de escribir.
+
+// MirrorAsymmetry es el primer índice en que una lista y su opuesta dejan de
+// corresponderse.
+type MirrorAsymmetry struct {
+	In, Out       string // p.ej. "DI" y "DO"
+	Index         int
+	LenIn, LenOut int
+	InPoint       *Point // nil si la lista ya terminó
+	OutPoint      *Point
+}
+
+func describeMirrored(list string, index int, p *Point) string {
+	if p == nil {
+		return trf("%s[%d] (fin de la lista)", list, index)
+	}
+	origin := pointRule(*p)
+	switch {
+	case p.line > 0 && origin != "":
+		return trf("%s[%d] %s (%s, línea %d del .SIG)", list, index, p.Name, origin, p.line)
+	case p.line > 0:
+		return trf("%s[%d] %s (línea %d del .SIG)", list, index, p.Name, p.line)
+	}
+	return trf("%s[%d] %s (%s)", list, index, p.Name, origin)
+}
+
+func (a MirrorAsymmetry) String() string {
+	return trf("%s/%s divergen en el índice %d: %s frente a %s; %s tiene %d entradas y %s %d",
+		a.In, a.Out, a.Index, describeMirrored(a.In, a.Index, a.InPoint), describeMirrored(a.Out, a.Index, a.OutPoint),
+		a.In, a.LenIn, a.Out, a.LenOut)
+}
+
+// checkMirrorSymmetry compara índice a índice cada pareja de listas cuyas
+// estrategias de espejo prometen simetría. asymmetric marca las parejas
+// ("AI/AO", "DI/DO") en que la configuración la rompe a propósito
+// (spare-input, spare-output, none o spares omit), que no se comprueban.
+func checkMirrorSymmetry(l *Lists, asymmetric map[string]bool) []MirrorAsymmetry {
+	var out []MirrorAsymmetry
+	for _, pair := range []struct {
+		in, out   string
+		ins, outs []Point
+	}{{"AI", "AO", l.AI, l.AO}, {"DI", "DO", l.DI, l.DO}} {
+		if asymmetric[mirrorPair(pair.in, pair.out)] {
+			continue
+		}
+		n := max(len(pair.ins), len(pair.outs))
+		for i := 0; i < n; i++ {
+			var in, o *Point
+			if i < len(pair.ins) {
+				in = &pair.ins[i]
+			}
+			if i < len(pair.outs) {
+				o = &pair.outs[i]
+			}
+			if in != nil && o != nil && in.line == o.line {
+				continue
+			}
+			out = append(out, MirrorAsymmetry{
+				In: pair.in, Out: pair.out, Index: i,
+				LenIn: len(pair.ins), LenOut: len(pair.outs),
+				InPoint: in, OutPoint: o,
+			})
+			break
+		}
+	}
+	return out
+}
+
+// mirrorPair es la clave de asymmetric para la pareja de una lista.
+func mirrorPair(inList, outList string) string {
+	return fmt.Sprintf("%s/%s", inList, outList)
+}
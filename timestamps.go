@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// --- MARCAS DE TIEMPO ---
+//
+// Todas las fechas que escribe dnpgen (log, manifiestos, modelo, métricas,
+// instantáneas, paquetes e informes) van en ISO-8601 con la zona horaria,
+// para que equipos de varias sedes puedan cruzar registros. app.timezone
+// elige entre la hora local del equipo (por defecto) y UTC.
+
+// logStampLayout es ISO-8601 con milisegundos y desplazamiento.
+const logStampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// validateTimezone comprueba app.timezone.
+func validateTimezone(tz string) error {
+	switch strings.ToLower(tz) {
+	case "", "local", "utc":
+		return nil
+	}
+	return fmt.Errorf(tr("timezone desconocida %q (local o utc)"), tz)
+}
+
+// stampZone es la zona de app.timezone.
+func stampZone() *time.Location {
+	if strings.EqualFold(GlobalConfig.App.Timezone, "utc") {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// stampTime pasa t a la zona configurada.
+func stampTime(t time.Time) time.Time {
+	return t.In(stampZone())
+}
+
+// stampNow es la hora actual en la zona configurada.
+func stampNow() time.Time {
+	return stampTime(time.Now())
+}
+
+// formatStamp da t en ISO-8601 (RFC 3339) en la zona configurada.
+func formatStamp(t time.Time) string {
+	return stampTime(t).Format(time.RFC3339)
+}
+
+// logOutput es el destino del log sin la marca de tiempo.
+var logOutput io.Writer
+
+// setLogOutput cambia el destino del log conservando la marca ISO-8601 al
+// principio de cada línea.
+func setLogOutput(w io.Writer) {
+	logOutput = w
+	log.SetOutput(stampWriter{w})
+}
+
+// stampWriter antepone la marca de tiempo a cada línea del log. El paquete
+// log solo sabe escribir la fecha sin zona, así que se desactiva la suya.
+type stampWriter struct{ w io.Writer }
+
+func (s stampWriter) Write(p []byte) (int, error) {
+	line := make([]byte, 0, len(logStampLayout)+1+len(p))
+	line = stampNow().AppendFormat(line, logStampLayout)
+	line = append(append(line, ' '), p...)
+	if _, err := s.w.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
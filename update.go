@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- ACTUALIZACIÓN DEL EJECUTABLE ---
+
+// UpdateConfig indica dónde se publican las versiones. URL admite:
+//
+//   - github:propietario/repositorio (última release de GitHub)
+//   - una carpeta local o compartida (\\servidor\dnpgen) o una URL http(s)
+//     con los ficheros VERSION, SHA256SUMS, SHA256SUMS.sig y los binarios.
+//
+// Los binarios se llaman dnpgen_<os>_<arch> (.exe en Windows). SHA256SUMS
+// tiene el formato de sha256sum y SHA256SUMS.sig es su firma Ed25519.
+type UpdateConfig struct {
+	URL           string `yaml:"url"`
+	PublicKey     string `yaml:"public_key"`     // Ed25519 en base64, hex o PEM
+	AllowUnsigned bool   `yaml:"allow_unsigned"` // solo comprobar SHA256SUMS
+	TimeoutSec    int    `yaml:"timeout_sec"`
+}
+
+// releaseSource resuelve los ficheros de una versión publicada.
+type releaseSource struct {
+	version string
+	fetch   func(name string) ([]byte, error)
+}
+
+func runUpdate(args []string) {
+	cfg := GlobalConfig.App.Update
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	fs.Usage = manUsage("update", fs)
+	url := fs.String("url", cfg.URL, tr("Origen de las versiones (anula app.update.url)"))
+	check := fs.Bool("check", false, tr("Solo comprobar si hay una versión nueva"))
+	force := fs.Bool("force", false, tr("Instalar aunque la versión no sea más reciente"))
+	fs.Parse(args)
+
+	if *url == "" {
+		log.Fatal(tr("[FATAL] Falta el origen de las versiones (-url o app.update.url)"))
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	os.Remove(exe + ".old") // resto de una actualización anterior
+
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	src, err := openReleaseSource(*url, &http.Client{Timeout: timeout})
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	current := buildInfo().Version
+	newer := compareVersions(src.version, current) > 0
+	fmt.Printf(tr("Versión instalada: %s | publicada: %s\n"), current, src.version)
+	if *check {
+		if newer {
+			fmt.Println(okText(tr("Hay una versión nueva disponible")))
+		}
+		return
+	}
+	if !newer && !*force {
+		fmt.Println(tr("Ya está actualizado"))
+		return
+	}
+
+	asset := "dnpgen_" + runtime.GOOS + "_" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		asset += ".exe"
+	}
+	bin, err := downloadVerified(src, asset, cfg)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	if err := replaceExecutable(exe, bin); err != nil {
+		log.Fatalf(tr("[FATAL] No se pudo reemplazar %s: %v"), exe, err)
+	}
+	fmt.Println(okText(trf("Actualizado a %s", src.version)))
+}
+
+// downloadVerified descarga asset y comprueba su hash en SHA256SUMS y la
+// firma de SHA256SUMS con la clave pública configurada.
+func downloadVerified(src *releaseSource, asset string, cfg UpdateConfig) ([]byte, error) {
+	sums, err := src.fetch("SHA256SUMS")
+	if err != nil {
+		return nil, fmt.Errorf("SHA256SUMS: %v", err)
+	}
+	switch {
+	case cfg.PublicKey != "":
+		// parsePublicKey comprueba la longitud: ed25519.Verify entra en pánico
+		// con una clave de otro tamaño.
+		pub, err := parsePublicKey(cfg.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("public_key: %v", err)
+		}
+		sig, err := src.fetch("SHA256SUMS.sig")
+		if err != nil {
+			return nil, fmt.Errorf("SHA256SUMS.sig: %v", err)
+		}
+		if raw, err := decodeKey(string(sig)); err == nil {
+			sig = raw // firma publicada en base64 o hex
+		}
+		if len(sig) != ed25519.SignatureSize {
+			return nil, fmt.Errorf(tr("SHA256SUMS.sig: firma de %d bytes (se esperan %d)"), len(sig), ed25519.SignatureSize)
+		}
+		if !ed25519.Verify(pub, sums, sig) {
+			return nil, errors.New(tr("la firma de SHA256SUMS no es válida"))
+		}
+	case !cfg.AllowUnsigned:
+		return nil, errors.New(tr("sin app.update.public_key no se instalan versiones (use allow_unsigned para aceptar solo el hash)"))
+	}
+
+	want, err := checksumFor(sums, asset)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Descargando %s...", asset)
+	bin, err := src.fetch(asset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", asset, err)
+	}
+	sum := sha256.Sum256(bin)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf(tr("hash de %s incorrecto: %s, se esperaba %s"), asset, got, want)
+	}
+	return bin, nil
+}
+
+// checksumFor busca el hash de name en un fichero con formato de sha256sum.
+func checksumFor(sums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf(tr("%s no aparece en SHA256SUMS"), name)
+}
+
+func decodeKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// replaceExecutable sustituye el binario en ejecución. Windows no permite
+// sobrescribirlo pero sí renombrarlo, así que se aparta como .old y se borra
+// en la siguiente actualización.
+func replaceExecutable(exe string, bin []byte) error {
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, bin, 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(exe, exe+".old"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, exe); err != nil {
+		os.Rename(exe+".old", exe)
+		return err
+	}
+	os.Remove(exe + ".old") // falla en Windows mientras el proceso siga vivo
+	return nil
+}
+
+func openReleaseSource(url string, client *http.Client) (*releaseSource, error) {
+	if repo, ok := strings.CutPrefix(url, "github:"); ok {
+		return githubRelease(repo, client)
+	}
+	var fetch func(name string) ([]byte, error)
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		base := strings.TrimSuffix(url, "/")
+		fetch = func(name string) ([]byte, error) { return httpGet(client, base+"/"+name) }
+	} else {
+		dir := strings.TrimPrefix(url, "file://")
+		fetch = func(name string) ([]byte, error) { return os.ReadFile(filepath.Join(dir, name)) }
+	}
+	v, err := fetch("VERSION")
+	if err != nil {
+		return nil, fmt.Errorf("VERSION: %v", err)
+	}
+	return &releaseSource{version: strings.TrimSpace(string(v)), fetch: fetch}, nil
+}
+
+// githubRelease usa la última release publicada; los ficheros son sus assets.
+func githubRelease(repo string, client *http.Client) (*releaseSource, error) {
+	data, err := httpGet(client, "https://api.github.com/repos/"+repo+"/releases/latest")
+	if err != nil {
+		return nil, err
+	}
+	var rel struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return nil, fmt.Errorf(tr("respuesta de GitHub: %v"), err)
+	}
+	assets := map[string]string{}
+	for _, a := range rel.Assets {
+		assets[a.Name] = a.URL
+	}
+	return &releaseSource{
+		version: rel.TagName,
+		fetch: func(name string) ([]byte, error) {
+			u, ok := assets[name]
+			if !ok {
+				return nil, fmt.Errorf(tr("la release %s no incluye %s"), rel.TagName, name)
+			}
+			return httpGet(client, u)
+		},
+	}, nil
+}
+
+func httpGet(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// compareVersions compara versiones "v1.2.3" numéricamente por componentes.
+// Una versión no numérica (p.ej. "dev") es anterior a cualquier otra.
+func compareVersions(a, b string) int {
+	pa, oka := versionParts(a)
+	pb, okb := versionParts(b)
+	switch {
+	case !oka || !okb:
+		if oka != okb {
+			if oka {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	}
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "-")
+	var out []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeRelease sirve los ficheros de una versión desde memoria.
+func fakeRelease(files map[string][]byte) *releaseSource {
+	return &releaseSource{version: "v9.9.9", fetch: func(name string) ([]byte, error) {
+		if b, ok := files[name]; ok {
+			return b, nil
+		}
+		return nil, os.ErrNotExist
+	}}
+}
+
+func TestDownloadVerifiedRejectsMalformedKeysAndSignatures(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := []byte("binario")
+	sum := sha256.Sum256(bin)
+	sums := []byte(fmt.Sprintf("%s  dnpgen.exe\n", hex.EncodeToString(sum[:])))
+	goodSig := ed25519.Sign(key, sums)
+	b64 := base64.StdEncoding.EncodeToString
+
+	cases := []struct {
+		name, key string
+		sig       []byte
+		wantErr   string
+	}{
+		{"válida", b64(pub), goodSig, ""},
+		{"clave corta", b64(pub[:16]), goodSig, "public_key"},
+		{"clave larga", b64(append(append([]byte{}, pub...), 0)), goodSig, "public_key"},
+		{"firma corta", b64(pub), goodSig[:10], "firma de 10 bytes"},
+		{"firma vacía", b64(pub), []byte{}, "firma de 0 bytes"},
+		{"firma en base64 de otra longitud", b64(pub), []byte(b64(goodSig[:32])), "firma de 32 bytes"},
+		{"firma ajena", b64(pub), make([]byte, ed25519.SignatureSize), "no es válida"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			src := fakeRelease(map[string][]byte{"SHA256SUMS": sums, "SHA256SUMS.sig": c.sig, "dnpgen.exe": bin})
+			got, err := downloadVerified(src, "dnpgen.exe", UpdateConfig{PublicKey: c.key})
+			if c.wantErr == "" {
+				if err != nil || string(got) != string(bin) {
+					t.Fatalf("downloadVerified = %q, %v", got, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("error = %v, se esperaba %q", err, c.wantErr)
+			}
+		})
+	}
+}
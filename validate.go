@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// --- VALIDADORES PROPIOS ---
+//
+// Comprobaciones específicas de un cliente (políticas de nombres, recuentos
+// mínimos por área...) que no tienen sentido como reglas genéricas. Reciben
+// el modelo de puntos ya clasificado y pueden vetar la generación antes de
+// escribir nada. Se registran en código (RegisterValidator, como los
+// exportadores) o como comandos externos en app.validators.
+
+// Validator comprueba el modelo de puntos de un nodo. Cada error devuelto es
+// un hallazgo "validator" (error por defecto, que veta la generación).
+type Validator interface {
+	Name() string
+	Validate(m *PointModel) []error
+}
+
+var validatorRegistry []Validator
+
+// RegisterValidator añade un validador; se ejecutan en orden de registro.
+func RegisterValidator(v Validator) {
+	for _, r := range validatorRegistry {
+		if strings.EqualFold(r.Name(), v.Name()) {
+			panic("validador duplicado: " + v.Name())
+		}
+	}
+	validatorRegistry = append(validatorRegistry, v)
+}
+
+// ValidatorCommand es un validador externo: recibe el modelo en JSON
+// (esquema de export-model) por la entrada estándar y veta la generación
+// saliendo con código distinto de 0. Cada línea no vacía de su salida
+// estándar es un error.
+type ValidatorCommand struct {
+	Name    string   `yaml:"name"`
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"` // admiten {project} y {node}
+	// TimeoutSec limita la ejecución; 60 por defecto.
+	TimeoutSec int `yaml:"timeout_sec"`
+	// Warn informa los errores como avisos sin vetar la generación.
+	Warn bool `yaml:"warn"`
+}
+
+func (c ValidatorCommand) label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Command
+}
+
+func (c ValidatorCommand) timeout() time.Duration {
+	if c.TimeoutSec > 0 {
+		return time.Duration(c.TimeoutSec) * time.Second
+	}
+	return 60 * time.Second
+}
+
+// run ejecuta el comando con el modelo serializado en input.
+func (c ValidatorCommand) run(m *PointModel, input []byte) []error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout())
+	defer cancel()
+	vars := map[string]string{"project": m.Project, "node": m.Node}
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = expandTemplate(a, vars)
+	}
+	cmd := exec.CommandContext(ctx, c.Command, args...)
+	cmd.Env = append(os.Environ(), "DNPGEN_PROJECT="+m.Project, "DNPGEN_NODE="+m.Node)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = bytes.NewReader(input), &stdout, &stderr
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return []error{fmt.Errorf(tr("sin respuesta en %s"), c.timeout())}
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return []error{err} // no se pudo lanzar
+	}
+	if err == nil {
+		return nil
+	}
+	var errs []error
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			errs = append(errs, errors.New(line))
+		}
+	}
+	if len(errs) == 0 {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return errs
+}
+
+// hasValidators indica si hay algo que validar, para no construir el modelo
+// en vano.
+func hasValidators() bool {
+	return len(validatorRegistry) > 0 || len(GlobalConfig.App.Validators) > 0
+}
+
+// runValidators ejecuta los validadores registrados y los de app.validators
+// y añade sus errores a s como hallazgos "validator", prefijados con el
+// nombre del validador. Los de validadores con warn: true son avisos.
+func runValidators(m *PointModel, s *findingSet) {
+	for _, v := range validatorRegistry {
+		for _, err := range v.Validate(m) {
+			s.add(FindingValidator, SeverityError, "%s: %v", v.Name(), err)
+		}
+	}
+	if len(GlobalConfig.App.Validators) == 0 {
+		return
+	}
+	input, err := json.Marshal(m)
+	if err != nil {
+		s.add(FindingValidator, SeverityError, tr("modelo de puntos: %v"), err)
+		return
+	}
+	for _, c := range GlobalConfig.App.Validators {
+		for _, err := range c.run(m, input) {
+			if c.Warn {
+				s.addAs(FindingValidator, SeverityWarning, fmt.Sprintf("%s: %v", c.label(), err))
+				continue
+			}
+			s.add(FindingValidator, SeverityError, "%s: %v", c.label(), err)
+		}
+	}
+}
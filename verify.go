@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// --- VERIFICACIÓN CONTRA UNA RTU REAL (MASTER DNP3) ---
+
+// VerifyConfig define la RTU contra la que se verifica en `verify`. Los flags
+// del subcomando tienen prioridad sobre estos valores.
+type VerifyConfig struct {
+	Host          string `yaml:"host"`
+	Port          int    `yaml:"port"`
+	Address       uint16 `yaml:"address"`        // dirección de la outstation
+	MasterAddress uint16 `yaml:"master_address"` // dirección local
+	TimeoutSec    int    `yaml:"timeout_sec"`
+}
+
+// pointRange es un rango contiguo de índices informado por la RTU.
+type pointRange struct{ start, stop int }
+
+func runVerify(args []string) {
+	cfg := GlobalConfig.App.Verify
+	if cfg.Port == 0 {
+		cfg.Port = 20000
+	}
+	if cfg.Address == 0 {
+		cfg.Address = 10
+	}
+	if cfg.MasterAddress == 0 {
+		cfg.MasterAddress = 1
+	}
+	if cfg.TimeoutSec == 0 {
+		cfg.TimeoutSec = 10
+	}
+
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.Usage = manUsage("verify", fs)
+	projectPath := fs.String("path", "", tr("Ruta raíz del proyecto"))
+	nodeName := fs.String("node", "", tr("Nombre del Nodo (usa su __lists.ini)"))
+	listsPath := fs.String("lists", "", tr("Ruta directa a un __lists.ini (alternativa a -path/-node)"))
+	host := fs.String("host", cfg.Host, tr("IP o nombre de la RTU"))
+	port := fs.Int("port", cfg.Port, tr("Puerto TCP DNP3 de la RTU"))
+	address := fs.Uint("address", uint(cfg.Address), tr("Dirección DNP3 de la outstation"))
+	master := fs.Uint("master", uint(cfg.MasterAddress), tr("Dirección DNP3 local (master)"))
+	fs.Parse(args)
+
+	file := *listsPath
+	if file == "" {
+		if *projectPath == "" || *nodeName == "" {
+			log.Fatal(tr("Uso: dnpgen.exe verify -path \"C:\\Ruta\" -node \"NombreNodo\" -host 10.0.0.5"))
+		}
+		abs, err := filepath.Abs(*projectPath)
+		if err != nil {
+			log.Fatalf(tr("Error ruta absoluta: %v"), err)
+		}
+		file = listsPathFor(abs, *nodeName)
+	}
+	if *host == "" {
+		log.Fatal(tr("[FATAL] Falta la dirección de la RTU (-host o app.verify.host)"))
+	}
+
+	lists, err := readListsFile(file)
+	if err != nil {
+		log.Fatalf(tr("[FATAL] Error leyendo %s: %v"), file, err)
+	}
+
+	target := net.JoinHostPort(*host, strconv.Itoa(*port))
+	log.Printf(tr("Conectando con RTU %s (outstation %d)..."), target, *address)
+	ranges, err := integrityPoll(target, uint16(*master), uint16(*address), time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		log.Fatalf(tr("[FATAL] Poll de integridad: %v"), err)
+	}
+
+	checks := []struct {
+		name  string
+		group byte
+		items []Point
+	}{
+		{"DI", 1, lists.DI},
+		{"DO", 10, lists.DO},
+		{"AI", 30, lists.AI},
+		{"AO", 40, lists.AO},
+	}
+	if len(lists.OS) > 0 {
+		checks = append(checks, struct {
+			name  string
+			group byte
+			items []Point
+		}{"OS", 110, lists.OS})
+	}
+	fmt.Println(boldText(tr("\n--- VERIFICACIÓN ---")))
+	failed := false
+	for _, c := range checks {
+		count, problems := comparePointRanges(ranges[c.group], len(c.items))
+		status := okText("OK")
+		if len(problems) > 0 {
+			status = errText(tr("DIFERENTE"))
+			failed = true
+		}
+		fmt.Printf(tr("%s: lista %d | RTU %d  [%s]\n"), c.name, len(c.items), count, status)
+		for _, p := range problems {
+			fmt.Printf("    - %s\n", p)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// comparePointRanges compara los índices informados por la RTU con los
+// esperados (0..expected-1) y describe cada diferencia.
+func comparePointRanges(ranges []pointRange, expected int) (int, []string) {
+	seen := map[int]bool{}
+	for _, r := range ranges {
+		for i := r.start; i <= r.stop; i++ {
+			seen[i] = true
+		}
+	}
+	var problems []string
+	if len(seen) != expected {
+		problems = append(problems, fmt.Sprintf(tr("la RTU informa %d puntos, la lista tiene %d"), len(seen), expected))
+	}
+	var missing, extra []int
+	for i := 0; i < expected; i++ {
+		if !seen[i] {
+			missing = append(missing, i)
+		}
+	}
+	for i := range seen {
+		if i >= expected {
+			extra = append(extra, i)
+		}
+	}
+	sort.Ints(extra)
+	if len(missing) > 0 {
+		problems = append(problems, tr("índices ausentes en la RTU: ")+formatIndexRanges(missing))
+	}
+	if len(extra) > 0 {
+		problems = append(problems, tr("índices fuera de la lista: ")+formatIndexRanges(extra))
+	}
+	return len(seen), problems
+}
+
+// formatIndexRanges compacta una lista ordenada de índices ("0-3, 7, 9-12").
+func formatIndexRanges(idx []int) string {
+	var out string
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && idx[j+1] == idx[j]+1 {
+			j++
+		}
+		if out != "" {
+			out += ", "
+		}
+		if i == j {
+			out += strconv.Itoa(idx[i])
+		} else {
+			out += fmt.Sprintf("%d-%d", idx[i], idx[j])
+		}
+		i = j + 1
+	}
+	return out
+}
+
+// integrityPoll lee la clase 0 de la outstation y devuelve los rangos de
+// índices informados por grupo de objeto (1, 10, 30, 40...).
+func integrityPoll(addr string, local, remote uint16, timeout time.Duration) (map[byte][]pointRange, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	c := newDNPConn(conn, local, remote, true)
+	c.deadline = timeout
+	if err := c.writeLink(linkResetLinkStates, true, nil); err != nil {
+		return nil, err
+	}
+
+	req := []byte{appFir | appFin, fcRead, 60, 1, 0x06}
+	if err := c.sendFragment(req); err != nil {
+		return nil, err
+	}
+
+	ranges := map[byte][]pointRange{}
+	first := true
+	for {
+		frag, err := c.readFragment()
+		if err != nil {
+			return nil, err
+		}
+		if len(frag) < 4 {
+			continue
+		}
+		ac, fc := frag[0], frag[1]
+		if fc == 0x82 { // respuesta no solicitada: se confirma y se ignora
+			if ac&appCon != 0 {
+				c.sendFragment([]byte{appFir | appFin | 0x10 | ac&0x0F, fcConfirm})
+			}
+			continue
+		}
+		if fc != fcResponse {
+			continue
+		}
+		if first && frag[3]&(iin2NoFuncSupport|iin2ObjectUnknown) != 0 {
+			return nil, fmt.Errorf(tr("la RTU rechazó la lectura de clase 0 (IIN2 0x%02X)"), frag[3])
+		}
+		first = false
+		objs, err := parseObjects(frag[4:])
+		if err != nil {
+			return nil, fmt.Errorf(tr("respuesta no interpretable: %v"), err)
+		}
+		for _, o := range objs {
+			if o.Qualifier > 0x01 {
+				continue
+			}
+			ranges[o.Group] = append(ranges[o.Group], pointRange{o.Start, o.Stop})
+		}
+		if ac&appCon != 0 {
+			if err := c.sendFragment([]byte{appFir | appFin | ac&0x0F, fcConfirm}); err != nil {
+				return nil, err
+			}
+		}
+		if ac&appFin != 0 {
+			return ranges, nil
+		}
+	}
+}
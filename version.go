@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// --- VERSIÓN Y METADATOS DE COMPILACIÓN ---
+
+// Se fijan al compilar:
+//
+//	go build -ldflags "-X main.version=3.3.0 -X main.commit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%d)"
+//
+// Sin ldflags se toman, si existen, los datos de VCS que Go incrusta al
+// compilar desde un checkout.
+var (
+	version   = "3.2"
+	commit    = ""
+	buildDate = ""
+)
+
+// BuildInfo identifica el binario que produjo una salida.
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"build_date,omitempty"`
+	Go      string `json:"go"`
+}
+
+func buildInfo() BuildInfo {
+	b := BuildInfo{Version: version, Commit: commit, Date: buildDate, Go: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && b.Commit == "":
+				b.Commit = s.Value[:min(len(s.Value), 12)]
+			case s.Key == "vcs.time" && b.Date == "":
+				b.Date = s.Value
+			case s.Key == "vcs.modified" && s.Value == "true" && commit == "" && !strings.HasSuffix(b.Commit, "-dirty"):
+				b.Commit += "-dirty"
+			}
+		}
+	}
+	return b
+}
+
+// String devuelve "3.3.0 (abc1234, 2026-10-01)".
+func (b BuildInfo) String() string {
+	var extra []string
+	if b.Commit != "" {
+		extra = append(extra, b.Commit)
+	}
+	if b.Date != "" {
+		extra = append(extra, b.Date)
+	}
+	if len(extra) == 0 {
+		return b.Version
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, strings.Join(extra, ", "))
+}
+
+// generatorLine es la línea que identifica al generador en las cabeceras de
+// los ficheros.
+func generatorLine() string {
+	return "dnpgen " + buildInfo().String()
+}
+
+// xmlGeneratorComment va tras la declaración XML de las exportaciones.
+func xmlGeneratorComment() string {
+	return "<!-- " + strings.ReplaceAll(generatorLine(), "--", "- -") + " -->\n"
+}
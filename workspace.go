@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- ESPACIO DE TRABAJO MULTIPROYECTO ---
+
+// Workspace agrupa varios proyectos (p.ej. todas las RTU de una región) para
+// generarlos en una sola ejecución con -workspace.
+type Workspace struct {
+	Projects []WorkspaceProject `yaml:"projects"`
+}
+
+// WorkspaceProject es un proyecto del espacio de trabajo. Las rutas relativas
+// parten del directorio del fichero de workspace.
+type WorkspaceProject struct {
+	Path    string   `yaml:"path"`
+	Nodes   []string `yaml:"nodes"`    // vacío = todos los nodos del proyecto
+	Out     string   `yaml:"out"`      // como -out; vacío = configuración global
+	SkipExt *bool    `yaml:"skip_ext"` // vacío = lo indicado en la línea de comandos
+}
+
+func loadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ws Workspace
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(ws.Projects) == 0 {
+		return nil, fmt.Errorf(tr("%s: no declara ningún proyecto"), path)
+	}
+	base, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	for i := range ws.Projects {
+		p := &ws.Projects[i]
+		if p.Path == "" {
+			return nil, fmt.Errorf(tr("%s: proyecto %d sin path"), path, i+1)
+		}
+		if !filepath.IsAbs(p.Path) {
+			p.Path = filepath.Join(base, p.Path)
+		}
+		if p.Out != "" && !filepath.IsAbs(p.Out) {
+			p.Out = filepath.Join(base, p.Out)
+		}
+	}
+	return &ws, nil
+}
+
+// runWorkspace genera cada proyecto del espacio de trabajo como con -all (o
+// solo sus nodos) y termina con un resumen por proyecto. Un proyecto que
+// falla no detiene a los siguientes. Con resume continúa la ejecución
+// interrumpida (ver openCheckpoint).
+func runWorkspace(path string, base GenerateRequest, resume bool) bool {
+	ws, err := loadWorkspace(path)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+	cp, err := openCheckpoint(path, base, resume)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+	ok := true
+	var rows [][]string
+	var failed []bool
+	for _, p := range ws.Projects {
+		log.Printf(tr("##### Proyecto %s #####"), p.Path)
+		req := base
+		req.ProjectPath = p.Path
+		if p.Out != "" {
+			req.OutDir = p.Out
+		}
+		if p.SkipExt != nil {
+			req.SkipExt = *p.SkipExt
+		}
+		nodes := tr("todos")
+		if len(p.Nodes) > 0 {
+			nodes = strconv.Itoa(len(p.Nodes))
+		}
+		status := "OK"
+		if !runBatch(req, p.Nodes, cp) {
+			status, ok = tr("ERROR"), false
+		}
+		rows = append(rows, []string{p.Path, nodes, status})
+		failed = append(failed, status != "OK")
+	}
+
+	fmt.Println(boldText(trf("\n--- WORKSPACE %s ---", filepath.Base(path))))
+	printTable(tr("PROYECTO\tNODOS\tRESULTADO"), rows, func(i int) func(string) string {
+		if failed[i] {
+			return errText
+		}
+		return okText
+	})
+	cp.close(ok)
+	return ok
+}
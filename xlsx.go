@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// --- EXPORTACIÓN EXCEL (XLSX) ---
+
+// xlsxSheet es una hoja con filas de texto; la primera fila es la cabecera.
+type xlsxSheet struct {
+	Name string
+	Rows [][]string
+}
+
+// writeXLSX escribe un libro Office Open XML mínimo (cadenas en línea, sin
+// estilos), suficiente para abrirse en Excel y LibreOffice.
+func writeXLSX(w io.Writer, sheets []xlsxSheet) error {
+	zw := zip.NewWriter(w)
+	add := func(name, content string) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	var ct, wb, rels strings.Builder
+	ct.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+`)
+	wb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i, sh := range sheets {
+		n := i + 1
+		fmt.Fprintf(&ct, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+		fmt.Fprintf(&wb, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(xlsxSheetName(sh.Name)), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+		if err := add(fmt.Sprintf("xl/worksheets/sheet%d.xml", n), xlsxSheetXML(sh)); err != nil {
+			return err
+		}
+	}
+	ct.WriteString("</Types>")
+	wb.WriteString("</sheets></workbook>")
+	rels.WriteString("</Relationships>")
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", ct.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", wb.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	}
+	for _, f := range files {
+		if err := add(f.name, f.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func xlsxSheetXML(sh xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range sh.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, v := range row {
+			ref := xlsxColumn(c) + strconv.Itoa(r+1)
+			if _, err := strconv.Atoi(v); err == nil && r > 0 {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, v)
+			} else {
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscape(v))
+			}
+		}
+		b.WriteString("</row>")
+	}
+	b.WriteString("</sheetData></worksheet>")
+	return b.String()
+}
+
+// xlsxColumn convierte un índice de columna (0 = A) a letras.
+func xlsxColumn(i int) string {
+	s := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = string(rune('A'+(i-1)%26)) + s
+	}
+	return s
+}
+
+// xlsxSheetName aplica las restricciones de Excel a los nombres de hoja.
+func xlsxSheetName(s string) string {
+	s = strings.NewReplacer(":", "_", "\\", "_", "/", "_", "?", "_", "*", "_", "[", "_", "]", "_").Replace(s)
+	if len(s) > 31 {
+		s = s[:31]
+	}
+	return s
+}
+
+func init() {
+	RegisterExporter(exporterFunc{"xlsx", ".points.xlsx", writePointsXLSX})
+}
+
+// writePointsXLSX escribe una hoja por lista.
+func writePointsXLSX(w io.Writer, ctx ExportContext) error {
+	var sheets []xlsxSheet
+	for _, list := range listSections(ctx.Lists) {
+		sh := xlsxSheet{Name: list.Name, Rows: [][]string{{"Índice", "Punto", "Variable", "Tipo", "Spare", "SOE", "Descripción", "Factor", "Offset", "Unidades", "Área", "Prioridad alarma"}}}
+		for idx, p := range list.Items {
+			spare, soe := "", ""
+			if p.Spare {
+				spare = "SI"
+			}
+			if p.SOE {
+				soe = "SI"
+			}
+			scale, offset, units := scalingColumns(p)
+			sh.Rows = append(sh.Rows, []string{strconv.Itoa(idx), p.Name, p.Var, p.Type, spare, soe, p.Desc, scale, offset, units, p.Area, p.Alarm})
+		}
+		sheets = append(sheets, sh)
+	}
+	if GlobalConfig.App.Areas.enabled() {
+		sheets = append(sheets, areaSheets(ctx.Lists)...)
+	}
+	if GlobalConfig.App.Alarms.enabled() {
+		sheets = append(sheets, alarmSheet(ctx.Lists))
+	}
+	return writeXLSX(w, sheets)
+}